@@ -11,6 +11,8 @@ import (
 	v1 "github.com/galadeat/bank-sim/api/proto/user/v1"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -23,13 +25,368 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// AccountType is the product an account was opened as. Each product has its own
+// interest rate; term deposits cannot be withdrawn from before they mature.
+type AccountType int32
+
+const (
+	AccountType_ACCOUNT_TYPE_UNSPECIFIED  AccountType = 0 // treated as checking
+	AccountType_ACCOUNT_TYPE_CHECKING     AccountType = 1
+	AccountType_ACCOUNT_TYPE_SAVINGS      AccountType = 2
+	AccountType_ACCOUNT_TYPE_TERM_DEPOSIT AccountType = 3
+)
+
+// Enum value maps for AccountType.
+var (
+	AccountType_name = map[int32]string{
+		0: "ACCOUNT_TYPE_UNSPECIFIED",
+		1: "ACCOUNT_TYPE_CHECKING",
+		2: "ACCOUNT_TYPE_SAVINGS",
+		3: "ACCOUNT_TYPE_TERM_DEPOSIT",
+	}
+	AccountType_value = map[string]int32{
+		"ACCOUNT_TYPE_UNSPECIFIED":  0,
+		"ACCOUNT_TYPE_CHECKING":     1,
+		"ACCOUNT_TYPE_SAVINGS":      2,
+		"ACCOUNT_TYPE_TERM_DEPOSIT": 3,
+	}
+)
+
+func (x AccountType) Enum() *AccountType {
+	p := new(AccountType)
+	*p = x
+	return p
+}
+
+func (x AccountType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (AccountType) Descriptor() protoreflect.EnumDescriptor {
+	return file_account_v2_account_proto_enumTypes[0].Descriptor()
+}
+
+func (AccountType) Type() protoreflect.EnumType {
+	return &file_account_v2_account_proto_enumTypes[0]
+}
+
+func (x AccountType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use AccountType.Descriptor instead.
+func (AccountType) EnumDescriptor() ([]byte, []int) {
+	return file_account_v2_account_proto_rawDescGZIP(), []int{0}
+}
+
+// AccountStatus is the lifecycle state of an account:
+// pending -> active on the first deposit, active <-> frozen, and any of them -> closed.
+// A frozen account can receive money but not send it; a closed account rejects everything.
+type AccountStatus int32
+
+const (
+	AccountStatus_ACCOUNT_STATUS_UNSPECIFIED AccountStatus = 0
+	AccountStatus_ACCOUNT_STATUS_ACTIVE      AccountStatus = 1
+	AccountStatus_ACCOUNT_STATUS_CLOSED      AccountStatus = 2
+	AccountStatus_ACCOUNT_STATUS_PENDING     AccountStatus = 3
+	AccountStatus_ACCOUNT_STATUS_FROZEN      AccountStatus = 4
+)
+
+// Enum value maps for AccountStatus.
+var (
+	AccountStatus_name = map[int32]string{
+		0: "ACCOUNT_STATUS_UNSPECIFIED",
+		1: "ACCOUNT_STATUS_ACTIVE",
+		2: "ACCOUNT_STATUS_CLOSED",
+		3: "ACCOUNT_STATUS_PENDING",
+		4: "ACCOUNT_STATUS_FROZEN",
+	}
+	AccountStatus_value = map[string]int32{
+		"ACCOUNT_STATUS_UNSPECIFIED": 0,
+		"ACCOUNT_STATUS_ACTIVE":      1,
+		"ACCOUNT_STATUS_CLOSED":      2,
+		"ACCOUNT_STATUS_PENDING":     3,
+		"ACCOUNT_STATUS_FROZEN":      4,
+	}
+)
+
+func (x AccountStatus) Enum() *AccountStatus {
+	p := new(AccountStatus)
+	*p = x
+	return p
+}
+
+func (x AccountStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (AccountStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_account_v2_account_proto_enumTypes[1].Descriptor()
+}
+
+func (AccountStatus) Type() protoreflect.EnumType {
+	return &file_account_v2_account_proto_enumTypes[1]
+}
+
+func (x AccountStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use AccountStatus.Descriptor instead.
+func (AccountStatus) EnumDescriptor() ([]byte, []int) {
+	return file_account_v2_account_proto_rawDescGZIP(), []int{1}
+}
+
+// BatchMode is what a batch does when one of its items fails.
+type BatchMode int32
+
+const (
+	BatchMode_BATCH_MODE_UNSPECIFIED BatchMode = 0 // treated as best effort
+	// every item is tried on its own; the results tell which ones failed
+	BatchMode_BATCH_MODE_BEST_EFFORT BatchMode = 1
+	// the items are checked together and applied only if every one of them can
+	// be; the first failing item fails the call and nothing is applied
+	BatchMode_BATCH_MODE_ALL_OR_NOTHING BatchMode = 2
+)
+
+// Enum value maps for BatchMode.
+var (
+	BatchMode_name = map[int32]string{
+		0: "BATCH_MODE_UNSPECIFIED",
+		1: "BATCH_MODE_BEST_EFFORT",
+		2: "BATCH_MODE_ALL_OR_NOTHING",
+	}
+	BatchMode_value = map[string]int32{
+		"BATCH_MODE_UNSPECIFIED":    0,
+		"BATCH_MODE_BEST_EFFORT":    1,
+		"BATCH_MODE_ALL_OR_NOTHING": 2,
+	}
+)
+
+func (x BatchMode) Enum() *BatchMode {
+	p := new(BatchMode)
+	*p = x
+	return p
+}
+
+func (x BatchMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (BatchMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_account_v2_account_proto_enumTypes[2].Descriptor()
+}
+
+func (BatchMode) Type() protoreflect.EnumType {
+	return &file_account_v2_account_proto_enumTypes[2]
+}
+
+func (x BatchMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use BatchMode.Descriptor instead.
+func (BatchMode) EnumDescriptor() ([]byte, []int) {
+	return file_account_v2_account_proto_rawDescGZIP(), []int{2}
+}
+
+type TransactionType int32
+
+const (
+	TransactionType_TRANSACTION_TYPE_UNSPECIFIED        TransactionType = 0
+	TransactionType_TRANSACTION_TYPE_DEPOSIT            TransactionType = 1
+	TransactionType_TRANSACTION_TYPE_WITHDRAWAL         TransactionType = 2
+	TransactionType_TRANSACTION_TYPE_INTEREST           TransactionType = 3
+	TransactionType_TRANSACTION_TYPE_OVERDRAFT_INTEREST TransactionType = 4
+	TransactionType_TRANSACTION_TYPE_FEE                TransactionType = 5
+	TransactionType_TRANSACTION_TYPE_TRANSFER_OUT       TransactionType = 6
+	TransactionType_TRANSACTION_TYPE_TRANSFER_IN        TransactionType = 7
+	TransactionType_TRANSACTION_TYPE_CAPTURE            TransactionType = 8
+	TransactionType_TRANSACTION_TYPE_REVERSAL           TransactionType = 9  // debit undoing a credit
+	TransactionType_TRANSACTION_TYPE_REFUND             TransactionType = 10 // credit undoing a debit
+)
+
+// Enum value maps for TransactionType.
+var (
+	TransactionType_name = map[int32]string{
+		0:  "TRANSACTION_TYPE_UNSPECIFIED",
+		1:  "TRANSACTION_TYPE_DEPOSIT",
+		2:  "TRANSACTION_TYPE_WITHDRAWAL",
+		3:  "TRANSACTION_TYPE_INTEREST",
+		4:  "TRANSACTION_TYPE_OVERDRAFT_INTEREST",
+		5:  "TRANSACTION_TYPE_FEE",
+		6:  "TRANSACTION_TYPE_TRANSFER_OUT",
+		7:  "TRANSACTION_TYPE_TRANSFER_IN",
+		8:  "TRANSACTION_TYPE_CAPTURE",
+		9:  "TRANSACTION_TYPE_REVERSAL",
+		10: "TRANSACTION_TYPE_REFUND",
+	}
+	TransactionType_value = map[string]int32{
+		"TRANSACTION_TYPE_UNSPECIFIED":        0,
+		"TRANSACTION_TYPE_DEPOSIT":            1,
+		"TRANSACTION_TYPE_WITHDRAWAL":         2,
+		"TRANSACTION_TYPE_INTEREST":           3,
+		"TRANSACTION_TYPE_OVERDRAFT_INTEREST": 4,
+		"TRANSACTION_TYPE_FEE":                5,
+		"TRANSACTION_TYPE_TRANSFER_OUT":       6,
+		"TRANSACTION_TYPE_TRANSFER_IN":        7,
+		"TRANSACTION_TYPE_CAPTURE":            8,
+		"TRANSACTION_TYPE_REVERSAL":           9,
+		"TRANSACTION_TYPE_REFUND":             10,
+	}
+)
+
+func (x TransactionType) Enum() *TransactionType {
+	p := new(TransactionType)
+	*p = x
+	return p
+}
+
+func (x TransactionType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (TransactionType) Descriptor() protoreflect.EnumDescriptor {
+	return file_account_v2_account_proto_enumTypes[3].Descriptor()
+}
+
+func (TransactionType) Type() protoreflect.EnumType {
+	return &file_account_v2_account_proto_enumTypes[3]
+}
+
+func (x TransactionType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use TransactionType.Descriptor instead.
+func (TransactionType) EnumDescriptor() ([]byte, []int) {
+	return file_account_v2_account_proto_rawDescGZIP(), []int{3}
+}
+
+type ReviewStatus int32
+
+const (
+	ReviewStatus_REVIEW_STATUS_UNSPECIFIED ReviewStatus = 0
+	ReviewStatus_REVIEW_STATUS_PENDING     ReviewStatus = 1
+	ReviewStatus_REVIEW_STATUS_APPROVED    ReviewStatus = 2
+	ReviewStatus_REVIEW_STATUS_REJECTED    ReviewStatus = 3
+)
+
+// Enum value maps for ReviewStatus.
+var (
+	ReviewStatus_name = map[int32]string{
+		0: "REVIEW_STATUS_UNSPECIFIED",
+		1: "REVIEW_STATUS_PENDING",
+		2: "REVIEW_STATUS_APPROVED",
+		3: "REVIEW_STATUS_REJECTED",
+	}
+	ReviewStatus_value = map[string]int32{
+		"REVIEW_STATUS_UNSPECIFIED": 0,
+		"REVIEW_STATUS_PENDING":     1,
+		"REVIEW_STATUS_APPROVED":    2,
+		"REVIEW_STATUS_REJECTED":    3,
+	}
+)
+
+func (x ReviewStatus) Enum() *ReviewStatus {
+	p := new(ReviewStatus)
+	*p = x
+	return p
+}
+
+func (x ReviewStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ReviewStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_account_v2_account_proto_enumTypes[4].Descriptor()
+}
+
+func (ReviewStatus) Type() protoreflect.EnumType {
+	return &file_account_v2_account_proto_enumTypes[4]
+}
+
+func (x ReviewStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ReviewStatus.Descriptor instead.
+func (ReviewStatus) EnumDescriptor() ([]byte, []int) {
+	return file_account_v2_account_proto_rawDescGZIP(), []int{4}
+}
+
+type HoldStatus int32
+
+const (
+	HoldStatus_HOLD_STATUS_UNSPECIFIED HoldStatus = 0
+	HoldStatus_HOLD_STATUS_ACTIVE      HoldStatus = 1
+	HoldStatus_HOLD_STATUS_CAPTURED    HoldStatus = 2
+	HoldStatus_HOLD_STATUS_VOIDED      HoldStatus = 3
+	HoldStatus_HOLD_STATUS_EXPIRED     HoldStatus = 4
+)
+
+// Enum value maps for HoldStatus.
+var (
+	HoldStatus_name = map[int32]string{
+		0: "HOLD_STATUS_UNSPECIFIED",
+		1: "HOLD_STATUS_ACTIVE",
+		2: "HOLD_STATUS_CAPTURED",
+		3: "HOLD_STATUS_VOIDED",
+		4: "HOLD_STATUS_EXPIRED",
+	}
+	HoldStatus_value = map[string]int32{
+		"HOLD_STATUS_UNSPECIFIED": 0,
+		"HOLD_STATUS_ACTIVE":      1,
+		"HOLD_STATUS_CAPTURED":    2,
+		"HOLD_STATUS_VOIDED":      3,
+		"HOLD_STATUS_EXPIRED":     4,
+	}
+)
+
+func (x HoldStatus) Enum() *HoldStatus {
+	p := new(HoldStatus)
+	*p = x
+	return p
+}
+
+func (x HoldStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (HoldStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_account_v2_account_proto_enumTypes[5].Descriptor()
+}
+
+func (HoldStatus) Type() protoreflect.EnumType {
+	return &file_account_v2_account_proto_enumTypes[5]
+}
+
+func (x HoldStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use HoldStatus.Descriptor instead.
+func (HoldStatus) EnumDescriptor() ([]byte, []int) {
+	return file_account_v2_account_proto_rawDescGZIP(), []int{5}
+}
+
 type AccountInfo struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Owner         *v1.UserInfo           `protobuf:"bytes,2,opt,name=owner,proto3" json:"owner,omitempty"`
-	Balance       *v11.Money             `protobuf:"bytes,3,opt,name=balance,proto3" json:"balance,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state                    protoimpl.MessageState `protogen:"open.v1"`
+	Id                       string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Owner                    *v1.UserInfo           `protobuf:"bytes,2,opt,name=owner,proto3" json:"owner,omitempty"`     // copy of the owner, kept up to date by the user service through SyncOwner
+	Balance                  *v11.Money             `protobuf:"bytes,3,opt,name=balance,proto3" json:"balance,omitempty"` // ledger balance, negative while overdrawn
+	Status                   AccountStatus          `protobuf:"varint,4,opt,name=status,proto3,enum=account.v2.AccountStatus" json:"status,omitempty"`
+	Type                     AccountType            `protobuf:"varint,5,opt,name=type,proto3,enum=account.v2.AccountType" json:"type,omitempty"`
+	OpenedAt                 *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=opened_at,json=openedAt,proto3" json:"opened_at,omitempty"`
+	MaturesAt                *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=matures_at,json=maturesAt,proto3" json:"matures_at,omitempty"`                                                 // term deposits only
+	AccruedInterest          *v11.Money             `protobuf:"bytes,8,opt,name=accrued_interest,json=accruedInterest,proto3" json:"accrued_interest,omitempty"`                               // accrued daily, posted to the balance monthly
+	OverdraftLimit           *v11.Money             `protobuf:"bytes,9,opt,name=overdraft_limit,json=overdraftLimit,proto3" json:"overdraft_limit,omitempty"`                                  // how far below zero the balance may go
+	AvailableBalance         *v11.Money             `protobuf:"bytes,10,opt,name=available_balance,json=availableBalance,proto3" json:"available_balance,omitempty"`                           // balance plus overdraft limit
+	AccruedOverdraftInterest *v11.Money             `protobuf:"bytes,11,opt,name=accrued_overdraft_interest,json=accruedOverdraftInterest,proto3" json:"accrued_overdraft_interest,omitempty"` // charged monthly on negative balances
+	OwnerId                  string                 `protobuf:"bytes,12,opt,name=owner_id,json=ownerId,proto3" json:"owner_id,omitempty"`                                                      // id of the owning user, the reference to go by
+	SourceId                 string                 `protobuf:"bytes,13,opt,name=source_id,json=sourceId,proto3" json:"source_id,omitempty"`                                                   // id of the account it was restored from, see RestoreAccount
+	unknownFields            protoimpl.UnknownFields
+	sizeCache                protoimpl.SizeCache
 }
 
 func (x *AccountInfo) Reset() {
@@ -83,6 +440,76 @@ func (x *AccountInfo) GetBalance() *v11.Money {
 	return nil
 }
 
+func (x *AccountInfo) GetStatus() AccountStatus {
+	if x != nil {
+		return x.Status
+	}
+	return AccountStatus_ACCOUNT_STATUS_UNSPECIFIED
+}
+
+func (x *AccountInfo) GetType() AccountType {
+	if x != nil {
+		return x.Type
+	}
+	return AccountType_ACCOUNT_TYPE_UNSPECIFIED
+}
+
+func (x *AccountInfo) GetOpenedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.OpenedAt
+	}
+	return nil
+}
+
+func (x *AccountInfo) GetMaturesAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.MaturesAt
+	}
+	return nil
+}
+
+func (x *AccountInfo) GetAccruedInterest() *v11.Money {
+	if x != nil {
+		return x.AccruedInterest
+	}
+	return nil
+}
+
+func (x *AccountInfo) GetOverdraftLimit() *v11.Money {
+	if x != nil {
+		return x.OverdraftLimit
+	}
+	return nil
+}
+
+func (x *AccountInfo) GetAvailableBalance() *v11.Money {
+	if x != nil {
+		return x.AvailableBalance
+	}
+	return nil
+}
+
+func (x *AccountInfo) GetAccruedOverdraftInterest() *v11.Money {
+	if x != nil {
+		return x.AccruedOverdraftInterest
+	}
+	return nil
+}
+
+func (x *AccountInfo) GetOwnerId() string {
+	if x != nil {
+		return x.OwnerId
+	}
+	return ""
+}
+
+func (x *AccountInfo) GetSourceId() string {
+	if x != nil {
+		return x.SourceId
+	}
+	return ""
+}
+
 type GetAccountResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Account       *AccountInfo           `protobuf:"bytes,1,opt,name=account,proto3" json:"account,omitempty"`
@@ -176,6 +603,8 @@ type CreateAccountRequest struct {
 	UserId         string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
 	InitialBalance *v11.Money             `protobuf:"bytes,2,opt,name=initial_balance,json=initialBalance,proto3" json:"initial_balance,omitempty"`
 	RequestId      string                 `protobuf:"bytes,3,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	Type           AccountType            `protobuf:"varint,4,opt,name=type,proto3,enum=account.v2.AccountType" json:"type,omitempty"`
+	TermMonths     int32                  `protobuf:"varint,5,opt,name=term_months,json=termMonths,proto3" json:"term_months,omitempty"` // required for term deposits
 	unknownFields  protoimpl.UnknownFields
 	sizeCache      protoimpl.SizeCache
 }
@@ -231,6 +660,20 @@ func (x *CreateAccountRequest) GetRequestId() string {
 	return ""
 }
 
+func (x *CreateAccountRequest) GetType() AccountType {
+	if x != nil {
+		return x.Type
+	}
+	return AccountType_ACCOUNT_TYPE_UNSPECIFIED
+}
+
+func (x *CreateAccountRequest) GetTermMonths() int32 {
+	if x != nil {
+		return x.TermMonths
+	}
+	return 0
+}
+
 type CreateAccountResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Account       *AccountInfo           `protobuf:"bytes,1,opt,name=account,proto3" json:"account,omitempty"`
@@ -278,6 +721,7 @@ func (x *CreateAccountResponse) GetAccount() *AccountInfo {
 type ListAccountsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	IncludeClosed bool                   `protobuf:"varint,2,opt,name=include_closed,json=includeClosed,proto3" json:"include_closed,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -319,6 +763,13 @@ func (x *ListAccountsRequest) GetUserId() string {
 	return ""
 }
 
+func (x *ListAccountsRequest) GetIncludeClosed() bool {
+	if x != nil {
+		return x.IncludeClosed
+	}
+	return false
+}
+
 type ListAccountsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Accounts      []*AccountInfo         `protobuf:"bytes,1,rep,name=accounts,proto3" json:"accounts,omitempty"`
@@ -615,9 +1066,12 @@ func (x *WithdrawRequest) GetRequestId() string {
 	return ""
 }
 
+// WithdrawResponse carries a review instead of an updated account when the
+// withdrawal was held by the risk checks; it is applied once the review is approved.
 type WithdrawResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Account       *AccountInfo           `protobuf:"bytes,1,opt,name=account,proto3" json:"account,omitempty"`
+	Review        *Review                `protobuf:"bytes,2,opt,name=review,proto3" json:"review,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -659,66 +1113,2954 @@ func (x *WithdrawResponse) GetAccount() *AccountInfo {
 	return nil
 }
 
-var File_account_v2_account_proto protoreflect.FileDescriptor
+func (x *WithdrawResponse) GetReview() *Review {
+	if x != nil {
+		return x.Review
+	}
+	return nil
+}
 
-const file_account_v2_account_proto_rawDesc = "" +
-	"\n" +
-	"\x18account/v2/account.proto\x12\n" +
-	"account.v2\x1a\x12user/v1/user.proto\x1a\x15common/v1/money.proto\"r\n" +
-	"\vAccountInfo\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\x12'\n" +
-	"\x05owner\x18\x02 \x01(\v2\x11.user.v1.UserInfoR\x05owner\x12*\n" +
-	"\abalance\x18\x03 \x01(\v2\x10.common.v1.MoneyR\abalance\"G\n" +
-	"\x12GetAccountResponse\x121\n" +
-	"\aaccount\x18\x01 \x01(\v2\x17.account.v2.AccountInfoR\aaccount\"#\n" +
-	"\x11GetAccountRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\"\x89\x01\n" +
-	"\x14CreateAccountRequest\x12\x17\n" +
-	"\auser_id\x18\x01 \x01(\tR\x06userId\x129\n" +
-	"\x0finitial_balance\x18\x02 \x01(\v2\x10.common.v1.MoneyR\x0einitialBalance\x12\x1d\n" +
-	"\n" +
-	"request_id\x18\x03 \x01(\tR\trequestId\"J\n" +
-	"\x15CreateAccountResponse\x121\n" +
-	"\aaccount\x18\x01 \x01(\v2\x17.account.v2.AccountInfoR\aaccount\".\n" +
-	"\x13ListAccountsRequest\x12\x17\n" +
-	"\auser_id\x18\x01 \x01(\tR\x06userId\"K\n" +
-	"\x14ListAccountsResponse\x123\n" +
-	"\baccounts\x18\x01 \x03(\v2\x17.account.v2.AccountInfoR\baccounts\"5\n" +
-	"\x14DeleteAccountRequest\x12\x1d\n" +
-	"\n" +
-	"account_id\x18\x01 \x01(\tR\taccountId\"6\n" +
-	"\x15DeleteAccountResponse\x12\x1d\n" +
-	"\n" +
-	"account_id\x18\x01 \x01(\tR\taccountId\"x\n" +
-	"\x0eDepositRequest\x12\x1d\n" +
-	"\n" +
-	"account_id\x18\x01 \x01(\tR\taccountId\x12(\n" +
-	"\x06amount\x18\x02 \x01(\v2\x10.common.v1.MoneyR\x06amount\x12\x1d\n" +
-	"\n" +
-	"request_id\x18\x03 \x01(\tR\trequestId\"D\n" +
-	"\x0fDepositResponse\x121\n" +
-	"\aaccount\x18\x01 \x01(\v2\x17.account.v2.AccountInfoR\aaccount\"y\n" +
-	"\x0fWithdrawRequest\x12\x1d\n" +
-	"\n" +
-	"account_id\x18\x01 \x01(\tR\taccountId\x12(\n" +
-	"\x06amount\x18\x02 \x01(\v2\x10.common.v1.MoneyR\x06amount\x12\x1d\n" +
-	"\n" +
-	"request_id\x18\x03 \x01(\tR\trequestId\"E\n" +
-	"\x10WithdrawResponse\x121\n" +
-	"\aaccount\x18\x01 \x01(\v2\x17.account.v2.AccountInfoR\aaccount2\xe0\x03\n" +
-	"\aAccount\x12T\n" +
-	"\rCreateAccount\x12 .account.v2.CreateAccountRequest\x1a!.account.v2.CreateAccountResponse\x12K\n" +
-	"\n" +
-	"GetAccount\x12\x1d.account.v2.GetAccountRequest\x1a\x1e.account.v2.GetAccountResponse\x12Q\n" +
-	"\fListAccounts\x12\x1f.account.v2.ListAccountsRequest\x1a .account.v2.ListAccountsResponse\x12T\n" +
-	"\rDeleteAccount\x12 .account.v2.DeleteAccountRequest\x1a!.account.v2.DeleteAccountResponse\x12B\n" +
-	"\aDeposit\x12\x1a.account.v2.DepositRequest\x1a\x1b.account.v2.DepositResponse\x12E\n" +
-	"\bWithdraw\x12\x1b.account.v2.WithdrawRequest\x1a\x1c.account.v2.WithdrawResponseB=Z;github.com/galadeat/bank-sim/api/proto/account/v2;accountv2b\x06proto3"
+// BatchError is why an item of a batch failed.
+type BatchError struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          int32                  `protobuf:"varint,1,opt,name=code,proto3" json:"code,omitempty"` // grpc status code
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
 
-var (
-	file_account_v2_account_proto_rawDescOnce sync.Once
-	file_account_v2_account_proto_rawDescData []byte
-)
+func (x *BatchError) Reset() {
+	*x = BatchError{}
+	mi := &file_account_v2_account_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchError) ProtoMessage() {}
+
+func (x *BatchError) ProtoReflect() protoreflect.Message {
+	mi := &file_account_v2_account_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchError.ProtoReflect.Descriptor instead.
+func (*BatchError) Descriptor() ([]byte, []int) {
+	return file_account_v2_account_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *BatchError) GetCode() int32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *BatchError) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// BatchCreateAccountsRequest opens many accounts in one call. Each item is a
+// CreateAccount request with its own request id, so a retried batch, or an item
+// that was sent before on its own, opens its account once.
+type BatchCreateAccountsRequest struct {
+	state         protoimpl.MessageState  `protogen:"open.v1"`
+	Items         []*CreateAccountRequest `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	Mode          BatchMode               `protobuf:"varint,2,opt,name=mode,proto3,enum=account.v2.BatchMode" json:"mode,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchCreateAccountsRequest) Reset() {
+	*x = BatchCreateAccountsRequest{}
+	mi := &file_account_v2_account_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchCreateAccountsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchCreateAccountsRequest) ProtoMessage() {}
+
+func (x *BatchCreateAccountsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_account_v2_account_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchCreateAccountsRequest.ProtoReflect.Descriptor instead.
+func (*BatchCreateAccountsRequest) Descriptor() ([]byte, []int) {
+	return file_account_v2_account_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *BatchCreateAccountsRequest) GetItems() []*CreateAccountRequest {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *BatchCreateAccountsRequest) GetMode() BatchMode {
+	if x != nil {
+		return x.Mode
+	}
+	return BatchMode_BATCH_MODE_UNSPECIFIED
+}
+
+type BatchCreateAccountsResponse struct {
+	state         protoimpl.MessageState       `protogen:"open.v1"`
+	Results       []*BatchCreateAccountsResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchCreateAccountsResponse) Reset() {
+	*x = BatchCreateAccountsResponse{}
+	mi := &file_account_v2_account_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchCreateAccountsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchCreateAccountsResponse) ProtoMessage() {}
+
+func (x *BatchCreateAccountsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_account_v2_account_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchCreateAccountsResponse.ProtoReflect.Descriptor instead.
+func (*BatchCreateAccountsResponse) Descriptor() ([]byte, []int) {
+	return file_account_v2_account_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *BatchCreateAccountsResponse) GetResults() []*BatchCreateAccountsResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+// BatchCreateAccountsResult is the outcome of the item at index: the account, or
+// the error in best effort mode.
+type BatchCreateAccountsResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Index         int32                  `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Account       *AccountInfo           `protobuf:"bytes,2,opt,name=account,proto3" json:"account,omitempty"`
+	Error         *BatchError            `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchCreateAccountsResult) Reset() {
+	*x = BatchCreateAccountsResult{}
+	mi := &file_account_v2_account_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchCreateAccountsResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchCreateAccountsResult) ProtoMessage() {}
+
+func (x *BatchCreateAccountsResult) ProtoReflect() protoreflect.Message {
+	mi := &file_account_v2_account_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchCreateAccountsResult.ProtoReflect.Descriptor instead.
+func (*BatchCreateAccountsResult) Descriptor() ([]byte, []int) {
+	return file_account_v2_account_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *BatchCreateAccountsResult) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *BatchCreateAccountsResult) GetAccount() *AccountInfo {
+	if x != nil {
+		return x.Account
+	}
+	return nil
+}
+
+func (x *BatchCreateAccountsResult) GetError() *BatchError {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+// BulkPostRequest is one posting of a BulkPost stream. The mode is read from
+// the first message. Deposits and withdrawals keep the request id semantics of
+// Deposit and Withdraw.
+type BulkPostRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Mode  BatchMode              `protobuf:"varint,1,opt,name=mode,proto3,enum=account.v2.BatchMode" json:"mode,omitempty"`
+	// Types that are valid to be assigned to Posting:
+	//
+	//	*BulkPostRequest_Deposit
+	//	*BulkPostRequest_Withdraw
+	Posting       isBulkPostRequest_Posting `protobuf_oneof:"posting"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BulkPostRequest) Reset() {
+	*x = BulkPostRequest{}
+	mi := &file_account_v2_account_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BulkPostRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BulkPostRequest) ProtoMessage() {}
+
+func (x *BulkPostRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_account_v2_account_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BulkPostRequest.ProtoReflect.Descriptor instead.
+func (*BulkPostRequest) Descriptor() ([]byte, []int) {
+	return file_account_v2_account_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *BulkPostRequest) GetMode() BatchMode {
+	if x != nil {
+		return x.Mode
+	}
+	return BatchMode_BATCH_MODE_UNSPECIFIED
+}
+
+func (x *BulkPostRequest) GetPosting() isBulkPostRequest_Posting {
+	if x != nil {
+		return x.Posting
+	}
+	return nil
+}
+
+func (x *BulkPostRequest) GetDeposit() *DepositRequest {
+	if x != nil {
+		if x, ok := x.Posting.(*BulkPostRequest_Deposit); ok {
+			return x.Deposit
+		}
+	}
+	return nil
+}
+
+func (x *BulkPostRequest) GetWithdraw() *WithdrawRequest {
+	if x != nil {
+		if x, ok := x.Posting.(*BulkPostRequest_Withdraw); ok {
+			return x.Withdraw
+		}
+	}
+	return nil
+}
+
+type isBulkPostRequest_Posting interface {
+	isBulkPostRequest_Posting()
+}
+
+type BulkPostRequest_Deposit struct {
+	Deposit *DepositRequest `protobuf:"bytes,2,opt,name=deposit,proto3,oneof"`
+}
+
+type BulkPostRequest_Withdraw struct {
+	Withdraw *WithdrawRequest `protobuf:"bytes,3,opt,name=withdraw,proto3,oneof"`
+}
+
+func (*BulkPostRequest_Deposit) isBulkPostRequest_Posting() {}
+
+func (*BulkPostRequest_Withdraw) isBulkPostRequest_Posting() {}
+
+// BulkPostResponse has a result for every posting, in the order they were sent.
+type BulkPostResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Results       []*BulkPostResult      `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	Applied       int32                  `protobuf:"varint,2,opt,name=applied,proto3" json:"applied,omitempty"`
+	Held          int32                  `protobuf:"varint,3,opt,name=held,proto3" json:"held,omitempty"` // withdrawals held for review, best effort mode only
+	Failed        int32                  `protobuf:"varint,4,opt,name=failed,proto3" json:"failed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BulkPostResponse) Reset() {
+	*x = BulkPostResponse{}
+	mi := &file_account_v2_account_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BulkPostResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BulkPostResponse) ProtoMessage() {}
+
+func (x *BulkPostResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_account_v2_account_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BulkPostResponse.ProtoReflect.Descriptor instead.
+func (*BulkPostResponse) Descriptor() ([]byte, []int) {
+	return file_account_v2_account_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *BulkPostResponse) GetResults() []*BulkPostResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *BulkPostResponse) GetApplied() int32 {
+	if x != nil {
+		return x.Applied
+	}
+	return 0
+}
+
+func (x *BulkPostResponse) GetHeld() int32 {
+	if x != nil {
+		return x.Held
+	}
+	return 0
+}
+
+func (x *BulkPostResponse) GetFailed() int32 {
+	if x != nil {
+		return x.Failed
+	}
+	return 0
+}
+
+type BulkPostResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Index         int32                  `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Account       *AccountInfo           `protobuf:"bytes,2,opt,name=account,proto3" json:"account,omitempty"`
+	Review        *Review                `protobuf:"bytes,3,opt,name=review,proto3" json:"review,omitempty"` // set when a withdrawal is held for review
+	Error         *BatchError            `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BulkPostResult) Reset() {
+	*x = BulkPostResult{}
+	mi := &file_account_v2_account_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BulkPostResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BulkPostResult) ProtoMessage() {}
+
+func (x *BulkPostResult) ProtoReflect() protoreflect.Message {
+	mi := &file_account_v2_account_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BulkPostResult.ProtoReflect.Descriptor instead.
+func (*BulkPostResult) Descriptor() ([]byte, []int) {
+	return file_account_v2_account_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *BulkPostResult) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *BulkPostResult) GetAccount() *AccountInfo {
+	if x != nil {
+		return x.Account
+	}
+	return nil
+}
+
+func (x *BulkPostResult) GetReview() *Review {
+	if x != nil {
+		return x.Review
+	}
+	return nil
+}
+
+func (x *BulkPostResult) GetError() *BatchError {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+// TransferRequest moves money between two accounts in one step. Both ledger
+// entries carry the request id.
+type TransferRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FromAccountId string                 `protobuf:"bytes,1,opt,name=from_account_id,json=fromAccountId,proto3" json:"from_account_id,omitempty"`
+	ToAccountId   string                 `protobuf:"bytes,2,opt,name=to_account_id,json=toAccountId,proto3" json:"to_account_id,omitempty"`
+	Amount        *v11.Money             `protobuf:"bytes,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	RequestId     string                 `protobuf:"bytes,4,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TransferRequest) Reset() {
+	*x = TransferRequest{}
+	mi := &file_account_v2_account_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransferRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransferRequest) ProtoMessage() {}
+
+func (x *TransferRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_account_v2_account_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransferRequest.ProtoReflect.Descriptor instead.
+func (*TransferRequest) Descriptor() ([]byte, []int) {
+	return file_account_v2_account_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *TransferRequest) GetFromAccountId() string {
+	if x != nil {
+		return x.FromAccountId
+	}
+	return ""
+}
+
+func (x *TransferRequest) GetToAccountId() string {
+	if x != nil {
+		return x.ToAccountId
+	}
+	return ""
+}
+
+func (x *TransferRequest) GetAmount() *v11.Money {
+	if x != nil {
+		return x.Amount
+	}
+	return nil
+}
+
+func (x *TransferRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+type TransferResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Debit         *Transaction           `protobuf:"bytes,1,opt,name=debit,proto3" json:"debit,omitempty"`
+	Credit        *Transaction           `protobuf:"bytes,2,opt,name=credit,proto3" json:"credit,omitempty"`
+	Review        *Review                `protobuf:"bytes,3,opt,name=review,proto3" json:"review,omitempty"` // set when the transfer is held for review
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TransferResponse) Reset() {
+	*x = TransferResponse{}
+	mi := &file_account_v2_account_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransferResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransferResponse) ProtoMessage() {}
+
+func (x *TransferResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_account_v2_account_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransferResponse.ProtoReflect.Descriptor instead.
+func (*TransferResponse) Descriptor() ([]byte, []int) {
+	return file_account_v2_account_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *TransferResponse) GetDebit() *Transaction {
+	if x != nil {
+		return x.Debit
+	}
+	return nil
+}
+
+func (x *TransferResponse) GetCredit() *Transaction {
+	if x != nil {
+		return x.Credit
+	}
+	return nil
+}
+
+func (x *TransferResponse) GetReview() *Review {
+	if x != nil {
+		return x.Review
+	}
+	return nil
+}
+
+// CloseUserAccounts closes every open account of the user. It fails with
+// FAILED_PRECONDITION and closes nothing if any of them holds funds.
+type CloseUserAccountsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CloseUserAccountsRequest) Reset() {
+	*x = CloseUserAccountsRequest{}
+	mi := &file_account_v2_account_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CloseUserAccountsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CloseUserAccountsRequest) ProtoMessage() {}
+
+func (x *CloseUserAccountsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_account_v2_account_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CloseUserAccountsRequest.ProtoReflect.Descriptor instead.
+func (*CloseUserAccountsRequest) Descriptor() ([]byte, []int) {
+	return file_account_v2_account_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *CloseUserAccountsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type CloseUserAccountsResponse struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	ClosedAccountIds []string               `protobuf:"bytes,1,rep,name=closed_account_ids,json=closedAccountIds,proto3" json:"closed_account_ids,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *CloseUserAccountsResponse) Reset() {
+	*x = CloseUserAccountsResponse{}
+	mi := &file_account_v2_account_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CloseUserAccountsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CloseUserAccountsResponse) ProtoMessage() {}
+
+func (x *CloseUserAccountsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_account_v2_account_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CloseUserAccountsResponse.ProtoReflect.Descriptor instead.
+func (*CloseUserAccountsResponse) Descriptor() ([]byte, []int) {
+	return file_account_v2_account_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *CloseUserAccountsResponse) GetClosedAccountIds() []string {
+	if x != nil {
+		return x.ClosedAccountIds
+	}
+	return nil
+}
+
+// SyncOwner replaces the owner copy of every account of the user, open or
+// closed, with owner. Accounts whose copy already matches, or has a higher
+// version, are left alone.
+type SyncOwnerRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Owner         *v1.UserInfo           `protobuf:"bytes,1,opt,name=owner,proto3" json:"owner,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SyncOwnerRequest) Reset() {
+	*x = SyncOwnerRequest{}
+	mi := &file_account_v2_account_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyncOwnerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncOwnerRequest) ProtoMessage() {}
+
+func (x *SyncOwnerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_account_v2_account_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncOwnerRequest.ProtoReflect.Descriptor instead.
+func (*SyncOwnerRequest) Descriptor() ([]byte, []int) {
+	return file_account_v2_account_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *SyncOwnerRequest) GetOwner() *v1.UserInfo {
+	if x != nil {
+		return x.Owner
+	}
+	return nil
+}
+
+type SyncOwnerResponse struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	UpdatedAccountIds []string               `protobuf:"bytes,1,rep,name=updated_account_ids,json=updatedAccountIds,proto3" json:"updated_account_ids,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *SyncOwnerResponse) Reset() {
+	*x = SyncOwnerResponse{}
+	mi := &file_account_v2_account_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyncOwnerResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncOwnerResponse) ProtoMessage() {}
+
+func (x *SyncOwnerResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_account_v2_account_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncOwnerResponse.ProtoReflect.Descriptor instead.
+func (*SyncOwnerResponse) Descriptor() ([]byte, []int) {
+	return file_account_v2_account_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *SyncOwnerResponse) GetUpdatedAccountIds() []string {
+	if x != nil {
+		return x.UpdatedAccountIds
+	}
+	return nil
+}
+
+type FreezeAccountRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountId     string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FreezeAccountRequest) Reset() {
+	*x = FreezeAccountRequest{}
+	mi := &file_account_v2_account_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FreezeAccountRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FreezeAccountRequest) ProtoMessage() {}
+
+func (x *FreezeAccountRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_account_v2_account_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FreezeAccountRequest.ProtoReflect.Descriptor instead.
+func (*FreezeAccountRequest) Descriptor() ([]byte, []int) {
+	return file_account_v2_account_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *FreezeAccountRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *FreezeAccountRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type FreezeAccountResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Account       *AccountInfo           `protobuf:"bytes,1,opt,name=account,proto3" json:"account,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FreezeAccountResponse) Reset() {
+	*x = FreezeAccountResponse{}
+	mi := &file_account_v2_account_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FreezeAccountResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FreezeAccountResponse) ProtoMessage() {}
+
+func (x *FreezeAccountResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_account_v2_account_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FreezeAccountResponse.ProtoReflect.Descriptor instead.
+func (*FreezeAccountResponse) Descriptor() ([]byte, []int) {
+	return file_account_v2_account_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *FreezeAccountResponse) GetAccount() *AccountInfo {
+	if x != nil {
+		return x.Account
+	}
+	return nil
+}
+
+type UnfreezeAccountRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountId     string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnfreezeAccountRequest) Reset() {
+	*x = UnfreezeAccountRequest{}
+	mi := &file_account_v2_account_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnfreezeAccountRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnfreezeAccountRequest) ProtoMessage() {}
+
+func (x *UnfreezeAccountRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_account_v2_account_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnfreezeAccountRequest.ProtoReflect.Descriptor instead.
+func (*UnfreezeAccountRequest) Descriptor() ([]byte, []int) {
+	return file_account_v2_account_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *UnfreezeAccountRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *UnfreezeAccountRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type UnfreezeAccountResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Account       *AccountInfo           `protobuf:"bytes,1,opt,name=account,proto3" json:"account,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnfreezeAccountResponse) Reset() {
+	*x = UnfreezeAccountResponse{}
+	mi := &file_account_v2_account_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnfreezeAccountResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnfreezeAccountResponse) ProtoMessage() {}
+
+func (x *UnfreezeAccountResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_account_v2_account_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnfreezeAccountResponse.ProtoReflect.Descriptor instead.
+func (*UnfreezeAccountResponse) Descriptor() ([]byte, []int) {
+	return file_account_v2_account_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *UnfreezeAccountResponse) GetAccount() *AccountInfo {
+	if x != nil {
+		return x.Account
+	}
+	return nil
+}
+
+type CloseAccountRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountId     string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CloseAccountRequest) Reset() {
+	*x = CloseAccountRequest{}
+	mi := &file_account_v2_account_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CloseAccountRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CloseAccountRequest) ProtoMessage() {}
+
+func (x *CloseAccountRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_account_v2_account_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CloseAccountRequest.ProtoReflect.Descriptor instead.
+func (*CloseAccountRequest) Descriptor() ([]byte, []int) {
+	return file_account_v2_account_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *CloseAccountRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *CloseAccountRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type CloseAccountResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Account       *AccountInfo           `protobuf:"bytes,1,opt,name=account,proto3" json:"account,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CloseAccountResponse) Reset() {
+	*x = CloseAccountResponse{}
+	mi := &file_account_v2_account_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CloseAccountResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CloseAccountResponse) ProtoMessage() {}
+
+func (x *CloseAccountResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_account_v2_account_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CloseAccountResponse.ProtoReflect.Descriptor instead.
+func (*CloseAccountResponse) Descriptor() ([]byte, []int) {
+	return file_account_v2_account_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *CloseAccountResponse) GetAccount() *AccountInfo {
+	if x != nil {
+		return x.Account
+	}
+	return nil
+}
+
+// StatusChange is an audit trail entry for a single lifecycle transition.
+type StatusChange struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	From          AccountStatus          `protobuf:"varint,1,opt,name=from,proto3,enum=account.v2.AccountStatus" json:"from,omitempty"`
+	To            AccountStatus          `protobuf:"varint,2,opt,name=to,proto3,enum=account.v2.AccountStatus" json:"to,omitempty"`
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	ChangedAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=changed_at,json=changedAt,proto3" json:"changed_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StatusChange) Reset() {
+	*x = StatusChange{}
+	mi := &file_account_v2_account_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatusChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatusChange) ProtoMessage() {}
+
+func (x *StatusChange) ProtoReflect() protoreflect.Message {
+	mi := &file_account_v2_account_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatusChange.ProtoReflect.Descriptor instead.
+func (*StatusChange) Descriptor() ([]byte, []int) {
+	return file_account_v2_account_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *StatusChange) GetFrom() AccountStatus {
+	if x != nil {
+		return x.From
+	}
+	return AccountStatus_ACCOUNT_STATUS_UNSPECIFIED
+}
+
+func (x *StatusChange) GetTo() AccountStatus {
+	if x != nil {
+		return x.To
+	}
+	return AccountStatus_ACCOUNT_STATUS_UNSPECIFIED
+}
+
+func (x *StatusChange) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *StatusChange) GetChangedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ChangedAt
+	}
+	return nil
+}
+
+type ListAccountEventsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountId     string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAccountEventsRequest) Reset() {
+	*x = ListAccountEventsRequest{}
+	mi := &file_account_v2_account_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAccountEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAccountEventsRequest) ProtoMessage() {}
+
+func (x *ListAccountEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_account_v2_account_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAccountEventsRequest.ProtoReflect.Descriptor instead.
+func (*ListAccountEventsRequest) Descriptor() ([]byte, []int) {
+	return file_account_v2_account_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *ListAccountEventsRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+type ListAccountEventsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Events        []*StatusChange        `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAccountEventsResponse) Reset() {
+	*x = ListAccountEventsResponse{}
+	mi := &file_account_v2_account_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAccountEventsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAccountEventsResponse) ProtoMessage() {}
+
+func (x *ListAccountEventsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_account_v2_account_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAccountEventsResponse.ProtoReflect.Descriptor instead.
+func (*ListAccountEventsResponse) Descriptor() ([]byte, []int) {
+	return file_account_v2_account_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *ListAccountEventsResponse) GetEvents() []*StatusChange {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+// Transaction is a ledger entry. Every balance change produces exactly one.
+// The amount is always positive; the type tells whether it was credited or debited.
+type Transaction struct {
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	Id                    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	AccountId             string                 `protobuf:"bytes,2,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Type                  TransactionType        `protobuf:"varint,3,opt,name=type,proto3,enum=account.v2.TransactionType" json:"type,omitempty"`
+	Amount                *v11.Money             `protobuf:"bytes,4,opt,name=amount,proto3" json:"amount,omitempty"`
+	BalanceAfter          *v11.Money             `protobuf:"bytes,5,opt,name=balance_after,json=balanceAfter,proto3" json:"balance_after,omitempty"`
+	RequestId             string                 `protobuf:"bytes,6,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	CreatedAt             *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	ReversesTransactionId string                 `protobuf:"bytes,8,opt,name=reverses_transaction_id,json=reversesTransactionId,proto3" json:"reverses_transaction_id,omitempty"` // set on reversals and refunds
+	ReversedAmount        *v11.Money             `protobuf:"bytes,9,opt,name=reversed_amount,json=reversedAmount,proto3" json:"reversed_amount,omitempty"`                        // how much of this entry has been reversed so far
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *Transaction) Reset() {
+	*x = Transaction{}
+	mi := &file_account_v2_account_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Transaction) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Transaction) ProtoMessage() {}
+
+func (x *Transaction) ProtoReflect() protoreflect.Message {
+	mi := &file_account_v2_account_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Transaction.ProtoReflect.Descriptor instead.
+func (*Transaction) Descriptor() ([]byte, []int) {
+	return file_account_v2_account_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *Transaction) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Transaction) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *Transaction) GetType() TransactionType {
+	if x != nil {
+		return x.Type
+	}
+	return TransactionType_TRANSACTION_TYPE_UNSPECIFIED
+}
+
+func (x *Transaction) GetAmount() *v11.Money {
+	if x != nil {
+		return x.Amount
+	}
+	return nil
+}
+
+func (x *Transaction) GetBalanceAfter() *v11.Money {
+	if x != nil {
+		return x.BalanceAfter
+	}
+	return nil
+}
+
+func (x *Transaction) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *Transaction) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Transaction) GetReversesTransactionId() string {
+	if x != nil {
+		return x.ReversesTransactionId
+	}
+	return ""
+}
+
+func (x *Transaction) GetReversedAmount() *v11.Money {
+	if x != nil {
+		return x.ReversedAmount
+	}
+	return nil
+}
+
+type ListTransactionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountId     string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTransactionsRequest) Reset() {
+	*x = ListTransactionsRequest{}
+	mi := &file_account_v2_account_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTransactionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTransactionsRequest) ProtoMessage() {}
+
+func (x *ListTransactionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_account_v2_account_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTransactionsRequest.ProtoReflect.Descriptor instead.
+func (*ListTransactionsRequest) Descriptor() ([]byte, []int) {
+	return file_account_v2_account_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *ListTransactionsRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+type ListTransactionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Transactions  []*Transaction         `protobuf:"bytes,1,rep,name=transactions,proto3" json:"transactions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTransactionsResponse) Reset() {
+	*x = ListTransactionsResponse{}
+	mi := &file_account_v2_account_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTransactionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTransactionsResponse) ProtoMessage() {}
+
+func (x *ListTransactionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_account_v2_account_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTransactionsResponse.ProtoReflect.Descriptor instead.
+func (*ListTransactionsResponse) Descriptor() ([]byte, []int) {
+	return file_account_v2_account_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *ListTransactionsResponse) GetTransactions() []*Transaction {
+	if x != nil {
+		return x.Transactions
+	}
+	return nil
+}
+
+// ReverseTransactionRequest undoes a deposit, withdrawal, capture, fee or transfer,
+// fully or in parts, until the whole amount has been reversed. Reversing a
+// transfer reverses both of its entries.
+type ReverseTransactionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TransactionId string                 `protobuf:"bytes,1,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	Amount        *v11.Money             `protobuf:"bytes,2,opt,name=amount,proto3" json:"amount,omitempty"` // empty reverses what is left of the transaction
+	RequestId     string                 `protobuf:"bytes,3,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	Reason        string                 `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReverseTransactionRequest) Reset() {
+	*x = ReverseTransactionRequest{}
+	mi := &file_account_v2_account_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReverseTransactionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReverseTransactionRequest) ProtoMessage() {}
+
+func (x *ReverseTransactionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_account_v2_account_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReverseTransactionRequest.ProtoReflect.Descriptor instead.
+func (*ReverseTransactionRequest) Descriptor() ([]byte, []int) {
+	return file_account_v2_account_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *ReverseTransactionRequest) GetTransactionId() string {
+	if x != nil {
+		return x.TransactionId
+	}
+	return ""
+}
+
+func (x *ReverseTransactionRequest) GetAmount() *v11.Money {
+	if x != nil {
+		return x.Amount
+	}
+	return nil
+}
+
+func (x *ReverseTransactionRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *ReverseTransactionRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type ReverseTransactionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reversals     []*Transaction         `protobuf:"bytes,1,rep,name=reversals,proto3" json:"reversals,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReverseTransactionResponse) Reset() {
+	*x = ReverseTransactionResponse{}
+	mi := &file_account_v2_account_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReverseTransactionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReverseTransactionResponse) ProtoMessage() {}
+
+func (x *ReverseTransactionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_account_v2_account_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReverseTransactionResponse.ProtoReflect.Descriptor instead.
+func (*ReverseTransactionResponse) Descriptor() ([]byte, []int) {
+	return file_account_v2_account_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *ReverseTransactionResponse) GetReversals() []*Transaction {
+	if x != nil {
+		return x.Reversals
+	}
+	return nil
+}
+
+type SetOverdraftLimitRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountId     string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Limit         *v11.Money             `protobuf:"bytes,2,opt,name=limit,proto3" json:"limit,omitempty"` // zero disables the overdraft
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetOverdraftLimitRequest) Reset() {
+	*x = SetOverdraftLimitRequest{}
+	mi := &file_account_v2_account_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetOverdraftLimitRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetOverdraftLimitRequest) ProtoMessage() {}
+
+func (x *SetOverdraftLimitRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_account_v2_account_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetOverdraftLimitRequest.ProtoReflect.Descriptor instead.
+func (*SetOverdraftLimitRequest) Descriptor() ([]byte, []int) {
+	return file_account_v2_account_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *SetOverdraftLimitRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *SetOverdraftLimitRequest) GetLimit() *v11.Money {
+	if x != nil {
+		return x.Limit
+	}
+	return nil
+}
+
+type SetOverdraftLimitResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Account       *AccountInfo           `protobuf:"bytes,1,opt,name=account,proto3" json:"account,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetOverdraftLimitResponse) Reset() {
+	*x = SetOverdraftLimitResponse{}
+	mi := &file_account_v2_account_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetOverdraftLimitResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetOverdraftLimitResponse) ProtoMessage() {}
+
+func (x *SetOverdraftLimitResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_account_v2_account_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetOverdraftLimitResponse.ProtoReflect.Descriptor instead.
+func (*SetOverdraftLimitResponse) Descriptor() ([]byte, []int) {
+	return file_account_v2_account_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *SetOverdraftLimitResponse) GetAccount() *AccountInfo {
+	if x != nil {
+		return x.Account
+	}
+	return nil
+}
+
+// Review is a withdrawal or outgoing transfer held by the risk checks.
+type Review struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	AccountId     string                 `protobuf:"bytes,2,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	ToAccountId   string                 `protobuf:"bytes,3,opt,name=to_account_id,json=toAccountId,proto3" json:"to_account_id,omitempty"` // empty for withdrawals
+	Type          TransactionType        `protobuf:"varint,4,opt,name=type,proto3,enum=account.v2.TransactionType" json:"type,omitempty"`   // WITHDRAWAL or TRANSFER_OUT
+	Amount        *v11.Money             `protobuf:"bytes,5,opt,name=amount,proto3" json:"amount,omitempty"`
+	RequestId     string                 `protobuf:"bytes,6,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	Score         int32                  `protobuf:"varint,7,opt,name=score,proto3" json:"score,omitempty"`
+	Reasons       []string               `protobuf:"bytes,8,rep,name=reasons,proto3" json:"reasons,omitempty"`
+	Status        ReviewStatus           `protobuf:"varint,9,opt,name=status,proto3,enum=account.v2.ReviewStatus" json:"status,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	ResolvedAt    *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=resolved_at,json=resolvedAt,proto3" json:"resolved_at,omitempty"`
+	Resolution    string                 `protobuf:"bytes,12,opt,name=resolution,proto3" json:"resolution,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Review) Reset() {
+	*x = Review{}
+	mi := &file_account_v2_account_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Review) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Review) ProtoMessage() {}
+
+func (x *Review) ProtoReflect() protoreflect.Message {
+	mi := &file_account_v2_account_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Review.ProtoReflect.Descriptor instead.
+func (*Review) Descriptor() ([]byte, []int) {
+	return file_account_v2_account_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *Review) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Review) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *Review) GetToAccountId() string {
+	if x != nil {
+		return x.ToAccountId
+	}
+	return ""
+}
+
+func (x *Review) GetType() TransactionType {
+	if x != nil {
+		return x.Type
+	}
+	return TransactionType_TRANSACTION_TYPE_UNSPECIFIED
+}
+
+func (x *Review) GetAmount() *v11.Money {
+	if x != nil {
+		return x.Amount
+	}
+	return nil
+}
+
+func (x *Review) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *Review) GetScore() int32 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *Review) GetReasons() []string {
+	if x != nil {
+		return x.Reasons
+	}
+	return nil
+}
+
+func (x *Review) GetStatus() ReviewStatus {
+	if x != nil {
+		return x.Status
+	}
+	return ReviewStatus_REVIEW_STATUS_UNSPECIFIED
+}
+
+func (x *Review) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Review) GetResolvedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ResolvedAt
+	}
+	return nil
+}
+
+func (x *Review) GetResolution() string {
+	if x != nil {
+		return x.Resolution
+	}
+	return ""
+}
+
+type ListPendingReviewsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPendingReviewsRequest) Reset() {
+	*x = ListPendingReviewsRequest{}
+	mi := &file_account_v2_account_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPendingReviewsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPendingReviewsRequest) ProtoMessage() {}
+
+func (x *ListPendingReviewsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_account_v2_account_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPendingReviewsRequest.ProtoReflect.Descriptor instead.
+func (*ListPendingReviewsRequest) Descriptor() ([]byte, []int) {
+	return file_account_v2_account_proto_rawDescGZIP(), []int{43}
+}
+
+type ListPendingReviewsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reviews       []*Review              `protobuf:"bytes,1,rep,name=reviews,proto3" json:"reviews,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPendingReviewsResponse) Reset() {
+	*x = ListPendingReviewsResponse{}
+	mi := &file_account_v2_account_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPendingReviewsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPendingReviewsResponse) ProtoMessage() {}
+
+func (x *ListPendingReviewsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_account_v2_account_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPendingReviewsResponse.ProtoReflect.Descriptor instead.
+func (*ListPendingReviewsResponse) Descriptor() ([]byte, []int) {
+	return file_account_v2_account_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *ListPendingReviewsResponse) GetReviews() []*Review {
+	if x != nil {
+		return x.Reviews
+	}
+	return nil
+}
+
+type ResolveReviewRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ReviewId      string                 `protobuf:"bytes,1,opt,name=review_id,json=reviewId,proto3" json:"review_id,omitempty"`
+	Approve       bool                   `protobuf:"varint,2,opt,name=approve,proto3" json:"approve,omitempty"`
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResolveReviewRequest) Reset() {
+	*x = ResolveReviewRequest{}
+	mi := &file_account_v2_account_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResolveReviewRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResolveReviewRequest) ProtoMessage() {}
+
+func (x *ResolveReviewRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_account_v2_account_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResolveReviewRequest.ProtoReflect.Descriptor instead.
+func (*ResolveReviewRequest) Descriptor() ([]byte, []int) {
+	return file_account_v2_account_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *ResolveReviewRequest) GetReviewId() string {
+	if x != nil {
+		return x.ReviewId
+	}
+	return ""
+}
+
+func (x *ResolveReviewRequest) GetApprove() bool {
+	if x != nil {
+		return x.Approve
+	}
+	return false
+}
+
+func (x *ResolveReviewRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type ResolveReviewResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Review        *Review                `protobuf:"bytes,1,opt,name=review,proto3" json:"review,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResolveReviewResponse) Reset() {
+	*x = ResolveReviewResponse{}
+	mi := &file_account_v2_account_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResolveReviewResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResolveReviewResponse) ProtoMessage() {}
+
+func (x *ResolveReviewResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_account_v2_account_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResolveReviewResponse.ProtoReflect.Descriptor instead.
+func (*ResolveReviewResponse) Descriptor() ([]byte, []int) {
+	return file_account_v2_account_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *ResolveReviewResponse) GetReview() *Review {
+	if x != nil {
+		return x.Review
+	}
+	return nil
+}
+
+// Hold reserves money on an account. Active holds reduce the available balance;
+// the ledger balance only changes when the hold is captured.
+type Hold struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	AccountId      string                 `protobuf:"bytes,2,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Amount         *v11.Money             `protobuf:"bytes,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	CapturedAmount *v11.Money             `protobuf:"bytes,4,opt,name=captured_amount,json=capturedAmount,proto3" json:"captured_amount,omitempty"`
+	Status         HoldStatus             `protobuf:"varint,5,opt,name=status,proto3,enum=account.v2.HoldStatus" json:"status,omitempty"`
+	RequestId      string                 `protobuf:"bytes,6,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	Description    string                 `protobuf:"bytes,7,opt,name=description,proto3" json:"description,omitempty"`
+	CreatedAt      *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	ExpiresAt      *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	ResolvedAt     *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=resolved_at,json=resolvedAt,proto3" json:"resolved_at,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Hold) Reset() {
+	*x = Hold{}
+	mi := &file_account_v2_account_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Hold) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Hold) ProtoMessage() {}
+
+func (x *Hold) ProtoReflect() protoreflect.Message {
+	mi := &file_account_v2_account_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Hold.ProtoReflect.Descriptor instead.
+func (*Hold) Descriptor() ([]byte, []int) {
+	return file_account_v2_account_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *Hold) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Hold) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *Hold) GetAmount() *v11.Money {
+	if x != nil {
+		return x.Amount
+	}
+	return nil
+}
+
+func (x *Hold) GetCapturedAmount() *v11.Money {
+	if x != nil {
+		return x.CapturedAmount
+	}
+	return nil
+}
+
+func (x *Hold) GetStatus() HoldStatus {
+	if x != nil {
+		return x.Status
+	}
+	return HoldStatus_HOLD_STATUS_UNSPECIFIED
+}
+
+func (x *Hold) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *Hold) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Hold) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Hold) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *Hold) GetResolvedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ResolvedAt
+	}
+	return nil
+}
+
+type AuthorizeHoldRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountId     string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Amount        *v11.Money             `protobuf:"bytes,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	RequestId     string                 `protobuf:"bytes,3,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	Ttl           *durationpb.Duration   `protobuf:"bytes,4,opt,name=ttl,proto3" json:"ttl,omitempty"` // defaults to 7 days
+	Description   string                 `protobuf:"bytes,5,opt,name=description,proto3" json:"description,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuthorizeHoldRequest) Reset() {
+	*x = AuthorizeHoldRequest{}
+	mi := &file_account_v2_account_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuthorizeHoldRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuthorizeHoldRequest) ProtoMessage() {}
+
+func (x *AuthorizeHoldRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_account_v2_account_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuthorizeHoldRequest.ProtoReflect.Descriptor instead.
+func (*AuthorizeHoldRequest) Descriptor() ([]byte, []int) {
+	return file_account_v2_account_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *AuthorizeHoldRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *AuthorizeHoldRequest) GetAmount() *v11.Money {
+	if x != nil {
+		return x.Amount
+	}
+	return nil
+}
+
+func (x *AuthorizeHoldRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *AuthorizeHoldRequest) GetTtl() *durationpb.Duration {
+	if x != nil {
+		return x.Ttl
+	}
+	return nil
+}
+
+func (x *AuthorizeHoldRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+type AuthorizeHoldResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Hold          *Hold                  `protobuf:"bytes,1,opt,name=hold,proto3" json:"hold,omitempty"`
+	Account       *AccountInfo           `protobuf:"bytes,2,opt,name=account,proto3" json:"account,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuthorizeHoldResponse) Reset() {
+	*x = AuthorizeHoldResponse{}
+	mi := &file_account_v2_account_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuthorizeHoldResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuthorizeHoldResponse) ProtoMessage() {}
+
+func (x *AuthorizeHoldResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_account_v2_account_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuthorizeHoldResponse.ProtoReflect.Descriptor instead.
+func (*AuthorizeHoldResponse) Descriptor() ([]byte, []int) {
+	return file_account_v2_account_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *AuthorizeHoldResponse) GetHold() *Hold {
+	if x != nil {
+		return x.Hold
+	}
+	return nil
+}
+
+func (x *AuthorizeHoldResponse) GetAccount() *AccountInfo {
+	if x != nil {
+		return x.Account
+	}
+	return nil
+}
+
+// CaptureHoldRequest captures the hold once, fully or partially. The part that
+// is not captured is released.
+type CaptureHoldRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	HoldId        string                 `protobuf:"bytes,1,opt,name=hold_id,json=holdId,proto3" json:"hold_id,omitempty"`
+	Amount        *v11.Money             `protobuf:"bytes,2,opt,name=amount,proto3" json:"amount,omitempty"` // empty captures the full hold
+	RequestId     string                 `protobuf:"bytes,3,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CaptureHoldRequest) Reset() {
+	*x = CaptureHoldRequest{}
+	mi := &file_account_v2_account_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CaptureHoldRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CaptureHoldRequest) ProtoMessage() {}
+
+func (x *CaptureHoldRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_account_v2_account_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CaptureHoldRequest.ProtoReflect.Descriptor instead.
+func (*CaptureHoldRequest) Descriptor() ([]byte, []int) {
+	return file_account_v2_account_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *CaptureHoldRequest) GetHoldId() string {
+	if x != nil {
+		return x.HoldId
+	}
+	return ""
+}
+
+func (x *CaptureHoldRequest) GetAmount() *v11.Money {
+	if x != nil {
+		return x.Amount
+	}
+	return nil
+}
+
+func (x *CaptureHoldRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+type CaptureHoldResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Hold          *Hold                  `protobuf:"bytes,1,opt,name=hold,proto3" json:"hold,omitempty"`
+	Transaction   *Transaction           `protobuf:"bytes,2,opt,name=transaction,proto3" json:"transaction,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CaptureHoldResponse) Reset() {
+	*x = CaptureHoldResponse{}
+	mi := &file_account_v2_account_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CaptureHoldResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CaptureHoldResponse) ProtoMessage() {}
+
+func (x *CaptureHoldResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_account_v2_account_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CaptureHoldResponse.ProtoReflect.Descriptor instead.
+func (*CaptureHoldResponse) Descriptor() ([]byte, []int) {
+	return file_account_v2_account_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *CaptureHoldResponse) GetHold() *Hold {
+	if x != nil {
+		return x.Hold
+	}
+	return nil
+}
+
+func (x *CaptureHoldResponse) GetTransaction() *Transaction {
+	if x != nil {
+		return x.Transaction
+	}
+	return nil
+}
+
+type VoidHoldRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	HoldId        string                 `protobuf:"bytes,1,opt,name=hold_id,json=holdId,proto3" json:"hold_id,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VoidHoldRequest) Reset() {
+	*x = VoidHoldRequest{}
+	mi := &file_account_v2_account_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VoidHoldRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VoidHoldRequest) ProtoMessage() {}
+
+func (x *VoidHoldRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_account_v2_account_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VoidHoldRequest.ProtoReflect.Descriptor instead.
+func (*VoidHoldRequest) Descriptor() ([]byte, []int) {
+	return file_account_v2_account_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *VoidHoldRequest) GetHoldId() string {
+	if x != nil {
+		return x.HoldId
+	}
+	return ""
+}
+
+func (x *VoidHoldRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type VoidHoldResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Hold          *Hold                  `protobuf:"bytes,1,opt,name=hold,proto3" json:"hold,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VoidHoldResponse) Reset() {
+	*x = VoidHoldResponse{}
+	mi := &file_account_v2_account_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VoidHoldResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VoidHoldResponse) ProtoMessage() {}
+
+func (x *VoidHoldResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_account_v2_account_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VoidHoldResponse.ProtoReflect.Descriptor instead.
+func (*VoidHoldResponse) Descriptor() ([]byte, []int) {
+	return file_account_v2_account_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *VoidHoldResponse) GetHold() *Hold {
+	if x != nil {
+		return x.Hold
+	}
+	return nil
+}
+
+type ListHoldsRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	AccountId       string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	IncludeInactive bool                   `protobuf:"varint,2,opt,name=include_inactive,json=includeInactive,proto3" json:"include_inactive,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ListHoldsRequest) Reset() {
+	*x = ListHoldsRequest{}
+	mi := &file_account_v2_account_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListHoldsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListHoldsRequest) ProtoMessage() {}
+
+func (x *ListHoldsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_account_v2_account_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListHoldsRequest.ProtoReflect.Descriptor instead.
+func (*ListHoldsRequest) Descriptor() ([]byte, []int) {
+	return file_account_v2_account_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *ListHoldsRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *ListHoldsRequest) GetIncludeInactive() bool {
+	if x != nil {
+		return x.IncludeInactive
+	}
+	return false
+}
+
+type ListHoldsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Holds         []*Hold                `protobuf:"bytes,1,rep,name=holds,proto3" json:"holds,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListHoldsResponse) Reset() {
+	*x = ListHoldsResponse{}
+	mi := &file_account_v2_account_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListHoldsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListHoldsResponse) ProtoMessage() {}
+
+func (x *ListHoldsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_account_v2_account_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListHoldsResponse.ProtoReflect.Descriptor instead.
+func (*ListHoldsResponse) Descriptor() ([]byte, []int) {
+	return file_account_v2_account_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *ListHoldsResponse) GetHolds() []*Hold {
+	if x != nil {
+		return x.Holds
+	}
+	return nil
+}
+
+// RestoreAccount recreates an exported account with its ledger, to seed a server
+// or move data between servers. The entries are taken as they are: no limits,
+// risk screening, fees or interest apply, and restored transfer entries are not
+// linked to each other. The account and its entries get new ids. If the owner
+// already has an account restored from account.source_id, or with that id, it
+// is returned as it is with existing set and nothing is restored.
+type RestoreAccountRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Account       *AccountInfo           `protobuf:"bytes,1,opt,name=account,proto3" json:"account,omitempty"`           // owner_id, type, status, balance, overdraft_limit, opened_at, matures_at and source_id are kept
+	Transactions  []*Transaction         `protobuf:"bytes,2,rep,name=transactions,proto3" json:"transactions,omitempty"` // oldest first; the first starts from a zero balance
+	RequestId     string                 `protobuf:"bytes,3,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	DryRun        bool                   `protobuf:"varint,4,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"` // only check the request; nothing is restored or saved for the request id
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreAccountRequest) Reset() {
+	*x = RestoreAccountRequest{}
+	mi := &file_account_v2_account_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreAccountRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreAccountRequest) ProtoMessage() {}
+
+func (x *RestoreAccountRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_account_v2_account_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreAccountRequest.ProtoReflect.Descriptor instead.
+func (*RestoreAccountRequest) Descriptor() ([]byte, []int) {
+	return file_account_v2_account_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *RestoreAccountRequest) GetAccount() *AccountInfo {
+	if x != nil {
+		return x.Account
+	}
+	return nil
+}
+
+func (x *RestoreAccountRequest) GetTransactions() []*Transaction {
+	if x != nil {
+		return x.Transactions
+	}
+	return nil
+}
+
+func (x *RestoreAccountRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *RestoreAccountRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+type RestoreAccountResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Account       *AccountInfo           `protobuf:"bytes,1,opt,name=account,proto3" json:"account,omitempty"`
+	Transactions  []*Transaction         `protobuf:"bytes,2,rep,name=transactions,proto3" json:"transactions,omitempty"` // with their new ids, in the order of the request
+	Existing      bool                   `protobuf:"varint,3,opt,name=existing,proto3" json:"existing,omitempty"`        // the account was restored before, see source_id
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreAccountResponse) Reset() {
+	*x = RestoreAccountResponse{}
+	mi := &file_account_v2_account_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreAccountResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreAccountResponse) ProtoMessage() {}
+
+func (x *RestoreAccountResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_account_v2_account_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreAccountResponse.ProtoReflect.Descriptor instead.
+func (*RestoreAccountResponse) Descriptor() ([]byte, []int) {
+	return file_account_v2_account_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *RestoreAccountResponse) GetAccount() *AccountInfo {
+	if x != nil {
+		return x.Account
+	}
+	return nil
+}
+
+func (x *RestoreAccountResponse) GetTransactions() []*Transaction {
+	if x != nil {
+		return x.Transactions
+	}
+	return nil
+}
+
+func (x *RestoreAccountResponse) GetExisting() bool {
+	if x != nil {
+		return x.Existing
+	}
+	return false
+}
+
+var File_account_v2_account_proto protoreflect.FileDescriptor
+
+const file_account_v2_account_proto_rawDesc = "" +
+	"\n" +
+	"\x18account/v2/account.proto\x12\n" +
+	"account.v2\x1a\x12user/v1/user.proto\x1a\x15common/v1/money.proto\x1a\x1egoogle/protobuf/duration.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\x85\x05\n" +
+	"\vAccountInfo\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12'\n" +
+	"\x05owner\x18\x02 \x01(\v2\x11.user.v1.UserInfoR\x05owner\x12*\n" +
+	"\abalance\x18\x03 \x01(\v2\x10.common.v1.MoneyR\abalance\x121\n" +
+	"\x06status\x18\x04 \x01(\x0e2\x19.account.v2.AccountStatusR\x06status\x12+\n" +
+	"\x04type\x18\x05 \x01(\x0e2\x17.account.v2.AccountTypeR\x04type\x127\n" +
+	"\topened_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\bopenedAt\x129\n" +
+	"\n" +
+	"matures_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tmaturesAt\x12;\n" +
+	"\x10accrued_interest\x18\b \x01(\v2\x10.common.v1.MoneyR\x0faccruedInterest\x129\n" +
+	"\x0foverdraft_limit\x18\t \x01(\v2\x10.common.v1.MoneyR\x0eoverdraftLimit\x12=\n" +
+	"\x11available_balance\x18\n" +
+	" \x01(\v2\x10.common.v1.MoneyR\x10availableBalance\x12N\n" +
+	"\x1aaccrued_overdraft_interest\x18\v \x01(\v2\x10.common.v1.MoneyR\x18accruedOverdraftInterest\x12\x19\n" +
+	"\bowner_id\x18\f \x01(\tR\aownerId\x12\x1b\n" +
+	"\tsource_id\x18\r \x01(\tR\bsourceId\"G\n" +
+	"\x12GetAccountResponse\x121\n" +
+	"\aaccount\x18\x01 \x01(\v2\x17.account.v2.AccountInfoR\aaccount\"#\n" +
+	"\x11GetAccountRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\xd7\x01\n" +
+	"\x14CreateAccountRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x129\n" +
+	"\x0finitial_balance\x18\x02 \x01(\v2\x10.common.v1.MoneyR\x0einitialBalance\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x03 \x01(\tR\trequestId\x12+\n" +
+	"\x04type\x18\x04 \x01(\x0e2\x17.account.v2.AccountTypeR\x04type\x12\x1f\n" +
+	"\vterm_months\x18\x05 \x01(\x05R\n" +
+	"termMonths\"J\n" +
+	"\x15CreateAccountResponse\x121\n" +
+	"\aaccount\x18\x01 \x01(\v2\x17.account.v2.AccountInfoR\aaccount\"U\n" +
+	"\x13ListAccountsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12%\n" +
+	"\x0einclude_closed\x18\x02 \x01(\bR\rincludeClosed\"K\n" +
+	"\x14ListAccountsResponse\x123\n" +
+	"\baccounts\x18\x01 \x03(\v2\x17.account.v2.AccountInfoR\baccounts\"5\n" +
+	"\x14DeleteAccountRequest\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\"6\n" +
+	"\x15DeleteAccountResponse\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\"x\n" +
+	"\x0eDepositRequest\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\x12(\n" +
+	"\x06amount\x18\x02 \x01(\v2\x10.common.v1.MoneyR\x06amount\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x03 \x01(\tR\trequestId\"D\n" +
+	"\x0fDepositResponse\x121\n" +
+	"\aaccount\x18\x01 \x01(\v2\x17.account.v2.AccountInfoR\aaccount\"y\n" +
+	"\x0fWithdrawRequest\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\x12(\n" +
+	"\x06amount\x18\x02 \x01(\v2\x10.common.v1.MoneyR\x06amount\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x03 \x01(\tR\trequestId\"q\n" +
+	"\x10WithdrawResponse\x121\n" +
+	"\aaccount\x18\x01 \x01(\v2\x17.account.v2.AccountInfoR\aaccount\x12*\n" +
+	"\x06review\x18\x02 \x01(\v2\x12.account.v2.ReviewR\x06review\":\n" +
+	"\n" +
+	"BatchError\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\x05R\x04code\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"\x7f\n" +
+	"\x1aBatchCreateAccountsRequest\x126\n" +
+	"\x05items\x18\x01 \x03(\v2 .account.v2.CreateAccountRequestR\x05items\x12)\n" +
+	"\x04mode\x18\x02 \x01(\x0e2\x15.account.v2.BatchModeR\x04mode\"^\n" +
+	"\x1bBatchCreateAccountsResponse\x12?\n" +
+	"\aresults\x18\x01 \x03(\v2%.account.v2.BatchCreateAccountsResultR\aresults\"\x92\x01\n" +
+	"\x19BatchCreateAccountsResult\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\x05R\x05index\x121\n" +
+	"\aaccount\x18\x02 \x01(\v2\x17.account.v2.AccountInfoR\aaccount\x12,\n" +
+	"\x05error\x18\x03 \x01(\v2\x16.account.v2.BatchErrorR\x05error\"\xba\x01\n" +
+	"\x0fBulkPostRequest\x12)\n" +
+	"\x04mode\x18\x01 \x01(\x0e2\x15.account.v2.BatchModeR\x04mode\x126\n" +
+	"\adeposit\x18\x02 \x01(\v2\x1a.account.v2.DepositRequestH\x00R\adeposit\x129\n" +
+	"\bwithdraw\x18\x03 \x01(\v2\x1b.account.v2.WithdrawRequestH\x00R\bwithdrawB\t\n" +
+	"\aposting\"\x8e\x01\n" +
+	"\x10BulkPostResponse\x124\n" +
+	"\aresults\x18\x01 \x03(\v2\x1a.account.v2.BulkPostResultR\aresults\x12\x18\n" +
+	"\aapplied\x18\x02 \x01(\x05R\aapplied\x12\x12\n" +
+	"\x04held\x18\x03 \x01(\x05R\x04held\x12\x16\n" +
+	"\x06failed\x18\x04 \x01(\x05R\x06failed\"\xb3\x01\n" +
+	"\x0eBulkPostResult\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\x05R\x05index\x121\n" +
+	"\aaccount\x18\x02 \x01(\v2\x17.account.v2.AccountInfoR\aaccount\x12*\n" +
+	"\x06review\x18\x03 \x01(\v2\x12.account.v2.ReviewR\x06review\x12,\n" +
+	"\x05error\x18\x04 \x01(\v2\x16.account.v2.BatchErrorR\x05error\"\xa6\x01\n" +
+	"\x0fTransferRequest\x12&\n" +
+	"\x0ffrom_account_id\x18\x01 \x01(\tR\rfromAccountId\x12\"\n" +
+	"\rto_account_id\x18\x02 \x01(\tR\vtoAccountId\x12(\n" +
+	"\x06amount\x18\x03 \x01(\v2\x10.common.v1.MoneyR\x06amount\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x04 \x01(\tR\trequestId\"\x9e\x01\n" +
+	"\x10TransferResponse\x12-\n" +
+	"\x05debit\x18\x01 \x01(\v2\x17.account.v2.TransactionR\x05debit\x12/\n" +
+	"\x06credit\x18\x02 \x01(\v2\x17.account.v2.TransactionR\x06credit\x12*\n" +
+	"\x06review\x18\x03 \x01(\v2\x12.account.v2.ReviewR\x06review\"3\n" +
+	"\x18CloseUserAccountsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"I\n" +
+	"\x19CloseUserAccountsResponse\x12,\n" +
+	"\x12closed_account_ids\x18\x01 \x03(\tR\x10closedAccountIds\";\n" +
+	"\x10SyncOwnerRequest\x12'\n" +
+	"\x05owner\x18\x01 \x01(\v2\x11.user.v1.UserInfoR\x05owner\"C\n" +
+	"\x11SyncOwnerResponse\x12.\n" +
+	"\x13updated_account_ids\x18\x01 \x03(\tR\x11updatedAccountIds\"M\n" +
+	"\x14FreezeAccountRequest\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\"J\n" +
+	"\x15FreezeAccountResponse\x121\n" +
+	"\aaccount\x18\x01 \x01(\v2\x17.account.v2.AccountInfoR\aaccount\"O\n" +
+	"\x16UnfreezeAccountRequest\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\"L\n" +
+	"\x17UnfreezeAccountResponse\x121\n" +
+	"\aaccount\x18\x01 \x01(\v2\x17.account.v2.AccountInfoR\aaccount\"L\n" +
+	"\x13CloseAccountRequest\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\"I\n" +
+	"\x14CloseAccountResponse\x121\n" +
+	"\aaccount\x18\x01 \x01(\v2\x17.account.v2.AccountInfoR\aaccount\"\xbb\x01\n" +
+	"\fStatusChange\x12-\n" +
+	"\x04from\x18\x01 \x01(\x0e2\x19.account.v2.AccountStatusR\x04from\x12)\n" +
+	"\x02to\x18\x02 \x01(\x0e2\x19.account.v2.AccountStatusR\x02to\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\x129\n" +
+	"\n" +
+	"changed_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tchangedAt\"9\n" +
+	"\x18ListAccountEventsRequest\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\"M\n" +
+	"\x19ListAccountEventsResponse\x120\n" +
+	"\x06events\x18\x01 \x03(\v2\x18.account.v2.StatusChangeR\x06events\"\x9b\x03\n" +
+	"\vTransaction\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x02 \x01(\tR\taccountId\x12/\n" +
+	"\x04type\x18\x03 \x01(\x0e2\x1b.account.v2.TransactionTypeR\x04type\x12(\n" +
+	"\x06amount\x18\x04 \x01(\v2\x10.common.v1.MoneyR\x06amount\x125\n" +
+	"\rbalance_after\x18\x05 \x01(\v2\x10.common.v1.MoneyR\fbalanceAfter\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x06 \x01(\tR\trequestId\x129\n" +
+	"\n" +
+	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x126\n" +
+	"\x17reverses_transaction_id\x18\b \x01(\tR\x15reversesTransactionId\x129\n" +
+	"\x0freversed_amount\x18\t \x01(\v2\x10.common.v1.MoneyR\x0ereversedAmount\"8\n" +
+	"\x17ListTransactionsRequest\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\"W\n" +
+	"\x18ListTransactionsResponse\x12;\n" +
+	"\ftransactions\x18\x01 \x03(\v2\x17.account.v2.TransactionR\ftransactions\"\xa3\x01\n" +
+	"\x19ReverseTransactionRequest\x12%\n" +
+	"\x0etransaction_id\x18\x01 \x01(\tR\rtransactionId\x12(\n" +
+	"\x06amount\x18\x02 \x01(\v2\x10.common.v1.MoneyR\x06amount\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x03 \x01(\tR\trequestId\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reason\"S\n" +
+	"\x1aReverseTransactionResponse\x125\n" +
+	"\treversals\x18\x01 \x03(\v2\x17.account.v2.TransactionR\treversals\"a\n" +
+	"\x18SetOverdraftLimitRequest\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\x12&\n" +
+	"\x05limit\x18\x02 \x01(\v2\x10.common.v1.MoneyR\x05limit\"N\n" +
+	"\x19SetOverdraftLimitResponse\x121\n" +
+	"\aaccount\x18\x01 \x01(\v2\x17.account.v2.AccountInfoR\aaccount\"\xcf\x03\n" +
+	"\x06Review\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x02 \x01(\tR\taccountId\x12\"\n" +
+	"\rto_account_id\x18\x03 \x01(\tR\vtoAccountId\x12/\n" +
+	"\x04type\x18\x04 \x01(\x0e2\x1b.account.v2.TransactionTypeR\x04type\x12(\n" +
+	"\x06amount\x18\x05 \x01(\v2\x10.common.v1.MoneyR\x06amount\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x06 \x01(\tR\trequestId\x12\x14\n" +
+	"\x05score\x18\a \x01(\x05R\x05score\x12\x18\n" +
+	"\areasons\x18\b \x03(\tR\areasons\x120\n" +
+	"\x06status\x18\t \x01(\x0e2\x18.account.v2.ReviewStatusR\x06status\x129\n" +
+	"\n" +
+	"created_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12;\n" +
+	"\vresolved_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"resolvedAt\x12\x1e\n" +
+	"\n" +
+	"resolution\x18\f \x01(\tR\n" +
+	"resolution\"\x1b\n" +
+	"\x19ListPendingReviewsRequest\"J\n" +
+	"\x1aListPendingReviewsResponse\x12,\n" +
+	"\areviews\x18\x01 \x03(\v2\x12.account.v2.ReviewR\areviews\"e\n" +
+	"\x14ResolveReviewRequest\x12\x1b\n" +
+	"\treview_id\x18\x01 \x01(\tR\breviewId\x12\x18\n" +
+	"\aapprove\x18\x02 \x01(\bR\aapprove\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\"C\n" +
+	"\x15ResolveReviewResponse\x12*\n" +
+	"\x06review\x18\x01 \x01(\v2\x12.account.v2.ReviewR\x06review\"\xbe\x03\n" +
+	"\x04Hold\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x02 \x01(\tR\taccountId\x12(\n" +
+	"\x06amount\x18\x03 \x01(\v2\x10.common.v1.MoneyR\x06amount\x129\n" +
+	"\x0fcaptured_amount\x18\x04 \x01(\v2\x10.common.v1.MoneyR\x0ecapturedAmount\x12.\n" +
+	"\x06status\x18\x05 \x01(\x0e2\x16.account.v2.HoldStatusR\x06status\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x06 \x01(\tR\trequestId\x12 \n" +
+	"\vdescription\x18\a \x01(\tR\vdescription\x129\n" +
+	"\n" +
+	"created_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"expires_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x12;\n" +
+	"\vresolved_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"resolvedAt\"\xcd\x01\n" +
+	"\x14AuthorizeHoldRequest\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\x12(\n" +
+	"\x06amount\x18\x02 \x01(\v2\x10.common.v1.MoneyR\x06amount\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x03 \x01(\tR\trequestId\x12+\n" +
+	"\x03ttl\x18\x04 \x01(\v2\x19.google.protobuf.DurationR\x03ttl\x12 \n" +
+	"\vdescription\x18\x05 \x01(\tR\vdescription\"p\n" +
+	"\x15AuthorizeHoldResponse\x12$\n" +
+	"\x04hold\x18\x01 \x01(\v2\x10.account.v2.HoldR\x04hold\x121\n" +
+	"\aaccount\x18\x02 \x01(\v2\x17.account.v2.AccountInfoR\aaccount\"v\n" +
+	"\x12CaptureHoldRequest\x12\x17\n" +
+	"\ahold_id\x18\x01 \x01(\tR\x06holdId\x12(\n" +
+	"\x06amount\x18\x02 \x01(\v2\x10.common.v1.MoneyR\x06amount\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x03 \x01(\tR\trequestId\"v\n" +
+	"\x13CaptureHoldResponse\x12$\n" +
+	"\x04hold\x18\x01 \x01(\v2\x10.account.v2.HoldR\x04hold\x129\n" +
+	"\vtransaction\x18\x02 \x01(\v2\x17.account.v2.TransactionR\vtransaction\"B\n" +
+	"\x0fVoidHoldRequest\x12\x17\n" +
+	"\ahold_id\x18\x01 \x01(\tR\x06holdId\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\"8\n" +
+	"\x10VoidHoldResponse\x12$\n" +
+	"\x04hold\x18\x01 \x01(\v2\x10.account.v2.HoldR\x04hold\"\\\n" +
+	"\x10ListHoldsRequest\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\x12)\n" +
+	"\x10include_inactive\x18\x02 \x01(\bR\x0fincludeInactive\";\n" +
+	"\x11ListHoldsResponse\x12&\n" +
+	"\x05holds\x18\x01 \x03(\v2\x10.account.v2.HoldR\x05holds\"\xbf\x01\n" +
+	"\x15RestoreAccountRequest\x121\n" +
+	"\aaccount\x18\x01 \x01(\v2\x17.account.v2.AccountInfoR\aaccount\x12;\n" +
+	"\ftransactions\x18\x02 \x03(\v2\x17.account.v2.TransactionR\ftransactions\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x03 \x01(\tR\trequestId\x12\x17\n" +
+	"\adry_run\x18\x04 \x01(\bR\x06dryRun\"\xa4\x01\n" +
+	"\x16RestoreAccountResponse\x121\n" +
+	"\aaccount\x18\x01 \x01(\v2\x17.account.v2.AccountInfoR\aaccount\x12;\n" +
+	"\ftransactions\x18\x02 \x03(\v2\x17.account.v2.TransactionR\ftransactions\x12\x1a\n" +
+	"\bexisting\x18\x03 \x01(\bR\bexisting*\x7f\n" +
+	"\vAccountType\x12\x1c\n" +
+	"\x18ACCOUNT_TYPE_UNSPECIFIED\x10\x00\x12\x19\n" +
+	"\x15ACCOUNT_TYPE_CHECKING\x10\x01\x12\x18\n" +
+	"\x14ACCOUNT_TYPE_SAVINGS\x10\x02\x12\x1d\n" +
+	"\x19ACCOUNT_TYPE_TERM_DEPOSIT\x10\x03*\x9c\x01\n" +
+	"\rAccountStatus\x12\x1e\n" +
+	"\x1aACCOUNT_STATUS_UNSPECIFIED\x10\x00\x12\x19\n" +
+	"\x15ACCOUNT_STATUS_ACTIVE\x10\x01\x12\x19\n" +
+	"\x15ACCOUNT_STATUS_CLOSED\x10\x02\x12\x1a\n" +
+	"\x16ACCOUNT_STATUS_PENDING\x10\x03\x12\x19\n" +
+	"\x15ACCOUNT_STATUS_FROZEN\x10\x04*b\n" +
+	"\tBatchMode\x12\x1a\n" +
+	"\x16BATCH_MODE_UNSPECIFIED\x10\x00\x12\x1a\n" +
+	"\x16BATCH_MODE_BEST_EFFORT\x10\x01\x12\x1d\n" +
+	"\x19BATCH_MODE_ALL_OR_NOTHING\x10\x02*\xf3\x02\n" +
+	"\x0fTransactionType\x12 \n" +
+	"\x1cTRANSACTION_TYPE_UNSPECIFIED\x10\x00\x12\x1c\n" +
+	"\x18TRANSACTION_TYPE_DEPOSIT\x10\x01\x12\x1f\n" +
+	"\x1bTRANSACTION_TYPE_WITHDRAWAL\x10\x02\x12\x1d\n" +
+	"\x19TRANSACTION_TYPE_INTEREST\x10\x03\x12'\n" +
+	"#TRANSACTION_TYPE_OVERDRAFT_INTEREST\x10\x04\x12\x18\n" +
+	"\x14TRANSACTION_TYPE_FEE\x10\x05\x12!\n" +
+	"\x1dTRANSACTION_TYPE_TRANSFER_OUT\x10\x06\x12 \n" +
+	"\x1cTRANSACTION_TYPE_TRANSFER_IN\x10\a\x12\x1c\n" +
+	"\x18TRANSACTION_TYPE_CAPTURE\x10\b\x12\x1d\n" +
+	"\x19TRANSACTION_TYPE_REVERSAL\x10\t\x12\x1b\n" +
+	"\x17TRANSACTION_TYPE_REFUND\x10\n" +
+	"*\x80\x01\n" +
+	"\fReviewStatus\x12\x1d\n" +
+	"\x19REVIEW_STATUS_UNSPECIFIED\x10\x00\x12\x19\n" +
+	"\x15REVIEW_STATUS_PENDING\x10\x01\x12\x1a\n" +
+	"\x16REVIEW_STATUS_APPROVED\x10\x02\x12\x1a\n" +
+	"\x16REVIEW_STATUS_REJECTED\x10\x03*\x8c\x01\n" +
+	"\n" +
+	"HoldStatus\x12\x1b\n" +
+	"\x17HOLD_STATUS_UNSPECIFIED\x10\x00\x12\x16\n" +
+	"\x12HOLD_STATUS_ACTIVE\x10\x01\x12\x18\n" +
+	"\x14HOLD_STATUS_CAPTURED\x10\x02\x12\x16\n" +
+	"\x12HOLD_STATUS_VOIDED\x10\x03\x12\x17\n" +
+	"\x13HOLD_STATUS_EXPIRED\x10\x042\xdc\x10\n" +
+	"\aAccount\x12T\n" +
+	"\rCreateAccount\x12 .account.v2.CreateAccountRequest\x1a!.account.v2.CreateAccountResponse\x12K\n" +
+	"\n" +
+	"GetAccount\x12\x1d.account.v2.GetAccountRequest\x1a\x1e.account.v2.GetAccountResponse\x12Q\n" +
+	"\fListAccounts\x12\x1f.account.v2.ListAccountsRequest\x1a .account.v2.ListAccountsResponse\x12T\n" +
+	"\rDeleteAccount\x12 .account.v2.DeleteAccountRequest\x1a!.account.v2.DeleteAccountResponse\x12B\n" +
+	"\aDeposit\x12\x1a.account.v2.DepositRequest\x1a\x1b.account.v2.DepositResponse\x12E\n" +
+	"\bWithdraw\x12\x1b.account.v2.WithdrawRequest\x1a\x1c.account.v2.WithdrawResponse\x12E\n" +
+	"\bTransfer\x12\x1b.account.v2.TransferRequest\x1a\x1c.account.v2.TransferResponse\x12f\n" +
+	"\x13BatchCreateAccounts\x12&.account.v2.BatchCreateAccountsRequest\x1a'.account.v2.BatchCreateAccountsResponse\x12G\n" +
+	"\bBulkPost\x12\x1b.account.v2.BulkPostRequest\x1a\x1c.account.v2.BulkPostResponse(\x01\x12T\n" +
+	"\rAuthorizeHold\x12 .account.v2.AuthorizeHoldRequest\x1a!.account.v2.AuthorizeHoldResponse\x12N\n" +
+	"\vCaptureHold\x12\x1e.account.v2.CaptureHoldRequest\x1a\x1f.account.v2.CaptureHoldResponse\x12E\n" +
+	"\bVoidHold\x12\x1b.account.v2.VoidHoldRequest\x1a\x1c.account.v2.VoidHoldResponse\x12H\n" +
+	"\tListHolds\x12\x1c.account.v2.ListHoldsRequest\x1a\x1d.account.v2.ListHoldsResponse\x12`\n" +
+	"\x11CloseUserAccounts\x12$.account.v2.CloseUserAccountsRequest\x1a%.account.v2.CloseUserAccountsResponse\x12H\n" +
+	"\tSyncOwner\x12\x1c.account.v2.SyncOwnerRequest\x1a\x1d.account.v2.SyncOwnerResponse\x12T\n" +
+	"\rFreezeAccount\x12 .account.v2.FreezeAccountRequest\x1a!.account.v2.FreezeAccountResponse\x12Z\n" +
+	"\x0fUnfreezeAccount\x12\".account.v2.UnfreezeAccountRequest\x1a#.account.v2.UnfreezeAccountResponse\x12Q\n" +
+	"\fCloseAccount\x12\x1f.account.v2.CloseAccountRequest\x1a .account.v2.CloseAccountResponse\x12`\n" +
+	"\x11ListAccountEvents\x12$.account.v2.ListAccountEventsRequest\x1a%.account.v2.ListAccountEventsResponse\x12]\n" +
+	"\x10ListTransactions\x12#.account.v2.ListTransactionsRequest\x1a$.account.v2.ListTransactionsResponse\x12c\n" +
+	"\x12ReverseTransaction\x12%.account.v2.ReverseTransactionRequest\x1a&.account.v2.ReverseTransactionResponse\x12`\n" +
+	"\x11SetOverdraftLimit\x12$.account.v2.SetOverdraftLimitRequest\x1a%.account.v2.SetOverdraftLimitResponse\x12c\n" +
+	"\x12ListPendingReviews\x12%.account.v2.ListPendingReviewsRequest\x1a&.account.v2.ListPendingReviewsResponse\x12T\n" +
+	"\rResolveReview\x12 .account.v2.ResolveReviewRequest\x1a!.account.v2.ResolveReviewResponse\x12W\n" +
+	"\x0eRestoreAccount\x12!.account.v2.RestoreAccountRequest\x1a\".account.v2.RestoreAccountResponseB=Z;github.com/galadeat/bank-sim/api/proto/account/v2;accountv2b\x06proto3"
+
+var (
+	file_account_v2_account_proto_rawDescOnce sync.Once
+	file_account_v2_account_proto_rawDescData []byte
+)
 
 func file_account_v2_account_proto_rawDescGZIP() []byte {
 	file_account_v2_account_proto_rawDescOnce.Do(func() {
@@ -727,52 +4069,214 @@ func file_account_v2_account_proto_rawDescGZIP() []byte {
 	return file_account_v2_account_proto_rawDescData
 }
 
-var file_account_v2_account_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_account_v2_account_proto_enumTypes = make([]protoimpl.EnumInfo, 6)
+var file_account_v2_account_proto_msgTypes = make([]protoimpl.MessageInfo, 58)
 var file_account_v2_account_proto_goTypes = []any{
-	(*AccountInfo)(nil),           // 0: account.v2.AccountInfo
-	(*GetAccountResponse)(nil),    // 1: account.v2.GetAccountResponse
-	(*GetAccountRequest)(nil),     // 2: account.v2.GetAccountRequest
-	(*CreateAccountRequest)(nil),  // 3: account.v2.CreateAccountRequest
-	(*CreateAccountResponse)(nil), // 4: account.v2.CreateAccountResponse
-	(*ListAccountsRequest)(nil),   // 5: account.v2.ListAccountsRequest
-	(*ListAccountsResponse)(nil),  // 6: account.v2.ListAccountsResponse
-	(*DeleteAccountRequest)(nil),  // 7: account.v2.DeleteAccountRequest
-	(*DeleteAccountResponse)(nil), // 8: account.v2.DeleteAccountResponse
-	(*DepositRequest)(nil),        // 9: account.v2.DepositRequest
-	(*DepositResponse)(nil),       // 10: account.v2.DepositResponse
-	(*WithdrawRequest)(nil),       // 11: account.v2.WithdrawRequest
-	(*WithdrawResponse)(nil),      // 12: account.v2.WithdrawResponse
-	(*v1.UserInfo)(nil),           // 13: user.v1.UserInfo
-	(*v11.Money)(nil),             // 14: common.v1.Money
+	(AccountType)(0),                    // 0: account.v2.AccountType
+	(AccountStatus)(0),                  // 1: account.v2.AccountStatus
+	(BatchMode)(0),                      // 2: account.v2.BatchMode
+	(TransactionType)(0),                // 3: account.v2.TransactionType
+	(ReviewStatus)(0),                   // 4: account.v2.ReviewStatus
+	(HoldStatus)(0),                     // 5: account.v2.HoldStatus
+	(*AccountInfo)(nil),                 // 6: account.v2.AccountInfo
+	(*GetAccountResponse)(nil),          // 7: account.v2.GetAccountResponse
+	(*GetAccountRequest)(nil),           // 8: account.v2.GetAccountRequest
+	(*CreateAccountRequest)(nil),        // 9: account.v2.CreateAccountRequest
+	(*CreateAccountResponse)(nil),       // 10: account.v2.CreateAccountResponse
+	(*ListAccountsRequest)(nil),         // 11: account.v2.ListAccountsRequest
+	(*ListAccountsResponse)(nil),        // 12: account.v2.ListAccountsResponse
+	(*DeleteAccountRequest)(nil),        // 13: account.v2.DeleteAccountRequest
+	(*DeleteAccountResponse)(nil),       // 14: account.v2.DeleteAccountResponse
+	(*DepositRequest)(nil),              // 15: account.v2.DepositRequest
+	(*DepositResponse)(nil),             // 16: account.v2.DepositResponse
+	(*WithdrawRequest)(nil),             // 17: account.v2.WithdrawRequest
+	(*WithdrawResponse)(nil),            // 18: account.v2.WithdrawResponse
+	(*BatchError)(nil),                  // 19: account.v2.BatchError
+	(*BatchCreateAccountsRequest)(nil),  // 20: account.v2.BatchCreateAccountsRequest
+	(*BatchCreateAccountsResponse)(nil), // 21: account.v2.BatchCreateAccountsResponse
+	(*BatchCreateAccountsResult)(nil),   // 22: account.v2.BatchCreateAccountsResult
+	(*BulkPostRequest)(nil),             // 23: account.v2.BulkPostRequest
+	(*BulkPostResponse)(nil),            // 24: account.v2.BulkPostResponse
+	(*BulkPostResult)(nil),              // 25: account.v2.BulkPostResult
+	(*TransferRequest)(nil),             // 26: account.v2.TransferRequest
+	(*TransferResponse)(nil),            // 27: account.v2.TransferResponse
+	(*CloseUserAccountsRequest)(nil),    // 28: account.v2.CloseUserAccountsRequest
+	(*CloseUserAccountsResponse)(nil),   // 29: account.v2.CloseUserAccountsResponse
+	(*SyncOwnerRequest)(nil),            // 30: account.v2.SyncOwnerRequest
+	(*SyncOwnerResponse)(nil),           // 31: account.v2.SyncOwnerResponse
+	(*FreezeAccountRequest)(nil),        // 32: account.v2.FreezeAccountRequest
+	(*FreezeAccountResponse)(nil),       // 33: account.v2.FreezeAccountResponse
+	(*UnfreezeAccountRequest)(nil),      // 34: account.v2.UnfreezeAccountRequest
+	(*UnfreezeAccountResponse)(nil),     // 35: account.v2.UnfreezeAccountResponse
+	(*CloseAccountRequest)(nil),         // 36: account.v2.CloseAccountRequest
+	(*CloseAccountResponse)(nil),        // 37: account.v2.CloseAccountResponse
+	(*StatusChange)(nil),                // 38: account.v2.StatusChange
+	(*ListAccountEventsRequest)(nil),    // 39: account.v2.ListAccountEventsRequest
+	(*ListAccountEventsResponse)(nil),   // 40: account.v2.ListAccountEventsResponse
+	(*Transaction)(nil),                 // 41: account.v2.Transaction
+	(*ListTransactionsRequest)(nil),     // 42: account.v2.ListTransactionsRequest
+	(*ListTransactionsResponse)(nil),    // 43: account.v2.ListTransactionsResponse
+	(*ReverseTransactionRequest)(nil),   // 44: account.v2.ReverseTransactionRequest
+	(*ReverseTransactionResponse)(nil),  // 45: account.v2.ReverseTransactionResponse
+	(*SetOverdraftLimitRequest)(nil),    // 46: account.v2.SetOverdraftLimitRequest
+	(*SetOverdraftLimitResponse)(nil),   // 47: account.v2.SetOverdraftLimitResponse
+	(*Review)(nil),                      // 48: account.v2.Review
+	(*ListPendingReviewsRequest)(nil),   // 49: account.v2.ListPendingReviewsRequest
+	(*ListPendingReviewsResponse)(nil),  // 50: account.v2.ListPendingReviewsResponse
+	(*ResolveReviewRequest)(nil),        // 51: account.v2.ResolveReviewRequest
+	(*ResolveReviewResponse)(nil),       // 52: account.v2.ResolveReviewResponse
+	(*Hold)(nil),                        // 53: account.v2.Hold
+	(*AuthorizeHoldRequest)(nil),        // 54: account.v2.AuthorizeHoldRequest
+	(*AuthorizeHoldResponse)(nil),       // 55: account.v2.AuthorizeHoldResponse
+	(*CaptureHoldRequest)(nil),          // 56: account.v2.CaptureHoldRequest
+	(*CaptureHoldResponse)(nil),         // 57: account.v2.CaptureHoldResponse
+	(*VoidHoldRequest)(nil),             // 58: account.v2.VoidHoldRequest
+	(*VoidHoldResponse)(nil),            // 59: account.v2.VoidHoldResponse
+	(*ListHoldsRequest)(nil),            // 60: account.v2.ListHoldsRequest
+	(*ListHoldsResponse)(nil),           // 61: account.v2.ListHoldsResponse
+	(*RestoreAccountRequest)(nil),       // 62: account.v2.RestoreAccountRequest
+	(*RestoreAccountResponse)(nil),      // 63: account.v2.RestoreAccountResponse
+	(*v1.UserInfo)(nil),                 // 64: user.v1.UserInfo
+	(*v11.Money)(nil),                   // 65: common.v1.Money
+	(*timestamppb.Timestamp)(nil),       // 66: google.protobuf.Timestamp
+	(*durationpb.Duration)(nil),         // 67: google.protobuf.Duration
 }
 var file_account_v2_account_proto_depIdxs = []int32{
-	13, // 0: account.v2.AccountInfo.owner:type_name -> user.v1.UserInfo
-	14, // 1: account.v2.AccountInfo.balance:type_name -> common.v1.Money
-	0,  // 2: account.v2.GetAccountResponse.account:type_name -> account.v2.AccountInfo
-	14, // 3: account.v2.CreateAccountRequest.initial_balance:type_name -> common.v1.Money
-	0,  // 4: account.v2.CreateAccountResponse.account:type_name -> account.v2.AccountInfo
-	0,  // 5: account.v2.ListAccountsResponse.accounts:type_name -> account.v2.AccountInfo
-	14, // 6: account.v2.DepositRequest.amount:type_name -> common.v1.Money
-	0,  // 7: account.v2.DepositResponse.account:type_name -> account.v2.AccountInfo
-	14, // 8: account.v2.WithdrawRequest.amount:type_name -> common.v1.Money
-	0,  // 9: account.v2.WithdrawResponse.account:type_name -> account.v2.AccountInfo
-	3,  // 10: account.v2.Account.CreateAccount:input_type -> account.v2.CreateAccountRequest
-	2,  // 11: account.v2.Account.GetAccount:input_type -> account.v2.GetAccountRequest
-	5,  // 12: account.v2.Account.ListAccounts:input_type -> account.v2.ListAccountsRequest
-	7,  // 13: account.v2.Account.DeleteAccount:input_type -> account.v2.DeleteAccountRequest
-	9,  // 14: account.v2.Account.Deposit:input_type -> account.v2.DepositRequest
-	11, // 15: account.v2.Account.Withdraw:input_type -> account.v2.WithdrawRequest
-	4,  // 16: account.v2.Account.CreateAccount:output_type -> account.v2.CreateAccountResponse
-	1,  // 17: account.v2.Account.GetAccount:output_type -> account.v2.GetAccountResponse
-	6,  // 18: account.v2.Account.ListAccounts:output_type -> account.v2.ListAccountsResponse
-	8,  // 19: account.v2.Account.DeleteAccount:output_type -> account.v2.DeleteAccountResponse
-	10, // 20: account.v2.Account.Deposit:output_type -> account.v2.DepositResponse
-	12, // 21: account.v2.Account.Withdraw:output_type -> account.v2.WithdrawResponse
-	16, // [16:22] is the sub-list for method output_type
-	10, // [10:16] is the sub-list for method input_type
-	10, // [10:10] is the sub-list for extension type_name
-	10, // [10:10] is the sub-list for extension extendee
-	0,  // [0:10] is the sub-list for field type_name
+	64,  // 0: account.v2.AccountInfo.owner:type_name -> user.v1.UserInfo
+	65,  // 1: account.v2.AccountInfo.balance:type_name -> common.v1.Money
+	1,   // 2: account.v2.AccountInfo.status:type_name -> account.v2.AccountStatus
+	0,   // 3: account.v2.AccountInfo.type:type_name -> account.v2.AccountType
+	66,  // 4: account.v2.AccountInfo.opened_at:type_name -> google.protobuf.Timestamp
+	66,  // 5: account.v2.AccountInfo.matures_at:type_name -> google.protobuf.Timestamp
+	65,  // 6: account.v2.AccountInfo.accrued_interest:type_name -> common.v1.Money
+	65,  // 7: account.v2.AccountInfo.overdraft_limit:type_name -> common.v1.Money
+	65,  // 8: account.v2.AccountInfo.available_balance:type_name -> common.v1.Money
+	65,  // 9: account.v2.AccountInfo.accrued_overdraft_interest:type_name -> common.v1.Money
+	6,   // 10: account.v2.GetAccountResponse.account:type_name -> account.v2.AccountInfo
+	65,  // 11: account.v2.CreateAccountRequest.initial_balance:type_name -> common.v1.Money
+	0,   // 12: account.v2.CreateAccountRequest.type:type_name -> account.v2.AccountType
+	6,   // 13: account.v2.CreateAccountResponse.account:type_name -> account.v2.AccountInfo
+	6,   // 14: account.v2.ListAccountsResponse.accounts:type_name -> account.v2.AccountInfo
+	65,  // 15: account.v2.DepositRequest.amount:type_name -> common.v1.Money
+	6,   // 16: account.v2.DepositResponse.account:type_name -> account.v2.AccountInfo
+	65,  // 17: account.v2.WithdrawRequest.amount:type_name -> common.v1.Money
+	6,   // 18: account.v2.WithdrawResponse.account:type_name -> account.v2.AccountInfo
+	48,  // 19: account.v2.WithdrawResponse.review:type_name -> account.v2.Review
+	9,   // 20: account.v2.BatchCreateAccountsRequest.items:type_name -> account.v2.CreateAccountRequest
+	2,   // 21: account.v2.BatchCreateAccountsRequest.mode:type_name -> account.v2.BatchMode
+	22,  // 22: account.v2.BatchCreateAccountsResponse.results:type_name -> account.v2.BatchCreateAccountsResult
+	6,   // 23: account.v2.BatchCreateAccountsResult.account:type_name -> account.v2.AccountInfo
+	19,  // 24: account.v2.BatchCreateAccountsResult.error:type_name -> account.v2.BatchError
+	2,   // 25: account.v2.BulkPostRequest.mode:type_name -> account.v2.BatchMode
+	15,  // 26: account.v2.BulkPostRequest.deposit:type_name -> account.v2.DepositRequest
+	17,  // 27: account.v2.BulkPostRequest.withdraw:type_name -> account.v2.WithdrawRequest
+	25,  // 28: account.v2.BulkPostResponse.results:type_name -> account.v2.BulkPostResult
+	6,   // 29: account.v2.BulkPostResult.account:type_name -> account.v2.AccountInfo
+	48,  // 30: account.v2.BulkPostResult.review:type_name -> account.v2.Review
+	19,  // 31: account.v2.BulkPostResult.error:type_name -> account.v2.BatchError
+	65,  // 32: account.v2.TransferRequest.amount:type_name -> common.v1.Money
+	41,  // 33: account.v2.TransferResponse.debit:type_name -> account.v2.Transaction
+	41,  // 34: account.v2.TransferResponse.credit:type_name -> account.v2.Transaction
+	48,  // 35: account.v2.TransferResponse.review:type_name -> account.v2.Review
+	64,  // 36: account.v2.SyncOwnerRequest.owner:type_name -> user.v1.UserInfo
+	6,   // 37: account.v2.FreezeAccountResponse.account:type_name -> account.v2.AccountInfo
+	6,   // 38: account.v2.UnfreezeAccountResponse.account:type_name -> account.v2.AccountInfo
+	6,   // 39: account.v2.CloseAccountResponse.account:type_name -> account.v2.AccountInfo
+	1,   // 40: account.v2.StatusChange.from:type_name -> account.v2.AccountStatus
+	1,   // 41: account.v2.StatusChange.to:type_name -> account.v2.AccountStatus
+	66,  // 42: account.v2.StatusChange.changed_at:type_name -> google.protobuf.Timestamp
+	38,  // 43: account.v2.ListAccountEventsResponse.events:type_name -> account.v2.StatusChange
+	3,   // 44: account.v2.Transaction.type:type_name -> account.v2.TransactionType
+	65,  // 45: account.v2.Transaction.amount:type_name -> common.v1.Money
+	65,  // 46: account.v2.Transaction.balance_after:type_name -> common.v1.Money
+	66,  // 47: account.v2.Transaction.created_at:type_name -> google.protobuf.Timestamp
+	65,  // 48: account.v2.Transaction.reversed_amount:type_name -> common.v1.Money
+	41,  // 49: account.v2.ListTransactionsResponse.transactions:type_name -> account.v2.Transaction
+	65,  // 50: account.v2.ReverseTransactionRequest.amount:type_name -> common.v1.Money
+	41,  // 51: account.v2.ReverseTransactionResponse.reversals:type_name -> account.v2.Transaction
+	65,  // 52: account.v2.SetOverdraftLimitRequest.limit:type_name -> common.v1.Money
+	6,   // 53: account.v2.SetOverdraftLimitResponse.account:type_name -> account.v2.AccountInfo
+	3,   // 54: account.v2.Review.type:type_name -> account.v2.TransactionType
+	65,  // 55: account.v2.Review.amount:type_name -> common.v1.Money
+	4,   // 56: account.v2.Review.status:type_name -> account.v2.ReviewStatus
+	66,  // 57: account.v2.Review.created_at:type_name -> google.protobuf.Timestamp
+	66,  // 58: account.v2.Review.resolved_at:type_name -> google.protobuf.Timestamp
+	48,  // 59: account.v2.ListPendingReviewsResponse.reviews:type_name -> account.v2.Review
+	48,  // 60: account.v2.ResolveReviewResponse.review:type_name -> account.v2.Review
+	65,  // 61: account.v2.Hold.amount:type_name -> common.v1.Money
+	65,  // 62: account.v2.Hold.captured_amount:type_name -> common.v1.Money
+	5,   // 63: account.v2.Hold.status:type_name -> account.v2.HoldStatus
+	66,  // 64: account.v2.Hold.created_at:type_name -> google.protobuf.Timestamp
+	66,  // 65: account.v2.Hold.expires_at:type_name -> google.protobuf.Timestamp
+	66,  // 66: account.v2.Hold.resolved_at:type_name -> google.protobuf.Timestamp
+	65,  // 67: account.v2.AuthorizeHoldRequest.amount:type_name -> common.v1.Money
+	67,  // 68: account.v2.AuthorizeHoldRequest.ttl:type_name -> google.protobuf.Duration
+	53,  // 69: account.v2.AuthorizeHoldResponse.hold:type_name -> account.v2.Hold
+	6,   // 70: account.v2.AuthorizeHoldResponse.account:type_name -> account.v2.AccountInfo
+	65,  // 71: account.v2.CaptureHoldRequest.amount:type_name -> common.v1.Money
+	53,  // 72: account.v2.CaptureHoldResponse.hold:type_name -> account.v2.Hold
+	41,  // 73: account.v2.CaptureHoldResponse.transaction:type_name -> account.v2.Transaction
+	53,  // 74: account.v2.VoidHoldResponse.hold:type_name -> account.v2.Hold
+	53,  // 75: account.v2.ListHoldsResponse.holds:type_name -> account.v2.Hold
+	6,   // 76: account.v2.RestoreAccountRequest.account:type_name -> account.v2.AccountInfo
+	41,  // 77: account.v2.RestoreAccountRequest.transactions:type_name -> account.v2.Transaction
+	6,   // 78: account.v2.RestoreAccountResponse.account:type_name -> account.v2.AccountInfo
+	41,  // 79: account.v2.RestoreAccountResponse.transactions:type_name -> account.v2.Transaction
+	9,   // 80: account.v2.Account.CreateAccount:input_type -> account.v2.CreateAccountRequest
+	8,   // 81: account.v2.Account.GetAccount:input_type -> account.v2.GetAccountRequest
+	11,  // 82: account.v2.Account.ListAccounts:input_type -> account.v2.ListAccountsRequest
+	13,  // 83: account.v2.Account.DeleteAccount:input_type -> account.v2.DeleteAccountRequest
+	15,  // 84: account.v2.Account.Deposit:input_type -> account.v2.DepositRequest
+	17,  // 85: account.v2.Account.Withdraw:input_type -> account.v2.WithdrawRequest
+	26,  // 86: account.v2.Account.Transfer:input_type -> account.v2.TransferRequest
+	20,  // 87: account.v2.Account.BatchCreateAccounts:input_type -> account.v2.BatchCreateAccountsRequest
+	23,  // 88: account.v2.Account.BulkPost:input_type -> account.v2.BulkPostRequest
+	54,  // 89: account.v2.Account.AuthorizeHold:input_type -> account.v2.AuthorizeHoldRequest
+	56,  // 90: account.v2.Account.CaptureHold:input_type -> account.v2.CaptureHoldRequest
+	58,  // 91: account.v2.Account.VoidHold:input_type -> account.v2.VoidHoldRequest
+	60,  // 92: account.v2.Account.ListHolds:input_type -> account.v2.ListHoldsRequest
+	28,  // 93: account.v2.Account.CloseUserAccounts:input_type -> account.v2.CloseUserAccountsRequest
+	30,  // 94: account.v2.Account.SyncOwner:input_type -> account.v2.SyncOwnerRequest
+	32,  // 95: account.v2.Account.FreezeAccount:input_type -> account.v2.FreezeAccountRequest
+	34,  // 96: account.v2.Account.UnfreezeAccount:input_type -> account.v2.UnfreezeAccountRequest
+	36,  // 97: account.v2.Account.CloseAccount:input_type -> account.v2.CloseAccountRequest
+	39,  // 98: account.v2.Account.ListAccountEvents:input_type -> account.v2.ListAccountEventsRequest
+	42,  // 99: account.v2.Account.ListTransactions:input_type -> account.v2.ListTransactionsRequest
+	44,  // 100: account.v2.Account.ReverseTransaction:input_type -> account.v2.ReverseTransactionRequest
+	46,  // 101: account.v2.Account.SetOverdraftLimit:input_type -> account.v2.SetOverdraftLimitRequest
+	49,  // 102: account.v2.Account.ListPendingReviews:input_type -> account.v2.ListPendingReviewsRequest
+	51,  // 103: account.v2.Account.ResolveReview:input_type -> account.v2.ResolveReviewRequest
+	62,  // 104: account.v2.Account.RestoreAccount:input_type -> account.v2.RestoreAccountRequest
+	10,  // 105: account.v2.Account.CreateAccount:output_type -> account.v2.CreateAccountResponse
+	7,   // 106: account.v2.Account.GetAccount:output_type -> account.v2.GetAccountResponse
+	12,  // 107: account.v2.Account.ListAccounts:output_type -> account.v2.ListAccountsResponse
+	14,  // 108: account.v2.Account.DeleteAccount:output_type -> account.v2.DeleteAccountResponse
+	16,  // 109: account.v2.Account.Deposit:output_type -> account.v2.DepositResponse
+	18,  // 110: account.v2.Account.Withdraw:output_type -> account.v2.WithdrawResponse
+	27,  // 111: account.v2.Account.Transfer:output_type -> account.v2.TransferResponse
+	21,  // 112: account.v2.Account.BatchCreateAccounts:output_type -> account.v2.BatchCreateAccountsResponse
+	24,  // 113: account.v2.Account.BulkPost:output_type -> account.v2.BulkPostResponse
+	55,  // 114: account.v2.Account.AuthorizeHold:output_type -> account.v2.AuthorizeHoldResponse
+	57,  // 115: account.v2.Account.CaptureHold:output_type -> account.v2.CaptureHoldResponse
+	59,  // 116: account.v2.Account.VoidHold:output_type -> account.v2.VoidHoldResponse
+	61,  // 117: account.v2.Account.ListHolds:output_type -> account.v2.ListHoldsResponse
+	29,  // 118: account.v2.Account.CloseUserAccounts:output_type -> account.v2.CloseUserAccountsResponse
+	31,  // 119: account.v2.Account.SyncOwner:output_type -> account.v2.SyncOwnerResponse
+	33,  // 120: account.v2.Account.FreezeAccount:output_type -> account.v2.FreezeAccountResponse
+	35,  // 121: account.v2.Account.UnfreezeAccount:output_type -> account.v2.UnfreezeAccountResponse
+	37,  // 122: account.v2.Account.CloseAccount:output_type -> account.v2.CloseAccountResponse
+	40,  // 123: account.v2.Account.ListAccountEvents:output_type -> account.v2.ListAccountEventsResponse
+	43,  // 124: account.v2.Account.ListTransactions:output_type -> account.v2.ListTransactionsResponse
+	45,  // 125: account.v2.Account.ReverseTransaction:output_type -> account.v2.ReverseTransactionResponse
+	47,  // 126: account.v2.Account.SetOverdraftLimit:output_type -> account.v2.SetOverdraftLimitResponse
+	50,  // 127: account.v2.Account.ListPendingReviews:output_type -> account.v2.ListPendingReviewsResponse
+	52,  // 128: account.v2.Account.ResolveReview:output_type -> account.v2.ResolveReviewResponse
+	63,  // 129: account.v2.Account.RestoreAccount:output_type -> account.v2.RestoreAccountResponse
+	105, // [105:130] is the sub-list for method output_type
+	80,  // [80:105] is the sub-list for method input_type
+	80,  // [80:80] is the sub-list for extension type_name
+	80,  // [80:80] is the sub-list for extension extendee
+	0,   // [0:80] is the sub-list for field type_name
 }
 
 func init() { file_account_v2_account_proto_init() }
@@ -780,18 +4284,23 @@ func file_account_v2_account_proto_init() {
 	if File_account_v2_account_proto != nil {
 		return
 	}
+	file_account_v2_account_proto_msgTypes[17].OneofWrappers = []any{
+		(*BulkPostRequest_Deposit)(nil),
+		(*BulkPostRequest_Withdraw)(nil),
+	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_account_v2_account_proto_rawDesc), len(file_account_v2_account_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   13,
+			NumEnums:      6,
+			NumMessages:   58,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_account_v2_account_proto_goTypes,
 		DependencyIndexes: file_account_v2_account_proto_depIdxs,
+		EnumInfos:         file_account_v2_account_proto_enumTypes,
 		MessageInfos:      file_account_v2_account_proto_msgTypes,
 	}.Build()
 	File_account_v2_account_proto = out.File
@@ -0,0 +1,878 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        v6.32.1
+// source: loan/v1/loan.proto
+
+package loanv1
+
+import (
+	v1 "github.com/galadeat/bank-sim/api/proto/common/v1"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type RepaymentType int32
+
+const (
+	RepaymentType_REPAYMENT_TYPE_UNSPECIFIED RepaymentType = 0
+	RepaymentType_REPAYMENT_TYPE_ANNUITY     RepaymentType = 1 // equal monthly installments
+	RepaymentType_REPAYMENT_TYPE_LINEAR      RepaymentType = 2 // equal principal parts, decreasing interest
+)
+
+// Enum value maps for RepaymentType.
+var (
+	RepaymentType_name = map[int32]string{
+		0: "REPAYMENT_TYPE_UNSPECIFIED",
+		1: "REPAYMENT_TYPE_ANNUITY",
+		2: "REPAYMENT_TYPE_LINEAR",
+	}
+	RepaymentType_value = map[string]int32{
+		"REPAYMENT_TYPE_UNSPECIFIED": 0,
+		"REPAYMENT_TYPE_ANNUITY":     1,
+		"REPAYMENT_TYPE_LINEAR":      2,
+	}
+)
+
+func (x RepaymentType) Enum() *RepaymentType {
+	p := new(RepaymentType)
+	*p = x
+	return p
+}
+
+func (x RepaymentType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (RepaymentType) Descriptor() protoreflect.EnumDescriptor {
+	return file_loan_v1_loan_proto_enumTypes[0].Descriptor()
+}
+
+func (RepaymentType) Type() protoreflect.EnumType {
+	return &file_loan_v1_loan_proto_enumTypes[0]
+}
+
+func (x RepaymentType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use RepaymentType.Descriptor instead.
+func (RepaymentType) EnumDescriptor() ([]byte, []int) {
+	return file_loan_v1_loan_proto_rawDescGZIP(), []int{0}
+}
+
+type LoanStatus int32
+
+const (
+	LoanStatus_LOAN_STATUS_UNSPECIFIED LoanStatus = 0
+	LoanStatus_LOAN_STATUS_ACTIVE      LoanStatus = 1
+	LoanStatus_LOAN_STATUS_DELINQUENT  LoanStatus = 2 // at least one installment is overdue
+	LoanStatus_LOAN_STATUS_PAID_OFF    LoanStatus = 3
+)
+
+// Enum value maps for LoanStatus.
+var (
+	LoanStatus_name = map[int32]string{
+		0: "LOAN_STATUS_UNSPECIFIED",
+		1: "LOAN_STATUS_ACTIVE",
+		2: "LOAN_STATUS_DELINQUENT",
+		3: "LOAN_STATUS_PAID_OFF",
+	}
+	LoanStatus_value = map[string]int32{
+		"LOAN_STATUS_UNSPECIFIED": 0,
+		"LOAN_STATUS_ACTIVE":      1,
+		"LOAN_STATUS_DELINQUENT":  2,
+		"LOAN_STATUS_PAID_OFF":    3,
+	}
+)
+
+func (x LoanStatus) Enum() *LoanStatus {
+	p := new(LoanStatus)
+	*p = x
+	return p
+}
+
+func (x LoanStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (LoanStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_loan_v1_loan_proto_enumTypes[1].Descriptor()
+}
+
+func (LoanStatus) Type() protoreflect.EnumType {
+	return &file_loan_v1_loan_proto_enumTypes[1]
+}
+
+func (x LoanStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use LoanStatus.Descriptor instead.
+func (LoanStatus) EnumDescriptor() ([]byte, []int) {
+	return file_loan_v1_loan_proto_rawDescGZIP(), []int{1}
+}
+
+type InstallmentStatus int32
+
+const (
+	InstallmentStatus_INSTALLMENT_STATUS_UNSPECIFIED InstallmentStatus = 0
+	InstallmentStatus_INSTALLMENT_STATUS_SCHEDULED   InstallmentStatus = 1
+	InstallmentStatus_INSTALLMENT_STATUS_PAID        InstallmentStatus = 2
+	InstallmentStatus_INSTALLMENT_STATUS_OVERDUE     InstallmentStatus = 3
+)
+
+// Enum value maps for InstallmentStatus.
+var (
+	InstallmentStatus_name = map[int32]string{
+		0: "INSTALLMENT_STATUS_UNSPECIFIED",
+		1: "INSTALLMENT_STATUS_SCHEDULED",
+		2: "INSTALLMENT_STATUS_PAID",
+		3: "INSTALLMENT_STATUS_OVERDUE",
+	}
+	InstallmentStatus_value = map[string]int32{
+		"INSTALLMENT_STATUS_UNSPECIFIED": 0,
+		"INSTALLMENT_STATUS_SCHEDULED":   1,
+		"INSTALLMENT_STATUS_PAID":        2,
+		"INSTALLMENT_STATUS_OVERDUE":     3,
+	}
+)
+
+func (x InstallmentStatus) Enum() *InstallmentStatus {
+	p := new(InstallmentStatus)
+	*p = x
+	return p
+}
+
+func (x InstallmentStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (InstallmentStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_loan_v1_loan_proto_enumTypes[2].Descriptor()
+}
+
+func (InstallmentStatus) Type() protoreflect.EnumType {
+	return &file_loan_v1_loan_proto_enumTypes[2]
+}
+
+func (x InstallmentStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use InstallmentStatus.Descriptor instead.
+func (InstallmentStatus) EnumDescriptor() ([]byte, []int) {
+	return file_loan_v1_loan_proto_rawDescGZIP(), []int{2}
+}
+
+type Installment struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Number             int32                  `protobuf:"varint,1,opt,name=number,proto3" json:"number,omitempty"`
+	DueAt              *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=due_at,json=dueAt,proto3" json:"due_at,omitempty"`
+	Principal          *v1.Money              `protobuf:"bytes,3,opt,name=principal,proto3" json:"principal,omitempty"`
+	Interest           *v1.Money              `protobuf:"bytes,4,opt,name=interest,proto3" json:"interest,omitempty"`
+	Total              *v1.Money              `protobuf:"bytes,5,opt,name=total,proto3" json:"total,omitempty"`
+	RemainingPrincipal *v1.Money              `protobuf:"bytes,6,opt,name=remaining_principal,json=remainingPrincipal,proto3" json:"remaining_principal,omitempty"` // after this installment is paid
+	Status             InstallmentStatus      `protobuf:"varint,7,opt,name=status,proto3,enum=loan.v1.InstallmentStatus" json:"status,omitempty"`
+	PaidAt             *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=paid_at,json=paidAt,proto3" json:"paid_at,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *Installment) Reset() {
+	*x = Installment{}
+	mi := &file_loan_v1_loan_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Installment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Installment) ProtoMessage() {}
+
+func (x *Installment) ProtoReflect() protoreflect.Message {
+	mi := &file_loan_v1_loan_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Installment.ProtoReflect.Descriptor instead.
+func (*Installment) Descriptor() ([]byte, []int) {
+	return file_loan_v1_loan_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Installment) GetNumber() int32 {
+	if x != nil {
+		return x.Number
+	}
+	return 0
+}
+
+func (x *Installment) GetDueAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DueAt
+	}
+	return nil
+}
+
+func (x *Installment) GetPrincipal() *v1.Money {
+	if x != nil {
+		return x.Principal
+	}
+	return nil
+}
+
+func (x *Installment) GetInterest() *v1.Money {
+	if x != nil {
+		return x.Interest
+	}
+	return nil
+}
+
+func (x *Installment) GetTotal() *v1.Money {
+	if x != nil {
+		return x.Total
+	}
+	return nil
+}
+
+func (x *Installment) GetRemainingPrincipal() *v1.Money {
+	if x != nil {
+		return x.RemainingPrincipal
+	}
+	return nil
+}
+
+func (x *Installment) GetStatus() InstallmentStatus {
+	if x != nil {
+		return x.Status
+	}
+	return InstallmentStatus_INSTALLMENT_STATUS_UNSPECIFIED
+}
+
+func (x *Installment) GetPaidAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.PaidAt
+	}
+	return nil
+}
+
+type LoanInfo struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	Id                   string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId               string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	AccountId            string                 `protobuf:"bytes,3,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"` // disbursement and repayment account
+	Principal            *v1.Money              `protobuf:"bytes,4,opt,name=principal,proto3" json:"principal,omitempty"`
+	AnnualRateBps        int64                  `protobuf:"varint,5,opt,name=annual_rate_bps,json=annualRateBps,proto3" json:"annual_rate_bps,omitempty"`
+	TermMonths           int32                  `protobuf:"varint,6,opt,name=term_months,json=termMonths,proto3" json:"term_months,omitempty"`
+	RepaymentType        RepaymentType          `protobuf:"varint,7,opt,name=repayment_type,json=repaymentType,proto3,enum=loan.v1.RepaymentType" json:"repayment_type,omitempty"`
+	Status               LoanStatus             `protobuf:"varint,8,opt,name=status,proto3,enum=loan.v1.LoanStatus" json:"status,omitempty"`
+	Schedule             []*Installment         `protobuf:"bytes,9,rep,name=schedule,proto3" json:"schedule,omitempty"`
+	DisbursedAt          *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=disbursed_at,json=disbursedAt,proto3" json:"disbursed_at,omitempty"`
+	OutstandingPrincipal *v1.Money              `protobuf:"bytes,11,opt,name=outstanding_principal,json=outstandingPrincipal,proto3" json:"outstanding_principal,omitempty"`
+	DaysPastDue          int32                  `protobuf:"varint,12,opt,name=days_past_due,json=daysPastDue,proto3" json:"days_past_due,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *LoanInfo) Reset() {
+	*x = LoanInfo{}
+	mi := &file_loan_v1_loan_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LoanInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoanInfo) ProtoMessage() {}
+
+func (x *LoanInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_loan_v1_loan_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoanInfo.ProtoReflect.Descriptor instead.
+func (*LoanInfo) Descriptor() ([]byte, []int) {
+	return file_loan_v1_loan_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *LoanInfo) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *LoanInfo) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *LoanInfo) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *LoanInfo) GetPrincipal() *v1.Money {
+	if x != nil {
+		return x.Principal
+	}
+	return nil
+}
+
+func (x *LoanInfo) GetAnnualRateBps() int64 {
+	if x != nil {
+		return x.AnnualRateBps
+	}
+	return 0
+}
+
+func (x *LoanInfo) GetTermMonths() int32 {
+	if x != nil {
+		return x.TermMonths
+	}
+	return 0
+}
+
+func (x *LoanInfo) GetRepaymentType() RepaymentType {
+	if x != nil {
+		return x.RepaymentType
+	}
+	return RepaymentType_REPAYMENT_TYPE_UNSPECIFIED
+}
+
+func (x *LoanInfo) GetStatus() LoanStatus {
+	if x != nil {
+		return x.Status
+	}
+	return LoanStatus_LOAN_STATUS_UNSPECIFIED
+}
+
+func (x *LoanInfo) GetSchedule() []*Installment {
+	if x != nil {
+		return x.Schedule
+	}
+	return nil
+}
+
+func (x *LoanInfo) GetDisbursedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DisbursedAt
+	}
+	return nil
+}
+
+func (x *LoanInfo) GetOutstandingPrincipal() *v1.Money {
+	if x != nil {
+		return x.OutstandingPrincipal
+	}
+	return nil
+}
+
+func (x *LoanInfo) GetDaysPastDue() int32 {
+	if x != nil {
+		return x.DaysPastDue
+	}
+	return 0
+}
+
+type OriginateLoanRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	AccountId     string                 `protobuf:"bytes,2,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Principal     *v1.Money              `protobuf:"bytes,3,opt,name=principal,proto3" json:"principal,omitempty"`
+	AnnualRateBps int64                  `protobuf:"varint,4,opt,name=annual_rate_bps,json=annualRateBps,proto3" json:"annual_rate_bps,omitempty"`
+	TermMonths    int32                  `protobuf:"varint,5,opt,name=term_months,json=termMonths,proto3" json:"term_months,omitempty"`
+	RepaymentType RepaymentType          `protobuf:"varint,6,opt,name=repayment_type,json=repaymentType,proto3,enum=loan.v1.RepaymentType" json:"repayment_type,omitempty"`
+	RequestId     string                 `protobuf:"bytes,7,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OriginateLoanRequest) Reset() {
+	*x = OriginateLoanRequest{}
+	mi := &file_loan_v1_loan_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OriginateLoanRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OriginateLoanRequest) ProtoMessage() {}
+
+func (x *OriginateLoanRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_loan_v1_loan_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OriginateLoanRequest.ProtoReflect.Descriptor instead.
+func (*OriginateLoanRequest) Descriptor() ([]byte, []int) {
+	return file_loan_v1_loan_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *OriginateLoanRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *OriginateLoanRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *OriginateLoanRequest) GetPrincipal() *v1.Money {
+	if x != nil {
+		return x.Principal
+	}
+	return nil
+}
+
+func (x *OriginateLoanRequest) GetAnnualRateBps() int64 {
+	if x != nil {
+		return x.AnnualRateBps
+	}
+	return 0
+}
+
+func (x *OriginateLoanRequest) GetTermMonths() int32 {
+	if x != nil {
+		return x.TermMonths
+	}
+	return 0
+}
+
+func (x *OriginateLoanRequest) GetRepaymentType() RepaymentType {
+	if x != nil {
+		return x.RepaymentType
+	}
+	return RepaymentType_REPAYMENT_TYPE_UNSPECIFIED
+}
+
+func (x *OriginateLoanRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+type OriginateLoanResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Loan          *LoanInfo              `protobuf:"bytes,1,opt,name=loan,proto3" json:"loan,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OriginateLoanResponse) Reset() {
+	*x = OriginateLoanResponse{}
+	mi := &file_loan_v1_loan_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OriginateLoanResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OriginateLoanResponse) ProtoMessage() {}
+
+func (x *OriginateLoanResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_loan_v1_loan_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OriginateLoanResponse.ProtoReflect.Descriptor instead.
+func (*OriginateLoanResponse) Descriptor() ([]byte, []int) {
+	return file_loan_v1_loan_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *OriginateLoanResponse) GetLoan() *LoanInfo {
+	if x != nil {
+		return x.Loan
+	}
+	return nil
+}
+
+type GetLoanRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLoanRequest) Reset() {
+	*x = GetLoanRequest{}
+	mi := &file_loan_v1_loan_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLoanRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLoanRequest) ProtoMessage() {}
+
+func (x *GetLoanRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_loan_v1_loan_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLoanRequest.ProtoReflect.Descriptor instead.
+func (*GetLoanRequest) Descriptor() ([]byte, []int) {
+	return file_loan_v1_loan_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetLoanRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type GetLoanResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Loan          *LoanInfo              `protobuf:"bytes,1,opt,name=loan,proto3" json:"loan,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLoanResponse) Reset() {
+	*x = GetLoanResponse{}
+	mi := &file_loan_v1_loan_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLoanResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLoanResponse) ProtoMessage() {}
+
+func (x *GetLoanResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_loan_v1_loan_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLoanResponse.ProtoReflect.Descriptor instead.
+func (*GetLoanResponse) Descriptor() ([]byte, []int) {
+	return file_loan_v1_loan_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetLoanResponse) GetLoan() *LoanInfo {
+	if x != nil {
+		return x.Loan
+	}
+	return nil
+}
+
+type ListLoansRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListLoansRequest) Reset() {
+	*x = ListLoansRequest{}
+	mi := &file_loan_v1_loan_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListLoansRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLoansRequest) ProtoMessage() {}
+
+func (x *ListLoansRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_loan_v1_loan_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLoansRequest.ProtoReflect.Descriptor instead.
+func (*ListLoansRequest) Descriptor() ([]byte, []int) {
+	return file_loan_v1_loan_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListLoansRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type ListLoansResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Loans         []*LoanInfo            `protobuf:"bytes,1,rep,name=loans,proto3" json:"loans,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListLoansResponse) Reset() {
+	*x = ListLoansResponse{}
+	mi := &file_loan_v1_loan_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListLoansResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLoansResponse) ProtoMessage() {}
+
+func (x *ListLoansResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_loan_v1_loan_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLoansResponse.ProtoReflect.Descriptor instead.
+func (*ListLoansResponse) Descriptor() ([]byte, []int) {
+	return file_loan_v1_loan_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListLoansResponse) GetLoans() []*LoanInfo {
+	if x != nil {
+		return x.Loans
+	}
+	return nil
+}
+
+var File_loan_v1_loan_proto protoreflect.FileDescriptor
+
+const file_loan_v1_loan_proto_rawDesc = "" +
+	"\n" +
+	"\x12loan/v1/loan.proto\x12\aloan.v1\x1a\x15common/v1/money.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\x8a\x03\n" +
+	"\vInstallment\x12\x16\n" +
+	"\x06number\x18\x01 \x01(\x05R\x06number\x121\n" +
+	"\x06due_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x05dueAt\x12.\n" +
+	"\tprincipal\x18\x03 \x01(\v2\x10.common.v1.MoneyR\tprincipal\x12,\n" +
+	"\binterest\x18\x04 \x01(\v2\x10.common.v1.MoneyR\binterest\x12&\n" +
+	"\x05total\x18\x05 \x01(\v2\x10.common.v1.MoneyR\x05total\x12A\n" +
+	"\x13remaining_principal\x18\x06 \x01(\v2\x10.common.v1.MoneyR\x12remainingPrincipal\x122\n" +
+	"\x06status\x18\a \x01(\x0e2\x1a.loan.v1.InstallmentStatusR\x06status\x123\n" +
+	"\apaid_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\x06paidAt\"\x93\x04\n" +
+	"\bLoanInfo\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x03 \x01(\tR\taccountId\x12.\n" +
+	"\tprincipal\x18\x04 \x01(\v2\x10.common.v1.MoneyR\tprincipal\x12&\n" +
+	"\x0fannual_rate_bps\x18\x05 \x01(\x03R\rannualRateBps\x12\x1f\n" +
+	"\vterm_months\x18\x06 \x01(\x05R\n" +
+	"termMonths\x12=\n" +
+	"\x0erepayment_type\x18\a \x01(\x0e2\x16.loan.v1.RepaymentTypeR\rrepaymentType\x12+\n" +
+	"\x06status\x18\b \x01(\x0e2\x13.loan.v1.LoanStatusR\x06status\x120\n" +
+	"\bschedule\x18\t \x03(\v2\x14.loan.v1.InstallmentR\bschedule\x12=\n" +
+	"\fdisbursed_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\vdisbursedAt\x12E\n" +
+	"\x15outstanding_principal\x18\v \x01(\v2\x10.common.v1.MoneyR\x14outstandingPrincipal\x12\"\n" +
+	"\rdays_past_due\x18\f \x01(\x05R\vdaysPastDue\"\xa5\x02\n" +
+	"\x14OriginateLoanRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x02 \x01(\tR\taccountId\x12.\n" +
+	"\tprincipal\x18\x03 \x01(\v2\x10.common.v1.MoneyR\tprincipal\x12&\n" +
+	"\x0fannual_rate_bps\x18\x04 \x01(\x03R\rannualRateBps\x12\x1f\n" +
+	"\vterm_months\x18\x05 \x01(\x05R\n" +
+	"termMonths\x12=\n" +
+	"\x0erepayment_type\x18\x06 \x01(\x0e2\x16.loan.v1.RepaymentTypeR\rrepaymentType\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\a \x01(\tR\trequestId\">\n" +
+	"\x15OriginateLoanResponse\x12%\n" +
+	"\x04loan\x18\x01 \x01(\v2\x11.loan.v1.LoanInfoR\x04loan\" \n" +
+	"\x0eGetLoanRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"8\n" +
+	"\x0fGetLoanResponse\x12%\n" +
+	"\x04loan\x18\x01 \x01(\v2\x11.loan.v1.LoanInfoR\x04loan\"+\n" +
+	"\x10ListLoansRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"<\n" +
+	"\x11ListLoansResponse\x12'\n" +
+	"\x05loans\x18\x01 \x03(\v2\x11.loan.v1.LoanInfoR\x05loans*f\n" +
+	"\rRepaymentType\x12\x1e\n" +
+	"\x1aREPAYMENT_TYPE_UNSPECIFIED\x10\x00\x12\x1a\n" +
+	"\x16REPAYMENT_TYPE_ANNUITY\x10\x01\x12\x19\n" +
+	"\x15REPAYMENT_TYPE_LINEAR\x10\x02*w\n" +
+	"\n" +
+	"LoanStatus\x12\x1b\n" +
+	"\x17LOAN_STATUS_UNSPECIFIED\x10\x00\x12\x16\n" +
+	"\x12LOAN_STATUS_ACTIVE\x10\x01\x12\x1a\n" +
+	"\x16LOAN_STATUS_DELINQUENT\x10\x02\x12\x18\n" +
+	"\x14LOAN_STATUS_PAID_OFF\x10\x03*\x96\x01\n" +
+	"\x11InstallmentStatus\x12\"\n" +
+	"\x1eINSTALLMENT_STATUS_UNSPECIFIED\x10\x00\x12 \n" +
+	"\x1cINSTALLMENT_STATUS_SCHEDULED\x10\x01\x12\x1b\n" +
+	"\x17INSTALLMENT_STATUS_PAID\x10\x02\x12\x1e\n" +
+	"\x1aINSTALLMENT_STATUS_OVERDUE\x10\x032\xd8\x01\n" +
+	"\x04Loan\x12N\n" +
+	"\rOriginateLoan\x12\x1d.loan.v1.OriginateLoanRequest\x1a\x1e.loan.v1.OriginateLoanResponse\x12<\n" +
+	"\aGetLoan\x12\x17.loan.v1.GetLoanRequest\x1a\x18.loan.v1.GetLoanResponse\x12B\n" +
+	"\tListLoans\x12\x19.loan.v1.ListLoansRequest\x1a\x1a.loan.v1.ListLoansResponseB7Z5github.com/galadeat/bank-sim/api/proto/loan/v1;loanv1b\x06proto3"
+
+var (
+	file_loan_v1_loan_proto_rawDescOnce sync.Once
+	file_loan_v1_loan_proto_rawDescData []byte
+)
+
+func file_loan_v1_loan_proto_rawDescGZIP() []byte {
+	file_loan_v1_loan_proto_rawDescOnce.Do(func() {
+		file_loan_v1_loan_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_loan_v1_loan_proto_rawDesc), len(file_loan_v1_loan_proto_rawDesc)))
+	})
+	return file_loan_v1_loan_proto_rawDescData
+}
+
+var file_loan_v1_loan_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_loan_v1_loan_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_loan_v1_loan_proto_goTypes = []any{
+	(RepaymentType)(0),            // 0: loan.v1.RepaymentType
+	(LoanStatus)(0),               // 1: loan.v1.LoanStatus
+	(InstallmentStatus)(0),        // 2: loan.v1.InstallmentStatus
+	(*Installment)(nil),           // 3: loan.v1.Installment
+	(*LoanInfo)(nil),              // 4: loan.v1.LoanInfo
+	(*OriginateLoanRequest)(nil),  // 5: loan.v1.OriginateLoanRequest
+	(*OriginateLoanResponse)(nil), // 6: loan.v1.OriginateLoanResponse
+	(*GetLoanRequest)(nil),        // 7: loan.v1.GetLoanRequest
+	(*GetLoanResponse)(nil),       // 8: loan.v1.GetLoanResponse
+	(*ListLoansRequest)(nil),      // 9: loan.v1.ListLoansRequest
+	(*ListLoansResponse)(nil),     // 10: loan.v1.ListLoansResponse
+	(*timestamppb.Timestamp)(nil), // 11: google.protobuf.Timestamp
+	(*v1.Money)(nil),              // 12: common.v1.Money
+}
+var file_loan_v1_loan_proto_depIdxs = []int32{
+	11, // 0: loan.v1.Installment.due_at:type_name -> google.protobuf.Timestamp
+	12, // 1: loan.v1.Installment.principal:type_name -> common.v1.Money
+	12, // 2: loan.v1.Installment.interest:type_name -> common.v1.Money
+	12, // 3: loan.v1.Installment.total:type_name -> common.v1.Money
+	12, // 4: loan.v1.Installment.remaining_principal:type_name -> common.v1.Money
+	2,  // 5: loan.v1.Installment.status:type_name -> loan.v1.InstallmentStatus
+	11, // 6: loan.v1.Installment.paid_at:type_name -> google.protobuf.Timestamp
+	12, // 7: loan.v1.LoanInfo.principal:type_name -> common.v1.Money
+	0,  // 8: loan.v1.LoanInfo.repayment_type:type_name -> loan.v1.RepaymentType
+	1,  // 9: loan.v1.LoanInfo.status:type_name -> loan.v1.LoanStatus
+	3,  // 10: loan.v1.LoanInfo.schedule:type_name -> loan.v1.Installment
+	11, // 11: loan.v1.LoanInfo.disbursed_at:type_name -> google.protobuf.Timestamp
+	12, // 12: loan.v1.LoanInfo.outstanding_principal:type_name -> common.v1.Money
+	12, // 13: loan.v1.OriginateLoanRequest.principal:type_name -> common.v1.Money
+	0,  // 14: loan.v1.OriginateLoanRequest.repayment_type:type_name -> loan.v1.RepaymentType
+	4,  // 15: loan.v1.OriginateLoanResponse.loan:type_name -> loan.v1.LoanInfo
+	4,  // 16: loan.v1.GetLoanResponse.loan:type_name -> loan.v1.LoanInfo
+	4,  // 17: loan.v1.ListLoansResponse.loans:type_name -> loan.v1.LoanInfo
+	5,  // 18: loan.v1.Loan.OriginateLoan:input_type -> loan.v1.OriginateLoanRequest
+	7,  // 19: loan.v1.Loan.GetLoan:input_type -> loan.v1.GetLoanRequest
+	9,  // 20: loan.v1.Loan.ListLoans:input_type -> loan.v1.ListLoansRequest
+	6,  // 21: loan.v1.Loan.OriginateLoan:output_type -> loan.v1.OriginateLoanResponse
+	8,  // 22: loan.v1.Loan.GetLoan:output_type -> loan.v1.GetLoanResponse
+	10, // 23: loan.v1.Loan.ListLoans:output_type -> loan.v1.ListLoansResponse
+	21, // [21:24] is the sub-list for method output_type
+	18, // [18:21] is the sub-list for method input_type
+	18, // [18:18] is the sub-list for extension type_name
+	18, // [18:18] is the sub-list for extension extendee
+	0,  // [0:18] is the sub-list for field type_name
+}
+
+func init() { file_loan_v1_loan_proto_init() }
+func file_loan_v1_loan_proto_init() {
+	if File_loan_v1_loan_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_loan_v1_loan_proto_rawDesc), len(file_loan_v1_loan_proto_rawDesc)),
+			NumEnums:      3,
+			NumMessages:   8,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_loan_v1_loan_proto_goTypes,
+		DependencyIndexes: file_loan_v1_loan_proto_depIdxs,
+		EnumInfos:         file_loan_v1_loan_proto_enumTypes,
+		MessageInfos:      file_loan_v1_loan_proto_msgTypes,
+	}.Build()
+	File_loan_v1_loan_proto = out.File
+	file_loan_v1_loan_proto_goTypes = nil
+	file_loan_v1_loan_proto_depIdxs = nil
+}
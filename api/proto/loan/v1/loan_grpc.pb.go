@@ -0,0 +1,197 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v6.32.1
+// source: loan/v1/loan.proto
+
+package loanv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Loan_OriginateLoan_FullMethodName = "/loan.v1.Loan/OriginateLoan"
+	Loan_GetLoan_FullMethodName       = "/loan.v1.Loan/GetLoan"
+	Loan_ListLoans_FullMethodName     = "/loan.v1.Loan/ListLoans"
+)
+
+// LoanClient is the client API for Loan service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type LoanClient interface {
+	OriginateLoan(ctx context.Context, in *OriginateLoanRequest, opts ...grpc.CallOption) (*OriginateLoanResponse, error)
+	GetLoan(ctx context.Context, in *GetLoanRequest, opts ...grpc.CallOption) (*GetLoanResponse, error)
+	ListLoans(ctx context.Context, in *ListLoansRequest, opts ...grpc.CallOption) (*ListLoansResponse, error)
+}
+
+type loanClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewLoanClient(cc grpc.ClientConnInterface) LoanClient {
+	return &loanClient{cc}
+}
+
+func (c *loanClient) OriginateLoan(ctx context.Context, in *OriginateLoanRequest, opts ...grpc.CallOption) (*OriginateLoanResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(OriginateLoanResponse)
+	err := c.cc.Invoke(ctx, Loan_OriginateLoan_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loanClient) GetLoan(ctx context.Context, in *GetLoanRequest, opts ...grpc.CallOption) (*GetLoanResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetLoanResponse)
+	err := c.cc.Invoke(ctx, Loan_GetLoan_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loanClient) ListLoans(ctx context.Context, in *ListLoansRequest, opts ...grpc.CallOption) (*ListLoansResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListLoansResponse)
+	err := c.cc.Invoke(ctx, Loan_ListLoans_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// LoanServer is the server API for Loan service.
+// All implementations must embed UnimplementedLoanServer
+// for forward compatibility.
+type LoanServer interface {
+	OriginateLoan(context.Context, *OriginateLoanRequest) (*OriginateLoanResponse, error)
+	GetLoan(context.Context, *GetLoanRequest) (*GetLoanResponse, error)
+	ListLoans(context.Context, *ListLoansRequest) (*ListLoansResponse, error)
+	mustEmbedUnimplementedLoanServer()
+}
+
+// UnimplementedLoanServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedLoanServer struct{}
+
+func (UnimplementedLoanServer) OriginateLoan(context.Context, *OriginateLoanRequest) (*OriginateLoanResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method OriginateLoan not implemented")
+}
+func (UnimplementedLoanServer) GetLoan(context.Context, *GetLoanRequest) (*GetLoanResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLoan not implemented")
+}
+func (UnimplementedLoanServer) ListLoans(context.Context, *ListLoansRequest) (*ListLoansResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListLoans not implemented")
+}
+func (UnimplementedLoanServer) mustEmbedUnimplementedLoanServer() {}
+func (UnimplementedLoanServer) testEmbeddedByValue()              {}
+
+// UnsafeLoanServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to LoanServer will
+// result in compilation errors.
+type UnsafeLoanServer interface {
+	mustEmbedUnimplementedLoanServer()
+}
+
+func RegisterLoanServer(s grpc.ServiceRegistrar, srv LoanServer) {
+	// If the following call pancis, it indicates UnimplementedLoanServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Loan_ServiceDesc, srv)
+}
+
+func _Loan_OriginateLoan_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OriginateLoanRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoanServer).OriginateLoan(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Loan_OriginateLoan_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoanServer).OriginateLoan(ctx, req.(*OriginateLoanRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Loan_GetLoan_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLoanRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoanServer).GetLoan(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Loan_GetLoan_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoanServer).GetLoan(ctx, req.(*GetLoanRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Loan_ListLoans_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListLoansRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoanServer).ListLoans(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Loan_ListLoans_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoanServer).ListLoans(ctx, req.(*ListLoansRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Loan_ServiceDesc is the grpc.ServiceDesc for Loan service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Loan_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "loan.v1.Loan",
+	HandlerType: (*LoanServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "OriginateLoan",
+			Handler:    _Loan_OriginateLoan_Handler,
+		},
+		{
+			MethodName: "GetLoan",
+			Handler:    _Loan_GetLoan_Handler,
+		},
+		{
+			MethodName: "ListLoans",
+			Handler:    _Loan_ListLoans_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "loan/v1/loan.proto",
+}
@@ -111,13 +111,16 @@ func (x *GetStatementResponse) GetRecords() []*TransactionRecord {
 }
 
 type TransactionRecord struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	TransactionId string                 `protobuf:"bytes,1,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
-	Type          string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"` // deposit, withdraw, transfer
-	Amount        *v1.Money              `protobuf:"bytes,3,opt,name=amount,proto3" json:"amount,omitempty"`
-	Timestamp     string                 `protobuf:"bytes,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	TransactionId         string                 `protobuf:"bytes,1,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	Type                  string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"` // deposit, withdrawal, transfer_in, transfer_out, reversal, refund, ...
+	Amount                *v1.Money              `protobuf:"bytes,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	Timestamp             string                 `protobuf:"bytes,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	BalanceAfter          *v1.Money              `protobuf:"bytes,5,opt,name=balance_after,json=balanceAfter,proto3" json:"balance_after,omitempty"`
+	ReversesTransactionId string                 `protobuf:"bytes,6,opt,name=reverses_transaction_id,json=reversesTransactionId,proto3" json:"reverses_transaction_id,omitempty"` // the entry a reversal or refund undoes
+	ReversedBy            []string               `protobuf:"bytes,7,rep,name=reversed_by,json=reversedBy,proto3" json:"reversed_by,omitempty"`                                    // reversals and refunds of this entry
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
 }
 
 func (x *TransactionRecord) Reset() {
@@ -178,6 +181,27 @@ func (x *TransactionRecord) GetTimestamp() string {
 	return ""
 }
 
+func (x *TransactionRecord) GetBalanceAfter() *v1.Money {
+	if x != nil {
+		return x.BalanceAfter
+	}
+	return nil
+}
+
+func (x *TransactionRecord) GetReversesTransactionId() string {
+	if x != nil {
+		return x.ReversesTransactionId
+	}
+	return ""
+}
+
+func (x *TransactionRecord) GetReversedBy() []string {
+	if x != nil {
+		return x.ReversedBy
+	}
+	return nil
+}
+
 var File_reporting_v1_reporting_proto protoreflect.FileDescriptor
 
 const file_reporting_v1_reporting_proto_rawDesc = "" +
@@ -187,12 +211,16 @@ const file_reporting_v1_reporting_proto_rawDesc = "" +
 	"\n" +
 	"account_id\x18\x01 \x01(\tR\taccountId\"Q\n" +
 	"\x14GetStatementResponse\x129\n" +
-	"\arecords\x18\x01 \x03(\v2\x1f.reporting.v1.TransactionRecordR\arecords\"\x96\x01\n" +
+	"\arecords\x18\x01 \x03(\v2\x1f.reporting.v1.TransactionRecordR\arecords\"\xa6\x02\n" +
 	"\x11TransactionRecord\x12%\n" +
 	"\x0etransaction_id\x18\x01 \x01(\tR\rtransactionId\x12\x12\n" +
 	"\x04type\x18\x02 \x01(\tR\x04type\x12(\n" +
 	"\x06amount\x18\x03 \x01(\v2\x10.common.v1.MoneyR\x06amount\x12\x1c\n" +
-	"\ttimestamp\x18\x04 \x01(\tR\ttimestamp2b\n" +
+	"\ttimestamp\x18\x04 \x01(\tR\ttimestamp\x125\n" +
+	"\rbalance_after\x18\x05 \x01(\v2\x10.common.v1.MoneyR\fbalanceAfter\x126\n" +
+	"\x17reverses_transaction_id\x18\x06 \x01(\tR\x15reversesTransactionId\x12\x1f\n" +
+	"\vreversed_by\x18\a \x03(\tR\n" +
+	"reversedBy2b\n" +
 	"\tReporting\x12U\n" +
 	"\fGetStatement\x12!.reporting.v1.GetStatementRequest\x1a\".reporting.v1.GetStatementResponseBAZ?github.com/galadeat/bank-sim/api/proto/reporting/v1;reportingv1b\x06proto3"
 
@@ -218,13 +246,14 @@ var file_reporting_v1_reporting_proto_goTypes = []any{
 var file_reporting_v1_reporting_proto_depIdxs = []int32{
 	2, // 0: reporting.v1.GetStatementResponse.records:type_name -> reporting.v1.TransactionRecord
 	3, // 1: reporting.v1.TransactionRecord.amount:type_name -> common.v1.Money
-	0, // 2: reporting.v1.Reporting.GetStatement:input_type -> reporting.v1.GetStatementRequest
-	1, // 3: reporting.v1.Reporting.GetStatement:output_type -> reporting.v1.GetStatementResponse
-	3, // [3:4] is the sub-list for method output_type
-	2, // [2:3] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	3, // 2: reporting.v1.TransactionRecord.balance_after:type_name -> common.v1.Money
+	0, // 3: reporting.v1.Reporting.GetStatement:input_type -> reporting.v1.GetStatementRequest
+	1, // 4: reporting.v1.Reporting.GetStatement:output_type -> reporting.v1.GetStatementResponse
+	4, // [4:5] is the sub-list for method output_type
+	3, // [3:4] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
 }
 
 func init() { file_reporting_v1_reporting_proto_init() }
@@ -0,0 +1,971 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        v6.32.1
+// source: scheduler/v1/scheduler.proto
+
+package schedulerv1
+
+import (
+	v1 "github.com/galadeat/bank-sim/api/proto/common/v1"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type StandingOrderStatus int32
+
+const (
+	StandingOrderStatus_STANDING_ORDER_STATUS_UNSPECIFIED StandingOrderStatus = 0
+	StandingOrderStatus_STANDING_ORDER_STATUS_ACTIVE      StandingOrderStatus = 1
+	StandingOrderStatus_STANDING_ORDER_STATUS_CANCELED    StandingOrderStatus = 2
+)
+
+// Enum value maps for StandingOrderStatus.
+var (
+	StandingOrderStatus_name = map[int32]string{
+		0: "STANDING_ORDER_STATUS_UNSPECIFIED",
+		1: "STANDING_ORDER_STATUS_ACTIVE",
+		2: "STANDING_ORDER_STATUS_CANCELED",
+	}
+	StandingOrderStatus_value = map[string]int32{
+		"STANDING_ORDER_STATUS_UNSPECIFIED": 0,
+		"STANDING_ORDER_STATUS_ACTIVE":      1,
+		"STANDING_ORDER_STATUS_CANCELED":    2,
+	}
+)
+
+func (x StandingOrderStatus) Enum() *StandingOrderStatus {
+	p := new(StandingOrderStatus)
+	*p = x
+	return p
+}
+
+func (x StandingOrderStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (StandingOrderStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_scheduler_v1_scheduler_proto_enumTypes[0].Descriptor()
+}
+
+func (StandingOrderStatus) Type() protoreflect.EnumType {
+	return &file_scheduler_v1_scheduler_proto_enumTypes[0]
+}
+
+func (x StandingOrderStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use StandingOrderStatus.Descriptor instead.
+func (StandingOrderStatus) EnumDescriptor() ([]byte, []int) {
+	return file_scheduler_v1_scheduler_proto_rawDescGZIP(), []int{0}
+}
+
+type ExecutionStatus int32
+
+const (
+	ExecutionStatus_EXECUTION_STATUS_UNSPECIFIED ExecutionStatus = 0
+	ExecutionStatus_EXECUTION_STATUS_SUCCEEDED   ExecutionStatus = 1
+	ExecutionStatus_EXECUTION_STATUS_RETRYING    ExecutionStatus = 2 // the attempt failed and will be retried
+	ExecutionStatus_EXECUTION_STATUS_FAILED      ExecutionStatus = 3 // all attempts failed; the occurrence is skipped
+	ExecutionStatus_EXECUTION_STATUS_HELD        ExecutionStatus = 4 // the transfer is held for review and is checked again later
+)
+
+// Enum value maps for ExecutionStatus.
+var (
+	ExecutionStatus_name = map[int32]string{
+		0: "EXECUTION_STATUS_UNSPECIFIED",
+		1: "EXECUTION_STATUS_SUCCEEDED",
+		2: "EXECUTION_STATUS_RETRYING",
+		3: "EXECUTION_STATUS_FAILED",
+		4: "EXECUTION_STATUS_HELD",
+	}
+	ExecutionStatus_value = map[string]int32{
+		"EXECUTION_STATUS_UNSPECIFIED": 0,
+		"EXECUTION_STATUS_SUCCEEDED":   1,
+		"EXECUTION_STATUS_RETRYING":    2,
+		"EXECUTION_STATUS_FAILED":      3,
+		"EXECUTION_STATUS_HELD":        4,
+	}
+)
+
+func (x ExecutionStatus) Enum() *ExecutionStatus {
+	p := new(ExecutionStatus)
+	*p = x
+	return p
+}
+
+func (x ExecutionStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ExecutionStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_scheduler_v1_scheduler_proto_enumTypes[1].Descriptor()
+}
+
+func (ExecutionStatus) Type() protoreflect.EnumType {
+	return &file_scheduler_v1_scheduler_proto_enumTypes[1]
+}
+
+func (x ExecutionStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ExecutionStatus.Descriptor instead.
+func (ExecutionStatus) EnumDescriptor() ([]byte, []int) {
+	return file_scheduler_v1_scheduler_proto_rawDescGZIP(), []int{1}
+}
+
+// StandingOrder transfers a fixed amount between two accounts on a schedule.
+// The schedule is a five-field cron expression (minute hour day-of-month month
+// day-of-week) or one of @hourly, @daily, @weekly, @monthly, @yearly.
+type StandingOrder struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	FromAccountId string                 `protobuf:"bytes,3,opt,name=from_account_id,json=fromAccountId,proto3" json:"from_account_id,omitempty"`
+	ToAccountId   string                 `protobuf:"bytes,4,opt,name=to_account_id,json=toAccountId,proto3" json:"to_account_id,omitempty"`
+	Amount        *v1.Money              `protobuf:"bytes,5,opt,name=amount,proto3" json:"amount,omitempty"`
+	Schedule      string                 `protobuf:"bytes,6,opt,name=schedule,proto3" json:"schedule,omitempty"`
+	Status        StandingOrderStatus    `protobuf:"varint,7,opt,name=status,proto3,enum=scheduler.v1.StandingOrderStatus" json:"status,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	NextRunAt     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=next_run_at,json=nextRunAt,proto3" json:"next_run_at,omitempty"` // the occurrence that is executed next
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StandingOrder) Reset() {
+	*x = StandingOrder{}
+	mi := &file_scheduler_v1_scheduler_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StandingOrder) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StandingOrder) ProtoMessage() {}
+
+func (x *StandingOrder) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_v1_scheduler_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StandingOrder.ProtoReflect.Descriptor instead.
+func (*StandingOrder) Descriptor() ([]byte, []int) {
+	return file_scheduler_v1_scheduler_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *StandingOrder) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *StandingOrder) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *StandingOrder) GetFromAccountId() string {
+	if x != nil {
+		return x.FromAccountId
+	}
+	return ""
+}
+
+func (x *StandingOrder) GetToAccountId() string {
+	if x != nil {
+		return x.ToAccountId
+	}
+	return ""
+}
+
+func (x *StandingOrder) GetAmount() *v1.Money {
+	if x != nil {
+		return x.Amount
+	}
+	return nil
+}
+
+func (x *StandingOrder) GetSchedule() string {
+	if x != nil {
+		return x.Schedule
+	}
+	return ""
+}
+
+func (x *StandingOrder) GetStatus() StandingOrderStatus {
+	if x != nil {
+		return x.Status
+	}
+	return StandingOrderStatus_STANDING_ORDER_STATUS_UNSPECIFIED
+}
+
+func (x *StandingOrder) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *StandingOrder) GetNextRunAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NextRunAt
+	}
+	return nil
+}
+
+// Execution is one attempt to execute an occurrence of a standing order.
+type Execution struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	ScheduledFor  *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=scheduled_for,json=scheduledFor,proto3" json:"scheduled_for,omitempty"`
+	Attempt       int32                  `protobuf:"varint,3,opt,name=attempt,proto3" json:"attempt,omitempty"`
+	Status        ExecutionStatus        `protobuf:"varint,4,opt,name=status,proto3,enum=scheduler.v1.ExecutionStatus" json:"status,omitempty"`
+	Error         string                 `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	RequestId     string                 `protobuf:"bytes,6,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"` // the transfer request id, shared by all attempts of an occurrence
+	ExecutedAt    *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=executed_at,json=executedAt,proto3" json:"executed_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Execution) Reset() {
+	*x = Execution{}
+	mi := &file_scheduler_v1_scheduler_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Execution) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Execution) ProtoMessage() {}
+
+func (x *Execution) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_v1_scheduler_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Execution.ProtoReflect.Descriptor instead.
+func (*Execution) Descriptor() ([]byte, []int) {
+	return file_scheduler_v1_scheduler_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Execution) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *Execution) GetScheduledFor() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ScheduledFor
+	}
+	return nil
+}
+
+func (x *Execution) GetAttempt() int32 {
+	if x != nil {
+		return x.Attempt
+	}
+	return 0
+}
+
+func (x *Execution) GetStatus() ExecutionStatus {
+	if x != nil {
+		return x.Status
+	}
+	return ExecutionStatus_EXECUTION_STATUS_UNSPECIFIED
+}
+
+func (x *Execution) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *Execution) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *Execution) GetExecutedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExecutedAt
+	}
+	return nil
+}
+
+type CreateStandingOrderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	FromAccountId string                 `protobuf:"bytes,2,opt,name=from_account_id,json=fromAccountId,proto3" json:"from_account_id,omitempty"`
+	ToAccountId   string                 `protobuf:"bytes,3,opt,name=to_account_id,json=toAccountId,proto3" json:"to_account_id,omitempty"`
+	Amount        *v1.Money              `protobuf:"bytes,4,opt,name=amount,proto3" json:"amount,omitempty"`
+	Schedule      string                 `protobuf:"bytes,5,opt,name=schedule,proto3" json:"schedule,omitempty"`
+	RequestId     string                 `protobuf:"bytes,6,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateStandingOrderRequest) Reset() {
+	*x = CreateStandingOrderRequest{}
+	mi := &file_scheduler_v1_scheduler_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateStandingOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateStandingOrderRequest) ProtoMessage() {}
+
+func (x *CreateStandingOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_v1_scheduler_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateStandingOrderRequest.ProtoReflect.Descriptor instead.
+func (*CreateStandingOrderRequest) Descriptor() ([]byte, []int) {
+	return file_scheduler_v1_scheduler_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CreateStandingOrderRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *CreateStandingOrderRequest) GetFromAccountId() string {
+	if x != nil {
+		return x.FromAccountId
+	}
+	return ""
+}
+
+func (x *CreateStandingOrderRequest) GetToAccountId() string {
+	if x != nil {
+		return x.ToAccountId
+	}
+	return ""
+}
+
+func (x *CreateStandingOrderRequest) GetAmount() *v1.Money {
+	if x != nil {
+		return x.Amount
+	}
+	return nil
+}
+
+func (x *CreateStandingOrderRequest) GetSchedule() string {
+	if x != nil {
+		return x.Schedule
+	}
+	return ""
+}
+
+func (x *CreateStandingOrderRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+type CreateStandingOrderResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Order         *StandingOrder         `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateStandingOrderResponse) Reset() {
+	*x = CreateStandingOrderResponse{}
+	mi := &file_scheduler_v1_scheduler_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateStandingOrderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateStandingOrderResponse) ProtoMessage() {}
+
+func (x *CreateStandingOrderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_v1_scheduler_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateStandingOrderResponse.ProtoReflect.Descriptor instead.
+func (*CreateStandingOrderResponse) Descriptor() ([]byte, []int) {
+	return file_scheduler_v1_scheduler_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CreateStandingOrderResponse) GetOrder() *StandingOrder {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+type GetStandingOrderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStandingOrderRequest) Reset() {
+	*x = GetStandingOrderRequest{}
+	mi := &file_scheduler_v1_scheduler_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStandingOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStandingOrderRequest) ProtoMessage() {}
+
+func (x *GetStandingOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_v1_scheduler_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStandingOrderRequest.ProtoReflect.Descriptor instead.
+func (*GetStandingOrderRequest) Descriptor() ([]byte, []int) {
+	return file_scheduler_v1_scheduler_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetStandingOrderRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type GetStandingOrderResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Order         *StandingOrder         `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStandingOrderResponse) Reset() {
+	*x = GetStandingOrderResponse{}
+	mi := &file_scheduler_v1_scheduler_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStandingOrderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStandingOrderResponse) ProtoMessage() {}
+
+func (x *GetStandingOrderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_v1_scheduler_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStandingOrderResponse.ProtoReflect.Descriptor instead.
+func (*GetStandingOrderResponse) Descriptor() ([]byte, []int) {
+	return file_scheduler_v1_scheduler_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetStandingOrderResponse) GetOrder() *StandingOrder {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+type ListStandingOrdersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListStandingOrdersRequest) Reset() {
+	*x = ListStandingOrdersRequest{}
+	mi := &file_scheduler_v1_scheduler_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListStandingOrdersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListStandingOrdersRequest) ProtoMessage() {}
+
+func (x *ListStandingOrdersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_v1_scheduler_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListStandingOrdersRequest.ProtoReflect.Descriptor instead.
+func (*ListStandingOrdersRequest) Descriptor() ([]byte, []int) {
+	return file_scheduler_v1_scheduler_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListStandingOrdersRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type ListStandingOrdersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Orders        []*StandingOrder       `protobuf:"bytes,1,rep,name=orders,proto3" json:"orders,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListStandingOrdersResponse) Reset() {
+	*x = ListStandingOrdersResponse{}
+	mi := &file_scheduler_v1_scheduler_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListStandingOrdersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListStandingOrdersResponse) ProtoMessage() {}
+
+func (x *ListStandingOrdersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_v1_scheduler_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListStandingOrdersResponse.ProtoReflect.Descriptor instead.
+func (*ListStandingOrdersResponse) Descriptor() ([]byte, []int) {
+	return file_scheduler_v1_scheduler_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListStandingOrdersResponse) GetOrders() []*StandingOrder {
+	if x != nil {
+		return x.Orders
+	}
+	return nil
+}
+
+type CancelStandingOrderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelStandingOrderRequest) Reset() {
+	*x = CancelStandingOrderRequest{}
+	mi := &file_scheduler_v1_scheduler_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelStandingOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelStandingOrderRequest) ProtoMessage() {}
+
+func (x *CancelStandingOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_v1_scheduler_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelStandingOrderRequest.ProtoReflect.Descriptor instead.
+func (*CancelStandingOrderRequest) Descriptor() ([]byte, []int) {
+	return file_scheduler_v1_scheduler_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *CancelStandingOrderRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type CancelStandingOrderResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Order         *StandingOrder         `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelStandingOrderResponse) Reset() {
+	*x = CancelStandingOrderResponse{}
+	mi := &file_scheduler_v1_scheduler_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelStandingOrderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelStandingOrderResponse) ProtoMessage() {}
+
+func (x *CancelStandingOrderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_v1_scheduler_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelStandingOrderResponse.ProtoReflect.Descriptor instead.
+func (*CancelStandingOrderResponse) Descriptor() ([]byte, []int) {
+	return file_scheduler_v1_scheduler_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *CancelStandingOrderResponse) GetOrder() *StandingOrder {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+type ListExecutionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListExecutionsRequest) Reset() {
+	*x = ListExecutionsRequest{}
+	mi := &file_scheduler_v1_scheduler_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListExecutionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListExecutionsRequest) ProtoMessage() {}
+
+func (x *ListExecutionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_v1_scheduler_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListExecutionsRequest.ProtoReflect.Descriptor instead.
+func (*ListExecutionsRequest) Descriptor() ([]byte, []int) {
+	return file_scheduler_v1_scheduler_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ListExecutionsRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+type ListExecutionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Executions    []*Execution           `protobuf:"bytes,1,rep,name=executions,proto3" json:"executions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListExecutionsResponse) Reset() {
+	*x = ListExecutionsResponse{}
+	mi := &file_scheduler_v1_scheduler_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListExecutionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListExecutionsResponse) ProtoMessage() {}
+
+func (x *ListExecutionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_scheduler_v1_scheduler_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListExecutionsResponse.ProtoReflect.Descriptor instead.
+func (*ListExecutionsResponse) Descriptor() ([]byte, []int) {
+	return file_scheduler_v1_scheduler_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ListExecutionsResponse) GetExecutions() []*Execution {
+	if x != nil {
+		return x.Executions
+	}
+	return nil
+}
+
+var File_scheduler_v1_scheduler_proto protoreflect.FileDescriptor
+
+const file_scheduler_v1_scheduler_proto_rawDesc = "" +
+	"\n" +
+	"\x1cscheduler/v1/scheduler.proto\x12\fscheduler.v1\x1a\x15common/v1/money.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xfc\x02\n" +
+	"\rStandingOrder\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12&\n" +
+	"\x0ffrom_account_id\x18\x03 \x01(\tR\rfromAccountId\x12\"\n" +
+	"\rto_account_id\x18\x04 \x01(\tR\vtoAccountId\x12(\n" +
+	"\x06amount\x18\x05 \x01(\v2\x10.common.v1.MoneyR\x06amount\x12\x1a\n" +
+	"\bschedule\x18\x06 \x01(\tR\bschedule\x129\n" +
+	"\x06status\x18\a \x01(\x0e2!.scheduler.v1.StandingOrderStatusR\x06status\x129\n" +
+	"\n" +
+	"created_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12:\n" +
+	"\vnext_run_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tnextRunAt\"\xaa\x02\n" +
+	"\tExecution\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12?\n" +
+	"\rscheduled_for\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\fscheduledFor\x12\x18\n" +
+	"\aattempt\x18\x03 \x01(\x05R\aattempt\x125\n" +
+	"\x06status\x18\x04 \x01(\x0e2\x1d.scheduler.v1.ExecutionStatusR\x06status\x12\x14\n" +
+	"\x05error\x18\x05 \x01(\tR\x05error\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x06 \x01(\tR\trequestId\x12;\n" +
+	"\vexecuted_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"executedAt\"\xe6\x01\n" +
+	"\x1aCreateStandingOrderRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12&\n" +
+	"\x0ffrom_account_id\x18\x02 \x01(\tR\rfromAccountId\x12\"\n" +
+	"\rto_account_id\x18\x03 \x01(\tR\vtoAccountId\x12(\n" +
+	"\x06amount\x18\x04 \x01(\v2\x10.common.v1.MoneyR\x06amount\x12\x1a\n" +
+	"\bschedule\x18\x05 \x01(\tR\bschedule\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x06 \x01(\tR\trequestId\"P\n" +
+	"\x1bCreateStandingOrderResponse\x121\n" +
+	"\x05order\x18\x01 \x01(\v2\x1b.scheduler.v1.StandingOrderR\x05order\")\n" +
+	"\x17GetStandingOrderRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"M\n" +
+	"\x18GetStandingOrderResponse\x121\n" +
+	"\x05order\x18\x01 \x01(\v2\x1b.scheduler.v1.StandingOrderR\x05order\"4\n" +
+	"\x19ListStandingOrdersRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"Q\n" +
+	"\x1aListStandingOrdersResponse\x123\n" +
+	"\x06orders\x18\x01 \x03(\v2\x1b.scheduler.v1.StandingOrderR\x06orders\",\n" +
+	"\x1aCancelStandingOrderRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"P\n" +
+	"\x1bCancelStandingOrderResponse\x121\n" +
+	"\x05order\x18\x01 \x01(\v2\x1b.scheduler.v1.StandingOrderR\x05order\"2\n" +
+	"\x15ListExecutionsRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\"Q\n" +
+	"\x16ListExecutionsResponse\x127\n" +
+	"\n" +
+	"executions\x18\x01 \x03(\v2\x17.scheduler.v1.ExecutionR\n" +
+	"executions*\x82\x01\n" +
+	"\x13StandingOrderStatus\x12%\n" +
+	"!STANDING_ORDER_STATUS_UNSPECIFIED\x10\x00\x12 \n" +
+	"\x1cSTANDING_ORDER_STATUS_ACTIVE\x10\x01\x12\"\n" +
+	"\x1eSTANDING_ORDER_STATUS_CANCELED\x10\x02*\xaa\x01\n" +
+	"\x0fExecutionStatus\x12 \n" +
+	"\x1cEXECUTION_STATUS_UNSPECIFIED\x10\x00\x12\x1e\n" +
+	"\x1aEXECUTION_STATUS_SUCCEEDED\x10\x01\x12\x1d\n" +
+	"\x19EXECUTION_STATUS_RETRYING\x10\x02\x12\x1b\n" +
+	"\x17EXECUTION_STATUS_FAILED\x10\x03\x12\x19\n" +
+	"\x15EXECUTION_STATUS_HELD\x10\x042\x91\x04\n" +
+	"\x0eStandingOrders\x12j\n" +
+	"\x13CreateStandingOrder\x12(.scheduler.v1.CreateStandingOrderRequest\x1a).scheduler.v1.CreateStandingOrderResponse\x12a\n" +
+	"\x10GetStandingOrder\x12%.scheduler.v1.GetStandingOrderRequest\x1a&.scheduler.v1.GetStandingOrderResponse\x12g\n" +
+	"\x12ListStandingOrders\x12'.scheduler.v1.ListStandingOrdersRequest\x1a(.scheduler.v1.ListStandingOrdersResponse\x12j\n" +
+	"\x13CancelStandingOrder\x12(.scheduler.v1.CancelStandingOrderRequest\x1a).scheduler.v1.CancelStandingOrderResponse\x12[\n" +
+	"\x0eListExecutions\x12#.scheduler.v1.ListExecutionsRequest\x1a$.scheduler.v1.ListExecutionsResponseBAZ?github.com/galadeat/bank-sim/api/proto/scheduler/v1;schedulerv1b\x06proto3"
+
+var (
+	file_scheduler_v1_scheduler_proto_rawDescOnce sync.Once
+	file_scheduler_v1_scheduler_proto_rawDescData []byte
+)
+
+func file_scheduler_v1_scheduler_proto_rawDescGZIP() []byte {
+	file_scheduler_v1_scheduler_proto_rawDescOnce.Do(func() {
+		file_scheduler_v1_scheduler_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_scheduler_v1_scheduler_proto_rawDesc), len(file_scheduler_v1_scheduler_proto_rawDesc)))
+	})
+	return file_scheduler_v1_scheduler_proto_rawDescData
+}
+
+var file_scheduler_v1_scheduler_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_scheduler_v1_scheduler_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_scheduler_v1_scheduler_proto_goTypes = []any{
+	(StandingOrderStatus)(0),            // 0: scheduler.v1.StandingOrderStatus
+	(ExecutionStatus)(0),                // 1: scheduler.v1.ExecutionStatus
+	(*StandingOrder)(nil),               // 2: scheduler.v1.StandingOrder
+	(*Execution)(nil),                   // 3: scheduler.v1.Execution
+	(*CreateStandingOrderRequest)(nil),  // 4: scheduler.v1.CreateStandingOrderRequest
+	(*CreateStandingOrderResponse)(nil), // 5: scheduler.v1.CreateStandingOrderResponse
+	(*GetStandingOrderRequest)(nil),     // 6: scheduler.v1.GetStandingOrderRequest
+	(*GetStandingOrderResponse)(nil),    // 7: scheduler.v1.GetStandingOrderResponse
+	(*ListStandingOrdersRequest)(nil),   // 8: scheduler.v1.ListStandingOrdersRequest
+	(*ListStandingOrdersResponse)(nil),  // 9: scheduler.v1.ListStandingOrdersResponse
+	(*CancelStandingOrderRequest)(nil),  // 10: scheduler.v1.CancelStandingOrderRequest
+	(*CancelStandingOrderResponse)(nil), // 11: scheduler.v1.CancelStandingOrderResponse
+	(*ListExecutionsRequest)(nil),       // 12: scheduler.v1.ListExecutionsRequest
+	(*ListExecutionsResponse)(nil),      // 13: scheduler.v1.ListExecutionsResponse
+	(*v1.Money)(nil),                    // 14: common.v1.Money
+	(*timestamppb.Timestamp)(nil),       // 15: google.protobuf.Timestamp
+}
+var file_scheduler_v1_scheduler_proto_depIdxs = []int32{
+	14, // 0: scheduler.v1.StandingOrder.amount:type_name -> common.v1.Money
+	0,  // 1: scheduler.v1.StandingOrder.status:type_name -> scheduler.v1.StandingOrderStatus
+	15, // 2: scheduler.v1.StandingOrder.created_at:type_name -> google.protobuf.Timestamp
+	15, // 3: scheduler.v1.StandingOrder.next_run_at:type_name -> google.protobuf.Timestamp
+	15, // 4: scheduler.v1.Execution.scheduled_for:type_name -> google.protobuf.Timestamp
+	1,  // 5: scheduler.v1.Execution.status:type_name -> scheduler.v1.ExecutionStatus
+	15, // 6: scheduler.v1.Execution.executed_at:type_name -> google.protobuf.Timestamp
+	14, // 7: scheduler.v1.CreateStandingOrderRequest.amount:type_name -> common.v1.Money
+	2,  // 8: scheduler.v1.CreateStandingOrderResponse.order:type_name -> scheduler.v1.StandingOrder
+	2,  // 9: scheduler.v1.GetStandingOrderResponse.order:type_name -> scheduler.v1.StandingOrder
+	2,  // 10: scheduler.v1.ListStandingOrdersResponse.orders:type_name -> scheduler.v1.StandingOrder
+	2,  // 11: scheduler.v1.CancelStandingOrderResponse.order:type_name -> scheduler.v1.StandingOrder
+	3,  // 12: scheduler.v1.ListExecutionsResponse.executions:type_name -> scheduler.v1.Execution
+	4,  // 13: scheduler.v1.StandingOrders.CreateStandingOrder:input_type -> scheduler.v1.CreateStandingOrderRequest
+	6,  // 14: scheduler.v1.StandingOrders.GetStandingOrder:input_type -> scheduler.v1.GetStandingOrderRequest
+	8,  // 15: scheduler.v1.StandingOrders.ListStandingOrders:input_type -> scheduler.v1.ListStandingOrdersRequest
+	10, // 16: scheduler.v1.StandingOrders.CancelStandingOrder:input_type -> scheduler.v1.CancelStandingOrderRequest
+	12, // 17: scheduler.v1.StandingOrders.ListExecutions:input_type -> scheduler.v1.ListExecutionsRequest
+	5,  // 18: scheduler.v1.StandingOrders.CreateStandingOrder:output_type -> scheduler.v1.CreateStandingOrderResponse
+	7,  // 19: scheduler.v1.StandingOrders.GetStandingOrder:output_type -> scheduler.v1.GetStandingOrderResponse
+	9,  // 20: scheduler.v1.StandingOrders.ListStandingOrders:output_type -> scheduler.v1.ListStandingOrdersResponse
+	11, // 21: scheduler.v1.StandingOrders.CancelStandingOrder:output_type -> scheduler.v1.CancelStandingOrderResponse
+	13, // 22: scheduler.v1.StandingOrders.ListExecutions:output_type -> scheduler.v1.ListExecutionsResponse
+	18, // [18:23] is the sub-list for method output_type
+	13, // [13:18] is the sub-list for method input_type
+	13, // [13:13] is the sub-list for extension type_name
+	13, // [13:13] is the sub-list for extension extendee
+	0,  // [0:13] is the sub-list for field type_name
+}
+
+func init() { file_scheduler_v1_scheduler_proto_init() }
+func file_scheduler_v1_scheduler_proto_init() {
+	if File_scheduler_v1_scheduler_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_scheduler_v1_scheduler_proto_rawDesc), len(file_scheduler_v1_scheduler_proto_rawDesc)),
+			NumEnums:      2,
+			NumMessages:   12,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_scheduler_v1_scheduler_proto_goTypes,
+		DependencyIndexes: file_scheduler_v1_scheduler_proto_depIdxs,
+		EnumInfos:         file_scheduler_v1_scheduler_proto_enumTypes,
+		MessageInfos:      file_scheduler_v1_scheduler_proto_msgTypes,
+	}.Build()
+	File_scheduler_v1_scheduler_proto = out.File
+	file_scheduler_v1_scheduler_proto_goTypes = nil
+	file_scheduler_v1_scheduler_proto_depIdxs = nil
+}
@@ -9,6 +9,7 @@ package userv1
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	wrapperspb "google.golang.org/protobuf/types/known/wrapperspb"
 	reflect "reflect"
 	sync "sync"
@@ -22,11 +23,63 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type UserStatus int32
+
+const (
+	UserStatus_USER_STATUS_UNSPECIFIED UserStatus = 0
+	UserStatus_USER_STATUS_ACTIVE      UserStatus = 1
+	UserStatus_USER_STATUS_CLOSED      UserStatus = 2 // soft-deleted, kept for reporting
+)
+
+// Enum value maps for UserStatus.
+var (
+	UserStatus_name = map[int32]string{
+		0: "USER_STATUS_UNSPECIFIED",
+		1: "USER_STATUS_ACTIVE",
+		2: "USER_STATUS_CLOSED",
+	}
+	UserStatus_value = map[string]int32{
+		"USER_STATUS_UNSPECIFIED": 0,
+		"USER_STATUS_ACTIVE":      1,
+		"USER_STATUS_CLOSED":      2,
+	}
+)
+
+func (x UserStatus) Enum() *UserStatus {
+	p := new(UserStatus)
+	*p = x
+	return p
+}
+
+func (x UserStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (UserStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_user_v1_user_proto_enumTypes[0].Descriptor()
+}
+
+func (UserStatus) Type() protoreflect.EnumType {
+	return &file_user_v1_user_proto_enumTypes[0]
+}
+
+func (x UserStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use UserStatus.Descriptor instead.
+func (UserStatus) EnumDescriptor() ([]byte, []int) {
+	return file_user_v1_user_proto_rawDescGZIP(), []int{0}
+}
+
 type UserInfo struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	Login         string                 `protobuf:"bytes,2,opt,name=login,proto3" json:"login,omitempty"`
 	Email         string                 `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"`
+	Status        UserStatus             `protobuf:"varint,4,opt,name=status,proto3,enum=user.v1.UserStatus" json:"status,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	Version       int64                  `protobuf:"varint,6,opt,name=version,proto3" json:"version,omitempty"` // starts at 1 and grows with every change; owner copies keep the newest
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -82,10 +135,32 @@ func (x *UserInfo) GetEmail() string {
 	return ""
 }
 
+func (x *UserInfo) GetStatus() UserStatus {
+	if x != nil {
+		return x.Status
+	}
+	return UserStatus_USER_STATUS_UNSPECIFIED
+}
+
+func (x *UserInfo) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *UserInfo) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
 type CreateUserRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Login         string                 `protobuf:"bytes,1,opt,name=login,proto3" json:"login,omitempty"`
 	Email         string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
+	RequestId     string                 `protobuf:"bytes,3,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"` // optional; retries with the same id create the user once
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -134,6 +209,13 @@ func (x *CreateUserRequest) GetEmail() string {
 	return ""
 }
 
+func (x *CreateUserRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
 type CreateUserResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -181,6 +263,7 @@ func (x *CreateUserResponse) GetId() string {
 type GetUserRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	IncludeClosed bool                   `protobuf:"varint,2,opt,name=include_closed,json=includeClosed,proto3" json:"include_closed,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -222,6 +305,13 @@ func (x *GetUserRequest) GetId() string {
 	return ""
 }
 
+func (x *GetUserRequest) GetIncludeClosed() bool {
+	if x != nil {
+		return x.IncludeClosed
+	}
+	return false
+}
+
 type GetUserResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	User          *UserInfo              `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
@@ -268,6 +358,7 @@ func (x *GetUserResponse) GetUser() *UserInfo {
 
 type ListUsersRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	IncludeClosed bool                   `protobuf:"varint,1,opt,name=include_closed,json=includeClosed,proto3" json:"include_closed,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -302,6 +393,13 @@ func (*ListUsersRequest) Descriptor() ([]byte, []int) {
 	return file_user_v1_user_proto_rawDescGZIP(), []int{5}
 }
 
+func (x *ListUsersRequest) GetIncludeClosed() bool {
+	if x != nil {
+		return x.IncludeClosed
+	}
+	return false
+}
+
 type ListUsersResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Users         []*UserInfo            `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
@@ -542,21 +640,29 @@ var File_user_v1_user_proto protoreflect.FileDescriptor
 
 const file_user_v1_user_proto_rawDesc = "" +
 	"\n" +
-	"\x12user/v1/user.proto\x12\auser.v1\x1a\x1egoogle/protobuf/wrappers.proto\"F\n" +
+	"\x12user/v1/user.proto\x12\auser.v1\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1egoogle/protobuf/wrappers.proto\"\xc8\x01\n" +
 	"\bUserInfo\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
 	"\x05login\x18\x02 \x01(\tR\x05login\x12\x14\n" +
-	"\x05email\x18\x03 \x01(\tR\x05email\"?\n" +
+	"\x05email\x18\x03 \x01(\tR\x05email\x12+\n" +
+	"\x06status\x18\x04 \x01(\x0e2\x13.user.v1.UserStatusR\x06status\x129\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12\x18\n" +
+	"\aversion\x18\x06 \x01(\x03R\aversion\"^\n" +
 	"\x11CreateUserRequest\x12\x14\n" +
 	"\x05login\x18\x01 \x01(\tR\x05login\x12\x14\n" +
-	"\x05email\x18\x02 \x01(\tR\x05email\"$\n" +
+	"\x05email\x18\x02 \x01(\tR\x05email\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x03 \x01(\tR\trequestId\"$\n" +
 	"\x12CreateUserResponse\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\" \n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"G\n" +
 	"\x0eGetUserRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\"8\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12%\n" +
+	"\x0einclude_closed\x18\x02 \x01(\bR\rincludeClosed\"8\n" +
 	"\x0fGetUserResponse\x12%\n" +
-	"\x04user\x18\x01 \x01(\v2\x11.user.v1.UserInfoR\x04user\"\x12\n" +
-	"\x10ListUsersRequest\"<\n" +
+	"\x04user\x18\x01 \x01(\v2\x11.user.v1.UserInfoR\x04user\"9\n" +
+	"\x10ListUsersRequest\x12%\n" +
+	"\x0einclude_closed\x18\x01 \x01(\bR\rincludeClosed\"<\n" +
 	"\x11ListUsersResponse\x12'\n" +
 	"\x05users\x18\x01 \x03(\v2\x11.user.v1.UserInfoR\x05users\"\x8b\x01\n" +
 	"\x11UpdateUserRequest\x12\x0e\n" +
@@ -568,7 +674,12 @@ const file_user_v1_user_proto_rawDesc = "" +
 	"\x11DeleteUserRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\".\n" +
 	"\x12DeleteUserResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess2\xdd\x02\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess*Y\n" +
+	"\n" +
+	"UserStatus\x12\x1b\n" +
+	"\x17USER_STATUS_UNSPECIFIED\x10\x00\x12\x16\n" +
+	"\x12USER_STATUS_ACTIVE\x10\x01\x12\x16\n" +
+	"\x12USER_STATUS_CLOSED\x10\x022\xdd\x02\n" +
 	"\x04User\x12E\n" +
 	"\n" +
 	"CreateUser\x12\x1a.user.v1.CreateUserRequest\x1a\x1b.user.v1.CreateUserResponse\x12<\n" +
@@ -591,42 +702,47 @@ func file_user_v1_user_proto_rawDescGZIP() []byte {
 	return file_user_v1_user_proto_rawDescData
 }
 
+var file_user_v1_user_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
 var file_user_v1_user_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
 var file_user_v1_user_proto_goTypes = []any{
-	(*UserInfo)(nil),               // 0: user.v1.UserInfo
-	(*CreateUserRequest)(nil),      // 1: user.v1.CreateUserRequest
-	(*CreateUserResponse)(nil),     // 2: user.v1.CreateUserResponse
-	(*GetUserRequest)(nil),         // 3: user.v1.GetUserRequest
-	(*GetUserResponse)(nil),        // 4: user.v1.GetUserResponse
-	(*ListUsersRequest)(nil),       // 5: user.v1.ListUsersRequest
-	(*ListUsersResponse)(nil),      // 6: user.v1.ListUsersResponse
-	(*UpdateUserRequest)(nil),      // 7: user.v1.UpdateUserRequest
-	(*UpdateUserResponse)(nil),     // 8: user.v1.UpdateUserResponse
-	(*DeleteUserRequest)(nil),      // 9: user.v1.DeleteUserRequest
-	(*DeleteUserResponse)(nil),     // 10: user.v1.DeleteUserResponse
-	(*wrapperspb.StringValue)(nil), // 11: google.protobuf.StringValue
+	(UserStatus)(0),                // 0: user.v1.UserStatus
+	(*UserInfo)(nil),               // 1: user.v1.UserInfo
+	(*CreateUserRequest)(nil),      // 2: user.v1.CreateUserRequest
+	(*CreateUserResponse)(nil),     // 3: user.v1.CreateUserResponse
+	(*GetUserRequest)(nil),         // 4: user.v1.GetUserRequest
+	(*GetUserResponse)(nil),        // 5: user.v1.GetUserResponse
+	(*ListUsersRequest)(nil),       // 6: user.v1.ListUsersRequest
+	(*ListUsersResponse)(nil),      // 7: user.v1.ListUsersResponse
+	(*UpdateUserRequest)(nil),      // 8: user.v1.UpdateUserRequest
+	(*UpdateUserResponse)(nil),     // 9: user.v1.UpdateUserResponse
+	(*DeleteUserRequest)(nil),      // 10: user.v1.DeleteUserRequest
+	(*DeleteUserResponse)(nil),     // 11: user.v1.DeleteUserResponse
+	(*timestamppb.Timestamp)(nil),  // 12: google.protobuf.Timestamp
+	(*wrapperspb.StringValue)(nil), // 13: google.protobuf.StringValue
 }
 var file_user_v1_user_proto_depIdxs = []int32{
-	0,  // 0: user.v1.GetUserResponse.user:type_name -> user.v1.UserInfo
-	0,  // 1: user.v1.ListUsersResponse.users:type_name -> user.v1.UserInfo
-	11, // 2: user.v1.UpdateUserRequest.login:type_name -> google.protobuf.StringValue
-	11, // 3: user.v1.UpdateUserRequest.email:type_name -> google.protobuf.StringValue
-	0,  // 4: user.v1.UpdateUserResponse.user:type_name -> user.v1.UserInfo
-	1,  // 5: user.v1.User.CreateUser:input_type -> user.v1.CreateUserRequest
-	3,  // 6: user.v1.User.GetUser:input_type -> user.v1.GetUserRequest
-	5,  // 7: user.v1.User.ListUsers:input_type -> user.v1.ListUsersRequest
-	7,  // 8: user.v1.User.UpdateUser:input_type -> user.v1.UpdateUserRequest
-	9,  // 9: user.v1.User.DeleteUser:input_type -> user.v1.DeleteUserRequest
-	2,  // 10: user.v1.User.CreateUser:output_type -> user.v1.CreateUserResponse
-	4,  // 11: user.v1.User.GetUser:output_type -> user.v1.GetUserResponse
-	6,  // 12: user.v1.User.ListUsers:output_type -> user.v1.ListUsersResponse
-	8,  // 13: user.v1.User.UpdateUser:output_type -> user.v1.UpdateUserResponse
-	10, // 14: user.v1.User.DeleteUser:output_type -> user.v1.DeleteUserResponse
-	10, // [10:15] is the sub-list for method output_type
-	5,  // [5:10] is the sub-list for method input_type
-	5,  // [5:5] is the sub-list for extension type_name
-	5,  // [5:5] is the sub-list for extension extendee
-	0,  // [0:5] is the sub-list for field type_name
+	0,  // 0: user.v1.UserInfo.status:type_name -> user.v1.UserStatus
+	12, // 1: user.v1.UserInfo.created_at:type_name -> google.protobuf.Timestamp
+	1,  // 2: user.v1.GetUserResponse.user:type_name -> user.v1.UserInfo
+	1,  // 3: user.v1.ListUsersResponse.users:type_name -> user.v1.UserInfo
+	13, // 4: user.v1.UpdateUserRequest.login:type_name -> google.protobuf.StringValue
+	13, // 5: user.v1.UpdateUserRequest.email:type_name -> google.protobuf.StringValue
+	1,  // 6: user.v1.UpdateUserResponse.user:type_name -> user.v1.UserInfo
+	2,  // 7: user.v1.User.CreateUser:input_type -> user.v1.CreateUserRequest
+	4,  // 8: user.v1.User.GetUser:input_type -> user.v1.GetUserRequest
+	6,  // 9: user.v1.User.ListUsers:input_type -> user.v1.ListUsersRequest
+	8,  // 10: user.v1.User.UpdateUser:input_type -> user.v1.UpdateUserRequest
+	10, // 11: user.v1.User.DeleteUser:input_type -> user.v1.DeleteUserRequest
+	3,  // 12: user.v1.User.CreateUser:output_type -> user.v1.CreateUserResponse
+	5,  // 13: user.v1.User.GetUser:output_type -> user.v1.GetUserResponse
+	7,  // 14: user.v1.User.ListUsers:output_type -> user.v1.ListUsersResponse
+	9,  // 15: user.v1.User.UpdateUser:output_type -> user.v1.UpdateUserResponse
+	11, // 16: user.v1.User.DeleteUser:output_type -> user.v1.DeleteUserResponse
+	12, // [12:17] is the sub-list for method output_type
+	7,  // [7:12] is the sub-list for method input_type
+	7,  // [7:7] is the sub-list for extension type_name
+	7,  // [7:7] is the sub-list for extension extendee
+	0,  // [0:7] is the sub-list for field type_name
 }
 
 func init() { file_user_v1_user_proto_init() }
@@ -639,13 +755,14 @@ func file_user_v1_user_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_user_v1_user_proto_rawDesc), len(file_user_v1_user_proto_rawDesc)),
-			NumEnums:      0,
+			NumEnums:      1,
 			NumMessages:   11,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_user_v1_user_proto_goTypes,
 		DependencyIndexes: file_user_v1_user_proto_depIdxs,
+		EnumInfos:         file_user_v1_user_proto_enumTypes,
 		MessageInfos:      file_user_v1_user_proto_msgTypes,
 	}.Build()
 	File_user_v1_user_proto = out.File
@@ -0,0 +1,15 @@
+// Command bank is the scriptable command line client, e.g.
+//
+//	bank user create --login alice --email alice@example.com
+//	bank account deposit <account-id> 10.50 USD
+package main
+
+import (
+	"os"
+
+	"github.com/galadeat/bank-sim/internal/cli"
+)
+
+func main() {
+	os.Exit(cli.Main(os.Args[1:], os.Stdout, os.Stderr))
+}
@@ -0,0 +1,248 @@
+// Command bankctl exports users, accounts and ledgers from the servers and
+// imports them back, as CSV (a directory of users.csv, accounts.csv and
+// transactions.csv) or as newline delimited JSON, e.g.
+//
+//	bankctl export demo/
+//	bankctl import --dry-run demo/
+//	bankctl --server 10.0.0.5 import demo.ndjson
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/galadeat/bank-sim/internal/dataset"
+	"github.com/galadeat/bank-sim/pkg/clients"
+)
+
+const usage = `usage: bankctl [--server host] [--timeout 1m] <command> [args]
+
+  export [--format csv|ndjson] <dir|file|->    write every user, account and ledger entry
+  import [--format csv|ndjson] [--dry-run] [-o table|json] <dir|file|->
+                                               validate a dataset and load it
+
+The format follows the path unless --format is given: .ndjson, .jsonl and -
+(stdin or stdout) are NDJSON, anything else is a CSV directory. Importing the
+same dataset again creates nothing new.
+`
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("bankctl", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	server := fs.String("server", "localhost", "host the services listen on")
+	timeout := fs.Duration("timeout", time.Minute, "deadline of the whole export or import")
+	if err := fs.Parse(args); err != nil || fs.NArg() == 0 {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+	if fs.Arg(0) == "help" {
+		fmt.Fprint(stdout, usage)
+		return 0
+	}
+
+	c, err := clients.New(clients.WithHost(*server))
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 1
+	}
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	cmd, rest := fs.Arg(0), fs.Args()[1:]
+	switch cmd {
+	case "export":
+		err = export(ctx, c, rest, stdout)
+	case "import":
+		err = load(ctx, c, rest, stdin, stdout)
+	default:
+		fmt.Fprintf(stderr, "error: unknown command %q\n\n%s", cmd, usage)
+		return 2
+	}
+	var ue usageError
+	switch {
+	case errors.As(err, &ue):
+		fmt.Fprintf(stderr, "error: %v\n\n%s", err, usage)
+		return 2
+	case err != nil:
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// usageError is a command line that could not be understood.
+type usageError string
+
+func (e usageError) Error() string { return string(e) }
+
+func export(ctx context.Context, c *clients.Clients, args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	format := fs.String("format", "", "csv or ndjson, guessed from the path when empty")
+	path, err := parsePath(fs, args, format)
+	if err != nil {
+		return err
+	}
+
+	d, err := dataset.Export(ctx, c.User, c.Account)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case *format == "csv":
+		err = d.WriteCSV(path)
+	case path == "-":
+		err = d.WriteNDJSON(stdout)
+	default:
+		err = writeFile(path, d.WriteNDJSON)
+	}
+	if err != nil {
+		return err
+	}
+	if path != "-" {
+		fmt.Fprintf(stdout, "exported %d users, %d accounts and %d transactions to %s\n", len(d.Users), len(d.Accounts), len(d.Transactions), path)
+	}
+	return nil
+}
+
+func load(ctx context.Context, c *clients.Clients, args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	format := fs.String("format", "", "csv or ndjson, guessed from the path when empty")
+	dryRun := fs.Bool("dry-run", false, "validate and report without changing anything")
+	output := fs.String("output", "table", "output format, table or json")
+	fs.StringVar(output, "o", "table", "shorthand for --output")
+	path, err := parsePath(fs, args, format)
+	if err != nil {
+		return err
+	}
+	if *output != "table" && *output != "json" {
+		return usageError("output must be table or json")
+	}
+
+	var d *dataset.Dataset
+	switch {
+	case *format == "csv":
+		d, err = dataset.ReadCSV(path)
+	case path == "-":
+		d, err = dataset.ReadNDJSON(stdin)
+	default:
+		var f *os.File
+		if f, err = os.Open(path); err == nil {
+			d, err = dataset.ReadNDJSON(f)
+			f.Close()
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	report, err := dataset.Import(ctx, c.User, c.Account, d, *dryRun)
+	if err != nil {
+		return err
+	}
+	if err := printReport(stdout, report, *output == "json"); err != nil {
+		return err
+	}
+	if n := report.Failed(); n > 0 {
+		return fmt.Errorf("%d records failed to import", n)
+	}
+	return nil
+}
+
+// parsePath parses the flags and the single path argument, and fills in the
+// format from the path when it was not given.
+func parsePath(fs *flag.FlagSet, args []string, format *string) (string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return "", usageError(fmt.Sprintf("%s: %v", fs.Name(), err))
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			break
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+	if len(positional) != 1 {
+		return "", usageError(fmt.Sprintf("%s: expected one path", fs.Name()))
+	}
+	path := positional[0]
+
+	switch *format {
+	case "":
+		*format = "csv"
+		if ext := strings.ToLower(filepath.Ext(path)); path == "-" || ext == ".ndjson" || ext == ".jsonl" {
+			*format = "ndjson"
+		}
+	case "csv", "ndjson":
+	default:
+		return "", usageError("format must be csv or ndjson")
+	}
+	if *format == "csv" && path == "-" {
+		return "", usageError("csv datasets are directories and cannot use -")
+	}
+	return path, nil
+}
+
+func writeFile(path string, write func(io.Writer) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func printReport(w io.Writer, report *dataset.Report, asJSON bool) error {
+	if asJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "KIND\tID\tNEW ID\tACTION\tERROR")
+	counts := make(map[string]int)
+	for _, r := range report.Results {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", r.Kind, r.ID, r.NewID, r.Action, r.Error)
+		counts[r.Action]++
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	var parts []string
+	for _, action := range []string{dataset.ActionCreated, dataset.ActionExists, dataset.ActionRestored,
+		dataset.ActionWouldCreate, dataset.ActionWouldRestore, dataset.ActionFailed} {
+		if counts[action] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[action], action))
+		}
+	}
+	summary := strings.Join(parts, ", ")
+	if summary == "" {
+		summary = "nothing to import"
+	}
+	_, err := fmt.Fprintf(w, "%s; %d transactions\n", summary, report.Transactions)
+	return err
+}
@@ -1,14 +1,24 @@
 package main
 
 import (
+	"flag"
+	"fmt"
 	"log"
+	"os"
+	"path/filepath"
 
 	"github.com/galadeat/bank-sim/internal/repl"
+	"github.com/galadeat/bank-sim/internal/tui"
 	"github.com/galadeat/bank-sim/pkg/clients"
 	"github.com/galadeat/bank-sim/pkg/logger"
 )
 
 func main() {
+	menu := flag.Bool("menu", false, "use the numbered menus instead of the command shell")
+	script := flag.String("script", "", "run a .bank script and exit")
+	dashboard := flag.Bool("tui", false, "show the full-screen dashboard")
+	flag.Parse()
+
 	file := logger.Init("appClient.log")
 	defer file.Close()
 	
@@ -20,7 +30,31 @@ func main() {
 	}
 	defer clients.Close()
 
+	if *dashboard {
+		if err := tui.Run(clients.User, clients.Account); err != nil {
+			log.Fatalf("dashboard failed: %v", err)
+		}
+		return
+	}
 	// run REPL
-	repl.Run(clients.User, clients.Account)
+	if *menu {
+		repl.Run(clients.User, clients.Account)
+		return
+	}
+	shell := repl.NewShell(clients.User, clients.Account, os.Stdout)
+	if *script != "" {
+		if err := shell.RunScript(*script); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+	history := ""
+	if home, err := os.UserHomeDir(); err == nil {
+		history = filepath.Join(home, ".bank_history")
+	}
+	if err := shell.Interactive(history); err != nil {
+		log.Fatalf("shell failed: %v", err)
+	}
 
 }
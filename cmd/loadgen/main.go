@@ -0,0 +1,96 @@
+// Command loadgen runs virtual users against the bank servers, reports latency,
+// throughput and status codes, and checks that no money appeared or vanished.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"os"
+	"os/signal"
+	"sync"
+	"time"
+
+	"github.com/galadeat/bank-sim/pkg/clients"
+)
+
+func main() {
+	users := flag.Int("users", 10, "number of virtual users")
+	duration := flag.Duration("duration", 30*time.Second, "how long the virtual users send requests")
+	requests := flag.Int("requests", 0, "requests per virtual user, 0 for no limit")
+	mixFlag := flag.String("mix", defaultMix, "operation weights as op=weight pairs")
+	amount := flag.Int64("amount", 100, "largest deposit, withdrawal or transfer in USD")
+	initial := flag.Int64("initial", 1000, "opening balance of every account in USD")
+	timeout := flag.Duration("timeout", 5*time.Second, "timeout of a single request")
+	seed := flag.Uint64("seed", 0, "random seed, 0 picks one")
+	flag.Parse()
+
+	m, err := parseMix(*mixFlag)
+	if err != nil {
+		log.Fatalf("invalid mix: %v", err)
+	}
+	if *users < 1 || *amount < 1 || *initial < 0 {
+		log.Fatal("users and amount must be positive, initial must not be negative")
+	}
+	if *seed == 0 {
+		*seed = rand.Uint64()
+	}
+
+	c, err := clients.New()
+	if err != nil {
+		log.Fatalf("failed to init clients: %v", err)
+	}
+	defer c.Close()
+
+	cfg := config{
+		mix:       m,
+		requests:  *requests,
+		maxAmount: *amount,
+		initial:   *initial,
+		timeout:   *timeout,
+		runID:     fmt.Sprintf("%06d", *seed%1_000_000),
+	}
+	fmt.Printf("%d users for %s, seed %d, mix %s\n\n", *users, *duration, *seed, *mixFlag)
+
+	// interrupting stops the traffic early; the report and money check still run
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	runCtx, cancel := context.WithTimeout(ctx, *duration)
+	defer cancel()
+
+	p := &pool{}
+	vusers := make([]*vuser, *users)
+	var wg sync.WaitGroup
+	start := time.Now()
+	for i := range vusers {
+		vusers[i] = &vuser{
+			id:      i + 1,
+			cfg:     cfg,
+			clients: c,
+			pool:    p,
+			rand:    rand.New(rand.NewPCG(*seed, uint64(i))),
+			stats:   newStats(),
+		}
+		wg.Add(1)
+		go func(u *vuser) {
+			defer wg.Done()
+			u.run(runCtx)
+		}(vusers[i])
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	total := newStats()
+	for _, u := range vusers {
+		total.merge(u.stats)
+	}
+	total.report(os.Stdout, elapsed)
+
+	// the check runs on the parent context, so it is not cut short by -duration
+	unresolved := p.resolve(context.Background(), *timeout)
+	if !p.verify(context.Background(), c, *timeout, unresolved, os.Stdout) {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+)
+
+// op is a kind of request a virtual user sends.
+type op string
+
+const (
+	opCreateUser    op = "create_user"
+	opCreateAccount op = "create_account"
+	opDeposit       op = "deposit"
+	opWithdraw      op = "withdraw"
+	opTransfer      op = "transfer"
+)
+
+var ops = []op{opCreateUser, opCreateAccount, opDeposit, opWithdraw, opTransfer}
+
+const defaultMix = "create_user=2,create_account=3,deposit=35,withdraw=25,transfer=35"
+
+// mix picks operations at random in proportion to their weights.
+type mix struct {
+	ops     []op
+	weights []int
+	total   int
+}
+
+// parseMix reads a comma separated list of op=weight pairs, for example
+// "deposit=3,withdraw=1". Operations that are left out are not sent.
+func parseMix(s string) (*mix, error) {
+	m := &mix{}
+	seen := make(map[op]bool)
+	for _, part := range strings.Split(s, ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return nil, fmt.Errorf("mix entry %q is not op=weight", part)
+		}
+		o := op(strings.TrimSpace(name))
+		if !knownOp(o) {
+			return nil, fmt.Errorf("unknown operation %q, want one of %v", o, ops)
+		}
+		if seen[o] {
+			return nil, fmt.Errorf("operation %q is listed twice", o)
+		}
+		seen[o] = true
+		w, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || w < 0 {
+			return nil, fmt.Errorf("weight of %q must be a non-negative integer", o)
+		}
+		if w == 0 {
+			continue
+		}
+		m.ops = append(m.ops, o)
+		m.weights = append(m.weights, w)
+		m.total += w
+	}
+	if m.total == 0 {
+		return nil, fmt.Errorf("mix has no operation with a positive weight")
+	}
+	return m, nil
+}
+
+func (m *mix) pick(r *rand.Rand) op {
+	n := r.IntN(m.total)
+	for i, w := range m.weights {
+		if n < w {
+			return m.ops[i]
+		}
+		n -= w
+	}
+	return m.ops[len(m.ops)-1]
+}
+
+func knownOp(o op) bool {
+	for _, known := range ops {
+		if o == known {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	accountv2 "github.com/galadeat/bank-sim/api/proto/account/v2"
+	"github.com/galadeat/bank-sim/pkg/clients"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// call sends one request and reports how it changed the money in the pool.
+type call func(ctx context.Context) (outcome, error)
+
+// outcome is the effect of a successful request on the pool.
+type outcome struct {
+	account string // opened account
+	delta   int64  // nanos that entered (positive) or left (negative) the bank
+	held    bool   // payment held for review, nothing moved
+}
+
+// pool is every account the run opened and the money they should hold between
+// them: opening balances and deposits, less withdrawals. Transfers move money
+// inside the pool and do not change the total.
+type pool struct {
+	mu       sync.RWMutex
+	accounts []string
+	expected int64
+	held     int
+
+	// unknown are requests that failed in a way that leaves their outcome open,
+	// such as a timeout. They are sent again with the same request id at the end.
+	unknown []call
+}
+
+// apply sends c and settles its outcome.
+func (p *pool) apply(ctx context.Context, c call) error {
+	out, err := c(ctx)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	switch {
+	case err == nil:
+		p.settle(out)
+	case outcomeUnknown(err):
+		p.unknown = append(p.unknown, c)
+	}
+	return err
+}
+
+// settle adds an outcome to the pool. Callers must hold p.mu.
+func (p *pool) settle(out outcome) {
+	if out.account != "" {
+		p.accounts = append(p.accounts, out.account)
+	}
+	if out.held {
+		p.held++
+	}
+	p.expected += out.delta
+}
+
+func (p *pool) random(r *rand.Rand) string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if len(p.accounts) == 0 {
+		return ""
+	}
+	return p.accounts[r.IntN(len(p.accounts))]
+}
+
+// pair returns two different accounts, or empty ids while there are fewer than two.
+func (p *pool) pair(r *rand.Rand) (string, string) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	n := len(p.accounts)
+	if n < 2 {
+		return "", ""
+	}
+	i := r.IntN(n)
+	j := (i + 1 + r.IntN(n-1)) % n
+	return p.accounts[i], p.accounts[j]
+}
+
+// resolve resends the requests with an unknown outcome. Thanks to idempotent
+// request ids a request that was applied the first time is not applied again,
+// and the response tells what happened. It returns how many stay unknown.
+func (p *pool) resolve(ctx context.Context, timeout time.Duration) int {
+	p.mu.Lock()
+	pending := p.unknown
+	p.unknown = nil
+	p.mu.Unlock()
+
+	var left int
+	for _, c := range pending {
+		var err error
+		for attempt := 0; attempt < 3; attempt++ {
+			reqCtx, cancel := context.WithTimeout(ctx, timeout)
+			var out outcome
+			out, err = c(reqCtx)
+			cancel()
+			if err == nil {
+				p.mu.Lock()
+				p.settle(out)
+				p.mu.Unlock()
+				break
+			}
+			if !outcomeUnknown(err) {
+				break
+			}
+			time.Sleep(time.Duration(attempt+1) * 100 * time.Millisecond)
+		}
+		if outcomeUnknown(err) {
+			left++
+		}
+	}
+	return left
+}
+
+// verify sums the balances of every account in the pool and reports whether
+// they add up to the money that entered and left the bank.
+func (p *pool) verify(ctx context.Context, c *clients.Clients, timeout time.Duration, unresolved int, w io.Writer) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	var actual int64
+	for _, id := range p.accounts {
+		reqCtx, cancel := context.WithTimeout(ctx, timeout)
+		resp, err := c.Account.GetAccount(reqCtx, &accountv2.GetAccountRequest{Id: id})
+		cancel()
+		if err != nil {
+			fmt.Fprintf(w, "\nmoney check failed: cannot read account %s: %v\n", id, err)
+			return false
+		}
+		actual += nanos(resp.Account.Balance)
+	}
+
+	fmt.Fprintf(w, "\naccounts: %d, payments held for review: %d\n", len(p.accounts), p.held)
+	fmt.Fprintf(w, "expected total: %s\nactual total:   %s\n", formatNanos(p.expected), formatNanos(actual))
+	switch {
+	case unresolved > 0:
+		fmt.Fprintf(w, "money check inconclusive: %d requests have an unknown outcome\n", unresolved)
+		return false
+	case actual != p.expected:
+		fmt.Fprintf(w, "money NOT conserved: off by %s\n", formatNanos(actual-p.expected))
+		return false
+	}
+	fmt.Fprintln(w, "money conserved")
+	return true
+}
+
+// outcomeUnknown reports whether a failed request may still have been applied.
+func outcomeUnknown(err error) bool {
+	switch status.Code(err) {
+	case codes.DeadlineExceeded, codes.Canceled, codes.Unavailable, codes.Unknown, codes.Internal:
+		return true
+	}
+	return false
+}
+
+func formatNanos(n int64) string {
+	sign := ""
+	if n < 0 {
+		sign, n = "-", -n
+	}
+	return fmt.Sprintf("%s%d.%02d %s", sign, n/1_000_000_000, n%1_000_000_000/10_000_000, currency)
+}
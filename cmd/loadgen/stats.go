@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"text/tabwriter"
+	"time"
+
+	"google.golang.org/grpc/codes"
+)
+
+// stats collects the outcome of every request one virtual user sent. Each user
+// has its own, so recording needs no locking; they are merged at the end.
+type stats struct {
+	latencies map[op][]time.Duration
+	codes     map[op]map[codes.Code]int
+}
+
+func newStats() *stats {
+	return &stats{
+		latencies: make(map[op][]time.Duration),
+		codes:     make(map[op]map[codes.Code]int),
+	}
+}
+
+func (s *stats) record(o op, d time.Duration, code codes.Code) {
+	s.latencies[o] = append(s.latencies[o], d)
+	if s.codes[o] == nil {
+		s.codes[o] = make(map[codes.Code]int)
+	}
+	s.codes[o][code]++
+}
+
+func (s *stats) merge(other *stats) {
+	for o, ls := range other.latencies {
+		s.latencies[o] = append(s.latencies[o], ls...)
+	}
+	for o, byCode := range other.codes {
+		for c, n := range byCode {
+			if s.codes[o] == nil {
+				s.codes[o] = make(map[codes.Code]int)
+			}
+			s.codes[o][c] += n
+		}
+	}
+}
+
+// report writes throughput, latency percentiles and status codes per operation.
+func (s *stats) report(w io.Writer, elapsed time.Duration) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "operation\trequests\treq/s\tp50\tp90\tp99\tmax\terrors\t")
+
+	var total int
+	for _, o := range ops {
+		ls := s.latencies[o]
+		if len(ls) == 0 {
+			continue
+		}
+		slices.Sort(ls)
+		total += len(ls)
+		fmt.Fprintf(tw, "%s\t%d\t%.1f\t%s\t%s\t%s\t%s\t%d\t\n",
+			o, len(ls), float64(len(ls))/elapsed.Seconds(),
+			percentile(ls, 50), percentile(ls, 90), percentile(ls, 99), ls[len(ls)-1],
+			len(ls)-s.codes[o][codes.OK])
+	}
+	fmt.Fprintf(tw, "total\t%d\t%.1f\t\t\t\t\t\t\n", total, float64(total)/elapsed.Seconds())
+	tw.Flush()
+
+	fmt.Fprintln(w, "\nstatus codes:")
+	for _, o := range ops {
+		byCode := s.codes[o]
+		if len(byCode) == 0 {
+			continue
+		}
+		found := make([]codes.Code, 0, len(byCode))
+		for c := range byCode {
+			found = append(found, c)
+		}
+		slices.Sort(found)
+		fmt.Fprintf(w, "  %s:", o)
+		for _, c := range found {
+			fmt.Fprintf(w, " %s=%d", c, byCode[c])
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+// percentile returns the p-th percentile of sorted latencies, rounded to
+// microseconds for printing.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	i := (len(sorted)*p+99)/100 - 1
+	if i < 0 {
+		i = 0
+	}
+	return sorted[i].Round(time.Microsecond)
+}
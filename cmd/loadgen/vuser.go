@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	accountv2 "github.com/galadeat/bank-sim/api/proto/account/v2"
+	commonv1 "github.com/galadeat/bank-sim/api/proto/common/v1"
+	userv1 "github.com/galadeat/bank-sim/api/proto/user/v1"
+	"github.com/galadeat/bank-sim/pkg/clients"
+	"github.com/gofrs/uuid"
+	"google.golang.org/grpc/status"
+)
+
+const currency = "USD"
+
+// config is what every virtual user is run with.
+type config struct {
+	mix       *mix
+	requests  int
+	maxAmount int64
+	initial   int64
+	timeout   time.Duration
+	runID     string
+}
+
+// vuser is a virtual user. It opens a user and an account, then sends requests
+// picked from the mix until ctx is done or its request budget is spent.
+type vuser struct {
+	id      int
+	cfg     config
+	clients *clients.Clients
+	pool    *pool
+	rand    *rand.Rand
+	stats   *stats
+
+	userID string
+	logins int
+}
+
+func (u *vuser) run(ctx context.Context) {
+	u.send(ctx, opCreateUser)
+	u.send(ctx, opCreateAccount)
+
+	for n := 0; u.cfg.requests == 0 || n < u.cfg.requests; n++ {
+		if ctx.Err() != nil {
+			return
+		}
+		u.send(ctx, u.cfg.mix.pick(u.rand))
+	}
+}
+
+// send builds a request for o, sends it and records the result. Operations that
+// cannot be built yet, such as a transfer before there are two accounts, are
+// skipped.
+func (u *vuser) send(ctx context.Context, o op) {
+	c := u.call(o)
+	if c == nil {
+		return
+	}
+	// a request already on the wire is let finish when the run ends, so that
+	// it does not have to be resolved afterwards
+	reqCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), u.cfg.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := u.pool.apply(reqCtx, c)
+	u.stats.record(o, time.Since(start), status.Code(err))
+}
+
+// call returns the request for o as a function that can be sent again with the
+// same request id when its first outcome is unknown.
+func (u *vuser) call(o op) call {
+	switch o {
+	case opCreateUser:
+		u.logins++
+		login := fmt.Sprintf("load%s_%d_%d", u.cfg.runID, u.id, u.logins)
+		req := &userv1.CreateUserRequest{Login: login, Email: login + "@loadgen.test", RequestId: newRequestID()}
+		return func(ctx context.Context) (outcome, error) {
+			resp, err := u.clients.User.CreateUser(ctx, req)
+			if err != nil {
+				return outcome{}, err
+			}
+			u.userID = resp.Id
+			return outcome{}, nil
+		}
+
+	case opCreateAccount:
+		if u.userID == "" {
+			return nil
+		}
+		req := &accountv2.CreateAccountRequest{UserId: u.userID, InitialBalance: usd(u.cfg.initial), RequestId: newRequestID()}
+		return func(ctx context.Context) (outcome, error) {
+			resp, err := u.clients.Account.CreateAccount(ctx, req)
+			if err != nil {
+				return outcome{}, err
+			}
+			return outcome{account: resp.Account.Id, delta: nanos(resp.Account.Balance)}, nil
+		}
+
+	case opDeposit:
+		acc := u.pool.random(u.rand)
+		if acc == "" {
+			return nil
+		}
+		req := &accountv2.DepositRequest{AccountId: acc, Amount: u.amount(), RequestId: newRequestID()}
+		return func(ctx context.Context) (outcome, error) {
+			if _, err := u.clients.Account.Deposit(ctx, req); err != nil {
+				return outcome{}, err
+			}
+			return outcome{delta: nanos(req.Amount)}, nil
+		}
+
+	case opWithdraw:
+		acc := u.pool.random(u.rand)
+		if acc == "" {
+			return nil
+		}
+		req := &accountv2.WithdrawRequest{AccountId: acc, Amount: u.amount(), RequestId: newRequestID()}
+		return func(ctx context.Context) (outcome, error) {
+			resp, err := u.clients.Account.Withdraw(ctx, req)
+			if err != nil {
+				return outcome{}, err
+			}
+			// a withdrawal held for review has not moved any money yet
+			if resp.Review != nil {
+				return outcome{held: true}, nil
+			}
+			return outcome{delta: -nanos(req.Amount)}, nil
+		}
+
+	case opTransfer:
+		from, to := u.pool.pair(u.rand)
+		if from == "" {
+			return nil
+		}
+		req := &accountv2.TransferRequest{FromAccountId: from, ToAccountId: to, Amount: u.amount(), RequestId: newRequestID()}
+		return func(ctx context.Context) (outcome, error) {
+			resp, err := u.clients.Account.Transfer(ctx, req)
+			if err != nil {
+				return outcome{}, err
+			}
+			return outcome{held: resp.Review != nil}, nil
+		}
+	}
+	return nil
+}
+
+func (u *vuser) amount() *commonv1.Money {
+	return usd(1 + u.rand.Int64N(u.cfg.maxAmount))
+}
+
+func usd(units int64) *commonv1.Money {
+	return &commonv1.Money{Currency: currency, Units: units}
+}
+
+// nanos returns m in billionths of a unit, which is exact for any amount the
+// run can reach.
+func nanos(m *commonv1.Money) int64 {
+	return m.GetUnits()*1_000_000_000 + int64(m.GetNanos())
+}
+
+func newRequestID() string {
+	return uuid.Must(uuid.NewV4()).String()
+}
@@ -1,36 +1,90 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
+	"io/fs"
 	"log"
 	"net"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
+	"time"
 
 	accountv2 "github.com/galadeat/bank-sim/api/proto/account/v2"
+	loanv1 "github.com/galadeat/bank-sim/api/proto/loan/v1"
+	reportingv1 "github.com/galadeat/bank-sim/api/proto/reporting/v1"
+	schedulerv1 "github.com/galadeat/bank-sim/api/proto/scheduler/v1"
 	userv1 "github.com/galadeat/bank-sim/api/proto/user/v1"
 	"github.com/galadeat/bank-sim/internal/account"
+	"github.com/galadeat/bank-sim/internal/idempotency"
+	"github.com/galadeat/bank-sim/internal/loan"
+	"github.com/galadeat/bank-sim/internal/reporting"
+	"github.com/galadeat/bank-sim/internal/resilience"
+	"github.com/galadeat/bank-sim/internal/risk"
+	"github.com/galadeat/bank-sim/internal/rules"
+	"github.com/galadeat/bank-sim/internal/scheduler"
 	"github.com/galadeat/bank-sim/internal/user"
 	"github.com/galadeat/bank-sim/pkg/logger"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
 const (
-	accountPort = "localhost:50051"
-	userPort    = "localhost:50052"
+	accountPort   = "localhost:50051"
+	userPort      = "localhost:50052"
+	loanPort      = "localhost:50053"
+	schedulerPort = "localhost:50054"
+	reportingPort = "localhost:50055"
 )
 
 func main() {
+	rulesFile := flag.String("rules", "configs/rules.json", "transaction limits and velocity rules")
+	idempotencyFile := flag.String("idempotency", "idempotency.json", "where responses of completed requests are kept for retries, across restarts; replicas default to idempotency-<port>.json")
+	accountAddr := flag.String("account-addr", accountPort, "listen address of the account service")
+	replica := flag.Bool("account-replica", false, "run only an account service replica on -account-addr, using the user service of the main server")
+	userTimeout := flag.Duration("user-timeout", 2*time.Second, "deadline of each call from the account service to the user service")
+	userCache := flag.Duration("user-cache", 0, "how long the account service keeps users it looked up, 0 to always ask the user service")
+	flag.Parse()
+
 	file := logger.Init("appServer.log")
 	defer file.Close()
+
+	// each process needs a journal of its own, so replicas on one host do not
+	// overwrite each other's
+	journal := *idempotencyFile
+	if *replica && !flagSet("idempotency") {
+		journal = replicaJournal(*accountAddr)
+	}
+	store, err := idempotency.Open(journal)
+	if err != nil {
+		log.Fatalf("failed to open idempotency store: %v", err)
+	}
+	defer store.Close()
+
+	userOpts := []resilience.Option{resilience.WithTimeout(*userTimeout), resilience.WithCache(*userCache)}
+	if *replica {
+		runAccountReplica(*accountAddr, *rulesFile, store, userOpts)
+		return
+	}
+
 	lisUser, err := net.Listen("tcp", userPort)
 	if err != nil {
 		panic(err)
 	}
 
-	grpcUser := grpc.NewServer()
-	userSvc := user.New()
+	connAcc, err := grpc.NewClient(*accountAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		panic(err)
+	}
+	accountClient := accountv2.NewAccountClient(connAcc)
+
+	grpcUser := grpc.NewServer(grpc.UnaryInterceptor(idempotency.UnaryServerInterceptor(store, userv1.User_CreateUser_FullMethodName)))
+	userSvc := user.New(user.WithAccountClient(accountClient))
 	userv1.RegisterUserServer(grpcUser, userSvc)
 	go grpcUser.Serve(lisUser)
 
@@ -40,13 +94,45 @@ func main() {
 	}
 	userClient := userv1.NewUserClient(connUser)
 
-	lisAcc, err := net.Listen("tcp", accountPort)
+	lisAcc, err := net.Listen("tcp", *accountAddr)
 	if err != nil {
 		panic(err)
 	}
-	grpcAcc := grpc.NewServer()
-	accSvc := account.New(userClient)
-	accountv2.RegisterAccountServer(grpcAcc, accSvc)
+	grpcAcc, accSvc, accHealth := newAccountServer(userClient, *rulesFile, store, userOpts)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go accSvc.RunInterestScheduler(ctx)
+	go accSvc.RunHoldExpiry(ctx)
+
+	lisLoan, err := net.Listen("tcp", loanPort)
+	if err != nil {
+		panic(err)
+	}
+	grpcLoan := grpc.NewServer()
+	loanSvc := loan.New(userClient, accountClient)
+	loanv1.RegisterLoanServer(grpcLoan, loanSvc)
+	go grpcLoan.Serve(lisLoan)
+	go loanSvc.RunCollector(ctx)
+
+	lisScheduler, err := net.Listen("tcp", schedulerPort)
+	if err != nil {
+		panic(err)
+	}
+	grpcScheduler := grpc.NewServer()
+	schedulerSvc := scheduler.New(userClient, accountClient)
+	schedulerv1.RegisterStandingOrdersServer(grpcScheduler, schedulerSvc)
+	go grpcScheduler.Serve(lisScheduler)
+	go schedulerSvc.Run(ctx)
+
+	lisReporting, err := net.Listen("tcp", reportingPort)
+	if err != nil {
+		panic(err)
+	}
+	grpcReporting := grpc.NewServer()
+	reportingv1.RegisterReportingServer(grpcReporting, reporting.New(accountClient))
+	go grpcReporting.Serve(lisReporting)
+
 	log.Printf("servers started")
 	if err := grpcAcc.Serve(lisAcc); err != nil {
 		log.Fatalf("account service failed: %v", err)
@@ -56,6 +142,94 @@ func main() {
 	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
 	<-c
 	log.Println("shutting down")
+	accHealth.Shutdown()
 	grpcUser.GracefulStop()
 	grpcAcc.GracefulStop()
+	grpcLoan.GracefulStop()
+	grpcScheduler.GracefulStop()
+	grpcReporting.GracefulStop()
+}
+
+// newAccountServer returns the account service on a gRPC server that also
+// serves the health service, reporting the account service as serving. Its
+// calls to the user service go through a resilience.UserClient, so a user
+// service that hangs or fails makes them fail fast with Unavailable.
+func newAccountServer(userClient userv1.UserClient, rulesFile string, store *idempotency.Store, userOpts []resilience.Option) (*grpc.Server, *account.Service, *health.Server) {
+	accOpts := []account.Option{
+		account.WithRiskScorer(risk.NewHeuristic(risk.DefaultHeuristic)),
+		account.WithIdempotency(store),
+	}
+	engine, err := rules.Load(rulesFile)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		log.Printf("rules file %s not found, limits are disabled", rulesFile)
+	case err != nil:
+		log.Fatalf("failed to load rules: %v", err)
+	default:
+		accOpts = append(accOpts, account.WithRules(engine))
+	}
+
+	grpcAcc := grpc.NewServer()
+	accSvc := account.New(resilience.NewUserClient(userClient, userOpts...), accOpts...)
+	accountv2.RegisterAccountServer(grpcAcc, accSvc)
+	healthSrv := health.NewServer()
+	healthpb.RegisterHealthServer(grpcAcc, healthSrv)
+	healthSrv.SetServingStatus(accountv2.Account_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	return grpcAcc, accSvc, healthSrv
+}
+
+// flagSet reports whether the flag was given on the command line.
+func flagSet(name string) bool {
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+// replicaJournal returns the idempotency journal of the replica listening on
+// addr, named after its port, which no other process on the host can use.
+func replicaJournal(addr string) string {
+	_, port, err := net.SplitHostPort(addr)
+	if err != nil || port == "" {
+		port = strings.NewReplacer(":", "-", "/", "-").Replace(addr)
+	}
+	return "idempotency-" + port + ".json"
+}
+
+// runAccountReplica serves one more account service on addr until interrupted.
+// Replicas keep their accounts in memory, like the main server: an account
+// lives on the replica that created it, which is why clients send every account
+// call to one replica and use the others only while it is not serving.
+func runAccountReplica(addr, rulesFile string, store *idempotency.Store, userOpts []resilience.Option) {
+	connUser, err := grpc.NewClient(userPort, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		log.Fatalf("failed to connect to the user service: %v", err)
+	}
+	defer connUser.Close()
+
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		log.Fatalf("failed to listen on %s: %v", addr, err)
+	}
+	grpcAcc, accSvc, healthSrv := newAccountServer(userv1.NewUserClient(connUser), rulesFile, store, userOpts)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go accSvc.RunInterestScheduler(ctx)
+	go accSvc.RunHoldExpiry(ctx)
+	go func() {
+		<-ctx.Done()
+		log.Printf("account replica %s shutting down", addr)
+		// stop taking new calls from health checking clients before draining
+		healthSrv.Shutdown()
+		grpcAcc.GracefulStop()
+	}()
+
+	log.Printf("account replica started on %s", addr)
+	if err := grpcAcc.Serve(lis); err != nil {
+		log.Fatalf("account replica failed: %v", err)
+	}
 }
@@ -0,0 +1,116 @@
+// Command sim runs a deterministic simulation of the bank over simulated days and
+// writes the resulting ledger. Runs with the same seed and script are identical.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/galadeat/bank-sim/internal/account"
+	"github.com/galadeat/bank-sim/internal/risk"
+	"github.com/galadeat/bank-sim/internal/rules"
+	"github.com/galadeat/bank-sim/internal/sim"
+	"google.golang.org/grpc/codes"
+)
+
+func main() {
+	seed := flag.Uint64("seed", 1, "seed of ids and generated behavior")
+	days := flag.Int("days", 30, "number of simulated days")
+	customers := flag.Int("customers", 10, "number of generated customers, ignored with -script")
+	scriptFile := flag.String("script", "", "scenario file to run instead of generated behavior, e.g. configs/sim.json")
+	start := flag.String("start", "", "first simulated day as 2006-01-02, overrides the script")
+	rulesFile := flag.String("rules", "", "transaction limits and velocity rules, none when empty")
+	out := flag.String("out", "", "ledger file, stdout when empty")
+	verbose := flag.Bool("v", false, "print service logs to stderr")
+	flag.Parse()
+
+	if !*verbose {
+		log.SetOutput(io.Discard)
+	}
+
+	cfg := sim.Config{
+		Seed:           *seed,
+		Days:           *days,
+		Customers:      *customers,
+		AccountOptions: []account.Option{account.WithRiskScorer(risk.NewHeuristic(risk.DefaultHeuristic))},
+	}
+	if *scriptFile != "" {
+		script, err := sim.LoadScript(*scriptFile)
+		if err != nil {
+			fatalf("failed to load script: %v", err)
+		}
+		cfg.Script = script
+	}
+	if *start != "" {
+		t, err := time.Parse(time.DateOnly, *start)
+		if err != nil {
+			fatalf("start must look like 2006-01-02")
+		}
+		cfg.Start = t
+		if cfg.Script != nil {
+			cfg.Script.Start = t
+		}
+	}
+	if *rulesFile != "" {
+		engine, err := rules.Load(*rulesFile)
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			fatalf("rules file %s not found", *rulesFile)
+		case err != nil:
+			fatalf("failed to load rules: %v", err)
+		}
+		cfg.AccountOptions = append(cfg.AccountOptions, account.WithRules(engine))
+	}
+
+	s, err := sim.New(cfg)
+	if err != nil {
+		fatalf("failed to start simulation: %v", err)
+	}
+	defer s.Close()
+
+	ctx := context.Background()
+	if err := s.Run(ctx); err != nil {
+		fatalf("simulation failed: %v", err)
+	}
+
+	w := os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			fatalf("failed to create ledger file: %v", err)
+		}
+		defer f.Close()
+		w = f
+	}
+	if err := s.WriteLedger(ctx, w); err != nil {
+		fatalf("failed to write ledger: %v", err)
+	}
+
+	stats := s.Stats()
+	fmt.Fprintf(os.Stderr, "%d days, %d requests", *days, stats.Requests)
+	failed := make([]codes.Code, 0, len(stats.Failed))
+	for c := range stats.Failed {
+		failed = append(failed, c)
+	}
+	sort.Slice(failed, func(i, j int) bool { return failed[i] < failed[j] })
+	for _, c := range failed {
+		fmt.Fprintf(os.Stderr, ", %s=%d", c, stats.Failed[c])
+	}
+	if stats.Held > 0 {
+		fmt.Fprintf(os.Stderr, ", held=%d", stats.Held)
+	}
+	fmt.Fprintln(os.Stderr)
+}
+
+func fatalf(format string, args ...any) {
+	fmt.Fprintf(os.Stderr, format+"\n", args...)
+	os.Exit(1)
+}
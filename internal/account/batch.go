@@ -0,0 +1,403 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+
+	accountv2 "github.com/galadeat/bank-sim/api/proto/account/v2"
+	commonv1 "github.com/galadeat/bank-sim/api/proto/common/v1"
+	userv1 "github.com/galadeat/bank-sim/api/proto/user/v1"
+	"github.com/galadeat/bank-sim/internal/idempotency"
+	"github.com/galadeat/bank-sim/internal/risk"
+	"github.com/galadeat/bank-sim/internal/rules"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// maxBatchItems is the most items one batch or bulk posting stream may carry.
+const maxBatchItems = 10_000
+
+// BatchCreateAccounts is the realization of the rpc method. Every item is a
+// CreateAccount request and keeps its request id semantics. In all or nothing
+// mode the items are validated and their users looked up before any account is
+// opened.
+func (s *Service) BatchCreateAccounts(ctx context.Context, req *accountv2.BatchCreateAccountsRequest) (*accountv2.BatchCreateAccountsResponse, error) {
+	select {
+	case <-ctx.Done():
+		return nil, status.Error(codes.Canceled, "request canceled by client")
+	default:
+	}
+
+	if err := checkBatchSize(len(req.Items)); err != nil {
+		return nil, err
+	}
+
+	results := make([]*accountv2.BatchCreateAccountsResult, len(req.Items))
+	if req.Mode != accountv2.BatchMode_BATCH_MODE_ALL_OR_NOTHING {
+		for i, item := range req.Items {
+			resp, err := s.CreateAccount(ctx, item)
+			results[i] = &accountv2.BatchCreateAccountsResult{Index: int32(i), Account: snapshot(resp.GetAccount()), Error: batchError(err)}
+		}
+		return &accountv2.BatchCreateAccountsResponse{Results: results}, nil
+	}
+
+	products := make([]Product, len(req.Items))
+	keys := make([]string, len(req.Items))
+	fingerprints := make([]string, len(req.Items))
+	seen := make(map[string]int, len(req.Items))
+	for i, item := range req.Items {
+		product, err := s.validateCreateAccount(item)
+		if err != nil {
+			return nil, itemError(i, err)
+		}
+		if first, dup := seen[item.RequestId]; dup {
+			return nil, itemError(i, status.Errorf(codes.InvalidArgument, "request id is also used by item %d", first))
+		}
+		seen[item.RequestId] = i
+		fingerprint, err := idempotency.Fingerprint(item)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to fingerprint request: %v", err)
+		}
+		products[i] = product
+		keys[i] = idempotency.Key(accountv2.Account_CreateAccount_FullMethodName, item.RequestId)
+		fingerprints[i] = fingerprint
+	}
+
+	resps, err := s.idempotency.DoAll(keys, fingerprints, func(pending []int) ([]proto.Message, error) {
+		owners := make(map[string]*userv1.UserInfo)
+		for _, i := range pending {
+			userID := req.Items[i].UserId
+			if _, ok := owners[userID]; ok {
+				continue
+			}
+			owner, err := s.owner(ctx, userID, false)
+			if err != nil {
+				return nil, itemError(i, err)
+			}
+			owners[userID] = owner
+		}
+
+		out := make([]proto.Message, 0, len(pending))
+		for _, i := range pending {
+			owner := proto.Clone(owners[req.Items[i].UserId]).(*userv1.UserInfo)
+			resp, err := s.openAccount(req.Items[i], products[i], owner)
+			if err != nil {
+				return out, itemError(i, err)
+			}
+			out = append(out, resp)
+		}
+		return out, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i, resp := range resps {
+		results[i] = &accountv2.BatchCreateAccountsResult{Index: int32(i), Account: snapshot(resp.(*accountv2.CreateAccountResponse).Account)}
+	}
+	log.Printf("accounts created in batch: count=%d", len(results))
+	return &accountv2.BatchCreateAccountsResponse{Results: results}, nil
+}
+
+// BulkPost is the realization of the rpc method. In best effort mode every
+// posting is applied as it arrives, like a Deposit or Withdraw call. In all or
+// nothing mode the postings are collected, checked together against the
+// balances, limits and risk checks, and applied only if every one passes.
+func (s *Service) BulkPost(stream grpc.ClientStreamingServer[accountv2.BulkPostRequest, accountv2.BulkPostResponse]) error {
+	ctx := stream.Context()
+	select {
+	case <-ctx.Done():
+		return status.Error(codes.Canceled, "request canceled by client")
+	default:
+	}
+
+	var mode accountv2.BatchMode
+	var items []*accountv2.BulkPostRequest
+	resp := &accountv2.BulkPostResponse{}
+	for i := 0; ; i++ {
+		item, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if i == 0 {
+			mode = item.Mode
+		}
+		if i >= maxBatchItems {
+			return status.Errorf(codes.InvalidArgument, "at most %d postings are accepted per stream", maxBatchItems)
+		}
+
+		if mode == accountv2.BatchMode_BATCH_MODE_ALL_OR_NOTHING {
+			items = append(items, item)
+			continue
+		}
+		resp.Results = append(resp.Results, s.post(ctx, i, item))
+	}
+
+	if err := checkBatchSize(len(items) + len(resp.Results)); err != nil {
+		return err
+	}
+	if mode == accountv2.BatchMode_BATCH_MODE_ALL_OR_NOTHING {
+		results, err := s.postAll(items)
+		if err != nil {
+			return err
+		}
+		resp.Results = results
+	}
+
+	for _, r := range resp.Results {
+		switch {
+		case r.Error != nil:
+			resp.Failed++
+		case r.Review != nil:
+			resp.Held++
+		default:
+			resp.Applied++
+		}
+	}
+	log.Printf("bulk post: mode=%s, applied=%d, held=%d, failed=%d", mode, resp.Applied, resp.Held, resp.Failed)
+	return stream.SendAndClose(resp)
+}
+
+// post applies one posting in best effort mode.
+func (s *Service) post(ctx context.Context, i int, item *accountv2.BulkPostRequest) *accountv2.BulkPostResult {
+	result := &accountv2.BulkPostResult{Index: int32(i)}
+	switch p := item.Posting.(type) {
+	case *accountv2.BulkPostRequest_Deposit:
+		resp, err := s.Deposit(ctx, p.Deposit)
+		result.Account, result.Error = snapshot(resp.GetAccount()), batchError(err)
+	case *accountv2.BulkPostRequest_Withdraw:
+		resp, err := s.Withdraw(ctx, p.Withdraw)
+		result.Account, result.Review, result.Error = snapshot(resp.GetAccount()), resp.GetReview(), batchError(err)
+	default:
+		result.Error = batchError(status.Error(codes.InvalidArgument, "posting must be a deposit or a withdrawal"))
+	}
+	return result
+}
+
+// posting is a deposit or withdrawal of an all or nothing bulk post.
+type posting struct {
+	index     int
+	deposit   bool
+	accountID string
+	amount    *commonv1.Money
+	requestID string
+}
+
+// postAll applies every posting or none of them.
+func (s *Service) postAll(items []*accountv2.BulkPostRequest) ([]*accountv2.BulkPostResult, error) {
+	postings := make([]posting, len(items))
+	keys := make([]string, len(items))
+	fingerprints := make([]string, len(items))
+	seen := make(map[string]int, len(items))
+	for i, item := range items {
+		var p posting
+		var method string
+		var req proto.Message
+		switch v := item.Posting.(type) {
+		case *accountv2.BulkPostRequest_Deposit:
+			p = posting{deposit: true, accountID: v.Deposit.AccountId, amount: v.Deposit.Amount, requestID: v.Deposit.RequestId}
+			method, req = accountv2.Account_Deposit_FullMethodName, v.Deposit
+			if err := validatePosting(p.accountID, p.amount, p.requestID, "deposit"); err != nil {
+				return nil, itemError(i, err)
+			}
+		case *accountv2.BulkPostRequest_Withdraw:
+			p = posting{accountID: v.Withdraw.AccountId, amount: v.Withdraw.Amount, requestID: v.Withdraw.RequestId}
+			method, req = accountv2.Account_Withdraw_FullMethodName, v.Withdraw
+			if err := validatePosting(p.accountID, p.amount, p.requestID, "withdrawal"); err != nil {
+				return nil, itemError(i, err)
+			}
+		default:
+			return nil, itemError(i, status.Error(codes.InvalidArgument, "posting must be a deposit or a withdrawal"))
+		}
+		p.index = i
+
+		key := idempotency.Key(method, p.requestID)
+		if first, dup := seen[key]; dup {
+			return nil, itemError(i, status.Errorf(codes.InvalidArgument, "request id is also used by item %d", first))
+		}
+		seen[key] = i
+		fingerprint, err := idempotency.Fingerprint(req)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to fingerprint request: %v", err)
+		}
+		postings[i], keys[i], fingerprints[i] = p, key, fingerprint
+	}
+
+	resps, err := s.idempotency.DoAll(keys, fingerprints, func(pending []int) ([]proto.Message, error) {
+		ids := make([]string, 0, len(pending))
+		todo := make([]posting, 0, len(pending))
+		for _, i := range pending {
+			ids = append(ids, postings[i].accountID)
+			todo = append(todo, postings[i])
+		}
+		unlock := s.locks.lock(ids...)
+		defer unlock()
+		unlockOwners := s.lockOwners(ids...)
+		defer unlockOwners()
+
+		if err := s.checkPostings(todo); err != nil {
+			return nil, err
+		}
+
+		out := make([]proto.Message, 0, len(todo))
+		for _, p := range todo {
+			acc, _ := s.account(p.accountID)
+			if p.deposit {
+				resp, err := s.credit(acc, p.amount, p.requestID)
+				if err != nil {
+					log.Printf("bulk post failed after its checks passed: item=%d, err=%v", p.index, err)
+					return out, itemError(p.index, err)
+				}
+				out = append(out, &accountv2.DepositResponse{Account: snapshot(resp.Account)})
+				continue
+			}
+			// the risk checks already passed in checkPostings
+			resp, err := s.withdraw(acc, p.amount, p.requestID, false)
+			if err != nil {
+				log.Printf("bulk post failed after its checks passed: item=%d, err=%v", p.index, err)
+				return out, itemError(p.index, err)
+			}
+			out = append(out, &accountv2.WithdrawResponse{Account: snapshot(resp.Account)})
+		}
+		return out, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*accountv2.BulkPostResult, len(resps))
+	for i, resp := range resps {
+		result := &accountv2.BulkPostResult{Index: int32(i)}
+		switch r := resp.(type) {
+		case *accountv2.DepositResponse:
+			result.Account = snapshot(r.Account)
+		case *accountv2.WithdrawResponse:
+			result.Account, result.Review = snapshot(r.Account), r.Review
+		}
+		results[i] = result
+	}
+	return results, nil
+}
+
+// checkPostings replays the postings, in order, on copies of their accounts and
+// fails with the first one that would be refused, so that applying them cannot
+// fail halfway. Withdrawals the risk checks would hold or decline are refused.
+// Callers must hold the locks of the accounts.
+func (s *Service) checkPostings(postings []posting) error {
+	now := s.clock.Now()
+	copies := make(map[string]*accountv2.AccountInfo)
+	ops := make([]rules.Operation, 0, len(postings))
+	for _, p := range postings {
+		acc, ok := copies[p.accountID]
+		if !ok {
+			live, found := s.account(p.accountID)
+			if !found {
+				return itemError(p.index, status.Error(codes.NotFound, "account not found"))
+			}
+			s.expireAccountHolds(live, now)
+			acc = proto.Clone(live).(*accountv2.AccountInfo)
+			copies[p.accountID] = acc
+		}
+
+		if p.deposit {
+			if err := canReceive(acc); err != nil {
+				return itemError(p.index, err)
+			}
+			balance, err := addMoney(acc.Balance, p.amount)
+			if err != nil {
+				return itemError(p.index, err)
+			}
+			ops = append(ops, s.operation(acc, rules.KindDeposit, p.amount))
+			s.setBalance(acc, balance)
+			if acc.Status == statusPending {
+				acc.Status = statusActive
+			}
+			continue
+		}
+
+		if err := s.canDebit(acc); err != nil {
+			return itemError(p.index, err)
+		}
+		balance, err := balanceAfterDebit(acc, p.amount)
+		if err != nil {
+			return itemError(p.index, err)
+		}
+		if err := s.assessPosting(acc, p); err != nil {
+			return itemError(p.index, err)
+		}
+		ops = append(ops, s.operation(acc, rules.KindWithdrawal, p.amount))
+		wasOverdrawn := isNegative(acc.Balance)
+		s.setBalance(acc, balance)
+		if fee := s.overdraftFee(acc); fee != nil && !wasOverdrawn && isNegative(balance) {
+			if balance, err = substractMoney(balance, fee, &commonv1.Money{Units: maxUnits}); err == nil {
+				s.setBalance(acc, balance)
+			}
+		}
+	}
+
+	if s.rules != nil {
+		if i, err := s.rules.CheckAll(ops...); err != nil {
+			return itemError(postings[i].index, err)
+		}
+	}
+	return nil
+}
+
+// assessPosting refuses a withdrawal the risk scorer would not allow straight
+// away; it can be posted on its own to go through review.
+func (s *Service) assessPosting(acc *accountv2.AccountInfo, p posting) error {
+	if s.risk == nil {
+		return nil
+	}
+	a := s.risk.Assess(risk.Payment{
+		Type:      accountv2.TransactionType_TRANSACTION_TYPE_WITHDRAWAL,
+		AccountID: acc.Id,
+		UserID:    acc.GetOwnerId(),
+		Amount:    p.amount,
+		OpenedAt:  acc.OpenedAt.AsTime(),
+		At:        s.clock.Now(),
+		History:   s.history(acc.Id),
+	})
+	switch a.Decision {
+	case risk.Allow:
+		return nil
+	case risk.Decline:
+		return status.Error(codes.PermissionDenied, "payment declined by risk checks")
+	}
+	return status.Error(codes.FailedPrecondition, "withdrawal would be held for review, post it on its own")
+}
+
+func checkBatchSize(n int) error {
+	switch {
+	case n == 0:
+		return status.Error(codes.InvalidArgument, "items are required")
+	case n > maxBatchItems:
+		return status.Errorf(codes.InvalidArgument, "at most %d items are accepted per batch, got %d", maxBatchItems, n)
+	}
+	return nil
+}
+
+// itemError prefixes the message of err with the index of the failed item and
+// keeps its code and details.
+func itemError(i int, err error) error {
+	st := status.Convert(err).Proto()
+	st.Message = fmt.Sprintf("item %d: %s", i, st.Message)
+	return status.FromProto(st).Err()
+}
+
+// batchError returns err for a per-item result, or nil.
+func batchError(err error) *accountv2.BatchError {
+	if err == nil {
+		return nil
+	}
+	st := status.Convert(err)
+	return &accountv2.BatchError{Code: int32(st.Code()), Message: st.Message()}
+}
@@ -0,0 +1,288 @@
+package account
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"testing"
+
+	accountv2 "github.com/galadeat/bank-sim/api/proto/account/v2"
+	commonv1 "github.com/galadeat/bank-sim/api/proto/common/v1"
+	userv1 "github.com/galadeat/bank-sim/api/proto/user/v1"
+	"github.com/galadeat/bank-sim/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// bulkStream feeds postings to BulkPost and keeps its response.
+type bulkStream struct {
+	grpc.ServerStream
+	items []*accountv2.BulkPostRequest
+	resp  *accountv2.BulkPostResponse
+}
+
+func (s *bulkStream) Context() context.Context { return context.Background() }
+
+func (s *bulkStream) Recv() (*accountv2.BulkPostRequest, error) {
+	if len(s.items) == 0 {
+		return nil, io.EOF
+	}
+	item := s.items[0]
+	s.items = s.items[1:]
+	return item, nil
+}
+
+func (s *bulkStream) SendAndClose(resp *accountv2.BulkPostResponse) error {
+	s.resp = resp
+	return nil
+}
+
+func bulkPost(t *testing.T, svc *Service, mode accountv2.BatchMode, items ...*accountv2.BulkPostRequest) (*accountv2.BulkPostResponse, error) {
+	t.Helper()
+	if len(items) > 0 {
+		items[0].Mode = mode
+	}
+	stream := &bulkStream{items: items}
+	err := svc.BulkPost(stream)
+	return stream.resp, err
+}
+
+func bulkDeposit(accountID string, units int64, requestID string) *accountv2.BulkPostRequest {
+	return &accountv2.BulkPostRequest{Posting: &accountv2.BulkPostRequest_Deposit{Deposit: &accountv2.DepositRequest{
+		AccountId: accountID, Amount: &commonv1.Money{Currency: "USD", Units: units}, RequestId: requestID,
+	}}}
+}
+
+func bulkWithdraw(accountID string, units int64, requestID string) *accountv2.BulkPostRequest {
+	return &accountv2.BulkPostRequest{Posting: &accountv2.BulkPostRequest_Withdraw{Withdraw: &accountv2.WithdrawRequest{
+		AccountId: accountID, Amount: &commonv1.Money{Currency: "USD", Units: units}, RequestId: requestID,
+	}}}
+}
+
+func newAccountRequest(userID string, units int64, requestID string) *accountv2.CreateAccountRequest {
+	return &accountv2.CreateAccountRequest{UserId: userID, InitialBalance: &commonv1.Money{Currency: "USD", Units: units}, RequestId: requestID}
+}
+
+func balanceOf(t *testing.T, svc *Service, accountID string) int64 {
+	t.Helper()
+	resp, err := svc.GetAccount(context.Background(), &accountv2.GetAccountRequest{Id: accountID})
+	require.NoError(t, err)
+	return resp.Account.Balance.Units
+}
+
+func TestBatchCreateAccounts(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("best effort reports failed items", func(t *testing.T) {
+		svc := newTestService(t)
+		resp, err := svc.BatchCreateAccounts(ctx, &accountv2.BatchCreateAccountsRequest{Items: []*accountv2.CreateAccountRequest{
+			newAccountRequest("user-1", 10, "r-1"),
+			newAccountRequest("user-2", -5, "r-2"),
+			newAccountRequest("user-3", 0, "r-3"),
+		}})
+		require.NoError(t, err)
+		require.Len(t, resp.Results, 3)
+		assert.Equal(t, "user-1", resp.Results[0].Account.OwnerId)
+		assert.Nil(t, resp.Results[1].Account)
+		assert.Equal(t, int32(codes.InvalidArgument), resp.Results[1].Error.Code)
+		assert.Equal(t, int32(2), resp.Results[2].Index)
+		assert.Equal(t, "user-3", resp.Results[2].Account.OwnerId)
+	})
+
+	t.Run("all or nothing opens nothing if an item is invalid", func(t *testing.T) {
+		svc := newTestService(t)
+		_, err := svc.BatchCreateAccounts(ctx, &accountv2.BatchCreateAccountsRequest{
+			Mode: accountv2.BatchMode_BATCH_MODE_ALL_OR_NOTHING,
+			Items: []*accountv2.CreateAccountRequest{
+				newAccountRequest("user-1", 10, "r-1"),
+				newAccountRequest("user-2", -5, "r-2"),
+			},
+		})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		assert.Contains(t, status.Convert(err).Message(), "item 1: ")
+		assert.Empty(t, svc.accountIDs(""))
+	})
+
+	t.Run("all or nothing opens nothing if a user is missing", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		user := mocks.NewMockUserClient(ctrl)
+		user.EXPECT().GetUser(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, req *userv1.GetUserRequest, _ ...grpc.CallOption) (*userv1.GetUserResponse, error) {
+				if req.Id == "ghost" {
+					return nil, status.Error(codes.NotFound, "user doesn't exist!")
+				}
+				return &userv1.GetUserResponse{User: &userv1.UserInfo{Id: req.Id}}, nil
+			}).AnyTimes()
+		svc := New(user)
+
+		_, err := svc.BatchCreateAccounts(ctx, &accountv2.BatchCreateAccountsRequest{
+			Mode: accountv2.BatchMode_BATCH_MODE_ALL_OR_NOTHING,
+			Items: []*accountv2.CreateAccountRequest{
+				newAccountRequest("user-1", 10, "r-1"),
+				newAccountRequest("ghost", 10, "r-2"),
+			},
+		})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+		assert.Empty(t, svc.accountIDs(""))
+	})
+
+	t.Run("items keep their request ids", func(t *testing.T) {
+		svc := newTestService(t)
+		single, err := svc.CreateAccount(ctx, newAccountRequest("user-1", 10, "r-1"))
+		require.NoError(t, err)
+
+		req := &accountv2.BatchCreateAccountsRequest{
+			Mode:  accountv2.BatchMode_BATCH_MODE_ALL_OR_NOTHING,
+			Items: []*accountv2.CreateAccountRequest{newAccountRequest("user-1", 10, "r-1"), newAccountRequest("user-2", 20, "r-2")},
+		}
+		first, err := svc.BatchCreateAccounts(ctx, req)
+		require.NoError(t, err)
+		again, err := svc.BatchCreateAccounts(ctx, req)
+		require.NoError(t, err)
+
+		assert.Equal(t, single.Account.Id, first.Results[0].Account.Id)
+		assert.Equal(t, first.Results[1].Account.Id, again.Results[1].Account.Id)
+		assert.Len(t, svc.accountIDs(""), 2)
+
+		// a single call with an id used in the batch gets the batch's account
+		resp, err := svc.CreateAccount(ctx, newAccountRequest("user-2", 20, "r-2"))
+		require.NoError(t, err)
+		assert.Equal(t, first.Results[1].Account.Id, resp.Account.Id)
+	})
+
+	t.Run("duplicate request ids", func(t *testing.T) {
+		svc := newTestService(t)
+		_, err := svc.BatchCreateAccounts(ctx, &accountv2.BatchCreateAccountsRequest{
+			Mode:  accountv2.BatchMode_BATCH_MODE_ALL_OR_NOTHING,
+			Items: []*accountv2.CreateAccountRequest{newAccountRequest("user-1", 10, "r-1"), newAccountRequest("user-2", 10, "r-1")},
+		})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("empty batch", func(t *testing.T) {
+		svc := newTestService(t)
+		_, err := svc.BatchCreateAccounts(ctx, &accountv2.BatchCreateAccountsRequest{})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestBulkPost(t *testing.T) {
+	open := func(t *testing.T, svc *Service, units int64) string {
+		t.Helper()
+		resp, err := svc.CreateAccount(context.Background(), newAccountRequest("user-1", units, fmt.Sprint("open-", units)))
+		require.NoError(t, err)
+		return resp.Account.Id
+	}
+
+	t.Run("best effort applies what it can", func(t *testing.T) {
+		svc := newTestService(t)
+		acc := open(t, svc, 100)
+
+		resp, err := bulkPost(t, svc, accountv2.BatchMode_BATCH_MODE_BEST_EFFORT,
+			bulkDeposit(acc, 50, "r-1"),
+			bulkWithdraw(acc, 500, "r-2"),
+			bulkWithdraw(acc, 30, "r-3"),
+			bulkDeposit("missing", 5, "r-4"),
+		)
+		require.NoError(t, err)
+		assert.Equal(t, int32(2), resp.Applied)
+		assert.Equal(t, int32(2), resp.Failed)
+		assert.Equal(t, int64(150), resp.Results[0].Account.Balance.Units, "results show the balance after each posting")
+		assert.Equal(t, int32(codes.FailedPrecondition), resp.Results[1].Error.Code)
+		assert.Equal(t, int64(120), resp.Results[2].Account.Balance.Units)
+		assert.Equal(t, int32(codes.NotFound), resp.Results[3].Error.Code)
+		assert.Equal(t, int64(120), balanceOf(t, svc, acc))
+	})
+
+	t.Run("all or nothing counts earlier postings", func(t *testing.T) {
+		svc := newTestService(t)
+		acc := open(t, svc, 100)
+		other := open(t, svc, 0)
+
+		resp, err := bulkPost(t, svc, accountv2.BatchMode_BATCH_MODE_ALL_OR_NOTHING,
+			bulkDeposit(acc, 50, "r-1"),
+			bulkWithdraw(acc, 120, "r-2"),
+			bulkDeposit(other, 10, "r-3"),
+			bulkWithdraw(other, 10, "r-4"),
+		)
+		require.NoError(t, err)
+		assert.Equal(t, int32(4), resp.Applied)
+		assert.Equal(t, int64(30), balanceOf(t, svc, acc))
+		assert.Equal(t, int64(0), balanceOf(t, svc, other))
+	})
+
+	t.Run("all or nothing applies nothing if a posting fails", func(t *testing.T) {
+		svc := newTestService(t)
+		acc := open(t, svc, 100)
+
+		_, err := bulkPost(t, svc, accountv2.BatchMode_BATCH_MODE_ALL_OR_NOTHING,
+			bulkDeposit(acc, 50, "r-1"),
+			bulkWithdraw(acc, 120, "r-2"),
+			bulkWithdraw(acc, 40, "r-3"),
+		)
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+		assert.Contains(t, status.Convert(err).Message(), "item 2: ")
+		assert.Equal(t, int64(100), balanceOf(t, svc, acc))
+		assert.Len(t, svc.history(acc), 1, "only the opening deposit")
+
+		// nothing was saved, so the same request ids can be posted again
+		_, err = svc.Deposit(context.Background(), &accountv2.DepositRequest{AccountId: acc, Amount: &commonv1.Money{Currency: "USD", Units: 5}, RequestId: "r-3"})
+		assert.NoError(t, err)
+	})
+
+	t.Run("retries are applied once", func(t *testing.T) {
+		svc := newTestService(t)
+		acc := open(t, svc, 100)
+		_, err := svc.Withdraw(context.Background(), &accountv2.WithdrawRequest{AccountId: acc, Amount: &commonv1.Money{Currency: "USD", Units: 10}, RequestId: "r-2"})
+		require.NoError(t, err)
+
+		for range 2 {
+			resp, err := bulkPost(t, svc, accountv2.BatchMode_BATCH_MODE_ALL_OR_NOTHING,
+				bulkDeposit(acc, 50, "r-1"),
+				bulkWithdraw(acc, 10, "r-2"),
+			)
+			require.NoError(t, err)
+			assert.Equal(t, int32(2), resp.Applied)
+		}
+		assert.Equal(t, int64(140), balanceOf(t, svc, acc))
+
+		_, err = bulkPost(t, svc, accountv2.BatchMode_BATCH_MODE_BEST_EFFORT, bulkDeposit(acc, 50, "r-1"))
+		require.NoError(t, err)
+		assert.Equal(t, int64(140), balanceOf(t, svc, acc))
+	})
+
+	t.Run("request id reused with other parameters", func(t *testing.T) {
+		svc := newTestService(t)
+		acc := open(t, svc, 100)
+		_, err := bulkPost(t, svc, accountv2.BatchMode_BATCH_MODE_ALL_OR_NOTHING, bulkDeposit(acc, 50, "r-1"))
+		require.NoError(t, err)
+
+		_, err = bulkPost(t, svc, accountv2.BatchMode_BATCH_MODE_ALL_OR_NOTHING,
+			bulkDeposit(acc, 5, "r-2"),
+			bulkDeposit(acc, 60, "r-1"),
+		)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		assert.Equal(t, int64(150), balanceOf(t, svc, acc))
+	})
+
+	t.Run("invalid postings", func(t *testing.T) {
+		svc := newTestService(t)
+		acc := open(t, svc, 100)
+
+		_, err := bulkPost(t, svc, accountv2.BatchMode_BATCH_MODE_ALL_OR_NOTHING,
+			bulkDeposit(acc, 5, "r-1"),
+			bulkDeposit(acc, 5, "r-1"),
+		)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err), "duplicate request id")
+
+		_, err = bulkPost(t, svc, accountv2.BatchMode_BATCH_MODE_ALL_OR_NOTHING, &accountv2.BulkPostRequest{})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err), "no posting")
+
+		_, err = bulkPost(t, svc, accountv2.BatchMode_BATCH_MODE_BEST_EFFORT)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err), "empty stream")
+	})
+}
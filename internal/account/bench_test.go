@@ -0,0 +1,85 @@
+package account
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"math/rand/v2"
+	"sync/atomic"
+	"testing"
+
+	accountv2 "github.com/galadeat/bank-sim/api/proto/account/v2"
+	commonv1 "github.com/galadeat/bank-sim/api/proto/common/v1"
+)
+
+// benchAccounts opens n funded accounts with service logging turned off.
+func benchAccounts(b *testing.B, n int) (*Service, []string) {
+	b.Helper()
+	out := log.Writer()
+	log.SetOutput(io.Discard)
+	b.Cleanup(func() { log.SetOutput(out) })
+
+	svc := newTestService(b)
+	ids := make([]string, n)
+	for i := range ids {
+		ids[i] = createTestAccount(b, svc, 1_000_000_000).Id
+	}
+	return svc, ids
+}
+
+// BenchmarkDepositParallel deposits into many accounts from all Ps. With per-account
+// locks the throughput can grow with GOMAXPROCS instead of queueing on one mutex.
+func BenchmarkDepositParallel(b *testing.B) {
+	for _, n := range []int{1, 64, 1024} {
+		b.Run(fmt.Sprintf("accounts=%d", n), func(b *testing.B) {
+			svc, ids := benchAccounts(b, n)
+			var seq atomic.Int64
+			amount := &commonv1.Money{Currency: "USD", Units: 1}
+
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					i := seq.Add(1)
+					_, err := svc.Deposit(context.Background(), &accountv2.DepositRequest{
+						AccountId: ids[int(i)%len(ids)],
+						Amount:    amount,
+						RequestId: fmt.Sprintf("d-%d", i),
+					})
+					if err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		})
+	}
+}
+
+// BenchmarkTransferParallel moves money between random pairs of accounts.
+func BenchmarkTransferParallel(b *testing.B) {
+	for _, n := range []int{2, 64, 1024} {
+		b.Run(fmt.Sprintf("accounts=%d", n), func(b *testing.B) {
+			svc, ids := benchAccounts(b, n)
+			var seq atomic.Int64
+			amount := &commonv1.Money{Currency: "USD", Units: 1}
+
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					i := seq.Add(1)
+					from := rand.IntN(len(ids))
+					to := (from + 1 + rand.IntN(len(ids)-1)) % len(ids)
+					_, err := svc.Transfer(context.Background(), &accountv2.TransferRequest{
+						FromAccountId: ids[from],
+						ToAccountId:   ids[to],
+						Amount:        amount,
+						RequestId:     fmt.Sprintf("t-%d", i),
+					})
+					if err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		})
+	}
+}
@@ -0,0 +1,295 @@
+package account
+
+import (
+	"context"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	accountv2 "github.com/galadeat/bank-sim/api/proto/account/v2"
+	commonv1 "github.com/galadeat/bank-sim/api/proto/common/v1"
+	"github.com/galadeat/bank-sim/internal/rules"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	holdActive   = accountv2.HoldStatus_HOLD_STATUS_ACTIVE
+	holdCaptured = accountv2.HoldStatus_HOLD_STATUS_CAPTURED
+	holdVoided   = accountv2.HoldStatus_HOLD_STATUS_VOIDED
+	holdExpired  = accountv2.HoldStatus_HOLD_STATUS_EXPIRED
+
+	defaultHoldTTL = 7 * oneDay
+	maxHoldTTL     = 30 * oneDay
+)
+
+// AuthorizeHold is the realization of the rpc method. It reserves the amount on
+// the account without touching the ledger balance. Holds count as withdrawals
+// for the limits in the rules engine.
+func (s *Service) AuthorizeHold(ctx context.Context, req *accountv2.AuthorizeHoldRequest) (*accountv2.AuthorizeHoldResponse, error) {
+	select {
+	case <-ctx.Done():
+		return nil, status.Error(codes.Canceled, "request canceled by client")
+	default:
+	}
+	if req.AccountId == "" {
+		return nil, status.Error(codes.InvalidArgument, "account id is required")
+	}
+	if req.Amount == nil || (req.Amount.Units == 0 && req.Amount.Nanos == 0) || isNegative(req.Amount) {
+		return nil, status.Error(codes.InvalidArgument, "hold must be greater than zero")
+	}
+	if req.RequestId == "" {
+		return nil, status.Error(codes.InvalidArgument, "request id is required")
+	}
+	ttl := defaultHoldTTL
+	if req.Ttl != nil {
+		ttl = req.Ttl.AsDuration()
+		if ttl <= 0 || ttl > maxHoldTTL {
+			return nil, status.Errorf(codes.InvalidArgument, "ttl must be positive and at most %s", maxHoldTTL)
+		}
+	}
+
+	return once(s, accountv2.Account_AuthorizeHold_FullMethodName, req.RequestId, req, func() (*accountv2.AuthorizeHoldResponse, error) {
+		return s.authorizeHold(req, ttl)
+	})
+}
+
+// authorizeHold places the hold under the account's lock.
+func (s *Service) authorizeHold(req *accountv2.AuthorizeHoldRequest, ttl time.Duration) (*accountv2.AuthorizeHoldResponse, error) {
+	unlock := s.locks.lock(req.AccountId)
+	defer unlock()
+	unlockOwners := s.lockOwners(req.AccountId)
+	defer unlockOwners()
+
+	acc, ok := s.account(req.AccountId)
+	if !ok {
+		return nil, status.Error(codes.NotFound, "account not found")
+	}
+
+	now := s.clock.Now()
+	if _, err := s.debitBalance(acc, req.Amount); err != nil {
+		return nil, err
+	}
+	op := s.operation(acc, rules.KindWithdrawal, req.Amount)
+	if err := s.checkRules(op); err != nil {
+		return nil, err
+	}
+
+	hold := &accountv2.Hold{
+		Id:          s.ids.NewID(),
+		AccountId:   acc.Id,
+		Amount:      req.Amount,
+		Status:      holdActive,
+		RequestId:   req.RequestId,
+		Description: req.Description,
+		CreatedAt:   timestamppb.New(now),
+		ExpiresAt:   timestamppb.New(now.Add(ttl)),
+	}
+	s.mu.Lock()
+	s.holds[hold.Id] = hold
+	s.accountHolds[acc.Id] = append(s.accountHolds[acc.Id], hold)
+	s.mu.Unlock()
+	s.setBalance(acc, acc.Balance)
+	s.recordRules(op)
+
+	log.Printf("hold authorized: id=%s, account_id=%s, request_id=%s, amount=%v, expires_at=%s", hold.Id, acc.Id, req.RequestId, req.Amount, hold.ExpiresAt.AsTime().Format(time.RFC3339))
+
+	return &accountv2.AuthorizeHoldResponse{Hold: snapshot(hold), Account: snapshot(acc)}, nil
+}
+
+// CaptureHold is the realization of the rpc method. The captured amount is
+// debited from the ledger and the rest of the hold is released.
+func (s *Service) CaptureHold(ctx context.Context, req *accountv2.CaptureHoldRequest) (*accountv2.CaptureHoldResponse, error) {
+	select {
+	case <-ctx.Done():
+		return nil, status.Error(codes.Canceled, "request canceled by client")
+	default:
+	}
+	if req.HoldId == "" {
+		return nil, status.Error(codes.InvalidArgument, "hold id is required")
+	}
+	if req.Amount != nil && ((req.Amount.Units == 0 && req.Amount.Nanos == 0) || isNegative(req.Amount)) {
+		return nil, status.Error(codes.InvalidArgument, "capture must be greater than zero")
+	}
+	if req.RequestId == "" {
+		return nil, status.Error(codes.InvalidArgument, "request id is required")
+	}
+
+	return once(s, accountv2.Account_CaptureHold_FullMethodName, req.RequestId, req, func() (*accountv2.CaptureHoldResponse, error) {
+		return s.captureHold(req)
+	})
+}
+
+// captureHold debits the captured amount under the account's lock.
+func (s *Service) captureHold(req *accountv2.CaptureHoldRequest) (*accountv2.CaptureHoldResponse, error) {
+	hold, acc, unlock, err := s.activeHold(req.HoldId)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	amount := hold.Amount
+	if req.Amount != nil {
+		if req.Amount.Currency != hold.Amount.Currency {
+			return nil, status.Error(codes.InvalidArgument, "currency mismatch")
+		}
+		if compareMoney(req.Amount, hold.Amount) > 0 {
+			return nil, status.Error(codes.FailedPrecondition, "capture exceeds the held amount")
+		}
+		amount = req.Amount
+	}
+	if err := canSend(acc); err != nil {
+		return nil, err
+	}
+	// the hold already reserved the money, so only the overdraft limit applies here
+	balance, err := substractMoney(acc.Balance, amount, acc.OverdraftLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	s.resolveHold(hold, holdCaptured)
+	hold.CapturedAmount = amount
+
+	wasOverdrawn := isNegative(acc.Balance)
+	s.setBalance(acc, balance)
+	tx := s.record(acc, accountv2.TransactionType_TRANSACTION_TYPE_CAPTURE, amount, req.RequestId)
+	if !wasOverdrawn && isNegative(balance) {
+		s.chargeOverdraftFee(acc, req.RequestId)
+	}
+
+	log.Printf("hold captured: id=%s, account_id=%s, request_id=%s, amount=%v, new_balance=%v", hold.Id, acc.Id, req.RequestId, amount, acc.Balance)
+
+	return &accountv2.CaptureHoldResponse{Hold: snapshot(hold), Transaction: tx}, nil
+}
+
+// VoidHold is the realization of the rpc method
+func (s *Service) VoidHold(ctx context.Context, req *accountv2.VoidHoldRequest) (*accountv2.VoidHoldResponse, error) {
+	select {
+	case <-ctx.Done():
+		return nil, status.Error(codes.Canceled, "request canceled by client")
+	default:
+	}
+	if req.HoldId == "" {
+		return nil, status.Error(codes.InvalidArgument, "hold id is required")
+	}
+
+	hold, _, unlock, err := s.activeHold(req.HoldId)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	s.resolveHold(hold, holdVoided)
+
+	log.Printf("hold voided: id=%s, account_id=%s, reason=%q", hold.Id, hold.AccountId, req.Reason)
+	return &accountv2.VoidHoldResponse{Hold: snapshot(hold)}, nil
+}
+
+// ListHolds is the realization of the rpc method
+func (s *Service) ListHolds(ctx context.Context, req *accountv2.ListHoldsRequest) (*accountv2.ListHoldsResponse, error) {
+	select {
+	case <-ctx.Done():
+		return nil, status.Error(codes.Canceled, "request canceled by client")
+	default:
+	}
+	if req.AccountId == "" {
+		return nil, status.Error(codes.InvalidArgument, "account id is required")
+	}
+
+	unlock := s.locks.lock(req.AccountId)
+	defer unlock()
+
+	acc, ok := s.account(req.AccountId)
+	if !ok {
+		return nil, status.Error(codes.NotFound, "account not found")
+	}
+	s.expireAccountHolds(acc, s.clock.Now())
+
+	var holds []*accountv2.Hold
+	for _, h := range s.holdsOf(acc.Id) {
+		if req.IncludeInactive || h.Status == holdActive {
+			holds = append(holds, snapshot(h))
+		}
+	}
+	return &accountv2.ListHoldsResponse{Holds: holds}, nil
+}
+
+// RunHoldExpiry expires holds past their TTL once a minute until ctx is done.
+func (s *Service) RunHoldExpiry(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case t := <-s.clock.After(time.Minute):
+			s.ExpireHolds(t)
+		}
+	}
+}
+
+// ExpireHolds releases every active hold whose TTL has passed at now.
+func (s *Service) ExpireHolds(now time.Time) {
+	s.mu.RLock()
+	ids := make([]string, 0, len(s.accountHolds))
+	for id := range s.accountHolds {
+		ids = append(ids, id)
+	}
+	s.mu.RUnlock()
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		unlock := s.locks.lock(id)
+		if acc, ok := s.account(id); ok {
+			s.expireAccountHolds(acc, now)
+		}
+		unlock()
+	}
+}
+
+// activeHold returns a hold that can still be captured or voided and locks its
+// account, expiring the hold first if its TTL has passed. Unless an error is
+// returned, the caller must call unlock.
+func (s *Service) activeHold(id string) (*accountv2.Hold, *accountv2.AccountInfo, func(), error) {
+	s.mu.RLock()
+	hold, ok := s.holds[id]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, nil, nil, status.Error(codes.NotFound, "hold not found")
+	}
+
+	unlock := s.locks.lock(hold.AccountId)
+	acc, _ := s.account(hold.AccountId)
+	s.expireAccountHolds(acc, s.clock.Now())
+	if hold.Status != holdActive {
+		unlock()
+		return nil, nil, nil, status.Errorf(codes.FailedPrecondition, "hold is %s", holdStatusName(hold.Status))
+	}
+	return hold, acc, unlock, nil
+}
+
+// expireAccountHolds expires the account's holds that are past their TTL.
+// Callers must hold the lock of acc.
+func (s *Service) expireAccountHolds(acc *accountv2.AccountInfo, now time.Time) {
+	for _, h := range s.holdsOf(acc.Id) {
+		if h.Status == holdActive && !now.Before(h.ExpiresAt.AsTime()) {
+			s.resolveHold(h, holdExpired)
+			log.Printf("hold expired: id=%s, account_id=%s", h.Id, h.AccountId)
+		}
+	}
+}
+
+// resolveHold ends an active hold and releases its amount. Callers must hold the
+// lock of the hold's account.
+func (s *Service) resolveHold(hold *accountv2.Hold, to accountv2.HoldStatus) {
+	hold.Status = to
+	hold.ResolvedAt = timestamppb.New(s.clock.Now())
+	if to != holdCaptured {
+		hold.CapturedAmount = &commonv1.Money{Currency: hold.Amount.Currency}
+	}
+	acc, _ := s.account(hold.AccountId)
+	s.setBalance(acc, acc.Balance)
+}
+
+func holdStatusName(st accountv2.HoldStatus) string {
+	return strings.ToLower(strings.TrimPrefix(st.String(), "HOLD_STATUS_"))
+}
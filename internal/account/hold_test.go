@@ -0,0 +1,179 @@
+package account
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	accountv2 "github.com/galadeat/bank-sim/api/proto/account/v2"
+	commonv1 "github.com/galadeat/bank-sim/api/proto/common/v1"
+	"github.com/galadeat/bank-sim/pkg/clock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestHolds(t *testing.T) {
+	usd := func(units int64) *commonv1.Money {
+		return &commonv1.Money{Currency: "USD", Units: units}
+	}
+	checking := accountv2.AccountType_ACCOUNT_TYPE_CHECKING
+	get := func(t *testing.T, svc *Service, id string) *accountv2.AccountInfo {
+		t.Helper()
+		resp, err := svc.GetAccount(context.Background(), &accountv2.GetAccountRequest{Id: id})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return resp.Account
+	}
+	authorize := func(t *testing.T, svc *Service, accID string, units int64, reqID string) *accountv2.Hold {
+		t.Helper()
+		resp, err := svc.AuthorizeHold(context.Background(), &accountv2.AuthorizeHoldRequest{AccountId: accID, Amount: usd(units), RequestId: reqID})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return resp.Hold
+	}
+
+	t.Run("hold reduces available balance only", func(t *testing.T) {
+		svc := newClockedService(t, clock.NewManual(simStart))
+		acc := openAccount(t, svc, checking, 100, 0)
+
+		hold := authorize(t, svc, acc.Id, 70, "h-1")
+		again := authorize(t, svc, acc.Id, 70, "h-1")
+		if again.Id != hold.Id {
+			t.Errorf("expected the retried authorization to return the same hold")
+		}
+
+		got := get(t, svc, acc.Id)
+		assertMoney(t, usd(100), got.Balance)
+		assertMoney(t, usd(30), got.AvailableBalance)
+
+		_, err := svc.Withdraw(context.Background(), &accountv2.WithdrawRequest{AccountId: acc.Id, Amount: usd(31), RequestId: "w-1"})
+		if status.Code(err) != codes.FailedPrecondition {
+			t.Errorf("expected %v, got %v", codes.FailedPrecondition, err)
+		}
+		_, err = svc.AuthorizeHold(context.Background(), &accountv2.AuthorizeHoldRequest{AccountId: acc.Id, Amount: usd(31), RequestId: "h-2"})
+		if status.Code(err) != codes.FailedPrecondition {
+			t.Errorf("expected %v, got %v", codes.FailedPrecondition, err)
+		}
+	})
+
+	t.Run("partial capture releases the rest", func(t *testing.T) {
+		ctx := context.Background()
+		svc := newClockedService(t, clock.NewManual(simStart))
+		acc := openAccount(t, svc, checking, 100, 0)
+		hold := authorize(t, svc, acc.Id, 70, "h-1")
+
+		_, err := svc.CaptureHold(ctx, &accountv2.CaptureHoldRequest{HoldId: hold.Id, Amount: usd(71), RequestId: "c-1"})
+		if status.Code(err) != codes.FailedPrecondition {
+			t.Fatalf("expected %v, got %v", codes.FailedPrecondition, err)
+		}
+
+		capture := &accountv2.CaptureHoldRequest{HoldId: hold.Id, Amount: usd(45), RequestId: "c-1"}
+		resp, err := svc.CaptureHold(ctx, capture)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if resp.Hold.Status != holdCaptured {
+			t.Errorf("expected captured hold, got %v", resp.Hold.Status)
+		}
+		if resp.Transaction.Type != accountv2.TransactionType_TRANSACTION_TYPE_CAPTURE {
+			t.Errorf("expected a capture ledger entry, got %v", resp.Transaction.Type)
+		}
+		assertMoney(t, usd(45), resp.Hold.CapturedAmount)
+
+		got := get(t, svc, acc.Id)
+		assertMoney(t, usd(55), got.Balance)
+		assertMoney(t, usd(55), got.AvailableBalance)
+
+		// the same request is not captured twice, another one finds the hold closed
+		if _, err := svc.CaptureHold(ctx, capture); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		_, err = svc.CaptureHold(ctx, &accountv2.CaptureHoldRequest{HoldId: hold.Id, RequestId: "c-2"})
+		if status.Code(err) != codes.FailedPrecondition {
+			t.Errorf("expected %v, got %v", codes.FailedPrecondition, err)
+		}
+		assertMoney(t, usd(55), get(t, svc, acc.Id).Balance)
+	})
+
+	t.Run("void releases the hold", func(t *testing.T) {
+		ctx := context.Background()
+		svc := newClockedService(t, clock.NewManual(simStart))
+		acc := openAccount(t, svc, checking, 100, 0)
+		hold := authorize(t, svc, acc.Id, 70, "h-1")
+
+		if _, err := svc.VoidHold(ctx, &accountv2.VoidHoldRequest{HoldId: hold.Id, Reason: "order canceled"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got := get(t, svc, acc.Id)
+		assertMoney(t, usd(100), got.Balance)
+		assertMoney(t, usd(100), got.AvailableBalance)
+
+		_, err := svc.CaptureHold(ctx, &accountv2.CaptureHoldRequest{HoldId: hold.Id, RequestId: "c-1"})
+		if status.Code(err) != codes.FailedPrecondition {
+			t.Errorf("expected %v, got %v", codes.FailedPrecondition, err)
+		}
+	})
+
+	t.Run("holds expire after their ttl", func(t *testing.T) {
+		ctx := context.Background()
+		c := clock.NewManual(simStart)
+		svc := newClockedService(t, c)
+		acc := openAccount(t, svc, checking, 100, 0)
+
+		resp, err := svc.AuthorizeHold(ctx, &accountv2.AuthorizeHoldRequest{AccountId: acc.Id, Amount: usd(70), RequestId: "h-1", Ttl: durationpb.New(time.Hour)})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		c.Advance(59 * time.Minute)
+		svc.ExpireHolds(c.Now())
+		assertMoney(t, usd(30), get(t, svc, acc.Id).AvailableBalance)
+
+		c.Advance(time.Minute)
+		svc.ExpireHolds(c.Now())
+		assertMoney(t, usd(100), get(t, svc, acc.Id).AvailableBalance)
+
+		list, _ := svc.ListHolds(ctx, &accountv2.ListHoldsRequest{AccountId: acc.Id, IncludeInactive: true})
+		if len(list.Holds) != 1 || list.Holds[0].Status != holdExpired {
+			t.Fatalf("expected one expired hold, got %v", list.Holds)
+		}
+		_, err = svc.CaptureHold(ctx, &accountv2.CaptureHoldRequest{HoldId: resp.Hold.Id, RequestId: "c-1"})
+		if status.Code(err) != codes.FailedPrecondition {
+			t.Errorf("expected %v, got %v", codes.FailedPrecondition, err)
+		}
+	})
+
+	t.Run("expired holds do not block withdrawals before the sweep", func(t *testing.T) {
+		c := clock.NewManual(simStart)
+		svc := newClockedService(t, c)
+		acc := openAccount(t, svc, checking, 100, 0)
+		if _, err := svc.AuthorizeHold(context.Background(), &accountv2.AuthorizeHoldRequest{AccountId: acc.Id, Amount: usd(70), RequestId: "h-1", Ttl: durationpb.New(time.Hour)}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		c.Advance(2 * time.Hour)
+		if _, err := svc.Withdraw(context.Background(), &accountv2.WithdrawRequest{AccountId: acc.Id, Amount: usd(100), RequestId: "w-1"}); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("account with active holds cannot be closed", func(t *testing.T) {
+		svc := newClockedService(t, clock.NewManual(simStart))
+		acc := openAccount(t, svc, checking, 100, 0)
+		if _, err := svc.SetOverdraftLimit(context.Background(), &accountv2.SetOverdraftLimitRequest{AccountId: acc.Id, Limit: usd(50)}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := svc.Withdraw(context.Background(), &accountv2.WithdrawRequest{AccountId: acc.Id, Amount: usd(100), RequestId: "w-1"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		authorize(t, svc, acc.Id, 10, "h-1")
+
+		_, err := svc.CloseAccount(context.Background(), &accountv2.CloseAccountRequest{AccountId: acc.Id, Reason: "test"})
+		if status.Code(err) != codes.FailedPrecondition {
+			t.Errorf("expected %v, got %v", codes.FailedPrecondition, err)
+		}
+	})
+}
@@ -0,0 +1,48 @@
+package account
+
+import (
+	"log"
+
+	"github.com/galadeat/bank-sim/internal/idempotency"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// WithIdempotency sets the store that keeps responses for retried requests. By
+// default they are kept in memory for idempotency.DefaultTTL.
+func WithIdempotency(store *idempotency.Store) Option {
+	return func(s *Service) {
+		s.idempotency = store
+	}
+}
+
+// once runs fn for the first request to method with a request id and returns
+// the saved response to retries, including retries that arrive while fn is still
+// running. A request id reused with different parameters is rejected.
+func once[T proto.Message](s *Service, method, requestID string, req proto.Message, fn func() (T, error)) (T, error) {
+	var zero T
+	fingerprint, err := idempotency.Fingerprint(req)
+	if err != nil {
+		return zero, status.Errorf(codes.Internal, "failed to fingerprint request: %v", err)
+	}
+	resp, err := s.idempotency.Do(idempotency.Key(method, requestID), fingerprint, func() (proto.Message, error) {
+		return fn()
+	})
+	if err != nil {
+		return zero, err
+	}
+	out, ok := resp.(T)
+	if !ok {
+		return zero, status.Errorf(codes.Internal, "unexpected saved response %T", resp)
+	}
+	return out, nil
+}
+
+// rememberOutcome replaces the response saved for a request that was held for
+// review.
+func (s *Service) rememberOutcome(method, requestID string, resp proto.Message) {
+	if err := s.idempotency.Replace(idempotency.Key(method, requestID), resp); err != nil {
+		log.Printf("idempotency save failed: method=%s, request_id=%s, err=%v", method, requestID, err)
+	}
+}
@@ -0,0 +1,66 @@
+package account
+
+import (
+	"context"
+	"testing"
+
+	accountv2 "github.com/galadeat/bank-sim/api/proto/account/v2"
+	commonv1 "github.com/galadeat/bank-sim/api/proto/common/v1"
+	"github.com/galadeat/bank-sim/internal/idempotency"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRequestIDReuse(t *testing.T) {
+	usd := func(units int64) *commonv1.Money {
+		return &commonv1.Money{Currency: "USD", Units: units}
+	}
+
+	t.Run("different amount is rejected", func(t *testing.T) {
+		ctx := context.Background()
+		svc := newTestService(t)
+		acc := createTestAccount(t, svc, 100)
+
+		if _, err := svc.Deposit(ctx, &accountv2.DepositRequest{AccountId: acc.Id, Amount: usd(10), RequestId: "d-1"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		_, err := svc.Deposit(ctx, &accountv2.DepositRequest{AccountId: acc.Id, Amount: usd(20), RequestId: "d-1"})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Fatalf("expected %v, got %v", codes.InvalidArgument, err)
+		}
+		acc, _ = svc.account(acc.Id)
+		assertMoney(t, usd(110), acc.Balance)
+	})
+
+	t.Run("same id on another method is independent", func(t *testing.T) {
+		ctx := context.Background()
+		svc := newTestService(t)
+		acc := createTestAccount(t, svc, 100)
+
+		if _, err := svc.Deposit(ctx, &accountv2.DepositRequest{AccountId: acc.Id, Amount: usd(10), RequestId: "op-1"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := svc.Withdraw(ctx, &accountv2.WithdrawRequest{AccountId: acc.Id, Amount: usd(10), RequestId: "op-1"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		acc, _ = svc.account(acc.Id)
+		assertMoney(t, usd(100), acc.Balance)
+	})
+
+	t.Run("responses are shared through the store", func(t *testing.T) {
+		ctx := context.Background()
+		store := idempotency.New()
+		svc := newTestService(t)
+		WithIdempotency(store)(svc)
+		acc := createTestAccount(t, svc, 100)
+
+		req := &accountv2.WithdrawRequest{AccountId: acc.Id, Amount: usd(30), RequestId: "w-1"}
+		if _, err := svc.Withdraw(ctx, req); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		fp, _ := idempotency.Fingerprint(req)
+		if _, ok, _ := store.Lookup(idempotency.Key(accountv2.Account_Withdraw_FullMethodName, "w-1"), fp); !ok {
+			t.Errorf("expected the withdrawal to be saved in the store")
+		}
+	})
+}
@@ -0,0 +1,177 @@
+package account
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+	"time"
+
+	accountv2 "github.com/galadeat/bank-sim/api/proto/account/v2"
+	commonv1 "github.com/galadeat/bank-sim/api/proto/common/v1"
+)
+
+const (
+	daysPerYear = 365
+	bpsDivisor  = 10_000
+	oneDay      = 24 * time.Hour
+)
+
+// accrual tracks interest that has been earned or charged but not yet posted to
+// the balance.
+type accrual struct {
+	// through is the last day (UTC midnight) interest has been accrued for.
+	through time.Time
+	// credit is interest earned on positive balances, debit is overdraft interest
+	// charged on negative ones. Both are non-negative nanos.
+	credit int64
+	debit  int64
+}
+
+// RunInterestScheduler accrues interest once a day until ctx is done. Interest is
+// accrued daily on the end-of-day balance and posted to the account on the first
+// day of every month as an interest ledger entry. Overdrawn balances accrue
+// overdraft interest the same way.
+func (s *Service) RunInterestScheduler(ctx context.Context) {
+	for {
+		now := s.clock.Now()
+		next := startOfDay(now).Add(oneDay)
+		select {
+		case <-ctx.Done():
+			return
+		case t := <-s.clock.After(next.Sub(now)):
+			s.AccrueInterest(t)
+		}
+	}
+}
+
+// AccrueInterest brings every account's interest up to date with now. Days that
+// were missed since the previous run are accrued on the current balance, so the
+// clock can be fast-forwarded in simulations.
+func (s *Service) AccrueInterest(now time.Time) {
+	today := startOfDay(now)
+
+	// accountIDs iterates in a stable order so ledger entries are reproducible
+	for _, id := range s.accountIDs("") {
+		s.accrueAccount(id, today)
+	}
+}
+
+// accrueAccount brings one account's interest up to date with today. Accounts
+// are locked one at a time, so accrual does not stop other requests.
+func (s *Service) accrueAccount(id string, today time.Time) {
+	unlock := s.locks.lock(id)
+	defer unlock()
+
+	s.mu.RLock()
+	acc, a := s.accounts[id], s.accruals[id]
+	s.mu.RUnlock()
+	if a == nil || isClosed(acc) {
+		return
+	}
+	s.accrue(acc, a, today)
+}
+
+// accrue accrues the days up to today, posting the interest of every month that
+// ends on the way. Callers must hold the lock of acc.
+func (s *Service) accrue(acc *accountv2.AccountInfo, a *accrual, today time.Time) {
+	product := s.products[accountType(acc.Type)]
+
+	for day := a.through.Add(oneDay); !day.After(today); day = day.Add(oneDay) {
+		if day.Month() != a.through.Month() {
+			s.postInterest(acc, a, a.through.Format("2006-01"))
+		}
+		if isNegative(acc.Balance) {
+			a.debit -= dailyInterest(acc.Balance, product.OverdraftRateBps)
+		} else {
+			a.credit += dailyInterest(acc.Balance, product.AnnualRateBps)
+		}
+		a.through = day
+	}
+	showAccrued(acc, a)
+}
+
+// settleInterest accrues the account's interest up to today and posts it without
+// waiting for the end of the month. Accounts are settled before they are closed,
+// which takes a zero balance, so interest is paid out or charged with the rest
+// of the balance instead of being dropped with the account. Callers must hold
+// the lock of acc.
+func (s *Service) settleInterest(acc *accountv2.AccountInfo) {
+	s.mu.RLock()
+	a := s.accruals[acc.Id]
+	s.mu.RUnlock()
+	if a == nil {
+		return
+	}
+	s.accrue(acc, a, startOfDay(s.clock.Now()))
+	// entries of a settlement are told apart from the monthly one by the day
+	s.postInterest(acc, a, a.through.Format("2006-01-02"))
+	showAccrued(acc, a)
+}
+
+// showAccrued copies the interest accrued but not yet posted into acc.
+func showAccrued(acc *accountv2.AccountInfo, a *accrual) {
+	acc.AccruedInterest = nanosToMoney(a.credit, acc.Balance.GetCurrency())
+	acc.AccruedOverdraftInterest = nanosToMoney(a.debit, acc.Balance.GetCurrency())
+}
+
+// postInterest moves the accrued interest into the balance, at the end of every
+// month and when the account is settled; period names them in the request ids
+// of the ledger entries. Callers must hold the lock of acc.
+func (s *Service) postInterest(acc *accountv2.AccountInfo, a *accrual, period string) {
+
+	if a.credit != 0 {
+		amount := nanosToMoney(a.credit, acc.Balance.GetCurrency())
+		balance, err := addMoney(acc.Balance, amount)
+		if err != nil {
+			log.Printf("interest posting failed: account_id=%s, err=%v", acc.Id, err)
+			return
+		}
+		s.setBalance(acc, balance)
+		a.credit = 0
+		s.record(acc, accountv2.TransactionType_TRANSACTION_TYPE_INTEREST, amount, fmt.Sprintf("interest:%s:%s", acc.Id, period))
+
+		log.Printf("interest posted: account_id=%s, amount=%v, new_balance=%v", acc.Id, amount, acc.Balance)
+	}
+
+	if a.debit != 0 {
+		amount := nanosToMoney(a.debit, acc.Balance.GetCurrency())
+		balance, err := substractMoney(acc.Balance, amount, &commonv1.Money{Units: maxUnits})
+		if err != nil {
+			log.Printf("overdraft interest posting failed: account_id=%s, err=%v", acc.Id, err)
+			return
+		}
+		s.setBalance(acc, balance)
+		a.debit = 0
+		s.record(acc, accountv2.TransactionType_TRANSACTION_TYPE_OVERDRAFT_INTEREST, amount, fmt.Sprintf("overdraft-interest:%s:%s", acc.Id, period))
+
+		log.Printf("overdraft interest posted: account_id=%s, amount=%v, new_balance=%v", acc.Id, amount, acc.Balance)
+	}
+}
+
+// dailyInterest returns one day of interest on balance in nanos. The result has the
+// sign of the balance.
+func dailyInterest(balance *commonv1.Money, rateBps int64) int64 {
+	if rateBps == 0 || balance == nil {
+		return 0
+	}
+	n := big.NewInt(balance.Units)
+	n.Mul(n, big.NewInt(1_000_000_000))
+	n.Add(n, big.NewInt(int64(balance.Nanos)))
+	n.Mul(n, big.NewInt(rateBps))
+	n.Quo(n, big.NewInt(bpsDivisor*daysPerYear))
+	return n.Int64()
+}
+
+func nanosToMoney(nanos int64, currency string) *commonv1.Money {
+	return &commonv1.Money{
+		Currency: currency,
+		Units:    nanos / 1_000_000_000,
+		Nanos:    int32(nanos % 1_000_000_000),
+	}
+}
+
+func startOfDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,224 @@
+package account
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	accountv2 "github.com/galadeat/bank-sim/api/proto/account/v2"
+	commonv1 "github.com/galadeat/bank-sim/api/proto/common/v1"
+	userv1 "github.com/galadeat/bank-sim/api/proto/user/v1"
+	"github.com/galadeat/bank-sim/mocks"
+	"github.com/galadeat/bank-sim/pkg/clock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+var simStart = time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+
+func newClockedService(t *testing.T, c clock.Clock) *Service {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	user := mocks.NewMockUserClient(ctrl)
+	user.EXPECT().
+		GetUser(gomock.Any(), gomock.Any()).
+		Return(&userv1.GetUserResponse{User: &userv1.UserInfo{Id: "user-123"}}, nil).
+		AnyTimes()
+	return New(user, WithClock(c))
+}
+
+func openAccount(t *testing.T, svc *Service, typ accountv2.AccountType, units int64, termMonths int32) *accountv2.AccountInfo {
+	t.Helper()
+	resp, err := svc.CreateAccount(context.Background(), &accountv2.CreateAccountRequest{
+		UserId:         "user-123",
+		InitialBalance: &commonv1.Money{Currency: "USD", Units: units},
+		RequestId:      typ.String(),
+		Type:           typ,
+		TermMonths:     termMonths,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return resp.Account
+}
+
+func TestAccrueInterest(t *testing.T) {
+	t.Run("savings interest is posted monthly", func(t *testing.T) {
+		c := clock.NewManual(simStart)
+		svc := newClockedService(t, c)
+		acc := openAccount(t, svc, accountv2.AccountType_ACCOUNT_TYPE_SAVINGS, 1000, 0)
+
+		// 1000 USD at 2.5% earns 0.068493150 USD a day
+		c.Set(time.Date(2025, 1, 31, 12, 0, 0, 0, time.UTC))
+		svc.AccrueInterest(c.Now())
+		acc, _ = svc.account(acc.Id)
+		assert.Equal(t, int64(1000), acc.Balance.Units)
+		assertMoney(t, &commonv1.Money{Currency: "USD", Units: 2, Nanos: 54794500}, acc.AccruedInterest)
+
+		c.Set(time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC))
+		svc.AccrueInterest(c.Now())
+		acc, _ = svc.account(acc.Id)
+		assertMoney(t, &commonv1.Money{Currency: "USD", Units: 1002, Nanos: 54794500}, acc.Balance)
+		assert.Equal(t, int64(68633890), int64(acc.AccruedInterest.Nanos))
+
+		txs, err := svc.ListTransactions(context.Background(), &accountv2.ListTransactionsRequest{AccountId: acc.Id})
+		assert.NoError(t, err)
+		assert.Len(t, txs.Transactions, 2)
+		interest := txs.Transactions[1]
+		assert.Equal(t, accountv2.TransactionType_TRANSACTION_TYPE_INTEREST, interest.Type)
+		assertMoney(t, &commonv1.Money{Currency: "USD", Units: 2, Nanos: 54794500}, interest.Amount)
+		assert.Equal(t, "interest:"+acc.Id+":2025-01", interest.RequestId)
+	})
+
+	t.Run("fast-forward posts every month", func(t *testing.T) {
+		c := clock.NewManual(simStart)
+		svc := newClockedService(t, c)
+		acc := openAccount(t, svc, accountv2.AccountType_ACCOUNT_TYPE_SAVINGS, 1000, 0)
+
+		c.Set(time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC))
+		svc.AccrueInterest(c.Now())
+
+		txs, _ := svc.ListTransactions(context.Background(), &accountv2.ListTransactionsRequest{AccountId: acc.Id})
+		var postings int
+		for _, tx := range txs.Transactions {
+			if tx.Type == accountv2.TransactionType_TRANSACTION_TYPE_INTEREST {
+				postings++
+			}
+		}
+		assert.Equal(t, 6, postings)
+		acc, _ = svc.account(acc.Id)
+		assert.Equal(t, int64(1012), acc.Balance.Units)
+	})
+
+	t.Run("checking accounts earn nothing", func(t *testing.T) {
+		c := clock.NewManual(simStart)
+		svc := newClockedService(t, c)
+		acc := openAccount(t, svc, accountv2.AccountType_ACCOUNT_TYPE_CHECKING, 1000, 0)
+
+		c.Set(time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC))
+		svc.AccrueInterest(c.Now())
+		acc, _ = svc.account(acc.Id)
+		assertMoney(t, &commonv1.Money{Currency: "USD", Units: 1000}, acc.Balance)
+	})
+
+	t.Run("closing pays out the interest accrued so far", func(t *testing.T) {
+		ctx := context.Background()
+		c := clock.NewManual(simStart)
+		svc := newClockedService(t, c)
+		acc := openAccount(t, svc, accountv2.AccountType_ACCOUNT_TYPE_SAVINGS, 1000, 0)
+
+		c.Set(time.Date(2025, 1, 31, 12, 0, 0, 0, time.UTC))
+		svc.AccrueInterest(c.Now())
+		_, err := svc.Withdraw(ctx, &accountv2.WithdrawRequest{AccountId: acc.Id, Amount: &commonv1.Money{Currency: "USD", Units: 1000}, RequestId: "w1"})
+		require.NoError(t, err)
+
+		// the interest is posted, so the account is not empty yet
+		_, err = svc.CloseAccount(ctx, &accountv2.CloseAccountRequest{AccountId: acc.Id, Reason: "moving away"})
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+		acc, _ = svc.account(acc.Id)
+		interest := &commonv1.Money{Currency: "USD", Units: 2, Nanos: 54794500}
+		assertMoney(t, interest, acc.Balance)
+		assertMoney(t, &commonv1.Money{Currency: "USD"}, acc.AccruedInterest)
+		txs := svc.history(acc.Id)
+		last := txs[len(txs)-1]
+		assert.Equal(t, accountv2.TransactionType_TRANSACTION_TYPE_INTEREST, last.Type)
+		assert.Equal(t, "interest:"+acc.Id+":2025-01-31", last.RequestId)
+
+		_, err = svc.Withdraw(ctx, &accountv2.WithdrawRequest{AccountId: acc.Id, Amount: interest, RequestId: "w2"})
+		require.NoError(t, err)
+		resp, err := svc.CloseAccount(ctx, &accountv2.CloseAccountRequest{AccountId: acc.Id, Reason: "moving away"})
+		require.NoError(t, err)
+		assert.Equal(t, statusClosed, resp.Account.Status)
+
+		// nothing is left to post at the end of the month
+		c.Set(time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC))
+		svc.AccrueInterest(c.Now())
+		assert.Len(t, svc.history(acc.Id), len(txs)+1)
+	})
+
+	t.Run("running twice on the same day is a no-op", func(t *testing.T) {
+		c := clock.NewManual(simStart)
+		svc := newClockedService(t, c)
+		acc := openAccount(t, svc, accountv2.AccountType_ACCOUNT_TYPE_SAVINGS, 1000, 0)
+
+		c.Advance(24 * time.Hour)
+		svc.AccrueInterest(c.Now())
+		acc, _ = svc.account(acc.Id)
+		first := proto.Clone(acc.AccruedInterest).(*commonv1.Money)
+		svc.AccrueInterest(c.Now())
+		acc, _ = svc.account(acc.Id)
+		assertMoney(t, first, acc.AccruedInterest)
+	})
+}
+
+func TestRunInterestScheduler(t *testing.T) {
+	c := clock.NewManual(simStart)
+	svc := newClockedService(t, c)
+	acc := openAccount(t, svc, accountv2.AccountType_ACCOUNT_TYPE_SAVINGS, 1000, 0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go svc.RunInterestScheduler(ctx)
+
+	for day := 0; day < 31; day++ {
+		waitForWaiter(t, c)
+		c.Advance(24 * time.Hour)
+	}
+	waitForWaiter(t, c)
+
+	svc.mu.RLock()
+	defer svc.mu.RUnlock()
+	assert.Equal(t, int64(1002), svc.accounts[acc.Id].Balance.Units)
+}
+
+func assertMoney(t *testing.T, want, got *commonv1.Money) {
+	t.Helper()
+	if !proto.Equal(want, got) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func waitForWaiter(t *testing.T, c *clock.Manual) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for c.Waiters() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("scheduler is not waiting on the clock")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestTermDeposit(t *testing.T) {
+	c := clock.NewManual(simStart)
+	svc := newClockedService(t, c)
+
+	_, err := svc.CreateAccount(context.Background(), &accountv2.CreateAccountRequest{
+		UserId:         "user-123",
+		InitialBalance: &commonv1.Money{Currency: "USD", Units: 1000},
+		RequestId:      "no-term",
+		Type:           accountv2.AccountType_ACCOUNT_TYPE_TERM_DEPOSIT,
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	acc := openAccount(t, svc, accountv2.AccountType_ACCOUNT_TYPE_TERM_DEPOSIT, 1000, 3)
+	assert.Equal(t, time.Date(2025, 4, 1, 9, 0, 0, 0, time.UTC), acc.MaturesAt.AsTime())
+
+	withdraw := func(reqID string) error {
+		_, err := svc.Withdraw(context.Background(), &accountv2.WithdrawRequest{
+			AccountId: acc.Id,
+			Amount:    &commonv1.Money{Currency: "USD", Units: 100},
+			RequestId: reqID,
+		})
+		return err
+	}
+
+	assert.Equal(t, codes.FailedPrecondition, status.Code(withdraw("w1")))
+
+	c.Set(acc.MaturesAt.AsTime())
+	assert.NoError(t, withdraw("w2"))
+}
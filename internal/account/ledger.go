@@ -0,0 +1,52 @@
+package account
+
+import (
+	"context"
+
+	accountv2 "github.com/galadeat/bank-sim/api/proto/account/v2"
+	commonv1 "github.com/galadeat/bank-sim/api/proto/common/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ListTransactions is the realization of the rpc method
+func (s *Service) ListTransactions(ctx context.Context, req *accountv2.ListTransactionsRequest) (*accountv2.ListTransactionsResponse, error) {
+	select {
+	case <-ctx.Done():
+		return nil, status.Error(codes.Canceled, "request canceled by client")
+	default:
+	}
+
+	if req.AccountId == "" {
+		return nil, status.Error(codes.InvalidArgument, "account id is required")
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, ok := s.accounts[req.AccountId]; !ok {
+		return nil, status.Error(codes.NotFound, "account not found")
+	}
+
+	return &accountv2.ListTransactionsResponse{Transactions: s.ledger[req.AccountId]}, nil
+}
+
+// record appends a ledger entry for a balance change that has already been applied
+// to acc. Callers must hold the lock of acc.
+func (s *Service) record(acc *accountv2.AccountInfo, typ accountv2.TransactionType, amount *commonv1.Money, requestID string) *accountv2.Transaction {
+	tx := &accountv2.Transaction{
+		Id:           s.ids.NewID(),
+		AccountId:    acc.Id,
+		Type:         typ,
+		Amount:       amount,
+		BalanceAfter: acc.Balance,
+		RequestId:    requestID,
+		CreatedAt:    timestamppb.New(s.clock.Now()),
+	}
+	s.mu.Lock()
+	s.ledger[acc.Id] = append(s.ledger[acc.Id], tx)
+	s.transactions[tx.Id] = tx
+	s.mu.Unlock()
+	return tx
+}
@@ -0,0 +1,208 @@
+package account
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	accountv2 "github.com/galadeat/bank-sim/api/proto/account/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	statusPending = accountv2.AccountStatus_ACCOUNT_STATUS_PENDING
+	statusActive  = accountv2.AccountStatus_ACCOUNT_STATUS_ACTIVE
+	statusFrozen  = accountv2.AccountStatus_ACCOUNT_STATUS_FROZEN
+	statusClosed  = accountv2.AccountStatus_ACCOUNT_STATUS_CLOSED
+)
+
+// transitions lists the allowed lifecycle moves. UNSPECIFIED is the state of an
+// account that is being created.
+var transitions = map[accountv2.AccountStatus][]accountv2.AccountStatus{
+	accountv2.AccountStatus_ACCOUNT_STATUS_UNSPECIFIED: {statusPending, statusActive},
+	statusPending: {statusActive, statusFrozen, statusClosed},
+	statusActive:  {statusFrozen, statusClosed},
+	statusFrozen:  {statusPending, statusActive, statusClosed},
+}
+
+// FreezeAccount is the realization of the rpc method
+func (s *Service) FreezeAccount(ctx context.Context, req *accountv2.FreezeAccountRequest) (*accountv2.FreezeAccountResponse, error) {
+	acc, err := s.changeStatus(ctx, req.AccountId, req.Reason, func(acc *accountv2.AccountInfo) (accountv2.AccountStatus, error) {
+		return statusFrozen, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &accountv2.FreezeAccountResponse{Account: acc}, nil
+}
+
+// UnfreezeAccount is the realization of the rpc method. The account returns to the
+// state it had before it was frozen, except that a pending account credited while
+// frozen becomes active, as it would have on its first deposit.
+func (s *Service) UnfreezeAccount(ctx context.Context, req *accountv2.UnfreezeAccountRequest) (*accountv2.UnfreezeAccountResponse, error) {
+	acc, err := s.changeStatus(ctx, req.AccountId, req.Reason, func(acc *accountv2.AccountInfo) (accountv2.AccountStatus, error) {
+		if acc.Status != statusFrozen {
+			return 0, status.Error(codes.FailedPrecondition, "account is not frozen")
+		}
+		return s.statusBeforeFreeze(acc.Id), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &accountv2.UnfreezeAccountResponse{Account: acc}, nil
+}
+
+// CloseAccount is the realization of the rpc method
+func (s *Service) CloseAccount(ctx context.Context, req *accountv2.CloseAccountRequest) (*accountv2.CloseAccountResponse, error) {
+	acc, err := s.changeStatus(ctx, req.AccountId, req.Reason, func(acc *accountv2.AccountInfo) (accountv2.AccountStatus, error) {
+		if !isClosed(acc) {
+			s.settleInterest(acc)
+		}
+		if !isZeroBalance(acc) {
+			return 0, status.Error(codes.FailedPrecondition, "cannot close account with non-zero balance")
+		}
+		for _, h := range s.holdsOf(acc.Id) {
+			if h.Status == holdActive {
+				return 0, status.Error(codes.FailedPrecondition, "cannot close account with active holds")
+			}
+		}
+		return statusClosed, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &accountv2.CloseAccountResponse{Account: acc}, nil
+}
+
+// ListAccountEvents is the realization of the rpc method
+func (s *Service) ListAccountEvents(ctx context.Context, req *accountv2.ListAccountEventsRequest) (*accountv2.ListAccountEventsResponse, error) {
+	select {
+	case <-ctx.Done():
+		return nil, status.Error(codes.Canceled, "request canceled by client")
+	default:
+	}
+
+	if req.AccountId == "" {
+		return nil, status.Error(codes.InvalidArgument, "account id is required")
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, ok := s.accounts[req.AccountId]; !ok {
+		return nil, status.Error(codes.NotFound, "account not found")
+	}
+
+	return &accountv2.ListAccountEventsResponse{Events: s.audit[req.AccountId]}, nil
+}
+
+// changeStatus validates the request, asks target for the new status and applies it.
+// It returns a copy of the changed account.
+func (s *Service) changeStatus(ctx context.Context, accountID, reason string, target func(*accountv2.AccountInfo) (accountv2.AccountStatus, error)) (*accountv2.AccountInfo, error) {
+	select {
+	case <-ctx.Done():
+		return nil, status.Error(codes.Canceled, "request canceled by client")
+	default:
+	}
+
+	if accountID == "" {
+		return nil, status.Error(codes.InvalidArgument, "account id is required")
+	}
+	if reason == "" {
+		return nil, status.Error(codes.InvalidArgument, "reason is required")
+	}
+
+	unlock := s.locks.lock(accountID)
+	defer unlock()
+
+	acc, ok := s.account(accountID)
+	if !ok {
+		return nil, status.Error(codes.NotFound, "account not found")
+	}
+
+	to, err := target(acc)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.transition(acc, to, reason); err != nil {
+		return nil, err
+	}
+	return snapshot(acc), nil
+}
+
+// transition moves acc to the given status and records it in the audit trail.
+// Callers must hold the lock of acc.
+func (s *Service) transition(acc *accountv2.AccountInfo, to accountv2.AccountStatus, reason string) error {
+	from := acc.Status
+	if !canTransition(from, to) {
+		return status.Errorf(codes.FailedPrecondition, "account cannot move from %s to %s", statusName(from), statusName(to))
+	}
+
+	acc.Status = to
+	s.mu.Lock()
+	s.audit[acc.Id] = append(s.audit[acc.Id], &accountv2.StatusChange{
+		From:      from,
+		To:        to,
+		Reason:    reason,
+		ChangedAt: timestamppb.New(s.clock.Now()),
+	})
+	s.mu.Unlock()
+
+	log.Printf("account status changed: id=%s, from=%s, to=%s, reason=%q", acc.Id, statusName(from), statusName(to), reason)
+	return nil
+}
+
+// statusBeforeFreeze returns the status the account had when it was last frozen,
+// or active if it was pending then and has been funded since.
+func (s *Service) statusBeforeFreeze(accountID string) accountv2.AccountStatus {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	events := s.audit[accountID]
+	for i := len(events) - 1; i >= 0; i-- {
+		if events[i].To != statusFrozen {
+			continue
+		}
+		if events[i].From == statusPending && len(s.ledger[accountID]) > 0 {
+			return statusActive
+		}
+		return events[i].From
+	}
+	return statusActive
+}
+
+func canTransition(from, to accountv2.AccountStatus) bool {
+	for _, allowed := range transitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// canReceive reports whether money may be credited to the account.
+func canReceive(acc *accountv2.AccountInfo) error {
+	if acc.Status == statusClosed {
+		return status.Error(codes.FailedPrecondition, "account is closed")
+	}
+	return nil
+}
+
+// canSend reports whether money may be debited from the account.
+func canSend(acc *accountv2.AccountInfo) error {
+	switch acc.Status {
+	case statusActive:
+		return nil
+	case statusClosed:
+		return status.Error(codes.FailedPrecondition, "account is closed")
+	case statusFrozen:
+		return status.Error(codes.FailedPrecondition, "account is frozen")
+	default:
+		return status.Errorf(codes.FailedPrecondition, "account is %s", statusName(acc.Status))
+	}
+}
+
+func statusName(st accountv2.AccountStatus) string {
+	return strings.ToLower(strings.TrimPrefix(st.String(), "ACCOUNT_STATUS_"))
+}
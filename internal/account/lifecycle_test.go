@@ -0,0 +1,171 @@
+package account
+
+import (
+	"context"
+	"testing"
+
+	accountv2 "github.com/galadeat/bank-sim/api/proto/account/v2"
+	commonv1 "github.com/galadeat/bank-sim/api/proto/common/v1"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestAccountLifecycle(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("pending until first deposit", func(t *testing.T) {
+		svc := newTestService(t)
+		acc := createTestAccount(t, svc, 0)
+		assert.Equal(t, statusPending, acc.Status)
+
+		_, err := svc.Withdraw(ctx, &accountv2.WithdrawRequest{
+			AccountId: acc.Id, Amount: &commonv1.Money{Currency: "USD", Units: 1}, RequestId: "w1",
+		})
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+		_, err = svc.Deposit(ctx, &accountv2.DepositRequest{
+			AccountId: acc.Id, Amount: &commonv1.Money{Currency: "USD", Units: 10}, RequestId: "d1",
+		})
+		assert.NoError(t, err)
+		acc, _ = svc.account(acc.Id)
+		assert.Equal(t, statusActive, acc.Status)
+	})
+
+	t.Run("frozen account receives but does not send", func(t *testing.T) {
+		svc := newTestService(t)
+		acc := createTestAccount(t, svc, 100)
+
+		_, err := svc.FreezeAccount(ctx, &accountv2.FreezeAccountRequest{AccountId: acc.Id, Reason: "suspicious activity"})
+		assert.NoError(t, err)
+
+		_, err = svc.Deposit(ctx, &accountv2.DepositRequest{
+			AccountId: acc.Id, Amount: &commonv1.Money{Currency: "USD", Units: 10}, RequestId: "d1",
+		})
+		assert.NoError(t, err)
+
+		_, err = svc.Withdraw(ctx, &accountv2.WithdrawRequest{
+			AccountId: acc.Id, Amount: &commonv1.Money{Currency: "USD", Units: 10}, RequestId: "w1",
+		})
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+		resp, err := svc.UnfreezeAccount(ctx, &accountv2.UnfreezeAccountRequest{AccountId: acc.Id, Reason: "cleared"})
+		assert.NoError(t, err)
+		assert.Equal(t, statusActive, resp.Account.Status)
+
+		_, err = svc.Withdraw(ctx, &accountv2.WithdrawRequest{
+			AccountId: acc.Id, Amount: &commonv1.Money{Currency: "USD", Units: 10}, RequestId: "w2",
+		})
+		assert.NoError(t, err)
+	})
+
+	t.Run("unfreeze restores pending status", func(t *testing.T) {
+		svc := newTestService(t)
+		acc := createTestAccount(t, svc, 0)
+
+		svc.FreezeAccount(ctx, &accountv2.FreezeAccountRequest{AccountId: acc.Id, Reason: "kyc"})
+		resp, err := svc.UnfreezeAccount(ctx, &accountv2.UnfreezeAccountRequest{AccountId: acc.Id, Reason: "kyc passed"})
+		assert.NoError(t, err)
+		assert.Equal(t, statusPending, resp.Account.Status)
+	})
+
+	t.Run("unfreeze activates pending account funded while frozen", func(t *testing.T) {
+		svc := newTestService(t)
+		acc := createTestAccount(t, svc, 0)
+
+		svc.FreezeAccount(ctx, &accountv2.FreezeAccountRequest{AccountId: acc.Id, Reason: "kyc"})
+		_, err := svc.Deposit(ctx, &accountv2.DepositRequest{
+			AccountId: acc.Id, Amount: &commonv1.Money{Currency: "USD", Units: 10}, RequestId: "d1",
+		})
+		assert.NoError(t, err)
+		acc, _ = svc.account(acc.Id)
+		assert.Equal(t, statusFrozen, acc.Status)
+
+		resp, err := svc.UnfreezeAccount(ctx, &accountv2.UnfreezeAccountRequest{AccountId: acc.Id, Reason: "kyc passed"})
+		assert.NoError(t, err)
+		assert.Equal(t, statusActive, resp.Account.Status)
+
+		_, err = svc.Withdraw(ctx, &accountv2.WithdrawRequest{
+			AccountId: acc.Id, Amount: &commonv1.Money{Currency: "USD", Units: 10}, RequestId: "w1",
+		})
+		assert.NoError(t, err)
+	})
+
+	t.Run("closed account rejects everything", func(t *testing.T) {
+		svc := newTestService(t)
+		acc := createTestAccount(t, svc, 0)
+
+		_, err := svc.CloseAccount(ctx, &accountv2.CloseAccountRequest{AccountId: acc.Id, Reason: "customer request"})
+		assert.NoError(t, err)
+
+		_, err = svc.Deposit(ctx, &accountv2.DepositRequest{
+			AccountId: acc.Id, Amount: &commonv1.Money{Currency: "USD", Units: 1}, RequestId: "d1",
+		})
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+		_, err = svc.FreezeAccount(ctx, &accountv2.FreezeAccountRequest{AccountId: acc.Id, Reason: "late"})
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+
+	t.Run("funded account cannot be closed", func(t *testing.T) {
+		svc := newTestService(t)
+		acc := createTestAccount(t, svc, 5)
+
+		_, err := svc.CloseAccount(ctx, &accountv2.CloseAccountRequest{AccountId: acc.Id, Reason: "customer request"})
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+
+	t.Run("reason is required", func(t *testing.T) {
+		svc := newTestService(t)
+		acc := createTestAccount(t, svc, 5)
+
+		_, err := svc.FreezeAccount(ctx, &accountv2.FreezeAccountRequest{AccountId: acc.Id})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("unfreeze active account", func(t *testing.T) {
+		svc := newTestService(t)
+		acc := createTestAccount(t, svc, 5)
+
+		_, err := svc.UnfreezeAccount(ctx, &accountv2.UnfreezeAccountRequest{AccountId: acc.Id, Reason: "noop"})
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+}
+
+func TestListAccountEvents(t *testing.T) {
+	ctx := context.Background()
+	svc := newTestService(t)
+	acc := createTestAccount(t, svc, 0)
+
+	svc.Deposit(ctx, &accountv2.DepositRequest{
+		AccountId: acc.Id, Amount: &commonv1.Money{Currency: "USD", Units: 1}, RequestId: "d1",
+	})
+	svc.FreezeAccount(ctx, &accountv2.FreezeAccountRequest{AccountId: acc.Id, Reason: "fraud check"})
+
+	resp, err := svc.ListAccountEvents(ctx, &accountv2.ListAccountEventsRequest{AccountId: acc.Id})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []struct {
+		from, to accountv2.AccountStatus
+		reason   string
+	}{
+		{accountv2.AccountStatus_ACCOUNT_STATUS_UNSPECIFIED, statusPending, "account created"},
+		{statusPending, statusActive, "first deposit"},
+		{statusActive, statusFrozen, "fraud check"},
+	}
+	if len(resp.Events) != len(want) {
+		t.Fatalf("expected %d events, got %d", len(want), len(resp.Events))
+	}
+	for i, w := range want {
+		got := resp.Events[i]
+		assert.Equal(t, w.from, got.From)
+		assert.Equal(t, w.to, got.To)
+		assert.Equal(t, w.reason, got.Reason)
+		assert.NotNil(t, got.ChangedAt)
+	}
+
+	_, err = svc.ListAccountEvents(ctx, &accountv2.ListAccountEventsRequest{AccountId: "not-found"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
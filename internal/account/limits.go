@@ -0,0 +1,70 @@
+package account
+
+import (
+	accountv2 "github.com/galadeat/bank-sim/api/proto/account/v2"
+	commonv1 "github.com/galadeat/bank-sim/api/proto/common/v1"
+	"github.com/galadeat/bank-sim/internal/rules"
+)
+
+// WithRules enables transaction limits and velocity rules for deposits,
+// withdrawals and transfers.
+func WithRules(engine *rules.Engine) Option {
+	return func(s *Service) {
+		s.rules = engine
+	}
+}
+
+// operation describes a balance change of acc for the rules engine.
+func (s *Service) operation(acc *accountv2.AccountInfo, kind rules.Kind, amount *commonv1.Money) rules.Operation {
+	return rules.Operation{
+		Kind:        kind,
+		AccountID:   acc.Id,
+		UserID:      acc.GetOwnerId(),
+		AccountType: acc.Type,
+		Amount:      amount,
+		At:          s.clock.Now(),
+	}
+}
+
+// lockOwners locks the owners of the accounts with the given ids and returns the
+// function that unlocks them. Rules that count the operations of a user span
+// all of the user's accounts, so checking and recording them under the account
+// locks alone would let two accounts of one user both pass. Owner locks are
+// taken after the account locks and are only needed with a rules engine.
+// Callers must hold the locks of the accounts.
+func (s *Service) lockOwners(ids ...string) (unlock func()) {
+	if s.rules == nil {
+		return func() {}
+	}
+	owners := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if acc, ok := s.account(id); ok {
+			owners = append(owners, acc.GetOwnerId())
+		}
+	}
+	return s.ownerLocks.lock(owners...)
+}
+
+// checkRules fails with the first rule the operations break. Callers must hold the
+// locks of the accounts and of their owners, see lockOwners, and call recordRules
+// once the operations have been applied.
+func (s *Service) checkRules(ops ...rules.Operation) error {
+	if s.rules == nil {
+		return nil
+	}
+	for _, op := range ops {
+		if err := s.rules.Check(op); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Service) recordRules(ops ...rules.Operation) {
+	if s.rules == nil {
+		return
+	}
+	for _, op := range ops {
+		s.rules.Record(op)
+	}
+}
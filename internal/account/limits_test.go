@@ -0,0 +1,126 @@
+package account
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	accountv2 "github.com/galadeat/bank-sim/api/proto/account/v2"
+	commonv1 "github.com/galadeat/bank-sim/api/proto/common/v1"
+	"github.com/galadeat/bank-sim/internal/risk"
+	"github.com/galadeat/bank-sim/internal/rules"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRules(t *testing.T) {
+	ctx := context.Background()
+	engine, err := rules.New(rules.Config{AccountTypes: map[string]rules.Policy{
+		"checking": {
+			Deposit:    rules.Limits{MaxAmount: "500"},
+			Withdrawal: rules.Limits{DailyAmount: "100"},
+		},
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	svc := newTestService(t)
+	WithRules(engine)(svc)
+	from := createTestAccount(t, svc, 1000)
+	to := createTestAccount(t, svc, 0)
+	usd := func(units int64) *commonv1.Money {
+		return &commonv1.Money{Currency: "USD", Units: units}
+	}
+
+	if _, err := svc.Withdraw(ctx, &accountv2.WithdrawRequest{AccountId: from.Id, Amount: usd(60), RequestId: "w-1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// the outgoing transfer counts towards the daily withdrawal limit
+	_, err = svc.Transfer(ctx, &accountv2.TransferRequest{FromAccountId: from.Id, ToAccountId: to.Id, Amount: usd(50), RequestId: "t-1"})
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("expected %v, got %v", codes.ResourceExhausted, err)
+	}
+	var reason string
+	for _, d := range status.Convert(err).Details() {
+		if info, ok := d.(*errdetails.ErrorInfo); ok {
+			reason = info.Reason
+		}
+	}
+	if reason != rules.ReasonDailyLimit {
+		t.Errorf("expected reason %s, got %q", rules.ReasonDailyLimit, reason)
+	}
+
+	_, err = svc.Deposit(ctx, &accountv2.DepositRequest{AccountId: to.Id, Amount: usd(501), RequestId: "d-1"})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected %v, got %v", codes.FailedPrecondition, err)
+	}
+
+	// a rejected operation changes nothing
+	got, _ := svc.GetAccount(ctx, &accountv2.GetAccountRequest{Id: from.Id})
+	assertMoney(t, usd(940), got.Account.Balance)
+	got, _ = svc.GetAccount(ctx, &accountv2.GetAccountRequest{Id: to.Id})
+	assertMoney(t, usd(0), got.Account.Balance)
+
+	// retrying an accepted request does not count it twice
+	if _, err := svc.Withdraw(ctx, &accountv2.WithdrawRequest{AccountId: from.Id, Amount: usd(60), RequestId: "w-1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := svc.Withdraw(ctx, &accountv2.WithdrawRequest{AccountId: from.Id, Amount: usd(40), RequestId: "w-2"}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+// slowScorer allows every payment after a pause, which widens the gap between
+// checking the rules and recording the operation.
+type slowScorer struct{}
+
+func (slowScorer) Assess(risk.Payment) risk.Assessment {
+	time.Sleep(5 * time.Millisecond)
+	return risk.Assessment{Decision: risk.Allow}
+}
+
+func TestUserVelocityAcrossAccounts(t *testing.T) {
+	ctx := context.Background()
+	engine, err := rules.New(rules.Config{AccountTypes: map[string]rules.Policy{
+		"checking": {Velocity: []rules.Velocity{{Scope: rules.ScopeUser, MaxCount: 1, Window: rules.Duration(time.Hour)}}},
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	svc := newTestService(t)
+	accounts := make([]*accountv2.AccountInfo, 8)
+	for i := range accounts {
+		accounts[i] = createTestAccount(t, svc, 100)
+	}
+	WithRules(engine)(svc)
+	WithRiskScorer(slowScorer{})(svc)
+
+	// the accounts belong to one user, so only one withdrawal fits the limit
+	var wg sync.WaitGroup
+	var succeeded atomic.Int32
+	for i, acc := range accounts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := svc.Withdraw(ctx, &accountv2.WithdrawRequest{
+				AccountId: acc.Id, Amount: &commonv1.Money{Currency: "USD", Units: 1}, RequestId: fmt.Sprintf("w-%d", i),
+			})
+			if err == nil {
+				succeeded.Add(1)
+			} else if status.Code(err) != codes.ResourceExhausted {
+				t.Errorf("expected %v, got %v", codes.ResourceExhausted, err)
+			}
+		}()
+	}
+	wg.Wait()
+	if n := succeeded.Load(); n != 1 {
+		t.Errorf("expected 1 withdrawal to pass the user limit, got %d", n)
+	}
+}
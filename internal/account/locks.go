@@ -0,0 +1,94 @@
+package account
+
+import (
+	"hash/fnv"
+	"slices"
+	"sync"
+
+	accountv2 "github.com/galadeat/bank-sim/api/proto/account/v2"
+	"google.golang.org/protobuf/proto"
+)
+
+// lockStripes is the number of account locks. Accounts hash onto them, so two
+// accounts occasionally share one; that only costs some parallelism.
+const lockStripes = 256
+
+// accountLocks serializes changes to an account: its fields, its ledger entries,
+// holds and reviews and its interest accrual. The maps that index accounts are
+// guarded separately by Service.mu, which is only held for the map access itself,
+// so the lock order is always account locks first, then s.mu. Service.ownerLocks
+// are of the same type, keyed by user id, and are taken between the two.
+type accountLocks [lockStripes]sync.Mutex
+
+// lock locks the accounts with the given ids and returns the function that
+// unlocks them. Empty ids are skipped. Stripes are taken in index order, so
+// operations on several accounts, such as transfers, cannot deadlock each other.
+func (l *accountLocks) lock(ids ...string) (unlock func()) {
+	stripes := make([]int, 0, len(ids))
+	for _, id := range ids {
+		if id != "" {
+			stripes = append(stripes, stripe(id))
+		}
+	}
+	slices.Sort(stripes)
+	stripes = slices.Compact(stripes)
+
+	for _, i := range stripes {
+		l[i].Lock()
+	}
+	return func() {
+		for i := len(stripes) - 1; i >= 0; i-- {
+			l[stripes[i]].Unlock()
+		}
+	}
+}
+
+func stripe(id string) int {
+	h := fnv.New32a()
+	h.Write([]byte(id))
+	return int(h.Sum32() % lockStripes)
+}
+
+// account returns the account with the given id.
+func (s *Service) account(id string) (*accountv2.AccountInfo, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	acc, ok := s.accounts[id]
+	return acc, ok
+}
+
+// accountIDs returns the ids of every account, or of the accounts owned by
+// userID when it is set, in a stable order.
+func (s *Service) accountIDs(userID string) []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	ids := make([]string, 0, len(s.accounts))
+	for id, acc := range s.accounts {
+		if userID == "" || acc.OwnerId == userID {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// holdsOf returns the holds placed on the account.
+func (s *Service) holdsOf(accountID string) []*accountv2.Hold {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.accountHolds[accountID]
+}
+
+// history returns the ledger entries of the account.
+func (s *Service) history(accountID string) []*accountv2.Transaction {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.ledger[accountID]
+}
+
+// snapshot copies an account, hold or review for a response. Responses are
+// sent after the locks are released, so they must not share the messages that
+// later calls change. Callers must hold the lock the message is changed under.
+func snapshot[M proto.Message](m M) M {
+	return proto.Clone(m).(M)
+}
@@ -0,0 +1,152 @@
+package account
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	accountv2 "github.com/galadeat/bank-sim/api/proto/account/v2"
+	commonv1 "github.com/galadeat/bank-sim/api/proto/common/v1"
+	userv1 "github.com/galadeat/bank-sim/api/proto/user/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+)
+
+// blockingUsers is a user service whose GetUser waits until release is closed.
+type blockingUsers struct {
+	userv1.UserClient
+	release chan struct{}
+}
+
+func (u *blockingUsers) GetUser(ctx context.Context, req *userv1.GetUserRequest, _ ...grpc.CallOption) (*userv1.GetUserResponse, error) {
+	<-u.release
+	return &userv1.GetUserResponse{User: &userv1.UserInfo{Id: req.Id}}, nil
+}
+
+func TestAccountLocks(t *testing.T) {
+	t.Run("slow user service does not stall deposits", func(t *testing.T) {
+		svc := newTestService(t)
+		acc := createTestAccount(t, svc, 100)
+
+		users := &blockingUsers{release: make(chan struct{})}
+		svc.userClient = users
+		created := make(chan error, 1)
+		go func() {
+			_, err := svc.CreateAccount(context.Background(), &accountv2.CreateAccountRequest{UserId: "user-123", RequestId: "slow"})
+			created <- err
+		}()
+
+		done := make(chan error, 1)
+		go func() {
+			_, err := svc.Deposit(context.Background(), &accountv2.DepositRequest{AccountId: acc.Id, Amount: &commonv1.Money{Currency: "USD", Units: 5}, RequestId: "d-1"})
+			done <- err
+		}()
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("deposit is blocked by the user service call")
+		}
+
+		close(users.release)
+		if err := <-created; err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("concurrent transfers keep the total", func(t *testing.T) {
+		svc := newTestService(t)
+		const accounts, workers, rounds = 8, 16, 50
+		ids := make([]string, accounts)
+		for i := range ids {
+			ids[i] = createTestAccount(t, svc, 1000).Id
+		}
+
+		var wg sync.WaitGroup
+		for w := 0; w < workers; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for r := 0; r < rounds; r++ {
+					// opposite directions on the same pair would deadlock with unordered locks
+					from, to := ids[(w+r)%accounts], ids[(w+r+1+w%3)%accounts]
+					if w%2 == 1 {
+						from, to = to, from
+					}
+					_, _ = svc.Transfer(context.Background(), &accountv2.TransferRequest{
+						FromAccountId: from,
+						ToAccountId:   to,
+						Amount:        &commonv1.Money{Currency: "USD", Units: 7},
+						RequestId:     fmt.Sprintf("t-%d-%d", w, r),
+					})
+				}
+			}()
+		}
+		wg.Wait()
+
+		var total int64
+		for _, id := range ids {
+			acc, _ := svc.account(id)
+			total += acc.Balance.Units
+		}
+		if total != accounts*1000 {
+			t.Errorf("expected a total of %d, got %d", accounts*1000, total)
+		}
+	})
+
+	// run with -race: responses are read after the service has released its locks
+	t.Run("reads return copies taken under the account lock", func(t *testing.T) {
+		svc := newTestService(t)
+		acc := createTestAccount(t, svc, 100)
+
+		const deposits = 200
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < deposits; i++ {
+				_, _ = svc.Deposit(context.Background(), &accountv2.DepositRequest{AccountId: acc.Id, Amount: &commonv1.Money{Currency: "USD", Units: 1}, RequestId: fmt.Sprintf("d-%d", i)})
+			}
+		}()
+		for i := 0; i < deposits; i++ {
+			got, err := svc.GetAccount(context.Background(), &accountv2.GetAccountRequest{Id: acc.Id})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if _, err := proto.Marshal(got); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			list, err := svc.ListAccounts(context.Background(), &accountv2.ListAccountsRequest{UserId: "user-123"})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if _, err := proto.Marshal(list); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+		wg.Wait()
+
+		got, err := svc.GetAccount(context.Background(), &accountv2.GetAccountRequest{Id: acc.Id})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := svc.Deposit(context.Background(), &accountv2.DepositRequest{AccountId: acc.Id, Amount: &commonv1.Money{Currency: "USD", Units: 1}, RequestId: "d-last"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got.Account.Balance.Units != 100+deposits {
+			t.Errorf("expected the returned balance to stay %d, got %d", 100+deposits, got.Account.Balance.Units)
+		}
+	})
+
+	t.Run("ids sharing a stripe are locked once", func(t *testing.T) {
+		var l accountLocks
+		unlock := l.lock("a", "a", "")
+		unlock()
+		unlock = l.lock("a")
+		unlock()
+	})
+}
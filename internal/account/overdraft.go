@@ -0,0 +1,106 @@
+package account
+
+import (
+	"context"
+	"log"
+
+	accountv2 "github.com/galadeat/bank-sim/api/proto/account/v2"
+	commonv1 "github.com/galadeat/bank-sim/api/proto/common/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// SetOverdraftLimit is the realization of the rpc method
+func (s *Service) SetOverdraftLimit(ctx context.Context, req *accountv2.SetOverdraftLimitRequest) (*accountv2.SetOverdraftLimitResponse, error) {
+	select {
+	case <-ctx.Done():
+		return nil, status.Error(codes.Canceled, "request canceled by client")
+	default:
+	}
+
+	if req.AccountId == "" {
+		return nil, status.Error(codes.InvalidArgument, "account id is required")
+	}
+	if req.Limit == nil || isNegative(req.Limit) {
+		return nil, status.Error(codes.InvalidArgument, "limit must not be negative")
+	}
+
+	unlock := s.locks.lock(req.AccountId)
+	defer unlock()
+
+	acc, ok := s.account(req.AccountId)
+	if !ok {
+		return nil, status.Error(codes.NotFound, "account not found")
+	}
+	if isClosed(acc) {
+		return nil, status.Error(codes.FailedPrecondition, "account is closed")
+	}
+	if !s.products[accountType(acc.Type)].Overdraft {
+		return nil, status.Errorf(codes.FailedPrecondition, "%v accounts do not allow overdrafts", accountType(acc.Type))
+	}
+	if currency := acc.Balance.GetCurrency(); currency != "" && req.Limit.Currency != currency {
+		return nil, status.Error(codes.InvalidArgument, "currency mismatch")
+	}
+
+	limit := normalizeMoney(&commonv1.Money{Currency: req.Limit.Currency, Units: req.Limit.Units, Nanos: req.Limit.Nanos})
+	floor := &commonv1.Money{Units: -limit.Units, Nanos: -limit.Nanos}
+	if compareMoney(acc.Balance, floor) < 0 {
+		return nil, status.Error(codes.FailedPrecondition, "limit is below the current overdrawn balance")
+	}
+
+	acc.OverdraftLimit = limit
+	s.setBalance(acc, acc.Balance)
+
+	log.Printf("overdraft limit set: account_id=%s, limit=%v", acc.Id, limit)
+
+	return &accountv2.SetOverdraftLimitResponse{Account: snapshot(acc)}, nil
+}
+
+// setBalance updates the ledger balance and the available balance derived from it:
+// the balance plus the overdraft limit, less active holds. Callers must hold the lock of acc.
+func (s *Service) setBalance(acc *accountv2.AccountInfo, balance *commonv1.Money) {
+	acc.Balance = balance
+	available := &commonv1.Money{Currency: balance.GetCurrency(), Units: balance.GetUnits(), Nanos: balance.GetNanos()}
+	if acc.OverdraftLimit != nil {
+		available.Units += acc.OverdraftLimit.Units
+		available.Nanos += acc.OverdraftLimit.Nanos
+	}
+	available = normalizeMoney(available)
+	for _, h := range s.holdsOf(acc.Id) {
+		if h.Status == holdActive {
+			available.Units -= h.Amount.Units
+			available.Nanos -= h.Amount.Nanos
+			available = normalizeMoney(available)
+		}
+	}
+	acc.AvailableBalance = available
+}
+
+// overdraftFee returns the fee charged when acc goes below zero, or nil if its
+// product has none.
+func (s *Service) overdraftFee(acc *accountv2.AccountInfo) *commonv1.Money {
+	fee := s.products[accountType(acc.Type)].OverdraftFee
+	if fee == nil || fee.Units == 0 && fee.Nanos == 0 {
+		return nil
+	}
+	return &commonv1.Money{Currency: acc.Balance.GetCurrency(), Units: fee.Units, Nanos: fee.Nanos}
+}
+
+func (s *Service) chargeOverdraftFee(acc *accountv2.AccountInfo, requestID string) {
+	amount := s.overdraftFee(acc)
+	if amount == nil {
+		return
+	}
+	balance, err := substractMoney(acc.Balance, amount, &commonv1.Money{Units: maxUnits})
+	if err != nil {
+		log.Printf("overdraft fee failed: account_id=%s, err=%v", acc.Id, err)
+		return
+	}
+	s.setBalance(acc, balance)
+	s.record(acc, accountv2.TransactionType_TRANSACTION_TYPE_FEE, amount, requestID+":overdraft-fee")
+
+	log.Printf("overdraft fee charged: account_id=%s, amount=%v, new_balance=%v", acc.Id, amount, acc.Balance)
+}
+
+// maxUnits is used as an unbounded limit for debits the bank forces through.
+const maxUnits = 1 << 62
@@ -0,0 +1,192 @@
+package account
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	accountv2 "github.com/galadeat/bank-sim/api/proto/account/v2"
+	commonv1 "github.com/galadeat/bank-sim/api/proto/common/v1"
+	"github.com/galadeat/bank-sim/pkg/clock"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestNormalizeMoney(t *testing.T) {
+	tests := []struct {
+		name string
+		in   *commonv1.Money
+		want *commonv1.Money
+	}{
+		{name: "carry nanos", in: &commonv1.Money{Units: 1, Nanos: 1_500_000_000}, want: &commonv1.Money{Units: 2, Nanos: 500_000_000}},
+		{name: "positive units, negative nanos", in: &commonv1.Money{Units: 2, Nanos: -500_000_000}, want: &commonv1.Money{Units: 1, Nanos: 500_000_000}},
+		{name: "negative units, positive nanos", in: &commonv1.Money{Units: -2, Nanos: 500_000_000}, want: &commonv1.Money{Units: -1, Nanos: -500_000_000}},
+		{name: "negative below one unit", in: &commonv1.Money{Units: 0, Nanos: -500_000_000}, want: &commonv1.Money{Units: 0, Nanos: -500_000_000}},
+		{name: "negative carry", in: &commonv1.Money{Units: -1, Nanos: -1_500_000_000}, want: &commonv1.Money{Units: -2, Nanos: -500_000_000}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertMoney(t, tt.want, normalizeMoney(tt.in))
+		})
+	}
+}
+
+func TestSubstractMoney(t *testing.T) {
+	usd := func(units int64, nanos int32) *commonv1.Money {
+		return &commonv1.Money{Currency: "USD", Units: units, Nanos: nanos}
+	}
+
+	tests := []struct {
+		name        string
+		a, b, limit *commonv1.Money
+		want        *commonv1.Money
+		wantErr     bool
+		wantReason  string
+	}{
+		{name: "within balance", a: usd(10, 0), b: usd(2, 500_000_000), want: usd(7, 500_000_000)},
+		{name: "insufficient without limit", a: usd(10, 0), b: usd(10, 1), wantErr: true, wantReason: ReasonInsufficientFunds},
+		{name: "into overdraft", a: usd(10, 0), b: usd(10, 500_000_000), limit: usd(1, 0), want: usd(0, -500_000_000)},
+		{name: "exactly at limit", a: usd(0, 0), b: usd(50, 0), limit: usd(50, 0), want: usd(-50, 0)},
+		{name: "beyond limit", a: usd(-49, 0), b: usd(1, 1), limit: usd(50, 0), wantErr: true, wantReason: ReasonInsufficientFunds},
+		{name: "currency mismatch", a: usd(10, 0), b: &commonv1.Money{Currency: "EUR", Units: 1}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := substractMoney(tt.a, tt.b, tt.limit)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error to be %v, got %v", tt.wantErr, err)
+			}
+			if err != nil {
+				assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+				if tt.wantReason != "" {
+					assert.Equal(t, tt.wantReason, errorReason(err))
+				}
+				return
+			}
+			assertMoney(t, tt.want, got)
+		})
+	}
+}
+
+func errorReason(err error) string {
+	for _, d := range status.Convert(err).Details() {
+		if info, ok := d.(*errdetails.ErrorInfo); ok {
+			return info.Reason
+		}
+	}
+	return ""
+}
+
+func TestOverdraft(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("withdraw into overdraft charges a fee", func(t *testing.T) {
+		svc := newTestService(t)
+		acc := createTestAccount(t, svc, 100)
+
+		resp, err := svc.SetOverdraftLimit(ctx, &accountv2.SetOverdraftLimitRequest{
+			AccountId: acc.Id, Limit: &commonv1.Money{Currency: "USD", Units: 50},
+		})
+		assert.NoError(t, err)
+		assertMoney(t, &commonv1.Money{Currency: "USD", Units: 150}, resp.Account.AvailableBalance)
+
+		_, err = svc.Withdraw(ctx, &accountv2.WithdrawRequest{
+			AccountId: acc.Id, Amount: &commonv1.Money{Currency: "USD", Units: 120, Nanos: 500_000_000}, RequestId: "w1",
+		})
+		assert.NoError(t, err)
+		// -20.50 plus the 5 USD overdraft fee
+		acc, _ = svc.account(acc.Id)
+		assertMoney(t, &commonv1.Money{Currency: "USD", Units: -25, Nanos: -500_000_000}, acc.Balance)
+		assertMoney(t, &commonv1.Money{Currency: "USD", Units: 24, Nanos: 500_000_000}, acc.AvailableBalance)
+
+		txs, _ := svc.ListTransactions(ctx, &accountv2.ListTransactionsRequest{AccountId: acc.Id})
+		last := txs.Transactions[len(txs.Transactions)-1]
+		assert.Equal(t, accountv2.TransactionType_TRANSACTION_TYPE_FEE, last.Type)
+
+		_, err = svc.Withdraw(ctx, &accountv2.WithdrawRequest{
+			AccountId: acc.Id, Amount: &commonv1.Money{Currency: "USD", Units: 25}, RequestId: "w2",
+		})
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+
+	t.Run("limit below overdrawn balance", func(t *testing.T) {
+		svc := newTestService(t)
+		acc := createTestAccount(t, svc, 0)
+		svc.Deposit(ctx, &accountv2.DepositRequest{
+			AccountId: acc.Id, Amount: &commonv1.Money{Currency: "USD", Units: 1}, RequestId: "d1",
+		})
+		svc.SetOverdraftLimit(ctx, &accountv2.SetOverdraftLimitRequest{
+			AccountId: acc.Id, Limit: &commonv1.Money{Currency: "USD", Units: 50},
+		})
+		svc.Withdraw(ctx, &accountv2.WithdrawRequest{
+			AccountId: acc.Id, Amount: &commonv1.Money{Currency: "USD", Units: 31}, RequestId: "w1",
+		})
+
+		_, err := svc.SetOverdraftLimit(ctx, &accountv2.SetOverdraftLimitRequest{
+			AccountId: acc.Id, Limit: &commonv1.Money{Currency: "USD", Units: 10},
+		})
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+
+	t.Run("invalid limits", func(t *testing.T) {
+		svc := newTestService(t)
+		acc := createTestAccount(t, svc, 10)
+
+		_, err := svc.SetOverdraftLimit(ctx, &accountv2.SetOverdraftLimitRequest{
+			AccountId: acc.Id, Limit: &commonv1.Money{Currency: "USD", Units: -1},
+		})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+		_, err = svc.SetOverdraftLimit(ctx, &accountv2.SetOverdraftLimitRequest{
+			AccountId: acc.Id, Limit: &commonv1.Money{Currency: "EUR", Units: 1},
+		})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("savings accounts cannot be overdrawn", func(t *testing.T) {
+		svc := newClockedService(t, clock.NewManual(simStart))
+		acc := openAccount(t, svc, accountv2.AccountType_ACCOUNT_TYPE_SAVINGS, 10, 0)
+
+		_, err := svc.SetOverdraftLimit(ctx, &accountv2.SetOverdraftLimitRequest{
+			AccountId: acc.Id, Limit: &commonv1.Money{Currency: "USD", Units: 1},
+		})
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+
+	t.Run("overdraft interest is charged monthly", func(t *testing.T) {
+		c := clock.NewManual(simStart)
+		svc := newClockedService(t, c)
+		acc := openAccount(t, svc, accountv2.AccountType_ACCOUNT_TYPE_CHECKING, 100, 0)
+		svc.SetOverdraftLimit(ctx, &accountv2.SetOverdraftLimitRequest{
+			AccountId: acc.Id, Limit: &commonv1.Money{Currency: "USD", Units: 1000},
+		})
+		svc.Withdraw(ctx, &accountv2.WithdrawRequest{
+			AccountId: acc.Id, Amount: &commonv1.Money{Currency: "USD", Units: 465}, RequestId: "w1",
+		})
+		// -365 after the withdrawal, -370 once the 5 USD fee is charged
+		acc, _ = svc.account(acc.Id)
+		assertMoney(t, &commonv1.Money{Currency: "USD", Units: -370}, acc.Balance)
+
+		c.Set(time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC))
+		svc.AccrueInterest(c.Now())
+
+		txs, _ := svc.ListTransactions(ctx, &accountv2.ListTransactionsRequest{AccountId: acc.Id})
+		var charged *accountv2.Transaction
+		for _, tx := range txs.Transactions {
+			if tx.Type == accountv2.TransactionType_TRANSACTION_TYPE_OVERDRAFT_INTEREST {
+				charged = tx
+			}
+		}
+		if charged == nil {
+			t.Fatalf("expected an overdraft interest entry")
+		}
+		// 30 days at 370 * 0.18 / 365 = 0.182465753 USD a day
+		assertMoney(t, &commonv1.Money{Currency: "USD", Units: 5, Nanos: 473972590}, charged.Amount)
+		acc, _ = svc.account(acc.Id)
+		assert.True(t, isNegative(acc.Balance))
+	})
+}
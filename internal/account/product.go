@@ -0,0 +1,40 @@
+package account
+
+import (
+	accountv2 "github.com/galadeat/bank-sim/api/proto/account/v2"
+	commonv1 "github.com/galadeat/bank-sim/api/proto/common/v1"
+)
+
+// Product describes the terms an account type is opened with.
+type Product struct {
+	// AnnualRateBps is the yearly interest rate on a positive balance in basis points.
+	AnnualRateBps int64
+	// Term is true for products that lock the balance until maturity.
+	Term bool
+	// Overdraft is true for products that accept an overdraft limit.
+	Overdraft bool
+	// OverdraftRateBps is the yearly interest rate charged on a negative balance.
+	OverdraftRateBps int64
+	// OverdraftFee is charged in the account currency whenever a withdrawal takes
+	// the balance below zero. The currency field is ignored.
+	OverdraftFee *commonv1.Money
+}
+
+// DefaultProducts are the products the service offers unless configured otherwise.
+var DefaultProducts = map[accountv2.AccountType]Product{
+	accountv2.AccountType_ACCOUNT_TYPE_CHECKING: {
+		AnnualRateBps:    0,
+		Overdraft:        true,
+		OverdraftRateBps: 1800,
+		OverdraftFee:     &commonv1.Money{Units: 5},
+	},
+	accountv2.AccountType_ACCOUNT_TYPE_SAVINGS:      {AnnualRateBps: 250},
+	accountv2.AccountType_ACCOUNT_TYPE_TERM_DEPOSIT: {AnnualRateBps: 450, Term: true},
+}
+
+func accountType(t accountv2.AccountType) accountv2.AccountType {
+	if t == accountv2.AccountType_ACCOUNT_TYPE_UNSPECIFIED {
+		return accountv2.AccountType_ACCOUNT_TYPE_CHECKING
+	}
+	return t
+}
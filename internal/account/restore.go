@@ -0,0 +1,285 @@
+package account
+
+import (
+	"context"
+	"log"
+
+	accountv2 "github.com/galadeat/bank-sim/api/proto/account/v2"
+	userv1 "github.com/galadeat/bank-sim/api/proto/user/v1"
+	"github.com/galadeat/bank-sim/pkg/money"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// creditTypes are the entry types that add to the balance; every other type
+// takes from it.
+var creditTypes = map[accountv2.TransactionType]bool{
+	accountv2.TransactionType_TRANSACTION_TYPE_DEPOSIT:     true,
+	accountv2.TransactionType_TRANSACTION_TYPE_INTEREST:    true,
+	accountv2.TransactionType_TRANSACTION_TYPE_TRANSFER_IN: true,
+	accountv2.TransactionType_TRANSACTION_TYPE_REFUND:      true,
+}
+
+// RestoreAccount is the realization of the rpc method
+func (s *Service) RestoreAccount(ctx context.Context, req *accountv2.RestoreAccountRequest) (*accountv2.RestoreAccountResponse, error) {
+	select {
+	case <-ctx.Done():
+		return nil, status.Error(codes.Canceled, "request canceled by client")
+	default:
+	}
+
+	product, err := s.validateRestore(req)
+	if err != nil {
+		return nil, err
+	}
+	// checked again under s.restoring, see restore
+	if resp, ok := s.restoredBefore(req); ok {
+		return resp, nil
+	}
+
+	// closed accounts may belong to users that are closed as well
+	if req.DryRun {
+		owner, err := s.owner(ctx, req.Account.OwnerId, isClosed(req.Account))
+		if err != nil {
+			return nil, err
+		}
+		return s.restored(req, owner, ""), nil
+	}
+
+	return once(s, accountv2.Account_RestoreAccount_FullMethodName, req.RequestId, req, func() (*accountv2.RestoreAccountResponse, error) {
+		owner, err := s.owner(ctx, req.Account.OwnerId, isClosed(req.Account))
+		if err != nil {
+			return nil, err
+		}
+		return s.restore(req, product, owner)
+	})
+}
+
+// validateRestore checks the account and that its entries add up to its balance,
+// and returns the product it was opened as.
+func (s *Service) validateRestore(req *accountv2.RestoreAccountRequest) (Product, error) {
+	acc := req.Account
+	if acc == nil {
+		return Product{}, status.Error(codes.InvalidArgument, "account is required")
+	}
+	if acc.OwnerId == "" {
+		return Product{}, status.Error(codes.InvalidArgument, "owner id is required")
+	}
+	if req.RequestId == "" {
+		return Product{}, status.Error(codes.InvalidArgument, "request id is required")
+	}
+
+	product, ok := s.products[accountType(acc.Type)]
+	if !ok {
+		return Product{}, status.Errorf(codes.InvalidArgument, "unsupported account type %v", acc.Type)
+	}
+	if product.Term && acc.MaturesAt == nil {
+		return Product{}, status.Error(codes.InvalidArgument, "term deposits need a maturity date")
+	}
+	if acc.Status == accountv2.AccountStatus_ACCOUNT_STATUS_UNSPECIFIED {
+		return Product{}, status.Error(codes.InvalidArgument, "account status is required")
+	}
+	if isClosed(acc) && !isZeroBalance(acc) {
+		return Product{}, status.Error(codes.InvalidArgument, "closed accounts must have a zero balance")
+	}
+	if isNegative(acc.OverdraftLimit) {
+		return Product{}, status.Error(codes.InvalidArgument, "overdraft limit must not be negative")
+	}
+
+	currency := acc.Balance.GetCurrency()
+	if _, ok := money.Lookup(currency); currency != "" && !ok {
+		return Product{}, status.Errorf(codes.InvalidArgument, "unknown currency %q", currency)
+	}
+
+	// the entries must lead from a zero balance to the balance of the account
+	var balance int64
+	seen := make(map[string]bool, len(req.Transactions))
+	for i, tx := range req.Transactions {
+		if tx.GetType() == accountv2.TransactionType_TRANSACTION_TYPE_UNSPECIFIED {
+			return Product{}, status.Errorf(codes.InvalidArgument, "transaction %d: type is required", i)
+		}
+		if tx.Amount.GetCurrency() != currency {
+			return Product{}, status.Errorf(codes.InvalidArgument, "transaction %d: currency %q differs from the account's %q", i, tx.Amount.GetCurrency(), currency)
+		}
+		if money.Nanos(tx.Amount) <= 0 {
+			return Product{}, status.Errorf(codes.InvalidArgument, "transaction %d: amount must be greater than zero", i)
+		}
+		if creditTypes[tx.Type] {
+			balance += money.Nanos(tx.Amount)
+		} else {
+			balance -= money.Nanos(tx.Amount)
+		}
+		if tx.BalanceAfter != nil && money.Nanos(tx.BalanceAfter) != balance {
+			return Product{}, status.Errorf(codes.InvalidArgument, "transaction %d: balance after is %s, the entries add up to %s",
+				i, money.Format(tx.BalanceAfter), money.Format(money.FromNanos(balance, currency)))
+		}
+		if ref := tx.ReversesTransactionId; ref != "" && !seen[ref] && !s.hasTransaction(ref) {
+			return Product{}, status.Errorf(codes.InvalidArgument, "transaction %d: reverses unknown transaction %s", i, ref)
+		}
+		if tx.Id != "" {
+			seen[tx.Id] = true
+		}
+	}
+	if balance != money.Nanos(acc.Balance) {
+		return Product{}, status.Errorf(codes.InvalidArgument, "balance is %s, the entries add up to %s",
+			money.Format(acc.Balance), money.Format(money.FromNanos(balance, currency)))
+	}
+	return product, nil
+}
+
+func (s *Service) hasTransaction(id string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, ok := s.transactions[id]
+	return ok
+}
+
+// restoredBefore returns the account of the owner that was restored from the
+// source account of req, or is that account, with as many of its entries as req
+// has. Restoring a dataset again so matches the accounts of the first run
+// without relying on request ids, which the idempotency store forgets.
+func (s *Service) restoredBefore(req *accountv2.RestoreAccountRequest) (*accountv2.RestoreAccountResponse, bool) {
+	source := req.Account.SourceId
+	if source == "" {
+		return nil, false
+	}
+
+	var id string
+	s.mu.RLock()
+	for _, acc := range s.accounts {
+		if acc.OwnerId == req.Account.OwnerId && (acc.SourceId == source || acc.Id == source) {
+			id = acc.Id
+			break
+		}
+	}
+	s.mu.RUnlock()
+	if id == "" {
+		return nil, false
+	}
+
+	unlock := s.locks.lock(id)
+	defer unlock()
+
+	acc, _ := s.account(id)
+	txs := s.history(id)
+	if len(txs) > len(req.Transactions) {
+		txs = txs[:len(req.Transactions)]
+	}
+	resp := &accountv2.RestoreAccountResponse{Account: proto.Clone(acc).(*accountv2.AccountInfo), Existing: true}
+	for _, tx := range txs {
+		resp.Transactions = append(resp.Transactions, proto.Clone(tx).(*accountv2.Transaction))
+	}
+	return resp, true
+}
+
+// restore adds the account and its ledger, unless it was restored before.
+func (s *Service) restore(req *accountv2.RestoreAccountRequest, product Product, owner *userv1.UserInfo) (*accountv2.RestoreAccountResponse, error) {
+	s.restoring.Lock()
+	defer s.restoring.Unlock()
+
+	if resp, ok := s.restoredBefore(req); ok {
+		log.Printf("account restored before: account=%s, source_id=%s, request_id=%s", resp.Account.Id, req.Account.SourceId, req.RequestId)
+		return resp, nil
+	}
+
+	resp := s.restored(req, owner, s.ids.NewID())
+	account := resp.Account
+	target := account.Status
+	account.Status = accountv2.AccountStatus_ACCOUNT_STATUS_UNSPECIFIED
+	if !product.Term {
+		account.MaturesAt = nil
+	}
+
+	// an account is opened as pending or active and then moved to its exported
+	// status, so the audit trail has the usual shape
+	initial := statusActive
+	if target == statusPending {
+		initial = statusPending
+	}
+	if err := s.transition(account, initial, "account restored"); err != nil {
+		return nil, err
+	}
+	if target != initial {
+		if err := s.transition(account, target, "account restored"); err != nil {
+			return nil, err
+		}
+	}
+
+	// the account is visible once it is in the map, so keep it locked until its
+	// ledger is in place
+	unlock := s.locks.lock(account.Id)
+	defer unlock()
+
+	s.mu.Lock()
+	s.accounts[account.Id] = account
+	s.accruals[account.Id] = &accrual{through: startOfDay(s.clock.Now())}
+	s.ledger[account.Id] = append(s.ledger[account.Id], resp.Transactions...)
+	for _, tx := range resp.Transactions {
+		s.transactions[tx.Id] = tx
+	}
+	s.mu.Unlock()
+
+	log.Printf("account restored: account=%v, transactions=%d, request_id=%s", account, len(resp.Transactions), req.RequestId)
+
+	return &accountv2.RestoreAccountResponse{Account: snapshot(account), Transactions: resp.Transactions}, nil
+}
+
+// restored returns the account and entries of req as they are restored under
+// accountID: entries get new ids, references to earlier entries of the request
+// are rewritten and missing balances after are filled in. A dry run passes an
+// empty accountID and gets empty ids.
+func (s *Service) restored(req *accountv2.RestoreAccountRequest, owner *userv1.UserInfo, accountID string) *accountv2.RestoreAccountResponse {
+	now := timestamppb.New(s.clock.Now())
+	account := &accountv2.AccountInfo{
+		Id:             accountID,
+		Owner:          owner,
+		OwnerId:        req.Account.OwnerId,
+		Balance:        req.Account.Balance,
+		Status:         req.Account.Status,
+		Type:           accountType(req.Account.Type),
+		OpenedAt:       req.Account.OpenedAt,
+		MaturesAt:      req.Account.MaturesAt,
+		OverdraftLimit: req.Account.OverdraftLimit,
+		SourceId:       req.Account.SourceId,
+	}
+	if account.OpenedAt == nil {
+		account.OpenedAt = now
+	}
+	account = proto.Clone(account).(*accountv2.AccountInfo)
+	s.setBalance(account, account.Balance)
+
+	currency := account.Balance.GetCurrency()
+	var balance int64
+
+	ids := make(map[string]string, len(req.Transactions))
+	txs := make([]*accountv2.Transaction, 0, len(req.Transactions))
+	for _, orig := range req.Transactions {
+		tx := proto.Clone(orig).(*accountv2.Transaction)
+		tx.AccountId = accountID
+		tx.Id = ""
+		if accountID != "" {
+			tx.Id = s.ids.NewID()
+		}
+		if orig.Id != "" {
+			ids[orig.Id] = tx.Id
+		}
+		if id, ok := ids[tx.ReversesTransactionId]; ok {
+			tx.ReversesTransactionId = id
+		}
+		if tx.CreatedAt == nil {
+			tx.CreatedAt = now
+		}
+		if creditTypes[tx.Type] {
+			balance += money.Nanos(tx.Amount)
+		} else {
+			balance -= money.Nanos(tx.Amount)
+		}
+		if tx.BalanceAfter == nil {
+			tx.BalanceAfter = money.FromNanos(balance, currency)
+		}
+		txs = append(txs, tx)
+	}
+	return &accountv2.RestoreAccountResponse{Account: account, Transactions: txs}
+}
@@ -0,0 +1,153 @@
+package account
+
+import (
+	"context"
+	"testing"
+
+	accountv2 "github.com/galadeat/bank-sim/api/proto/account/v2"
+	commonv1 "github.com/galadeat/bank-sim/api/proto/common/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func restoreRequest() *accountv2.RestoreAccountRequest {
+	usd := func(units int64) *commonv1.Money { return &commonv1.Money{Currency: "USD", Units: units} }
+	return &accountv2.RestoreAccountRequest{
+		Account: &accountv2.AccountInfo{
+			Id:      "old-acc",
+			OwnerId: "user-1",
+			Balance: usd(70),
+			Status:  statusFrozen,
+			Type:    accountv2.AccountType_ACCOUNT_TYPE_CHECKING,
+		},
+		Transactions: []*accountv2.Transaction{
+			{Id: "old-1", Type: accountv2.TransactionType_TRANSACTION_TYPE_DEPOSIT, Amount: usd(100), BalanceAfter: usd(100), RequestId: "r-1"},
+			{Id: "old-2", Type: accountv2.TransactionType_TRANSACTION_TYPE_WITHDRAWAL, Amount: usd(40), BalanceAfter: usd(60), RequestId: "r-2"},
+			{Id: "old-3", Type: accountv2.TransactionType_TRANSACTION_TYPE_REFUND, Amount: usd(10), RequestId: "r-3", ReversesTransactionId: "old-2"},
+		},
+		RequestId: "restore-1",
+	}
+}
+
+func TestRestoreAccount(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("restores the account and its ledger", func(t *testing.T) {
+		svc := newTestService(t)
+		resp, err := svc.RestoreAccount(ctx, restoreRequest())
+		require.NoError(t, err)
+
+		acc := resp.Account
+		assert.NotEqual(t, "old-acc", acc.Id)
+		assert.Equal(t, "user-1", acc.OwnerId)
+		assert.Equal(t, "user-1", acc.Owner.Id)
+		assert.Equal(t, statusFrozen, acc.Status)
+		assert.Equal(t, int64(70), acc.AvailableBalance.Units)
+
+		txs := svc.history(acc.Id)
+		require.Len(t, txs, 3)
+		assert.Equal(t, acc.Id, txs[0].AccountId)
+		assert.NotEqual(t, "old-1", txs[0].Id)
+		assert.Equal(t, txs[1].Id, txs[2].ReversesTransactionId)
+		assert.Equal(t, int64(70), txs[2].BalanceAfter.Units, "missing balances after are filled in")
+		assert.Equal(t, "r-2", txs[1].RequestId)
+
+		events, err := svc.ListAccountEvents(ctx, &accountv2.ListAccountEventsRequest{AccountId: acc.Id})
+		require.NoError(t, err)
+		require.Len(t, events.Events, 2)
+		assert.Equal(t, statusActive, events.Events[0].To)
+		assert.Equal(t, statusFrozen, events.Events[1].To)
+	})
+
+	t.Run("retries restore once", func(t *testing.T) {
+		svc := newTestService(t)
+		first, err := svc.RestoreAccount(ctx, restoreRequest())
+		require.NoError(t, err)
+		again, err := svc.RestoreAccount(ctx, restoreRequest())
+		require.NoError(t, err)
+		assert.Equal(t, first.Account.Id, again.Account.Id)
+		assert.Len(t, svc.accountIDs(""), 1)
+	})
+
+	t.Run("restores a source account once", func(t *testing.T) {
+		svc := newTestService(t)
+		req := restoreRequest()
+		req.Account.SourceId = "old-acc"
+		first, err := svc.RestoreAccount(ctx, req)
+		require.NoError(t, err)
+		assert.False(t, first.Existing)
+		assert.Equal(t, "old-acc", first.Account.SourceId)
+
+		// a later import uses another request id once the first is forgotten
+		req.RequestId = "restore-2"
+		again, err := svc.RestoreAccount(ctx, req)
+		require.NoError(t, err)
+		assert.True(t, again.Existing)
+		assert.Equal(t, first.Account.Id, again.Account.Id)
+		require.Len(t, again.Transactions, 3)
+		assert.Equal(t, first.Transactions[2].Id, again.Transactions[2].Id)
+		assert.Len(t, svc.accountIDs(""), 1)
+
+		req.RequestId, req.DryRun = "restore-3", true
+		dry, err := svc.RestoreAccount(ctx, req)
+		require.NoError(t, err)
+		assert.True(t, dry.Existing)
+
+		// an import into the server the dataset came from finds the account itself
+		req.Account.SourceId, req.RequestId, req.DryRun = first.Account.Id, "restore-4", false
+		same, err := svc.RestoreAccount(ctx, req)
+		require.NoError(t, err)
+		assert.True(t, same.Existing)
+		assert.Equal(t, first.Account.Id, same.Account.Id)
+
+		req.Account.OwnerId, req.RequestId = "user-2", "restore-5"
+		other, err := svc.RestoreAccount(ctx, req)
+		require.NoError(t, err)
+		assert.False(t, other.Existing, "accounts of other owners do not match")
+		assert.Len(t, svc.accountIDs(""), 2)
+	})
+
+	t.Run("dry run restores nothing", func(t *testing.T) {
+		svc := newTestService(t)
+		req := restoreRequest()
+		req.DryRun = true
+		resp, err := svc.RestoreAccount(ctx, req)
+		require.NoError(t, err)
+		assert.Empty(t, resp.Account.Id)
+		assert.Len(t, resp.Transactions, 3)
+		assert.Empty(t, svc.accountIDs(""))
+
+		req.DryRun = false
+		resp, err = svc.RestoreAccount(ctx, req)
+		require.NoError(t, err)
+		assert.NotEmpty(t, resp.Account.Id, "a dry run saves nothing for the request id")
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for name, change := range map[string]func(*accountv2.RestoreAccountRequest){
+			"no owner":          func(r *accountv2.RestoreAccountRequest) { r.Account.OwnerId = "" },
+			"no request id":     func(r *accountv2.RestoreAccountRequest) { r.RequestId = "" },
+			"no status":         func(r *accountv2.RestoreAccountRequest) { r.Account.Status = 0 },
+			"wrong balance":     func(r *accountv2.RestoreAccountRequest) { r.Account.Balance.Units = 80 },
+			"wrong entry":       func(r *accountv2.RestoreAccountRequest) { r.Transactions[1].BalanceAfter.Units = 50 },
+			"other currency":    func(r *accountv2.RestoreAccountRequest) { r.Transactions[0].Amount.Currency = "EUR" },
+			"zero amount":       func(r *accountv2.RestoreAccountRequest) { r.Transactions[2].Amount.Units = 0 },
+			"unknown reference": func(r *accountv2.RestoreAccountRequest) { r.Transactions[2].ReversesTransactionId = "old-9" },
+			"closed with money": func(r *accountv2.RestoreAccountRequest) { r.Account.Status = statusClosed },
+			"term without maturity": func(r *accountv2.RestoreAccountRequest) {
+				r.Account.Type = accountv2.AccountType_ACCOUNT_TYPE_TERM_DEPOSIT
+			},
+		} {
+			t.Run(name, func(t *testing.T) {
+				svc := newTestService(t)
+				req := restoreRequest()
+				change(req)
+				_, err := svc.RestoreAccount(ctx, req)
+				assert.Equal(t, codes.InvalidArgument, status.Code(err))
+				assert.Empty(t, svc.accountIDs(""))
+			})
+		}
+	})
+}
@@ -0,0 +1,148 @@
+package account
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	accountv2 "github.com/galadeat/bank-sim/api/proto/account/v2"
+	commonv1 "github.com/galadeat/bank-sim/api/proto/common/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// reversalTypes maps each reversible entry type to the type of the entry that
+// undoes it.
+var reversalTypes = map[accountv2.TransactionType]accountv2.TransactionType{
+	accountv2.TransactionType_TRANSACTION_TYPE_DEPOSIT:      accountv2.TransactionType_TRANSACTION_TYPE_REVERSAL,
+	accountv2.TransactionType_TRANSACTION_TYPE_TRANSFER_IN:  accountv2.TransactionType_TRANSACTION_TYPE_REVERSAL,
+	accountv2.TransactionType_TRANSACTION_TYPE_WITHDRAWAL:   accountv2.TransactionType_TRANSACTION_TYPE_REFUND,
+	accountv2.TransactionType_TRANSACTION_TYPE_TRANSFER_OUT: accountv2.TransactionType_TRANSACTION_TYPE_REFUND,
+	accountv2.TransactionType_TRANSACTION_TYPE_CAPTURE:      accountv2.TransactionType_TRANSACTION_TYPE_REFUND,
+	accountv2.TransactionType_TRANSACTION_TYPE_FEE:          accountv2.TransactionType_TRANSACTION_TYPE_REFUND,
+}
+
+// ReverseTransaction is the realization of the rpc method. Reversals are forced
+// through like fees: a reversed deposit may take the balance below the overdraft
+// limit, and frozen accounts are not exempt. Closed accounts cannot be reversed.
+func (s *Service) ReverseTransaction(ctx context.Context, req *accountv2.ReverseTransactionRequest) (*accountv2.ReverseTransactionResponse, error) {
+	select {
+	case <-ctx.Done():
+		return nil, status.Error(codes.Canceled, "request canceled by client")
+	default:
+	}
+	if req.TransactionId == "" {
+		return nil, status.Error(codes.InvalidArgument, "transaction id is required")
+	}
+	if req.Amount != nil && ((req.Amount.Units == 0 && req.Amount.Nanos == 0) || isNegative(req.Amount)) {
+		return nil, status.Error(codes.InvalidArgument, "reversal must be greater than zero")
+	}
+	if req.RequestId == "" {
+		return nil, status.Error(codes.InvalidArgument, "request id is required")
+	}
+	if req.Reason == "" {
+		return nil, status.Error(codes.InvalidArgument, "reason is required")
+	}
+
+	return once(s, accountv2.Account_ReverseTransaction_FullMethodName, req.RequestId, req, func() (*accountv2.ReverseTransactionResponse, error) {
+		return s.reverse(req)
+	})
+}
+
+// reverse undoes the transaction under the locks of every account it touched.
+func (s *Service) reverse(req *accountv2.ReverseTransactionRequest) (*accountv2.ReverseTransactionResponse, error) {
+	s.mu.RLock()
+	orig, ok := s.transactions[req.TransactionId]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, status.Error(codes.NotFound, "transaction not found")
+	}
+	legs := s.transactionLegs(orig)
+	ids := make([]string, 0, len(legs))
+	for _, leg := range legs {
+		ids = append(ids, leg.AccountId)
+	}
+	unlock := s.locks.lock(ids...)
+	defer unlock()
+
+	if _, ok := reversalTypes[orig.Type]; !ok {
+		return nil, status.Errorf(codes.FailedPrecondition, "%s entries cannot be reversed", strings.ToLower(strings.TrimPrefix(orig.Type.String(), "TRANSACTION_TYPE_")))
+	}
+
+	remaining, err := substractMoney(orig.Amount, reversed(orig), &commonv1.Money{Units: maxUnits})
+	if err != nil {
+		return nil, err
+	}
+	if remaining.Units == 0 && remaining.Nanos == 0 {
+		return nil, status.Error(codes.FailedPrecondition, "transaction is already fully reversed")
+	}
+	amount := remaining
+	if req.Amount != nil {
+		if req.Amount.Currency != orig.Amount.Currency {
+			return nil, status.Error(codes.InvalidArgument, "currency mismatch")
+		}
+		if compareMoney(req.Amount, remaining) > 0 {
+			return nil, status.Errorf(codes.FailedPrecondition, "only %v of the transaction is left to reverse", remaining)
+		}
+		amount = req.Amount
+	}
+
+	// compute every new balance before applying any, so a transfer is reversed
+	// on both sides or not at all
+	balances := make([]*commonv1.Money, len(legs))
+	for i, leg := range legs {
+		acc, _ := s.account(leg.AccountId)
+		if isClosed(acc) {
+			return nil, status.Error(codes.FailedPrecondition, "account is closed")
+		}
+		if reversalTypes[leg.Type] == accountv2.TransactionType_TRANSACTION_TYPE_REFUND {
+			balances[i], err = addMoney(acc.Balance, amount)
+		} else {
+			balances[i], err = substractMoney(acc.Balance, amount, &commonv1.Money{Units: maxUnits})
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	resp := &accountv2.ReverseTransactionResponse{}
+	for i, leg := range legs {
+		acc, _ := s.account(leg.AccountId)
+		s.setBalance(acc, balances[i])
+		entry := s.record(acc, reversalTypes[leg.Type], amount, req.RequestId)
+		entry.ReversesTransactionId = leg.Id
+
+		total, err := addMoney(leg.ReversedAmount, amount)
+		if err != nil {
+			return nil, err
+		}
+		leg.ReversedAmount = total
+		resp.Reversals = append(resp.Reversals, entry)
+
+		log.Printf("transaction reversed: id=%s, account_id=%s, request_id=%s, amount=%v, reason=%q, new_balance=%v", leg.Id, acc.Id, req.RequestId, amount, req.Reason, acc.Balance)
+	}
+
+	return resp, nil
+}
+
+// transactionLegs returns the entries that make up the operation tx belongs to:
+// both sides of a transfer, or tx alone.
+func (s *Service) transactionLegs(tx *accountv2.Transaction) []*accountv2.Transaction {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	switch tx.Type {
+	case accountv2.TransactionType_TRANSACTION_TYPE_TRANSFER_OUT, accountv2.TransactionType_TRANSACTION_TYPE_TRANSFER_IN:
+		if resp, ok := s.transfers[tx.RequestId]; ok && resp.Debit != nil && resp.Credit != nil {
+			return []*accountv2.Transaction{resp.Debit, resp.Credit}
+		}
+	}
+	return []*accountv2.Transaction{tx}
+}
+
+// reversed returns how much of tx has been reversed so far.
+func reversed(tx *accountv2.Transaction) *commonv1.Money {
+	if tx.ReversedAmount == nil {
+		return &commonv1.Money{Currency: tx.Amount.GetCurrency()}
+	}
+	return tx.ReversedAmount
+}
@@ -0,0 +1,134 @@
+package account
+
+import (
+	"context"
+	"testing"
+
+	accountv2 "github.com/galadeat/bank-sim/api/proto/account/v2"
+	commonv1 "github.com/galadeat/bank-sim/api/proto/common/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestReverseTransaction(t *testing.T) {
+	usd := func(units int64) *commonv1.Money {
+		return &commonv1.Money{Currency: "USD", Units: units}
+	}
+	balance := func(t *testing.T, svc *Service, id string) *commonv1.Money {
+		t.Helper()
+		got, err := svc.GetAccount(context.Background(), &accountv2.GetAccountRequest{Id: id})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return got.Account.Balance
+	}
+	lastEntry := func(t *testing.T, svc *Service, id string) *accountv2.Transaction {
+		t.Helper()
+		resp, err := svc.ListTransactions(context.Background(), &accountv2.ListTransactionsRequest{AccountId: id})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return resp.Transactions[len(resp.Transactions)-1]
+	}
+
+	t.Run("partial refunds up to the original amount", func(t *testing.T) {
+		ctx := context.Background()
+		svc := newTestService(t)
+		acc := createTestAccount(t, svc, 100)
+		if _, err := svc.Withdraw(ctx, &accountv2.WithdrawRequest{AccountId: acc.Id, Amount: usd(60), RequestId: "w-1"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		withdrawal := lastEntry(t, svc, acc.Id)
+
+		first, err := svc.ReverseTransaction(ctx, &accountv2.ReverseTransactionRequest{TransactionId: withdrawal.Id, Amount: usd(20), RequestId: "r-1", Reason: "item returned"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		refund := first.Reversals[0]
+		if refund.Type != accountv2.TransactionType_TRANSACTION_TYPE_REFUND || refund.ReversesTransactionId != withdrawal.Id {
+			t.Errorf("expected a refund linked to %s, got %v", withdrawal.Id, refund)
+		}
+		assertMoney(t, usd(60), balance(t, svc, acc.Id))
+
+		// the same request is not applied twice
+		again, _ := svc.ReverseTransaction(ctx, &accountv2.ReverseTransactionRequest{TransactionId: withdrawal.Id, Amount: usd(20), RequestId: "r-1", Reason: "item returned"})
+		if again.Reversals[0].Id != refund.Id {
+			t.Errorf("expected the retried request to return the first refund")
+		}
+
+		_, err = svc.ReverseTransaction(ctx, &accountv2.ReverseTransactionRequest{TransactionId: withdrawal.Id, Amount: usd(41), RequestId: "r-2", Reason: "too much"})
+		if status.Code(err) != codes.FailedPrecondition {
+			t.Fatalf("expected %v, got %v", codes.FailedPrecondition, err)
+		}
+
+		// without an amount the rest is refunded
+		if _, err := svc.ReverseTransaction(ctx, &accountv2.ReverseTransactionRequest{TransactionId: withdrawal.Id, RequestId: "r-3", Reason: "rest"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		assertMoney(t, usd(100), balance(t, svc, acc.Id))
+
+		_, err = svc.ReverseTransaction(ctx, &accountv2.ReverseTransactionRequest{TransactionId: withdrawal.Id, RequestId: "r-4", Reason: "again"})
+		if status.Code(err) != codes.FailedPrecondition {
+			t.Errorf("expected %v, got %v", codes.FailedPrecondition, err)
+		}
+	})
+
+	t.Run("reversed deposit may overdraw", func(t *testing.T) {
+		ctx := context.Background()
+		svc := newTestService(t)
+		acc := createTestAccount(t, svc, 0)
+		if _, err := svc.Deposit(ctx, &accountv2.DepositRequest{AccountId: acc.Id, Amount: usd(50), RequestId: "d-1"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		deposit := lastEntry(t, svc, acc.Id)
+		if _, err := svc.Withdraw(ctx, &accountv2.WithdrawRequest{AccountId: acc.Id, Amount: usd(30), RequestId: "w-1"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		resp, err := svc.ReverseTransaction(ctx, &accountv2.ReverseTransactionRequest{TransactionId: deposit.Id, RequestId: "r-1", Reason: "chargeback"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if resp.Reversals[0].Type != accountv2.TransactionType_TRANSACTION_TYPE_REVERSAL {
+			t.Errorf("expected a reversal, got %v", resp.Reversals[0].Type)
+		}
+		assertMoney(t, usd(-30), balance(t, svc, acc.Id))
+	})
+
+	t.Run("transfer is reversed on both sides", func(t *testing.T) {
+		ctx := context.Background()
+		svc := newTestService(t)
+		from := createTestAccount(t, svc, 100)
+		to := createTestAccount(t, svc, 10)
+		tr, err := svc.Transfer(ctx, &accountv2.TransferRequest{FromAccountId: from.Id, ToAccountId: to.Id, Amount: usd(40), RequestId: "t-1"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		resp, err := svc.ReverseTransaction(ctx, &accountv2.ReverseTransactionRequest{TransactionId: tr.Credit.Id, Amount: usd(15), RequestId: "r-1", Reason: "dispute"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(resp.Reversals) != 2 {
+			t.Fatalf("expected 2 entries, got %d", len(resp.Reversals))
+		}
+		assertMoney(t, usd(75), balance(t, svc, from.Id))
+		assertMoney(t, usd(35), balance(t, svc, to.Id))
+		assertMoney(t, usd(15), tr.Debit.ReversedAmount)
+		assertMoney(t, usd(15), tr.Credit.ReversedAmount)
+	})
+
+	t.Run("interest cannot be reversed", func(t *testing.T) {
+		ctx := context.Background()
+		svc := newTestService(t)
+		acc := createTestAccount(t, svc, 100)
+		unlock := svc.locks.lock(acc.Id)
+		tx := svc.record(acc, accountv2.TransactionType_TRANSACTION_TYPE_INTEREST, usd(1), "interest:test")
+		unlock()
+
+		_, err := svc.ReverseTransaction(ctx, &accountv2.ReverseTransactionRequest{TransactionId: tx.Id, RequestId: "r-1", Reason: "test"})
+		if status.Code(err) != codes.FailedPrecondition {
+			t.Errorf("expected %v, got %v", codes.FailedPrecondition, err)
+		}
+	})
+}
@@ -0,0 +1,208 @@
+package account
+
+import (
+	"context"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+
+	accountv2 "github.com/galadeat/bank-sim/api/proto/account/v2"
+	commonv1 "github.com/galadeat/bank-sim/api/proto/common/v1"
+	"github.com/galadeat/bank-sim/internal/risk"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ReasonRiskDeclined is the ErrorInfo reason of a payment declined by the risk checks.
+const ReasonRiskDeclined = "RISK_DECLINED"
+
+// RiskScorer assesses withdrawals and outgoing transfers before they are
+// committed. It is called with the account locked and must not call back into
+// the service. Payments from different accounts are assessed concurrently.
+type RiskScorer interface {
+	Assess(p risk.Payment) risk.Assessment
+}
+
+// WithRiskScorer enables risk checks on withdrawals and outgoing transfers.
+func WithRiskScorer(scorer RiskScorer) Option {
+	return func(s *Service) {
+		s.risk = scorer
+	}
+}
+
+// ListPendingReviews is the realization of the rpc method
+func (s *Service) ListPendingReviews(ctx context.Context, req *accountv2.ListPendingReviewsRequest) (*accountv2.ListPendingReviewsResponse, error) {
+	select {
+	case <-ctx.Done():
+		return nil, status.Error(codes.Canceled, "request canceled by client")
+	default:
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var reviews []*accountv2.Review
+	for _, r := range s.reviews {
+		if r.Status == accountv2.ReviewStatus_REVIEW_STATUS_PENDING {
+			reviews = append(reviews, snapshot(r))
+		}
+	}
+	sort.Slice(reviews, func(i, j int) bool {
+		a, b := reviews[i].CreatedAt.AsTime(), reviews[j].CreatedAt.AsTime()
+		if a.Equal(b) {
+			return reviews[i].Id < reviews[j].Id
+		}
+		return a.Before(b)
+	})
+	return &accountv2.ListPendingReviewsResponse{Reviews: reviews}, nil
+}
+
+// ResolveReview is the realization of the rpc method. An approved payment is
+// applied with its original request id; if it can no longer be applied, for
+// example because the balance has changed, the review stays pending.
+func (s *Service) ResolveReview(ctx context.Context, req *accountv2.ResolveReviewRequest) (*accountv2.ResolveReviewResponse, error) {
+	select {
+	case <-ctx.Done():
+		return nil, status.Error(codes.Canceled, "request canceled by client")
+	default:
+	}
+
+	if req.ReviewId == "" {
+		return nil, status.Error(codes.InvalidArgument, "review id is required")
+	}
+	if req.Reason == "" {
+		return nil, status.Error(codes.InvalidArgument, "reason is required")
+	}
+
+	s.mu.RLock()
+	review, ok := s.reviews[req.ReviewId]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, status.Error(codes.NotFound, "review not found")
+	}
+
+	// a review is only changed under the lock of the account it holds money for
+	unlock := s.locks.lock(review.AccountId, review.ToAccountId)
+	defer unlock()
+	unlockOwners := s.lockOwners(review.AccountId, review.ToAccountId)
+	defer unlockOwners()
+
+	if review.Status != accountv2.ReviewStatus_REVIEW_STATUS_PENDING {
+		return nil, status.Error(codes.FailedPrecondition, "review is already resolved")
+	}
+
+	resolution := accountv2.ReviewStatus_REVIEW_STATUS_REJECTED
+	if req.Approve {
+		if err := s.applyReviewed(review); err != nil {
+			return nil, err
+		}
+		resolution = accountv2.ReviewStatus_REVIEW_STATUS_APPROVED
+	}
+	// the review queue is read under s.mu alone
+	s.mu.Lock()
+	review.Status = resolution
+	review.ResolvedAt = timestamppb.New(s.clock.Now())
+	review.Resolution = req.Reason
+	resolved := snapshot(review)
+	s.mu.Unlock()
+
+	log.Printf("review resolved: id=%s, request_id=%s, status=%s, reason=%q", review.Id, review.RequestId, review.Status, req.Reason)
+	return &accountv2.ResolveReviewResponse{Review: resolved}, nil
+}
+
+// applyReviewed applies an approved payment and replaces the held response, so a
+// retried request sees the outcome. Callers must hold the locks of the accounts
+// in the review.
+func (s *Service) applyReviewed(review *accountv2.Review) error {
+	from, ok := s.account(review.AccountId)
+	if !ok {
+		return status.Error(codes.NotFound, "account not found")
+	}
+
+	if review.Type == accountv2.TransactionType_TRANSACTION_TYPE_TRANSFER_OUT {
+		to, ok := s.account(review.ToAccountId)
+		if !ok {
+			return status.Error(codes.NotFound, "destination account not found")
+		}
+		resp, err := s.transfer(from, to, review.Amount, review.RequestId, false)
+		if err != nil {
+			return err
+		}
+		resp.Review = review
+		s.mu.Lock()
+		s.transfers[review.RequestId] = resp
+		s.mu.Unlock()
+		s.rememberOutcome(accountv2.Account_Transfer_FullMethodName, review.RequestId, resp)
+		return nil
+	}
+
+	resp, err := s.withdraw(from, review.Amount, review.RequestId, false)
+	if err != nil {
+		return err
+	}
+	resp.Review = review
+	s.rememberOutcome(accountv2.Account_Withdraw_FullMethodName, review.RequestId, resp)
+	return nil
+}
+
+// screen asks the risk scorer about a payment from acc. It returns a review if the
+// payment is held, and an error if it is declined. Callers must hold the lock of
+// acc.
+func (s *Service) screen(acc *accountv2.AccountInfo, toAccountID string, typ accountv2.TransactionType, amount *commonv1.Money, requestID string) (*accountv2.Review, error) {
+	if s.risk == nil {
+		return nil, nil
+	}
+
+	now := s.clock.Now()
+	a := s.risk.Assess(risk.Payment{
+		Type:      typ,
+		AccountID: acc.Id,
+		UserID:    acc.GetOwnerId(),
+		Amount:    amount,
+		OpenedAt:  acc.OpenedAt.AsTime(),
+		At:        now,
+		History:   s.history(acc.Id),
+	})
+
+	switch a.Decision {
+	case risk.Allow:
+		return nil, nil
+	case risk.Decline:
+		log.Printf("payment declined: account_id=%s, request_id=%s, score=%d, reasons=%v", acc.Id, requestID, a.Score, a.Reasons)
+		st, err := status.New(codes.PermissionDenied, "payment declined by risk checks").WithDetails(&errdetails.ErrorInfo{
+			Reason: ReasonRiskDeclined,
+			Domain: "risk.bank-sim",
+			Metadata: map[string]string{
+				"account_id": acc.Id,
+				"score":      strconv.Itoa(a.Score),
+				"reasons":    strings.Join(a.Reasons, ","),
+			},
+		})
+		if err != nil {
+			return nil, status.Error(codes.PermissionDenied, "payment declined by risk checks")
+		}
+		return nil, st.Err()
+	}
+
+	review := &accountv2.Review{
+		Id:          s.ids.NewID(),
+		AccountId:   acc.Id,
+		ToAccountId: toAccountID,
+		Type:        typ,
+		Amount:      amount,
+		RequestId:   requestID,
+		Score:       int32(a.Score),
+		Reasons:     a.Reasons,
+		Status:      accountv2.ReviewStatus_REVIEW_STATUS_PENDING,
+		CreatedAt:   timestamppb.New(now),
+	}
+	s.mu.Lock()
+	s.reviews[review.Id] = review
+	s.mu.Unlock()
+
+	log.Printf("payment held for review: id=%s, account_id=%s, request_id=%s, score=%d, reasons=%v", review.Id, acc.Id, requestID, a.Score, a.Reasons)
+	return review, nil
+}
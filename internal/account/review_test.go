@@ -0,0 +1,157 @@
+package account
+
+import (
+	"context"
+	"testing"
+
+	accountv2 "github.com/galadeat/bank-sim/api/proto/account/v2"
+	commonv1 "github.com/galadeat/bank-sim/api/proto/common/v1"
+	"github.com/galadeat/bank-sim/internal/risk"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fixedScorer returns the same decision for every payment and remembers what it saw.
+type fixedScorer struct {
+	decision risk.Decision
+	seen     []risk.Payment
+}
+
+func (f *fixedScorer) Assess(p risk.Payment) risk.Assessment {
+	f.seen = append(f.seen, p)
+	return risk.Assessment{Decision: f.decision, Score: 70, Reasons: []string{"test"}}
+}
+
+func TestRiskChecks(t *testing.T) {
+	usd := func(units int64) *commonv1.Money {
+		return &commonv1.Money{Currency: "USD", Units: units}
+	}
+	balance := func(t *testing.T, svc *Service, id string) *commonv1.Money {
+		t.Helper()
+		got, err := svc.GetAccount(context.Background(), &accountv2.GetAccountRequest{Id: id})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return got.Account.Balance
+	}
+
+	t.Run("declined", func(t *testing.T) {
+		svc := newTestService(t)
+		scorer := &fixedScorer{decision: risk.Decline}
+		WithRiskScorer(scorer)(svc)
+		acc := createTestAccount(t, svc, 100)
+
+		_, err := svc.Withdraw(context.Background(), &accountv2.WithdrawRequest{AccountId: acc.Id, Amount: usd(10), RequestId: "w-1"})
+		if status.Code(err) != codes.PermissionDenied {
+			t.Fatalf("expected %v, got %v", codes.PermissionDenied, err)
+		}
+		assertMoney(t, usd(100), balance(t, svc, acc.Id))
+		if len(scorer.seen) != 1 || scorer.seen[0].Type != accountv2.TransactionType_TRANSACTION_TYPE_WITHDRAWAL {
+			t.Errorf("expected the scorer to see one withdrawal, got %v", scorer.seen)
+		}
+	})
+
+	t.Run("deposits are not scored", func(t *testing.T) {
+		svc := newTestService(t)
+		WithRiskScorer(&fixedScorer{decision: risk.Decline})(svc)
+		acc := createTestAccount(t, svc, 100)
+
+		if _, err := svc.Deposit(context.Background(), &accountv2.DepositRequest{AccountId: acc.Id, Amount: usd(10), RequestId: "d-1"}); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("held withdrawal is applied on approval", func(t *testing.T) {
+		ctx := context.Background()
+		svc := newTestService(t)
+		WithRiskScorer(&fixedScorer{decision: risk.Review})(svc)
+		acc := createTestAccount(t, svc, 100)
+
+		req := &accountv2.WithdrawRequest{AccountId: acc.Id, Amount: usd(40), RequestId: "w-1"}
+		held, err := svc.Withdraw(ctx, req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if held.Review == nil || held.Account != nil {
+			t.Fatalf("expected the withdrawal to be held, got %v", held)
+		}
+		assertMoney(t, usd(100), balance(t, svc, acc.Id))
+
+		pending, _ := svc.ListPendingReviews(ctx, &accountv2.ListPendingReviewsRequest{})
+		if len(pending.Reviews) != 1 || pending.Reviews[0].Id != held.Review.Id {
+			t.Fatalf("expected the review in the queue, got %v", pending.Reviews)
+		}
+
+		resolved, err := svc.ResolveReview(ctx, &accountv2.ResolveReviewRequest{ReviewId: held.Review.Id, Approve: true, Reason: "customer confirmed"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if resolved.Review.Status != accountv2.ReviewStatus_REVIEW_STATUS_APPROVED {
+			t.Errorf("expected approved review, got %v", resolved.Review.Status)
+		}
+		assertMoney(t, usd(60), balance(t, svc, acc.Id))
+
+		// a retry of the original request sees the applied withdrawal
+		retried, err := svc.Withdraw(ctx, req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		assertMoney(t, usd(60), retried.Account.Balance)
+		assertMoney(t, usd(60), balance(t, svc, acc.Id))
+
+		_, err = svc.ResolveReview(ctx, &accountv2.ResolveReviewRequest{ReviewId: held.Review.Id, Approve: false, Reason: "again"})
+		if status.Code(err) != codes.FailedPrecondition {
+			t.Errorf("expected %v, got %v", codes.FailedPrecondition, err)
+		}
+	})
+
+	t.Run("held transfer is dropped on rejection", func(t *testing.T) {
+		ctx := context.Background()
+		svc := newTestService(t)
+		WithRiskScorer(&fixedScorer{decision: risk.Review})(svc)
+		from := createTestAccount(t, svc, 100)
+		to := createTestAccount(t, svc, 0)
+
+		held, err := svc.Transfer(ctx, &accountv2.TransferRequest{FromAccountId: from.Id, ToAccountId: to.Id, Amount: usd(40), RequestId: "t-1"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if held.Review == nil || held.Review.ToAccountId != to.Id {
+			t.Fatalf("expected the transfer to be held, got %v", held)
+		}
+
+		if _, err := svc.ResolveReview(ctx, &accountv2.ResolveReviewRequest{ReviewId: held.Review.Id, Approve: false, Reason: "fraud"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		assertMoney(t, usd(100), balance(t, svc, from.Id))
+		assertMoney(t, usd(0), balance(t, svc, to.Id))
+
+		pending, _ := svc.ListPendingReviews(ctx, &accountv2.ListPendingReviewsRequest{})
+		if len(pending.Reviews) != 0 {
+			t.Errorf("expected an empty queue, got %v", pending.Reviews)
+		}
+	})
+
+	t.Run("approval fails when funds are gone", func(t *testing.T) {
+		ctx := context.Background()
+		svc := newTestService(t)
+		scorer := &fixedScorer{decision: risk.Review}
+		WithRiskScorer(scorer)(svc)
+		acc := createTestAccount(t, svc, 100)
+
+		held, _ := svc.Withdraw(ctx, &accountv2.WithdrawRequest{AccountId: acc.Id, Amount: usd(80), RequestId: "w-1"})
+		scorer.decision = risk.Allow
+		if _, err := svc.Withdraw(ctx, &accountv2.WithdrawRequest{AccountId: acc.Id, Amount: usd(50), RequestId: "w-2"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		_, err := svc.ResolveReview(ctx, &accountv2.ResolveReviewRequest{ReviewId: held.Review.Id, Approve: true, Reason: "ok"})
+		if status.Code(err) != codes.FailedPrecondition {
+			t.Fatalf("expected %v, got %v", codes.FailedPrecondition, err)
+		}
+		pending, _ := svc.ListPendingReviews(ctx, &accountv2.ListPendingReviewsRequest{})
+		if len(pending.Reviews) != 1 {
+			t.Errorf("expected the review to stay pending, got %v", pending.Reviews)
+		}
+	})
+}
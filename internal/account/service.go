@@ -8,31 +8,108 @@ import (
 	accountv2 "github.com/galadeat/bank-sim/api/proto/account/v2"
 	commonv1 "github.com/galadeat/bank-sim/api/proto/common/v1"
 	userv1 "github.com/galadeat/bank-sim/api/proto/user/v1"
-	"github.com/gofrs/uuid"
+	"github.com/galadeat/bank-sim/internal/idempotency"
+	"github.com/galadeat/bank-sim/internal/rules"
+	"github.com/galadeat/bank-sim/pkg/clock"
+	"github.com/galadeat/bank-sim/pkg/idgen"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+// ReasonInsufficientFunds is the ErrorInfo reason of payments the balance, the
+// overdraft limit or the funds not on hold do not cover.
+const ReasonInsufficientFunds = "INSUFFICIENT_FUNDS"
+
 type Service struct {
 	accountv2.UnimplementedAccountServer
+
+	// locks serialize changes to each account and ownerLocks the rules checks
+	// of each user's accounts (see lockOwners); mu only guards the maps below
+	// and is never held while waiting for one of them or a remote call.
+	locks        accountLocks
+	ownerLocks   accountLocks
 	mu           sync.RWMutex
 	accounts     map[string]*accountv2.AccountInfo
-	deposits     map[string]*accountv2.DepositResponse
-	withdraws    map[string]*accountv2.WithdrawResponse
-	accCreations map[string]*accountv2.CreateAccountResponse
+	transfers    map[string]*accountv2.TransferResponse
+	reviews      map[string]*accountv2.Review
+	holds        map[string]*accountv2.Hold
+	accountHolds map[string][]*accountv2.Hold
+	audit        map[string][]*accountv2.StatusChange
+	ledger       map[string][]*accountv2.Transaction
+	transactions map[string]*accountv2.Transaction
+	accruals     map[string]*accrual
+	// closedOwners holds the users whose accounts CloseUserAccounts has closed or
+	// is closing. No account is opened for them, even by a request that looked
+	// the user up before; it is changed and checked under the user's owner lock.
+	closedOwners map[string]bool
 
 	userClient userv1.UserClient
+	clock      clock.Clock
+	ids        idgen.IDGenerator
+	products   map[accountv2.AccountType]Product
+	rules      *rules.Engine
+	risk       RiskScorer
+
+	idempotency *idempotency.Store
+
+	// restoring serializes RestoreAccount, so an account is restored from a
+	// source account once even when the same dataset is imported twice at once.
+	restoring sync.Mutex
+}
+
+// Option configures a Service.
+type Option func(*Service)
+
+// WithClock sets the clock used for timestamps, maturities and interest accrual.
+func WithClock(c clock.Clock) Option {
+	return func(s *Service) {
+		s.clock = c
+	}
+}
+
+// WithIDGenerator sets the generator of account, ledger entry, hold and review ids.
+func WithIDGenerator(g idgen.IDGenerator) Option {
+	return func(s *Service) {
+		s.ids = g
+	}
+}
+
+// WithProducts replaces the product catalogue. Account types missing from it
+// cannot be opened.
+func WithProducts(products map[accountv2.AccountType]Product) Option {
+	return func(s *Service) {
+		s.products = products
+	}
 }
 
 // New is the constructor
-func New(userClient userv1.UserClient) *Service {
-	return &Service{
+func New(userClient userv1.UserClient, opts ...Option) *Service {
+	s := &Service{
 		accounts:     make(map[string]*accountv2.AccountInfo),
-		accCreations: make(map[string]*accountv2.CreateAccountResponse),
-		deposits:     make(map[string]*accountv2.DepositResponse),
-		withdraws:    make(map[string]*accountv2.WithdrawResponse),
+		transfers:    make(map[string]*accountv2.TransferResponse),
+		reviews:      make(map[string]*accountv2.Review),
+		holds:        make(map[string]*accountv2.Hold),
+		accountHolds: make(map[string][]*accountv2.Hold),
+		audit:        make(map[string][]*accountv2.StatusChange),
+		ledger:       make(map[string][]*accountv2.Transaction),
+		transactions: make(map[string]*accountv2.Transaction),
+		accruals:     make(map[string]*accrual),
+		closedOwners: make(map[string]bool),
 		userClient:   userClient,
+		clock:        clock.Real(),
+		ids:          idgen.Random(),
+		products:     DefaultProducts,
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+	if s.idempotency == nil {
+		s.idempotency = idempotency.New(idempotency.WithClock(s.clock))
+	}
+	return s
 }
 
 // CreateAccount is the realization of the rpc method
@@ -43,45 +120,124 @@ func (s *Service) CreateAccount(ctx context.Context, req *accountv2.CreateAccoun
 	default:
 	}
 
+	product, err := s.validateCreateAccount(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return once(s, accountv2.Account_CreateAccount_FullMethodName, req.RequestId, req, func() (*accountv2.CreateAccountResponse, error) {
+		return s.createAccount(ctx, req, product)
+	})
+}
+
+// validateCreateAccount checks the request and returns the product it opens.
+func (s *Service) validateCreateAccount(req *accountv2.CreateAccountRequest) (Product, error) {
 	if req.UserId == "" {
-		return nil, status.Error(codes.InvalidArgument, "user id is required")
+		return Product{}, status.Error(codes.InvalidArgument, "user id is required")
 	}
 
 	if req.RequestId == "" {
-		return nil, status.Error(codes.InvalidArgument, "request id is required")
+		return Product{}, status.Error(codes.InvalidArgument, "request id is required")
 	}
 
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	typ := accountType(req.Type)
+	product, ok := s.products[typ]
+	if !ok {
+		return Product{}, status.Errorf(codes.InvalidArgument, "unsupported account type %v", req.Type)
+	}
+	if product.Term && req.TermMonths <= 0 {
+		return Product{}, status.Error(codes.InvalidArgument, "term months must be positive for term deposits")
+	}
+	if isNegative(req.InitialBalance) {
+		return Product{}, status.Error(codes.InvalidArgument, "initial balance must not be negative")
+	}
+	return product, nil
+}
 
-	if resp, ok := s.accCreations[req.RequestId]; ok {
-		return resp, nil
+// createAccount opens the account. The user service is called before any lock is
+// taken, so a slow call does not hold up other requests.
+func (s *Service) createAccount(ctx context.Context, req *accountv2.CreateAccountRequest, product Product) (*accountv2.CreateAccountResponse, error) {
+	owner, err := s.owner(ctx, req.UserId, false)
+	if err != nil {
+		return nil, err
 	}
+	return s.openAccount(req, product, owner)
+}
 
-	userResp, err := s.userClient.GetUser(ctx, &userv1.GetUserRequest{Id: req.UserId})
+// owner looks the user up in the user service. Closed users are only found with
+// includeClosed.
+func (s *Service) owner(ctx context.Context, userID string, includeClosed bool) (*userv1.UserInfo, error) {
+	userResp, err := s.userClient.GetUser(ctx, &userv1.GetUserRequest{Id: userID, IncludeClosed: includeClosed})
 	if err != nil {
 		if st, ok := status.FromError(err); ok {
 			return nil, st.Err()
 		}
 		return nil, status.Errorf(codes.Internal, "failed to call UserService: %v", err)
 	}
+	return userResp.User, nil
+}
 
-	id, err := uuid.NewV4()
-	if err != nil {
-		return nil, status.Errorf(codes.Internal, "error while generating account id: %v", err)
+// userCache is implemented by user clients that cache users, such as
+// resilience.UserClient.
+type userCache interface {
+	Forget(userID string)
+}
+
+// forgetUser drops the user from the cache of the user client, if it has one.
+// The user service reports changes of a user through SyncOwner and
+// CloseUserAccounts, and the next lookup of the user has to see them.
+func (s *Service) forgetUser(userID string) {
+	if c, ok := s.userClient.(userCache); ok {
+		c.Forget(userID)
 	}
+}
 
-	account := &accountv2.AccountInfo{Id: id.String(),
-		Owner:   userResp.User,
-		Balance: req.InitialBalance}
+// openAccount adds the account of owner.
+func (s *Service) openAccount(req *accountv2.CreateAccountRequest, product Product, owner *userv1.UserInfo) (*accountv2.CreateAccountResponse, error) {
+	now := s.clock.Now()
+	account := &accountv2.AccountInfo{Id: s.ids.NewID(),
+		Owner:    owner,
+		OwnerId:  req.UserId,
+		Balance:  req.InitialBalance,
+		Type:     accountType(req.Type),
+		OpenedAt: timestamppb.New(now)}
+	if product.Term {
+		account.MaturesAt = timestamppb.New(now.AddDate(0, int(req.TermMonths), 0))
+	}
+
+	// an account without an initial balance stays pending until its first deposit
+	initial := statusPending
+	if !isZeroBalance(account) {
+		initial = statusActive
+	}
+	if err := s.transition(account, initial, "account created"); err != nil {
+		return nil, err
+	}
+
+	// the account is visible once it is in the map, so keep it locked until the
+	// opening deposit is recorded
+	unlock := s.locks.lock(account.Id)
+	defer unlock()
+	// the owner may have been looked up before CloseUserAccounts started; it
+	// lists the accounts only once no more can be added
+	unlockOwner := s.ownerLocks.lock(req.UserId)
+	defer unlockOwner()
+	if s.ownerClosed(req.UserId) {
+		return nil, status.Errorf(codes.FailedPrecondition, "user %s is being deleted", req.UserId)
+	}
 
-	s.accounts[id.String()] = account
+	s.setBalance(account, account.Balance)
+	s.mu.Lock()
+	s.accounts[account.Id] = account
+	s.accruals[account.Id] = &accrual{through: startOfDay(now)}
+	s.mu.Unlock()
+	if !isZeroBalance(account) {
+		s.record(account, accountv2.TransactionType_TRANSACTION_TYPE_DEPOSIT, account.Balance, req.RequestId)
+	}
 
 	log.Printf("account created: account=%v, request_id=%s", account, req.RequestId)
 
-	resp := &accountv2.CreateAccountResponse{Account: account}
-	s.accCreations[req.RequestId] = resp
-	return resp, nil
+	return &accountv2.CreateAccountResponse{Account: snapshot(account)}, nil
 }
 
 // GetAccount is the realization of the rpc method
@@ -96,13 +252,14 @@ func (s *Service) GetAccount(ctx context.Context, req *accountv2.GetAccountReque
 		return nil, status.Error(codes.InvalidArgument, "account id is required")
 	}
 
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-	account, ok := s.accounts[req.Id]
+	unlock := s.locks.lock(req.Id)
+	defer unlock()
+
+	account, ok := s.account(req.Id)
 	if !ok {
 		return nil, status.Error(codes.NotFound, "account not found")
 	}
-	return &accountv2.GetAccountResponse{Account: account}, nil
+	return &accountv2.GetAccountResponse{Account: snapshot(account)}, nil
 
 }
 
@@ -118,7 +275,9 @@ func (s *Service) ListAccounts(ctx context.Context, req *accountv2.ListAccountsR
 		return nil, status.Error(codes.InvalidArgument, "user id is required")
 	}
 
-	_, err := s.userClient.GetUser(ctx, &userv1.GetUserRequest{Id: req.UserId})
+	// closed accounts are kept after their user is deleted, so they are listed
+	// for closed users too
+	_, err := s.userClient.GetUser(ctx, &userv1.GetUserRequest{Id: req.UserId, IncludeClosed: req.IncludeClosed})
 	if err != nil {
 		if st, ok := status.FromError(err); ok {
 			return nil, st.Err()
@@ -126,14 +285,17 @@ func (s *Service) ListAccounts(ctx context.Context, req *accountv2.ListAccountsR
 		return nil, status.Errorf(codes.Internal, "failed to call UserService: %v", err)
 	}
 
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+	ids := s.accountIDs(req.UserId)
+	unlock := s.locks.lock(ids...)
+	defer unlock()
 
 	var accounts []*accountv2.AccountInfo
-	for _, account := range s.accounts {
-		if account.Owner.Id == req.UserId {
-			accounts = append(accounts, account)
+	for _, id := range ids {
+		account, _ := s.account(id)
+		if isClosed(account) && !req.IncludeClosed {
+			continue
 		}
+		accounts = append(accounts, snapshot(account))
 	}
 
 	return &accountv2.ListAccountsResponse{Accounts: accounts}, nil
@@ -151,20 +313,28 @@ func (s *Service) DeleteAccount(ctx context.Context, req *accountv2.DeleteAccoun
 		return nil, status.Error(codes.InvalidArgument, "account id is required")
 	}
 
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	acc, ok := s.accounts[req.AccountId]
+	unlock := s.locks.lock(req.AccountId)
+	defer unlock()
+	acc, ok := s.account(req.AccountId)
 	if !ok {
 		return nil, status.Error(codes.NotFound, "account not found")
 	}
 
-	if acc.Balance.Units != 0 || acc.Balance.Nanos != 0 {
+	if isClosed(acc) {
+		return nil, status.Error(codes.FailedPrecondition, "account is already closed")
+	}
+
+	s.settleInterest(acc)
+	if !isZeroBalance(acc) {
 		return nil, status.Error(codes.FailedPrecondition, "cannot delete account with non-zero balance")
 	}
 
-	delete(s.accounts, req.AccountId)
+	// accounts are closed rather than removed so their history stays available for reporting
+	if err := s.transition(acc, statusClosed, "account deleted"); err != nil {
+		return nil, err
+	}
 
-	log.Printf("account deleted: id=%s", req.AccountId)
+	log.Printf("account closed: id=%s", req.AccountId)
 
 	return &accountv2.DeleteAccountResponse{AccountId: req.AccountId}, nil
 }
@@ -176,40 +346,71 @@ func (s *Service) Deposit(ctx context.Context, req *accountv2.DepositRequest) (*
 		return nil, status.Error(codes.Canceled, "request canceled by client")
 	default:
 	}
-	if req.AccountId == "" {
-		return nil, status.Error(codes.InvalidArgument, "account id is required")
-	}
-	if req.Amount == nil || (req.Amount.Units == 0 && req.Amount.Nanos == 0) {
-		return nil, status.Error(codes.InvalidArgument, "deposit must be greater than zero")
-	}
-	if req.RequestId == "" {
-		return nil, status.Error(codes.InvalidArgument, "request id is required")
+	if err := validatePosting(req.AccountId, req.Amount, req.RequestId, "deposit"); err != nil {
+		return nil, err
 	}
 
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	return once(s, accountv2.Account_Deposit_FullMethodName, req.RequestId, req, func() (*accountv2.DepositResponse, error) {
+		return s.deposit(req)
+	})
+}
 
-	if resp, ok := s.deposits[req.RequestId]; ok {
-		return resp, nil
+// validatePosting checks the fields of a deposit or withdrawal.
+func validatePosting(accountID string, amount *commonv1.Money, requestID, kind string) error {
+	if accountID == "" {
+		return status.Error(codes.InvalidArgument, "account id is required")
+	}
+	if amount == nil || (amount.Units == 0 && amount.Nanos == 0) || isNegative(amount) {
+		return status.Errorf(codes.InvalidArgument, "%s must be greater than zero", kind)
+	}
+	if requestID == "" {
+		return status.Error(codes.InvalidArgument, "request id is required")
 	}
+	return nil
+}
 
-	acc, ok := s.accounts[req.AccountId]
+// deposit credits the account under its lock.
+func (s *Service) deposit(req *accountv2.DepositRequest) (*accountv2.DepositResponse, error) {
+	unlock := s.locks.lock(req.AccountId)
+	defer unlock()
+	unlockOwners := s.lockOwners(req.AccountId)
+	defer unlockOwners()
+
+	acc, ok := s.account(req.AccountId)
 	if !ok {
 		return nil, status.Error(codes.NotFound, "account not found")
 	}
+	return s.credit(acc, req.Amount, req.RequestId)
+}
+
+// credit deposits amount to acc. Callers must hold the lock of acc.
+func (s *Service) credit(acc *accountv2.AccountInfo, amount *commonv1.Money, requestID string) (*accountv2.DepositResponse, error) {
+	if err := canReceive(acc); err != nil {
+		return nil, err
+	}
 
-	balance, err := addMoney(acc.Balance, req.Amount)
+	balance, err := addMoney(acc.Balance, amount)
 	if err != nil {
 		return nil, err
 	}
+	op := s.operation(acc, rules.KindDeposit, amount)
+	if err := s.checkRules(op); err != nil {
+		return nil, err
+	}
 
-	s.accounts[req.AccountId].Balance = balance
+	s.setBalance(acc, balance)
+	s.record(acc, accountv2.TransactionType_TRANSACTION_TYPE_DEPOSIT, amount, requestID)
+	s.recordRules(op)
 
-	log.Printf("deposit: account_id=%s, request_id=%s, amount=%v, new_balance=%v", req.AccountId, req.RequestId, req.Amount, acc.Balance)
+	if acc.Status == statusPending {
+		if err := s.transition(acc, statusActive, "first deposit"); err != nil {
+			return nil, err
+		}
+	}
 
-	resp := &accountv2.DepositResponse{Account: acc}
-	s.deposits[req.RequestId] = resp
-	return resp, nil
+	log.Printf("deposit: account_id=%s, request_id=%s, amount=%v, new_balance=%v", acc.Id, requestID, amount, acc.Balance)
+
+	return &accountv2.DepositResponse{Account: snapshot(acc)}, nil
 }
 
 // Withdraw is the realization of the rpc method.
@@ -219,35 +420,171 @@ func (s *Service) Withdraw(ctx context.Context, req *accountv2.WithdrawRequest)
 		return nil, status.Error(codes.Canceled, "request canceled by client")
 	default:
 	}
-	if req.AccountId == "" {
-		return nil, status.Error(codes.InvalidArgument, "account id is required")
+	if err := validatePosting(req.AccountId, req.Amount, req.RequestId, "withdrawal"); err != nil {
+		return nil, err
 	}
-	if req.RequestId == "" {
-		return nil, status.Error(codes.InvalidArgument, "request id is required")
+
+	return once(s, accountv2.Account_Withdraw_FullMethodName, req.RequestId, req, func() (*accountv2.WithdrawResponse, error) {
+		unlock := s.locks.lock(req.AccountId)
+		defer unlock()
+		unlockOwners := s.lockOwners(req.AccountId)
+		defer unlockOwners()
+
+		acc, ok := s.account(req.AccountId)
+		if !ok {
+			return nil, status.Error(codes.NotFound, "account not found")
+		}
+		return s.withdraw(acc, req.Amount, req.RequestId, true)
+	})
+}
+
+// withdraw debits acc. With screen set the payment is first assessed by the risk
+// scorer and may be held for review instead of applied. Callers must hold the
+// lock of acc.
+func (s *Service) withdraw(acc *accountv2.AccountInfo, amount *commonv1.Money, requestID string, screen bool) (*accountv2.WithdrawResponse, error) {
+	balance, err := s.debitBalance(acc, amount)
+	if err != nil {
+		return nil, err
+	}
+	op := s.operation(acc, rules.KindWithdrawal, amount)
+	if err := s.checkRules(op); err != nil {
+		return nil, err
+	}
+	if screen {
+		review, err := s.screen(acc, "", accountv2.TransactionType_TRANSACTION_TYPE_WITHDRAWAL, amount, requestID)
+		if err != nil {
+			return nil, err
+		}
+		if review != nil {
+			return &accountv2.WithdrawResponse{Review: snapshot(review)}, nil
+		}
+	}
+
+	wasOverdrawn := isNegative(acc.Balance)
+	s.setBalance(acc, balance)
+	s.record(acc, accountv2.TransactionType_TRANSACTION_TYPE_WITHDRAWAL, amount, requestID)
+	s.recordRules(op)
+	if !wasOverdrawn && isNegative(balance) {
+		s.chargeOverdraftFee(acc, requestID)
+	}
+
+	log.Printf("withdraw: account_id=%s, request_id=%s, new_balance=%v", acc.Id, requestID, acc.Balance)
+	return &accountv2.WithdrawResponse{Account: snapshot(acc)}, nil
+}
+
+// CloseUserAccounts is the realization of the rpc method. It is called by the user
+// service before a user is deleted and closes either all or none of the user's accounts.
+func (s *Service) CloseUserAccounts(ctx context.Context, req *accountv2.CloseUserAccountsRequest) (*accountv2.CloseUserAccountsResponse, error) {
+	select {
+	case <-ctx.Done():
+		return nil, status.Error(codes.Canceled, "request canceled by client")
+	default:
+	}
+
+	if req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user id is required")
+	}
+	// the user is being deleted, so it must not be served from a cache
+	s.forgetUser(req.UserId)
+	// once the owner is marked, no account is added and the list below is complete
+	s.setOwnerClosed(req.UserId, true)
+
+	owned := s.accountIDs(req.UserId)
+	unlock := s.locks.lock(owned...)
+	defer unlock()
+
+	var open []*accountv2.AccountInfo
+	for _, id := range owned {
+		acc, _ := s.account(id)
+		if isClosed(acc) {
+			continue
+		}
+		s.settleInterest(acc)
+		if !isZeroBalance(acc) {
+			// the user is not deleted, so accounts may be opened for it again
+			s.setOwnerClosed(req.UserId, false)
+			return nil, status.Errorf(codes.FailedPrecondition, "user has funded account %s", acc.Id)
+		}
+		open = append(open, acc)
+	}
+
+	ids := make([]string, 0, len(open))
+	for _, acc := range open {
+		if err := s.transition(acc, statusClosed, "user deleted"); err != nil {
+			s.setOwnerClosed(req.UserId, false)
+			return nil, err
+		}
+		ids = append(ids, acc.Id)
 	}
 
+	log.Printf("user accounts closed: user_id=%s, accounts=%v", req.UserId, ids)
+
+	return &accountv2.CloseUserAccountsResponse{ClosedAccountIds: ids}, nil
+}
+
+// setOwnerClosed marks or unmarks the user as one whose accounts are closed, see
+// closedOwners. It waits for accounts of the user that are being opened.
+func (s *Service) setOwnerClosed(userID string, closed bool) {
+	unlock := s.ownerLocks.lock(userID)
+	defer unlock()
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	if closed {
+		s.closedOwners[userID] = true
+	} else {
+		delete(s.closedOwners, userID)
+	}
+}
+
+// ownerClosed reports whether the user's accounts are closed or being closed.
+// Callers must hold the owner lock of the user.
+func (s *Service) ownerClosed(userID string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.closedOwners[userID]
+}
 
-	if resp, ok := s.withdraws[req.RequestId]; ok {
-		return resp, nil
+// SyncOwner is the realization of the rpc method. The user service calls it after
+// a user changes, so the owner copies of the user's accounts do not go stale.
+func (s *Service) SyncOwner(ctx context.Context, req *accountv2.SyncOwnerRequest) (*accountv2.SyncOwnerResponse, error) {
+	select {
+	case <-ctx.Done():
+		return nil, status.Error(codes.Canceled, "request canceled by client")
+	default:
 	}
-	acc, ok := s.accounts[req.AccountId]
-	if !ok {
-		return nil, status.Error(codes.NotFound, "account not found")
+
+	if req.GetOwner().GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "owner id is required")
 	}
-	balance, err := substractMoney(acc.Balance, req.Amount)
-	if err != nil {
-		return nil, err
+	s.forgetUser(req.Owner.Id)
+
+	owned := s.accountIDs(req.Owner.Id)
+	unlock := s.locks.lock(owned...)
+	defer unlock()
+
+	var ids []string
+	for _, id := range owned {
+		acc, _ := s.account(id)
+		// changes may be synced out of order; an older copy never replaces a newer one
+		if proto.Equal(acc.Owner, req.Owner) || req.Owner.Version < acc.Owner.GetVersion() {
+			continue
+		}
+		acc.Owner = proto.Clone(req.Owner).(*userv1.UserInfo)
+		ids = append(ids, id)
 	}
 
-	s.accounts[req.AccountId].Balance = balance
+	if len(ids) > 0 {
+		log.Printf("account owners synced: user_id=%s, accounts=%v", req.Owner.Id, ids)
+	}
+	return &accountv2.SyncOwnerResponse{UpdatedAccountIds: ids}, nil
+}
 
-	log.Printf("withdraw: account_id=%s, request_id=%s, new_balance=%v", req.AccountId, req.RequestId, acc.Balance)
-	resp := &accountv2.WithdrawResponse{Account: acc}
-	s.withdraws[req.RequestId] = resp
-	return resp, nil
+func isClosed(acc *accountv2.AccountInfo) bool {
+	return acc.Status == statusClosed
+}
 
+func isZeroBalance(acc *accountv2.AccountInfo) bool {
+	return acc.Balance.GetUnits() == 0 && acc.Balance.GetNanos() == 0
 }
 
 func addMoney(a, b *commonv1.Money) (*commonv1.Money, error) {
@@ -270,19 +607,37 @@ func addMoney(a, b *commonv1.Money) (*commonv1.Money, error) {
 
 }
 
-func substractMoney(a, b *commonv1.Money) (*commonv1.Money, error) {
+// insufficientFunds returns a FailedPrecondition error carrying an ErrorInfo
+// with ReasonInsufficientFunds.
+func insufficientFunds(msg string) error {
+	st, err := status.New(codes.FailedPrecondition, msg).WithDetails(&errdetails.ErrorInfo{
+		Reason: ReasonInsufficientFunds,
+		Domain: "account.bank-sim",
+	})
+	if err != nil {
+		return status.Error(codes.FailedPrecondition, msg)
+	}
+	return st.Err()
+}
+
+// substractMoney returns a-b. It fails if the result would fall below -limit;
+// a nil limit means the balance may not go negative.
+func substractMoney(a, b, limit *commonv1.Money) (*commonv1.Money, error) {
 	if a.Currency != b.Currency {
 		return nil, status.Error(codes.FailedPrecondition, "currency mismatch")
 	}
-	if a.Units < b.Units || (a.Units == b.Units && a.Nanos < b.Nanos) {
-		return nil, status.Error(codes.FailedPrecondition, "insufficient balance")
-	}
-	balance := &commonv1.Money{
+	balance := normalizeMoney(&commonv1.Money{
 		Currency: a.Currency,
 		Units:    a.Units - b.Units,
 		Nanos:    a.Nanos - b.Nanos,
+	})
+	floor := &commonv1.Money{Currency: a.Currency, Units: -limit.GetUnits(), Nanos: -limit.GetNanos()}
+	if compareMoney(balance, floor) < 0 {
+		if limit.GetUnits() != 0 || limit.GetNanos() != 0 {
+			return nil, insufficientFunds("overdraft limit exceeded")
+		}
+		return nil, insufficientFunds("insufficient balance")
 	}
-	balance = normalizeMoney(balance)
 	return balance, nil
 }
 
@@ -298,18 +653,44 @@ func isZero(a, b *commonv1.Money) *commonv1.Money {
 	return nil
 }
 
+// normalizeMoney carries whole units out of nanos and makes units and nanos share
+// the same sign.
 func normalizeMoney(m *commonv1.Money) *commonv1.Money {
 	if m.Nanos >= 1_000_000_000 || m.Nanos <= -1_000_000_000 {
 		m.Units += int64(m.Nanos / 1_000_000_000)
 		m.Nanos = m.Nanos % 1_000_000_000
 	}
-	if m.Nanos < 0 {
+	if m.Units > 0 && m.Nanos < 0 {
 		m.Units--
 		m.Nanos += 1_000_000_000
 	}
+	if m.Units < 0 && m.Nanos > 0 {
+		m.Units++
+		m.Nanos -= 1_000_000_000
+	}
 	return &commonv1.Money{
 		Currency: m.Currency,
 		Nanos:    m.Nanos,
 		Units:    m.Units,
 	}
 }
+
+// compareMoney returns -1, 0 or 1 as a is less than, equal to or greater than b.
+// Both must be normalized; currencies are not compared.
+func compareMoney(a, b *commonv1.Money) int {
+	switch {
+	case a.GetUnits() < b.GetUnits():
+		return -1
+	case a.GetUnits() > b.GetUnits():
+		return 1
+	case a.GetNanos() < b.GetNanos():
+		return -1
+	case a.GetNanos() > b.GetNanos():
+		return 1
+	}
+	return 0
+}
+
+func isNegative(m *commonv1.Money) bool {
+	return m.GetUnits() < 0 || m.GetNanos() < 0
+}
@@ -3,15 +3,19 @@ package account
 import (
 	"context"
 	"testing"
+	"time"
 
 	accountv2 "github.com/galadeat/bank-sim/api/proto/account/v2"
 	commonv1 "github.com/galadeat/bank-sim/api/proto/common/v1"
 	userv1 "github.com/galadeat/bank-sim/api/proto/user/v1"
+	"github.com/galadeat/bank-sim/internal/resilience"
 	"github.com/galadeat/bank-sim/mocks"
+	"github.com/gofrs/uuid"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 )
 
 func TestCreateAccount(t *testing.T) {
@@ -224,7 +228,7 @@ func TestGetAccount(t *testing.T) {
 				t.Errorf("expected %v, got %v", tt.wantErrCode, err)
 			}
 			if accGot != nil {
-				assert.Equal(t, accGot.Account, accCreated.Account)
+				assert.True(t, proto.Equal(accCreated.Account, accGot.Account), "expected %v, got %v", accCreated.Account, accGot.Account)
 			}
 
 		})
@@ -239,6 +243,262 @@ func TestGetAccount(t *testing.T) {
 	})
 }
 
+func TestDeleteAccount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	user := mocks.NewMockUserClient(ctrl)
+	user.EXPECT().
+		GetUser(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, req *userv1.GetUserRequest, _ ...interface{}) (*userv1.GetUserResponse, error) {
+			assert.Equal(t, "user-123", req.Id)
+			return &userv1.GetUserResponse{User: &userv1.UserInfo{Id: "user-123"}}, nil
+		}).
+		AnyTimes()
+
+	svc := New(user)
+
+	funded, err := svc.CreateAccount(context.Background(), &accountv2.CreateAccountRequest{
+		UserId:         "user-123",
+		InitialBalance: &commonv1.Money{Currency: "USD", Units: 10},
+		RequestId:      "1",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	empty, err := svc.CreateAccount(context.Background(), &accountv2.CreateAccountRequest{
+		UserId:         "user-123",
+		InitialBalance: &commonv1.Money{Currency: "USD"},
+		RequestId:      "2",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	t.Run("non-zero balance", func(t *testing.T) {
+		_, err := svc.DeleteAccount(context.Background(), &accountv2.DeleteAccountRequest{AccountId: funded.Account.Id})
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+
+	t.Run("account is closed, not removed", func(t *testing.T) {
+		_, err := svc.DeleteAccount(context.Background(), &accountv2.DeleteAccountRequest{AccountId: empty.Account.Id})
+		assert.NoError(t, err)
+
+		got, err := svc.GetAccount(context.Background(), &accountv2.GetAccountRequest{Id: empty.Account.Id})
+		assert.NoError(t, err)
+		assert.Equal(t, accountv2.AccountStatus_ACCOUNT_STATUS_CLOSED, got.Account.Status)
+
+		list, err := svc.ListAccounts(context.Background(), &accountv2.ListAccountsRequest{UserId: "user-123"})
+		assert.NoError(t, err)
+		assert.Len(t, list.Accounts, 1)
+
+		list, err = svc.ListAccounts(context.Background(), &accountv2.ListAccountsRequest{UserId: "user-123", IncludeClosed: true})
+		assert.NoError(t, err)
+		assert.Len(t, list.Accounts, 2)
+	})
+
+	t.Run("closed account rejects deposits", func(t *testing.T) {
+		_, err := svc.Deposit(context.Background(), &accountv2.DepositRequest{
+			AccountId: empty.Account.Id,
+			Amount:    &commonv1.Money{Currency: "USD", Units: 1},
+			RequestId: "3",
+		})
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+
+	t.Run("already closed", func(t *testing.T) {
+		_, err := svc.DeleteAccount(context.Background(), &accountv2.DeleteAccountRequest{AccountId: empty.Account.Id})
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+}
+
+func TestCloseUserAccounts(t *testing.T) {
+	t.Run("closes zero-balance accounts", func(t *testing.T) {
+		svc := newTestService(t)
+		acc, _ := svc.CreateAccount(context.Background(), &accountv2.CreateAccountRequest{
+			UserId: "user-123", InitialBalance: &commonv1.Money{Currency: "USD"}, RequestId: "1",
+		})
+		other, _ := svc.CreateAccount(context.Background(), &accountv2.CreateAccountRequest{
+			UserId: "user-456", InitialBalance: &commonv1.Money{Currency: "USD"}, RequestId: "2",
+		})
+
+		resp, err := svc.CloseUserAccounts(context.Background(), &accountv2.CloseUserAccountsRequest{UserId: "user-123"})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{acc.Account.Id}, resp.ClosedAccountIds)
+		got, _ := svc.account(other.Account.Id)
+		assert.Equal(t, accountv2.AccountStatus_ACCOUNT_STATUS_PENDING, got.Status)
+	})
+
+	t.Run("refuses when any account is funded", func(t *testing.T) {
+		svc := newTestService(t)
+		empty, _ := svc.CreateAccount(context.Background(), &accountv2.CreateAccountRequest{
+			UserId: "user-123", InitialBalance: &commonv1.Money{Currency: "USD"}, RequestId: "1",
+		})
+		svc.CreateAccount(context.Background(), &accountv2.CreateAccountRequest{
+			UserId: "user-123", InitialBalance: &commonv1.Money{Currency: "USD", Units: 5}, RequestId: "2",
+		})
+
+		_, err := svc.CloseUserAccounts(context.Background(), &accountv2.CloseUserAccountsRequest{UserId: "user-123"})
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+		got, _ := svc.account(empty.Account.Id)
+		assert.Equal(t, accountv2.AccountStatus_ACCOUNT_STATUS_PENDING, got.Status)
+	})
+
+	t.Run("refuses accounts of a user looked up before", func(t *testing.T) {
+		svc := newTestService(t)
+		users := &blockingUsers{release: make(chan struct{})}
+		svc.userClient = users
+
+		created := make(chan error, 1)
+		go func() {
+			_, err := svc.CreateAccount(context.Background(), &accountv2.CreateAccountRequest{UserId: "user-123", RequestId: "late"})
+			created <- err
+		}()
+		resp, err := svc.CloseUserAccounts(context.Background(), &accountv2.CloseUserAccountsRequest{UserId: "user-123"})
+		assert.NoError(t, err)
+		assert.Empty(t, resp.ClosedAccountIds)
+
+		close(users.release)
+		assert.Equal(t, codes.FailedPrecondition, status.Code(<-created))
+		assert.Empty(t, svc.accountIDs("user-123"))
+	})
+
+	t.Run("opens accounts again after a refused close", func(t *testing.T) {
+		svc := newTestService(t)
+		createTestAccount(t, svc, 5)
+
+		_, err := svc.CloseUserAccounts(context.Background(), &accountv2.CloseUserAccountsRequest{UserId: "user-123"})
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+		createTestAccount(t, svc, 0)
+	})
+
+	t.Run("user id is empty", func(t *testing.T) {
+		svc := newTestService(t)
+		_, err := svc.CloseUserAccounts(context.Background(), &accountv2.CloseUserAccountsRequest{})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestSyncOwner(t *testing.T) {
+	t.Run("replaces stale owner copies", func(t *testing.T) {
+		svc := newTestService(t)
+		acc, _ := svc.CreateAccount(context.Background(), &accountv2.CreateAccountRequest{
+			UserId: "user-123", InitialBalance: &commonv1.Money{Currency: "USD"}, RequestId: "1",
+		})
+		other, _ := svc.CreateAccount(context.Background(), &accountv2.CreateAccountRequest{
+			UserId: "user-456", InitialBalance: &commonv1.Money{Currency: "USD"}, RequestId: "2",
+		})
+		assert.Equal(t, "user-123", acc.Account.OwnerId)
+
+		owner := &userv1.UserInfo{Id: "user-123", Login: "renamed", Email: "renamed@test.com"}
+		resp, err := svc.SyncOwner(context.Background(), &accountv2.SyncOwnerRequest{Owner: owner})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{acc.Account.Id}, resp.UpdatedAccountIds)
+
+		got, _ := svc.GetAccount(context.Background(), &accountv2.GetAccountRequest{Id: acc.Account.Id})
+		assert.True(t, proto.Equal(owner, got.Account.Owner))
+		got, _ = svc.GetAccount(context.Background(), &accountv2.GetAccountRequest{Id: other.Account.Id})
+		assert.Equal(t, "user-456", got.Account.Owner.Id)
+
+		resp, err = svc.SyncOwner(context.Background(), &accountv2.SyncOwnerRequest{Owner: owner})
+		assert.NoError(t, err)
+		assert.Empty(t, resp.UpdatedAccountIds, "copies already match")
+	})
+
+	t.Run("older versions are ignored", func(t *testing.T) {
+		svc := newTestService(t)
+		acc, _ := svc.CreateAccount(context.Background(), &accountv2.CreateAccountRequest{
+			UserId: "user-123", InitialBalance: &commonv1.Money{Currency: "USD"}, RequestId: "1",
+		})
+
+		newer := &userv1.UserInfo{Id: "user-123", Login: "second", Version: 3}
+		older := &userv1.UserInfo{Id: "user-123", Login: "first", Version: 2}
+		_, err := svc.SyncOwner(context.Background(), &accountv2.SyncOwnerRequest{Owner: newer})
+		assert.NoError(t, err)
+		resp, err := svc.SyncOwner(context.Background(), &accountv2.SyncOwnerRequest{Owner: older})
+		assert.NoError(t, err)
+		assert.Empty(t, resp.UpdatedAccountIds)
+
+		got, _ := svc.GetAccount(context.Background(), &accountv2.GetAccountRequest{Id: acc.Account.Id})
+		assert.True(t, proto.Equal(newer, got.Account.Owner))
+	})
+
+	t.Run("owner id is empty", func(t *testing.T) {
+		svc := newTestService(t)
+		_, err := svc.SyncOwner(context.Background(), &accountv2.SyncOwnerRequest{Owner: &userv1.UserInfo{Login: "alice"}})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestUserCacheIsInvalidated(t *testing.T) {
+	ctx := context.Background()
+	for _, tt := range []struct {
+		name   string
+		change func(svc *Service) error
+		// wantCode is the code of the next account, which a closed owner may not open
+		wantCode codes.Code
+	}{
+		{name: "close user accounts", change: func(svc *Service) error {
+			_, err := svc.CloseUserAccounts(ctx, &accountv2.CloseUserAccountsRequest{UserId: "user-123"})
+			return err
+		}, wantCode: codes.FailedPrecondition},
+		{name: "sync owner", change: func(svc *Service) error {
+			_, err := svc.SyncOwner(ctx, &accountv2.SyncOwnerRequest{Owner: &userv1.UserInfo{Id: "user-123", Login: "renamed"}})
+			return err
+		}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			user := mocks.NewMockUserClient(ctrl)
+			gomock.InOrder(
+				user.EXPECT().GetUser(gomock.Any(), gomock.Any()).Return(&userv1.GetUserResponse{User: &userv1.UserInfo{Id: "user-123", Login: "alice"}}, nil),
+				user.EXPECT().GetUser(gomock.Any(), gomock.Any()).Return(&userv1.GetUserResponse{User: &userv1.UserInfo{Id: "user-123", Login: "renamed"}}, nil),
+			)
+			svc := New(resilience.NewUserClient(user, resilience.WithCache(time.Hour)))
+
+			_, err := svc.CreateAccount(ctx, &accountv2.CreateAccountRequest{UserId: "user-123", InitialBalance: &commonv1.Money{Currency: "USD"}, RequestId: "1"})
+			assert.NoError(t, err)
+			assert.NoError(t, tt.change(svc))
+
+			// the next account asks the user service again
+			resp, err := svc.CreateAccount(ctx, &accountv2.CreateAccountRequest{UserId: "user-123", InitialBalance: &commonv1.Money{Currency: "USD"}, RequestId: "2"})
+			assert.Equal(t, tt.wantCode, status.Code(err))
+			if err == nil {
+				assert.Equal(t, "renamed", resp.Account.Owner.Login)
+			}
+		})
+	}
+}
+
+// newTestService returns a service whose user client knows every user id.
+func newTestService(t testing.TB) *Service {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	user := mocks.NewMockUserClient(ctrl)
+	user.EXPECT().
+		GetUser(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, req *userv1.GetUserRequest, _ ...interface{}) (*userv1.GetUserResponse, error) {
+			return &userv1.GetUserResponse{User: &userv1.UserInfo{Id: req.Id}}, nil
+		}).
+		AnyTimes()
+	return New(user)
+}
+
+// createTestAccount opens a USD account for user-123 with the given balance.
+func createTestAccount(t testing.TB, svc *Service, units int64) *accountv2.AccountInfo {
+	t.Helper()
+	reqID, _ := uuid.NewV4()
+	resp, err := svc.CreateAccount(context.Background(), &accountv2.CreateAccountRequest{
+		UserId:         "user-123",
+		InitialBalance: &commonv1.Money{Currency: "USD", Units: units},
+		RequestId:      reqID.String(),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return resp.Account
+}
+
 //func TestListAccounts(t *testing.T) {
 //	ctrl := gomock.NewController(t)
 //	defer ctrl.Finish()
@@ -0,0 +1,145 @@
+package account
+
+import (
+	"context"
+	"log"
+
+	accountv2 "github.com/galadeat/bank-sim/api/proto/account/v2"
+	commonv1 "github.com/galadeat/bank-sim/api/proto/common/v1"
+	"github.com/galadeat/bank-sim/internal/rules"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Transfer is the realization of the rpc method. Both accounts are locked for
+// the whole transfer, so the money is never debited without being credited.
+func (s *Service) Transfer(ctx context.Context, req *accountv2.TransferRequest) (*accountv2.TransferResponse, error) {
+	select {
+	case <-ctx.Done():
+		return nil, status.Error(codes.Canceled, "request canceled by client")
+	default:
+	}
+	if req.FromAccountId == "" || req.ToAccountId == "" {
+		return nil, status.Error(codes.InvalidArgument, "source and destination account ids are required")
+	}
+	if req.FromAccountId == req.ToAccountId {
+		return nil, status.Error(codes.InvalidArgument, "cannot transfer to the same account")
+	}
+	if req.Amount == nil || (req.Amount.Units == 0 && req.Amount.Nanos == 0) || isNegative(req.Amount) {
+		return nil, status.Error(codes.InvalidArgument, "transfer must be greater than zero")
+	}
+	if req.RequestId == "" {
+		return nil, status.Error(codes.InvalidArgument, "request id is required")
+	}
+
+	return once(s, accountv2.Account_Transfer_FullMethodName, req.RequestId, req, func() (*accountv2.TransferResponse, error) {
+		unlock := s.locks.lock(req.FromAccountId, req.ToAccountId)
+		defer unlock()
+		unlockOwners := s.lockOwners(req.FromAccountId, req.ToAccountId)
+		defer unlockOwners()
+
+		from, ok := s.account(req.FromAccountId)
+		if !ok {
+			return nil, status.Error(codes.NotFound, "source account not found")
+		}
+		to, ok := s.account(req.ToAccountId)
+		if !ok {
+			return nil, status.Error(codes.NotFound, "destination account not found")
+		}
+		resp, err := s.transfer(from, to, req.Amount, req.RequestId, true)
+		if err != nil {
+			return nil, err
+		}
+		s.mu.Lock()
+		s.transfers[req.RequestId] = resp
+		s.mu.Unlock()
+		return resp, nil
+	})
+}
+
+// transfer moves amount from one account to the other. With screen set the
+// payment is first assessed by the risk scorer and may be held for review
+// instead of applied. Callers must hold the locks of both accounts.
+func (s *Service) transfer(from, to *accountv2.AccountInfo, amount *commonv1.Money, requestID string, screen bool) (*accountv2.TransferResponse, error) {
+	if err := canReceive(to); err != nil {
+		return nil, err
+	}
+
+	fromBalance, err := s.debitBalance(from, amount)
+	if err != nil {
+		return nil, err
+	}
+	toBalance, err := addMoney(to.Balance, amount)
+	if err != nil {
+		return nil, err
+	}
+	ops := []rules.Operation{
+		s.operation(from, rules.KindWithdrawal, amount),
+		s.operation(to, rules.KindDeposit, amount),
+	}
+	if err := s.checkRules(ops...); err != nil {
+		return nil, err
+	}
+	if screen {
+		review, err := s.screen(from, to.Id, accountv2.TransactionType_TRANSACTION_TYPE_TRANSFER_OUT, amount, requestID)
+		if err != nil {
+			return nil, err
+		}
+		if review != nil {
+			return &accountv2.TransferResponse{Review: snapshot(review)}, nil
+		}
+	}
+
+	wasOverdrawn := isNegative(from.Balance)
+	s.setBalance(from, fromBalance)
+	debit := s.record(from, accountv2.TransactionType_TRANSACTION_TYPE_TRANSFER_OUT, amount, requestID)
+	if !wasOverdrawn && isNegative(fromBalance) {
+		s.chargeOverdraftFee(from, requestID)
+	}
+
+	s.setBalance(to, toBalance)
+	credit := s.record(to, accountv2.TransactionType_TRANSACTION_TYPE_TRANSFER_IN, amount, requestID)
+	if to.Status == statusPending {
+		if err := s.transition(to, statusActive, "first deposit"); err != nil {
+			return nil, err
+		}
+	}
+	s.recordRules(ops...)
+
+	log.Printf("transfer: from_account_id=%s, to_account_id=%s, request_id=%s, amount=%v", from.Id, to.Id, requestID, amount)
+	return &accountv2.TransferResponse{Debit: debit, Credit: credit}, nil
+}
+
+// debitBalance checks that amount may be taken from acc, counting funds on hold,
+// and returns the balance it would leave. Callers must hold the lock of acc.
+func (s *Service) debitBalance(acc *accountv2.AccountInfo, amount *commonv1.Money) (*commonv1.Money, error) {
+	if err := s.canDebit(acc); err != nil {
+		return nil, err
+	}
+	s.expireAccountHolds(acc, s.clock.Now())
+	return balanceAfterDebit(acc, amount)
+}
+
+// canDebit reports whether money may be taken from acc at all.
+func (s *Service) canDebit(acc *accountv2.AccountInfo) error {
+	if err := canSend(acc); err != nil {
+		return err
+	}
+	if acc.MaturesAt != nil && s.clock.Now().Before(acc.MaturesAt.AsTime()) {
+		return status.Error(codes.FailedPrecondition, "term deposit has not matured")
+	}
+	return nil
+}
+
+// balanceAfterDebit returns the balance taking amount from acc would leave, if
+// the overdraft limit and the funds on hold allow it.
+func balanceAfterDebit(acc *accountv2.AccountInfo, amount *commonv1.Money) (*commonv1.Money, error) {
+	balance, err := substractMoney(acc.Balance, amount, acc.OverdraftLimit)
+	if err != nil {
+		return nil, err
+	}
+	if compareMoney(amount, acc.AvailableBalance) > 0 {
+		return nil, insufficientFunds("insufficient available balance, funds are on hold")
+	}
+	return balance, nil
+}
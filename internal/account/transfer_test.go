@@ -0,0 +1,103 @@
+package account
+
+import (
+	"context"
+	"testing"
+
+	accountv2 "github.com/galadeat/bank-sim/api/proto/account/v2"
+	commonv1 "github.com/galadeat/bank-sim/api/proto/common/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestTransfer(t *testing.T) {
+	usd := func(units int64) *commonv1.Money {
+		return &commonv1.Money{Currency: "USD", Units: units}
+	}
+
+	t.Run("moves money and is idempotent", func(t *testing.T) {
+		ctx := context.Background()
+		svc := newTestService(t)
+		from := createTestAccount(t, svc, 100)
+		to := createTestAccount(t, svc, 0)
+
+		req := &accountv2.TransferRequest{FromAccountId: from.Id, ToAccountId: to.Id, Amount: usd(30), RequestId: "tr-1"}
+		first, err := svc.Transfer(ctx, req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		second, err := svc.Transfer(ctx, req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if first.Debit.Id != second.Debit.Id {
+			t.Errorf("expected the retried transfer to return the first result")
+		}
+
+		assertMoney(t, usd(70), first.Debit.BalanceAfter)
+		assertMoney(t, usd(30), first.Credit.BalanceAfter)
+		if first.Credit.Type != accountv2.TransactionType_TRANSACTION_TYPE_TRANSFER_IN {
+			t.Errorf("expected transfer in, got %v", first.Credit.Type)
+		}
+
+		got, _ := svc.GetAccount(ctx, &accountv2.GetAccountRequest{Id: to.Id})
+		assertMoney(t, usd(30), got.Account.Balance)
+		if got.Account.Status != statusActive {
+			t.Errorf("expected the pending destination to become active, got %v", got.Account.Status)
+		}
+		got, _ = svc.GetAccount(ctx, &accountv2.GetAccountRequest{Id: from.Id})
+		assertMoney(t, usd(70), got.Account.Balance)
+	})
+
+	tests := []struct {
+		name        string
+		prepare     func(svc *Service, from, to *accountv2.AccountInfo)
+		amount      int64
+		sameAccount bool
+		wantErrCode codes.Code
+	}{
+		{name: "insufficient funds", amount: 101, wantErrCode: codes.FailedPrecondition},
+		{name: "same account", amount: 1, sameAccount: true, wantErrCode: codes.InvalidArgument},
+		{name: "zero amount", amount: 0, wantErrCode: codes.InvalidArgument},
+		{
+			name:   "destination closed",
+			amount: 1,
+			prepare: func(svc *Service, _, to *accountv2.AccountInfo) {
+				svc.CloseAccount(context.Background(), &accountv2.CloseAccountRequest{AccountId: to.Id, Reason: "test"})
+			},
+			wantErrCode: codes.FailedPrecondition,
+		},
+		{
+			name:   "source frozen",
+			amount: 1,
+			prepare: func(svc *Service, from, _ *accountv2.AccountInfo) {
+				svc.FreezeAccount(context.Background(), &accountv2.FreezeAccountRequest{AccountId: from.Id, Reason: "test"})
+			},
+			wantErrCode: codes.FailedPrecondition,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			svc := newTestService(t)
+			from := createTestAccount(t, svc, 100)
+			to := createTestAccount(t, svc, 0)
+			if tt.prepare != nil {
+				tt.prepare(svc, from, to)
+			}
+			toID := to.Id
+			if tt.sameAccount {
+				toID = from.Id
+			}
+
+			_, err := svc.Transfer(ctx, &accountv2.TransferRequest{FromAccountId: from.Id, ToAccountId: toID, Amount: usd(tt.amount), RequestId: "tr-1"})
+			if status.Code(err) != tt.wantErrCode {
+				t.Fatalf("expected %v, got %v", tt.wantErrCode, err)
+			}
+
+			got, _ := svc.GetAccount(ctx, &accountv2.GetAccountRequest{Id: from.Id})
+			assertMoney(t, usd(100), got.Account.Balance)
+		})
+	}
+}
@@ -0,0 +1,238 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	accountv2 "github.com/galadeat/bank-sim/api/proto/account/v2"
+	commonv1 "github.com/galadeat/bank-sim/api/proto/common/v1"
+	"github.com/galadeat/bank-sim/pkg/money"
+	"github.com/gofrs/uuid"
+)
+
+func (a *App) accountCommands() map[string]func(context.Context, []string) error {
+	return map[string]func(context.Context, []string) error{
+		"create":   a.createAccount,
+		"get":      a.getAccount,
+		"list":     a.listAccounts,
+		"deposit":  a.deposit,
+		"withdraw": a.withdraw,
+		"transfer": a.transfer,
+		"history":  a.history,
+		"close":    a.closeAccount,
+	}
+}
+
+var accountTypes = map[string]accountv2.AccountType{
+	"checking":     accountv2.AccountType_ACCOUNT_TYPE_CHECKING,
+	"savings":      accountv2.AccountType_ACCOUNT_TYPE_SAVINGS,
+	"term_deposit": accountv2.AccountType_ACCOUNT_TYPE_TERM_DEPOSIT,
+}
+
+func (a *App) createAccount(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("account create", flag.ContinueOnError)
+	typ := fs.String("type", "checking", "checking, savings or term_deposit")
+	term := fs.Int("term", 0, "term in months, term deposits only")
+	requestID := requestIDFlag(fs)
+	pos, err := parseArgs(fs, args, 1, 3)
+	if err != nil {
+		return err
+	}
+	accType, ok := accountTypes[*typ]
+	if !ok {
+		return usageError(fmt.Sprintf("account create: unknown type %q", *typ))
+	}
+
+	req := &accountv2.CreateAccountRequest{UserId: pos[0], Type: accType, TermMonths: int32(*term), RequestId: *requestID}
+	switch len(pos) {
+	case 2:
+		return usageError("account create: the initial balance needs an amount and a currency")
+	case 3:
+		if req.InitialBalance, err = parseAmount(pos[1], pos[2]); err != nil {
+			return err
+		}
+	}
+
+	resp, err := a.Account.CreateAccount(ctx, req)
+	if err != nil {
+		return err
+	}
+	a.LastID = resp.Account.Id
+	return a.print(resp, func(tw *tabwriter.Writer) {
+		accountTable(tw, resp.Account)
+	})
+}
+
+func (a *App) getAccount(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("account get", flag.ContinueOnError)
+	pos, err := parseArgs(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	resp, err := a.Account.GetAccount(ctx, &accountv2.GetAccountRequest{Id: pos[0]})
+	if err != nil {
+		return err
+	}
+	return a.print(resp, func(tw *tabwriter.Writer) {
+		accountTable(tw, resp.Account)
+	})
+}
+
+func (a *App) listAccounts(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("account list", flag.ContinueOnError)
+	all := fs.Bool("all", false, "include closed accounts")
+	pos, err := parseArgs(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	resp, err := a.Account.ListAccounts(ctx, &accountv2.ListAccountsRequest{UserId: pos[0], IncludeClosed: *all})
+	if err != nil {
+		return err
+	}
+	return a.print(resp, func(tw *tabwriter.Writer) {
+		accountTable(tw, resp.Accounts...)
+	})
+}
+
+func (a *App) deposit(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("account deposit", flag.ContinueOnError)
+	requestID := requestIDFlag(fs)
+	pos, err := parseArgs(fs, args, 3, 3)
+	if err != nil {
+		return err
+	}
+	amount, err := parseAmount(pos[1], pos[2])
+	if err != nil {
+		return err
+	}
+
+	resp, err := a.Account.Deposit(ctx, &accountv2.DepositRequest{AccountId: pos[0], Amount: amount, RequestId: *requestID})
+	if err != nil {
+		return err
+	}
+	return a.print(resp, func(tw *tabwriter.Writer) {
+		accountTable(tw, resp.Account)
+	})
+}
+
+func (a *App) withdraw(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("account withdraw", flag.ContinueOnError)
+	requestID := requestIDFlag(fs)
+	pos, err := parseArgs(fs, args, 3, 3)
+	if err != nil {
+		return err
+	}
+	amount, err := parseAmount(pos[1], pos[2])
+	if err != nil {
+		return err
+	}
+
+	resp, err := a.Account.Withdraw(ctx, &accountv2.WithdrawRequest{AccountId: pos[0], Amount: amount, RequestId: *requestID})
+	if err != nil {
+		return err
+	}
+	return a.print(resp, func(tw *tabwriter.Writer) {
+		if resp.Review != nil {
+			reviewLine(tw, resp.Review)
+			return
+		}
+		accountTable(tw, resp.Account)
+	})
+}
+
+func (a *App) transfer(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("account transfer", flag.ContinueOnError)
+	requestID := requestIDFlag(fs)
+	pos, err := parseArgs(fs, args, 4, 4)
+	if err != nil {
+		return err
+	}
+	amount, err := parseAmount(pos[2], pos[3])
+	if err != nil {
+		return err
+	}
+
+	resp, err := a.Account.Transfer(ctx, &accountv2.TransferRequest{FromAccountId: pos[0], ToAccountId: pos[1], Amount: amount, RequestId: *requestID})
+	if err != nil {
+		return err
+	}
+	return a.print(resp, func(tw *tabwriter.Writer) {
+		if resp.Review != nil {
+			reviewLine(tw, resp.Review)
+			return
+		}
+		transactionTable(tw, resp.Debit, resp.Credit)
+	})
+}
+
+func (a *App) history(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("account history", flag.ContinueOnError)
+	pos, err := parseArgs(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	resp, err := a.Account.ListTransactions(ctx, &accountv2.ListTransactionsRequest{AccountId: pos[0]})
+	if err != nil {
+		return err
+	}
+	return a.print(resp, func(tw *tabwriter.Writer) {
+		transactionTable(tw, resp.Transactions...)
+	})
+}
+
+func (a *App) closeAccount(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("account close", flag.ContinueOnError)
+	reason := fs.String("reason", "", "reason recorded in the account history")
+	pos, err := parseArgs(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	resp, err := a.Account.CloseAccount(ctx, &accountv2.CloseAccountRequest{AccountId: pos[0], Reason: *reason})
+	if err != nil {
+		return err
+	}
+	return a.print(resp, func(tw *tabwriter.Writer) {
+		accountTable(tw, resp.Account)
+	})
+}
+
+// requestIDFlag adds the --request-id flag, which defaults to a new id.
+func requestIDFlag(fs *flag.FlagSet) *string {
+	return fs.String("request-id", uuid.Must(uuid.NewV4()).String(), "request id; repeating it applies the command once")
+}
+
+func parseAmount(amount, currency string) (*commonv1.Money, error) {
+	m, err := money.Parse(amount, currency)
+	if err != nil {
+		return nil, usageError(err.Error())
+	}
+	return m, nil
+}
+
+func accountTable(tw *tabwriter.Writer, accounts ...*accountv2.AccountInfo) {
+	fmt.Fprintln(tw, "ID\tOWNER\tTYPE\tSTATUS\tBALANCE\tAVAILABLE")
+	for _, acc := range accounts {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", acc.Id, acc.Owner.GetLogin(),
+			enumName(acc.Type.String(), "ACCOUNT_TYPE_"), enumName(acc.Status.String(), "ACCOUNT_STATUS_"),
+			money.Format(acc.Balance), money.Format(acc.AvailableBalance))
+	}
+}
+
+func transactionTable(tw *tabwriter.Writer, txs ...*accountv2.Transaction) {
+	fmt.Fprintln(tw, "TIME\tID\tACCOUNT\tTYPE\tAMOUNT\tBALANCE")
+	for _, tx := range txs {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", tx.CreatedAt.AsTime().Format(time.RFC3339), tx.Id, tx.AccountId,
+			enumName(tx.Type.String(), "TRANSACTION_TYPE_"), money.Format(tx.Amount), money.Format(tx.BalanceAfter))
+	}
+}
+
+func reviewLine(tw *tabwriter.Writer, r *accountv2.Review) {
+	fmt.Fprintf(tw, "held for review %s (score %d)\n", r.Id, r.Score)
+}
@@ -0,0 +1,138 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"slices"
+	"text/tabwriter"
+
+	accountv2 "github.com/galadeat/bank-sim/api/proto/account/v2"
+	userv1 "github.com/galadeat/bank-sim/api/proto/user/v1"
+)
+
+func (a *App) checkCommands() map[string]func(context.Context, []string) error {
+	return map[string]func(context.Context, []string) error{
+		"owners": a.checkOwners,
+	}
+}
+
+// Mismatch is a field of an account's owner copy that differs from the user.
+type Mismatch struct {
+	AccountID    string `json:"account_id"`
+	UserID       string `json:"user_id"`
+	Field        string `json:"field"`
+	AccountValue string `json:"account_value"`
+	UserValue    string `json:"user_value"`
+	Fixed        bool   `json:"fixed"`
+}
+
+// ownerReport is the result of "check owners".
+type ownerReport struct {
+	Users      int        `json:"users"`
+	Accounts   int        `json:"accounts"`
+	Mismatches []Mismatch `json:"mismatches"`
+}
+
+// checkOwners compares the owner copy of every account of every user, closed
+// ones included, with the user, and with --fix has the account service replace stale copies.
+// It fails while any mismatch is left.
+func (a *App) checkOwners(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("check owners", flag.ContinueOnError)
+	fix := fs.Bool("fix", false, "replace stale owner copies")
+	if _, err := parseArgs(fs, args, 0, 0); err != nil {
+		return err
+	}
+
+	// closed users keep their closed accounts, whose owner copies must match too
+	users, err := a.User.ListUsers(ctx, &userv1.ListUsersRequest{IncludeClosed: true})
+	if err != nil {
+		return err
+	}
+
+	report := ownerReport{Users: len(users.Users), Mismatches: []Mismatch{}}
+	for _, user := range users.Users {
+		resp, err := a.Account.ListAccounts(ctx, &accountv2.ListAccountsRequest{UserId: user.Id, IncludeClosed: true})
+		if err != nil {
+			return err
+		}
+		report.Accounts += len(resp.Accounts)
+
+		var found []Mismatch
+		for _, acc := range resp.Accounts {
+			found = append(found, ownerMismatches(acc, user)...)
+		}
+		if len(found) > 0 && *fix {
+			synced, err := a.Account.SyncOwner(ctx, &accountv2.SyncOwnerRequest{Owner: user})
+			if err != nil {
+				return err
+			}
+			// the account service skips copies it holds a newer version of, so
+			// only the accounts it reports as updated are fixed
+			for i := range found {
+				found[i].Fixed = slices.Contains(synced.UpdatedAccountIds, found[i].AccountID)
+			}
+		}
+		report.Mismatches = append(report.Mismatches, found...)
+	}
+
+	if err := a.printOwnerReport(report); err != nil {
+		return err
+	}
+	if left := outOfSync(report.Mismatches); left > 0 {
+		return fmt.Errorf("%d owner fields out of sync, run \"check owners --fix\" to repair them", left)
+	}
+	return nil
+}
+
+// ownerMismatches returns the fields of the account's owner copy that differ
+// from user.
+func ownerMismatches(acc *accountv2.AccountInfo, user *userv1.UserInfo) []Mismatch {
+	owner := acc.GetOwner()
+	fields := []struct{ name, account, user string }{
+		{"owner_id", acc.OwnerId, user.Id},
+		{"id", owner.GetId(), user.Id},
+		{"login", owner.GetLogin(), user.Login},
+		{"email", owner.GetEmail(), user.Email},
+		{"status", enumName(owner.GetStatus().String(), "USER_STATUS_"), enumName(user.Status.String(), "USER_STATUS_")},
+	}
+	var out []Mismatch
+	for _, f := range fields {
+		if f.account != f.user {
+			out = append(out, Mismatch{AccountID: acc.Id, UserID: user.Id, Field: f.name, AccountValue: f.account, UserValue: f.user})
+		}
+	}
+	return out
+}
+
+func outOfSync(mismatches []Mismatch) int {
+	n := 0
+	for _, m := range mismatches {
+		if !m.Fixed {
+			n++
+		}
+	}
+	return n
+}
+
+func (a *App) printOwnerReport(report ownerReport) error {
+	if a.JSON {
+		enc := json.NewEncoder(a.Out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+
+	if len(report.Mismatches) > 0 {
+		tw := tabwriter.NewWriter(a.Out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "ACCOUNT\tUSER\tFIELD\tACCOUNT VALUE\tUSER VALUE\tFIXED")
+		for _, m := range report.Mismatches {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%t\n", m.AccountID, m.UserID, m.Field, m.AccountValue, m.UserValue, m.Fixed)
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(a.Out, "checked %d users and %d accounts, %d mismatches\n", report.Users, report.Accounts, len(report.Mismatches))
+	return err
+}
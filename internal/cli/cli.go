@@ -0,0 +1,251 @@
+// Package cli is the scriptable command line client. Every run executes one
+// command, prints the result as a table or as JSON and exits with a code derived
+// from the gRPC status, so scenarios can be written as shell scripts.
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	accountv2 "github.com/galadeat/bank-sim/api/proto/account/v2"
+	userv1 "github.com/galadeat/bank-sim/api/proto/user/v1"
+	"github.com/galadeat/bank-sim/pkg/clients"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Exit codes. Errors returned by the servers map to the codes below; anything
+// else, such as an unreachable server, exits with ExitFailure.
+const (
+	ExitOK                 = 0
+	ExitFailure            = 1
+	ExitUsage              = 2
+	ExitInvalidArgument    = 3
+	ExitNotFound           = 4
+	ExitAlreadyExists      = 5
+	ExitPermissionDenied   = 6
+	ExitFailedPrecondition = 7
+	ExitResourceExhausted  = 8
+	ExitUnavailable        = 9
+)
+
+// CommandHelp lists the commands and their arguments.
+const CommandHelp = `users:
+  user create --login <login> --email <email>
+  user get <user-id>
+  user list [--all]
+  user update <user-id> [--login <login>] [--email <email>]
+  user delete <user-id>
+
+accounts:
+  account create <user-id> [<amount> <currency>] [--type checking|savings|term_deposit] [--term <months>]
+  account get <account-id>
+  account list <user-id> [--all]
+  account deposit <account-id> <amount> <currency>
+  account withdraw <account-id> <amount> <currency>
+  account transfer <from-account-id> <to-account-id> <amount> <currency>
+  account history <account-id>
+  account close <account-id> [--reason <reason>]
+
+checks:
+  check owners [--fix]    accounts whose owner copy differs from the user
+
+Commands that move money take --request-id; repeating a command with the same id
+applies it once.
+`
+
+const usage = `usage: bank [--server host] [--output table|json] [--timeout 10s] <command> [args]
+
+` + CommandHelp + `
+exit codes: 0 ok, 1 failure, 2 usage, 3 invalid argument, 4 not found,
+5 already exists, 6 permission denied, 7 failed precondition,
+8 resource exhausted, 9 unavailable or deadline exceeded
+`
+
+// Main parses the global flags, connects to the servers and runs the command.
+// It returns the exit code.
+func Main(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("bank", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	server := fs.String("server", "localhost", "host the services listen on")
+	output := fs.String("output", "table", "output format, table or json")
+	fs.StringVar(output, "o", "table", "shorthand for --output")
+	timeout := fs.Duration("timeout", 10*time.Second, "deadline of every request")
+	if err := fs.Parse(args); err != nil {
+		return report(stderr, usageError(err.Error()))
+	}
+	if fs.NArg() == 0 || fs.Arg(0) == "help" {
+		fmt.Fprint(stdout, usage)
+		return ExitOK
+	}
+	if *output != "table" && *output != "json" {
+		return report(stderr, usageError("output must be table or json"))
+	}
+
+	c, err := clients.New(clients.WithHost(*server))
+	if err != nil {
+		return report(stderr, err)
+	}
+	defer c.Close()
+
+	app := &App{User: c.User, Account: c.Account, Out: stdout, JSON: *output == "json"}
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	return report(stderr, app.Exec(ctx, fs.Args()))
+}
+
+// App executes commands against the user and account services.
+type App struct {
+	User    userv1.UserClient
+	Account accountv2.AccountClient
+	Out     io.Writer
+	JSON    bool
+
+	// LastID is the id of the user or account the last command created, or
+	// empty when it created nothing.
+	LastID string
+}
+
+// Commands returns the command names of every group, e.g. "user": create, get, ...
+func Commands() map[string][]string {
+	a := &App{}
+	out := make(map[string][]string)
+	for group, commands := range map[string]map[string]func(context.Context, []string) error{
+		"user":    a.userCommands(),
+		"account": a.accountCommands(),
+		"check":   a.checkCommands(),
+	} {
+		for name := range commands {
+			out[group] = append(out[group], name)
+		}
+		sort.Strings(out[group])
+	}
+	return out
+}
+
+// Exec runs one command, such as "user get <id>".
+func (a *App) Exec(ctx context.Context, args []string) error {
+	a.LastID = ""
+	if len(args) < 2 {
+		return usageError("expected a command such as \"user list\"")
+	}
+	group, cmd, rest := args[0], args[1], args[2:]
+
+	var commands map[string]func(context.Context, []string) error
+	switch group {
+	case "user":
+		commands = a.userCommands()
+	case "account":
+		commands = a.accountCommands()
+	case "check":
+		commands = a.checkCommands()
+	default:
+		return usageError(fmt.Sprintf("unknown command %q", group))
+	}
+	run, ok := commands[cmd]
+	if !ok {
+		return usageError(fmt.Sprintf("unknown command %q", group+" "+cmd))
+	}
+	return run(ctx, rest)
+}
+
+// usageError is a command line that could not be understood.
+type usageError string
+
+func (e usageError) Error() string { return string(e) }
+
+// ExitCode returns the exit code for the error of a command.
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+	var ue usageError
+	if errors.As(err, &ue) {
+		return ExitUsage
+	}
+	st, ok := status.FromError(err)
+	if !ok {
+		return ExitFailure
+	}
+	switch st.Code() {
+	case codes.InvalidArgument, codes.OutOfRange:
+		return ExitInvalidArgument
+	case codes.NotFound:
+		return ExitNotFound
+	case codes.AlreadyExists:
+		return ExitAlreadyExists
+	case codes.PermissionDenied, codes.Unauthenticated:
+		return ExitPermissionDenied
+	case codes.FailedPrecondition, codes.Aborted:
+		return ExitFailedPrecondition
+	case codes.ResourceExhausted:
+		return ExitResourceExhausted
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return ExitUnavailable
+	}
+	return ExitFailure
+}
+
+// report prints err, if any, and returns its exit code.
+func report(w io.Writer, err error) int {
+	code := ExitCode(err)
+	switch {
+	case err == nil:
+	case code == ExitUsage:
+		fmt.Fprintf(w, "error: %v\n\n%s", err, usage)
+	default:
+		fmt.Fprintf(w, "error: %s\n", Describe(err))
+	}
+	return code
+}
+
+// Describe returns err for people: the status code in words followed by the
+// message for errors returned by the servers.
+func Describe(err error) string {
+	if st, ok := status.FromError(err); ok && st.Code() != codes.Unknown {
+		return codeName(st.Code()) + ": " + st.Message()
+	}
+	return err.Error()
+}
+
+// codeName spells a status code in words: FailedPrecondition becomes
+// "failed precondition".
+func codeName(c codes.Code) string {
+	var b strings.Builder
+	for i, r := range c.String() {
+		if i > 0 && unicode.IsUpper(r) {
+			b.WriteByte(' ')
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+// parseArgs parses flags placed anywhere between the positional arguments and
+// checks the number of positional arguments is between min and max.
+func parseArgs(fs *flag.FlagSet, args []string, min, max int) ([]string, error) {
+	fs.SetOutput(io.Discard)
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, usageError(fmt.Sprintf("%s: %v", fs.Name(), err))
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			break
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+	if len(positional) < min || len(positional) > max {
+		return nil, usageError(fmt.Sprintf("%s: wrong number of arguments", fs.Name()))
+	}
+	return positional, nil
+}
@@ -0,0 +1,220 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	accountv2 "github.com/galadeat/bank-sim/api/proto/account/v2"
+	commonv1 "github.com/galadeat/bank-sim/api/proto/common/v1"
+	userv1 "github.com/galadeat/bank-sim/api/proto/user/v1"
+	"github.com/galadeat/bank-sim/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func newTestApp(t *testing.T) (*App, *mocks.MockUserClient, *mocks.MockAccountClient, *bytes.Buffer) {
+	ctrl := gomock.NewController(t)
+	users := mocks.NewMockUserClient(ctrl)
+	accounts := mocks.NewMockAccountClient(ctrl)
+	out := &bytes.Buffer{}
+	return &App{User: users, Account: accounts, Out: out}, users, accounts, out
+}
+
+func TestUserCreate(t *testing.T) {
+	app, users, _, out := newTestApp(t)
+	users.EXPECT().CreateUser(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, req *userv1.CreateUserRequest, _ ...grpc.CallOption) (*userv1.CreateUserResponse, error) {
+			assert.Equal(t, "alice", req.Login)
+			assert.Equal(t, "alice@example.com", req.Email)
+			assert.Equal(t, "req-1", req.RequestId)
+			return &userv1.CreateUserResponse{Id: "user-1"}, nil
+		})
+
+	err := app.Exec(context.Background(), []string{"user", "create", "--login", "alice", "--email", "alice@example.com", "--request-id", "req-1"})
+	require.NoError(t, err)
+	assert.Equal(t, "user-1\n", out.String())
+}
+
+func TestAccountDeposit(t *testing.T) {
+	account := &accountv2.AccountInfo{
+		Id:               "acc-1",
+		Owner:            &userv1.UserInfo{Login: "alice"},
+		Balance:          &commonv1.Money{Currency: "USD", Units: 10, Nanos: 500_000_000},
+		AvailableBalance: &commonv1.Money{Currency: "USD", Units: 10, Nanos: 500_000_000},
+		Status:           accountv2.AccountStatus_ACCOUNT_STATUS_ACTIVE,
+		Type:             accountv2.AccountType_ACCOUNT_TYPE_CHECKING,
+	}
+
+	t.Run("table", func(t *testing.T) {
+		app, _, accounts, out := newTestApp(t)
+		accounts.EXPECT().Deposit(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, req *accountv2.DepositRequest, _ ...grpc.CallOption) (*accountv2.DepositResponse, error) {
+				assert.Equal(t, "acc-1", req.AccountId)
+				assert.Equal(t, int64(10), req.Amount.Units)
+				assert.Equal(t, int32(500_000_000), req.Amount.Nanos)
+				assert.Equal(t, "USD", req.Amount.Currency)
+				assert.NotEmpty(t, req.RequestId)
+				return &accountv2.DepositResponse{Account: account}, nil
+			})
+
+		require.NoError(t, app.Exec(context.Background(), []string{"account", "deposit", "acc-1", "10.50", "usd"}))
+		assert.Equal(t, "ID     OWNER  TYPE      STATUS  BALANCE  AVAILABLE\n"+
+			"acc-1  alice  checking  active  $10.50   $10.50\n", out.String())
+	})
+
+	t.Run("json", func(t *testing.T) {
+		app, _, accounts, out := newTestApp(t)
+		app.JSON = true
+		accounts.EXPECT().Deposit(gomock.Any(), gomock.Any()).Return(&accountv2.DepositResponse{Account: account}, nil)
+
+		require.NoError(t, app.Exec(context.Background(), []string{"account", "deposit", "acc-1", "10.50", "USD"}))
+		var got struct {
+			Account struct {
+				ID      string `json:"id"`
+				Balance struct {
+					Units string `json:"units"`
+				} `json:"balance"`
+			} `json:"account"`
+		}
+		require.NoError(t, json.Unmarshal(out.Bytes(), &got))
+		assert.Equal(t, "acc-1", got.Account.ID)
+		assert.Equal(t, "10", got.Account.Balance.Units)
+	})
+
+	t.Run("malformed amount", func(t *testing.T) {
+		app, _, _, _ := newTestApp(t)
+		err := app.Exec(context.Background(), []string{"account", "deposit", "acc-1", "ten", "USD"})
+		assert.Equal(t, ExitUsage, ExitCode(err))
+	})
+}
+
+func TestAccountTransferHeldForReview(t *testing.T) {
+	app, _, accounts, out := newTestApp(t)
+	accounts.EXPECT().Transfer(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, req *accountv2.TransferRequest, _ ...grpc.CallOption) (*accountv2.TransferResponse, error) {
+			assert.Equal(t, "acc-1", req.FromAccountId)
+			assert.Equal(t, "acc-2", req.ToAccountId)
+			assert.Equal(t, "req-7", req.RequestId)
+			return &accountv2.TransferResponse{Review: &accountv2.Review{Id: "rev-1", Score: 60}}, nil
+		})
+
+	// flags may come after the positional arguments
+	err := app.Exec(context.Background(), []string{"account", "transfer", "acc-1", "acc-2", "25", "USD", "--request-id", "req-7"})
+	require.NoError(t, err)
+	assert.Equal(t, "held for review rev-1 (score 60)\n", out.String())
+}
+
+func TestCheckOwners(t *testing.T) {
+	alice := &userv1.UserInfo{Id: "user-1", Login: "alice2", Email: "alice@example.com"}
+	stale := &accountv2.AccountInfo{Id: "acc-1", OwnerId: "user-1", Owner: &userv1.UserInfo{Id: "user-1", Login: "alice", Email: "alice@example.com"}}
+	synced := &accountv2.AccountInfo{Id: "acc-2", OwnerId: "user-1", Owner: alice}
+
+	t.Run("reports mismatches", func(t *testing.T) {
+		app, users, accounts, out := newTestApp(t)
+		users.EXPECT().ListUsers(gomock.Any(), &userv1.ListUsersRequest{IncludeClosed: true}).Return(&userv1.ListUsersResponse{Users: []*userv1.UserInfo{alice}}, nil)
+		accounts.EXPECT().ListAccounts(gomock.Any(), gomock.Any()).Return(&accountv2.ListAccountsResponse{Accounts: []*accountv2.AccountInfo{stale, synced}}, nil)
+
+		err := app.Exec(context.Background(), []string{"check", "owners"})
+		assert.EqualError(t, err, `1 owner fields out of sync, run "check owners --fix" to repair them`)
+		assert.Equal(t, ExitFailure, ExitCode(err))
+		assert.Equal(t, "ACCOUNT  USER    FIELD  ACCOUNT VALUE  USER VALUE  FIXED\n"+
+			"acc-1    user-1  login  alice          alice2      false\n"+
+			"checked 1 users and 2 accounts, 1 mismatches\n", out.String())
+	})
+
+	t.Run("fix syncs the owner", func(t *testing.T) {
+		app, users, accounts, out := newTestApp(t)
+		app.JSON = true
+		users.EXPECT().ListUsers(gomock.Any(), gomock.Any()).Return(&userv1.ListUsersResponse{Users: []*userv1.UserInfo{alice}}, nil)
+		accounts.EXPECT().ListAccounts(gomock.Any(), gomock.Any()).Return(&accountv2.ListAccountsResponse{Accounts: []*accountv2.AccountInfo{stale, synced}}, nil)
+		accounts.EXPECT().SyncOwner(gomock.Any(), &accountv2.SyncOwnerRequest{Owner: alice}).Return(&accountv2.SyncOwnerResponse{UpdatedAccountIds: []string{"acc-1"}}, nil)
+
+		require.NoError(t, app.Exec(context.Background(), []string{"check", "owners", "--fix"}))
+		var report struct {
+			Mismatches []Mismatch `json:"mismatches"`
+		}
+		require.NoError(t, json.Unmarshal(out.Bytes(), &report))
+		require.Len(t, report.Mismatches, 1)
+		assert.True(t, report.Mismatches[0].Fixed)
+	})
+
+	t.Run("fix reports accounts it did not update", func(t *testing.T) {
+		app, users, accounts, out := newTestApp(t)
+		users.EXPECT().ListUsers(gomock.Any(), gomock.Any()).Return(&userv1.ListUsersResponse{Users: []*userv1.UserInfo{alice}}, nil)
+		accounts.EXPECT().ListAccounts(gomock.Any(), gomock.Any()).Return(&accountv2.ListAccountsResponse{Accounts: []*accountv2.AccountInfo{stale, synced}}, nil)
+		accounts.EXPECT().SyncOwner(gomock.Any(), &accountv2.SyncOwnerRequest{Owner: alice}).Return(&accountv2.SyncOwnerResponse{}, nil)
+
+		err := app.Exec(context.Background(), []string{"check", "owners", "--fix"})
+		assert.EqualError(t, err, `1 owner fields out of sync, run "check owners --fix" to repair them`)
+		assert.Contains(t, out.String(), "acc-1    user-1  login  alice          alice2      false\n")
+	})
+}
+
+func TestUsageErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "no command", args: []string{"user"}},
+		{name: "unknown group", args: []string{"loan", "list"}},
+		{name: "unknown command", args: []string{"user", "rename"}},
+		{name: "missing argument", args: []string{"account", "get"}},
+		{name: "extra argument", args: []string{"user", "get", "a", "b"}},
+		{name: "unknown flag", args: []string{"user", "list", "--closed"}},
+		{name: "unknown account type", args: []string{"account", "create", "user-1", "--type", "gold"}},
+		{name: "balance without currency", args: []string{"account", "create", "user-1", "100"}},
+		{name: "update without fields", args: []string{"user", "update", "user-1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app, _, _, _ := newTestApp(t)
+			err := app.Exec(context.Background(), tt.args)
+			require.Error(t, err)
+			assert.Equal(t, ExitUsage, ExitCode(err))
+		})
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{err: nil, want: ExitOK},
+		{err: errors.New("connection refused"), want: ExitFailure},
+		{err: status.Error(codes.InvalidArgument, "bad"), want: ExitInvalidArgument},
+		{err: status.Error(codes.NotFound, "missing"), want: ExitNotFound},
+		{err: status.Error(codes.AlreadyExists, "taken"), want: ExitAlreadyExists},
+		{err: status.Error(codes.PermissionDenied, "declined"), want: ExitPermissionDenied},
+		{err: status.Error(codes.FailedPrecondition, "insufficient funds"), want: ExitFailedPrecondition},
+		{err: status.Error(codes.ResourceExhausted, "limit"), want: ExitResourceExhausted},
+		{err: status.Error(codes.DeadlineExceeded, "slow"), want: ExitUnavailable},
+		{err: status.Error(codes.Internal, "boom"), want: ExitFailure},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, ExitCode(tt.err), "error %v", tt.err)
+	}
+}
+
+func TestReport(t *testing.T) {
+	var buf bytes.Buffer
+	assert.Equal(t, ExitFailedPrecondition, report(&buf, status.Error(codes.FailedPrecondition, "insufficient balance")))
+	assert.Equal(t, "error: failed precondition: insufficient balance\n", buf.String())
+}
+
+func TestMainHelp(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, ExitOK, Main([]string{"help"}, &stdout, &stderr))
+	assert.Contains(t, stdout.String(), "account deposit <account-id> <amount> <currency>")
+
+	stdout.Reset()
+	assert.Equal(t, ExitUsage, Main([]string{"--output", "xml", "user", "list"}, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "output must be table or json")
+}
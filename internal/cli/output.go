@@ -0,0 +1,40 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"text/tabwriter"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// print writes resp as indented JSON with proto field names, or as the table
+// drawn by table.
+func (a *App) print(resp proto.Message, table func(tw *tabwriter.Writer)) error {
+	if a.JSON {
+		data, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(resp)
+		if err != nil {
+			return err
+		}
+		// protojson output is not stable byte for byte; re-indenting it is
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, data, "", "  "); err != nil {
+			return err
+		}
+		buf.WriteByte('\n')
+		_, err = a.Out.Write(buf.Bytes())
+		return err
+	}
+
+	tw := tabwriter.NewWriter(a.Out, 0, 0, 2, ' ', 0)
+	table(tw)
+	return tw.Flush()
+}
+
+// enumName returns an enum value without its prefix, in lower case:
+// ACCOUNT_STATUS_ACTIVE becomes active.
+func enumName(name, prefix string) string {
+	return strings.ToLower(strings.TrimPrefix(name, prefix))
+}
@@ -0,0 +1,125 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"text/tabwriter"
+
+	userv1 "github.com/galadeat/bank-sim/api/proto/user/v1"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func (a *App) userCommands() map[string]func(context.Context, []string) error {
+	return map[string]func(context.Context, []string) error{
+		"create": a.createUser,
+		"get":    a.getUser,
+		"list":   a.listUsers,
+		"update": a.updateUser,
+		"delete": a.deleteUser,
+	}
+}
+
+func (a *App) createUser(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("user create", flag.ContinueOnError)
+	login := fs.String("login", "", "login")
+	email := fs.String("email", "", "email")
+	requestID := requestIDFlag(fs)
+	if _, err := parseArgs(fs, args, 0, 0); err != nil {
+		return err
+	}
+
+	resp, err := a.User.CreateUser(ctx, &userv1.CreateUserRequest{Login: *login, Email: *email, RequestId: *requestID})
+	if err != nil {
+		return err
+	}
+	a.LastID = resp.Id
+	// the id alone, so that id=$(bank user create ...) works
+	return a.print(resp, func(tw *tabwriter.Writer) {
+		fmt.Fprintln(tw, resp.Id)
+	})
+}
+
+func (a *App) getUser(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("user get", flag.ContinueOnError)
+	pos, err := parseArgs(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	resp, err := a.User.GetUser(ctx, &userv1.GetUserRequest{Id: pos[0], IncludeClosed: true})
+	if err != nil {
+		return err
+	}
+	return a.print(resp, func(tw *tabwriter.Writer) {
+		userTable(tw, resp.User)
+	})
+}
+
+func (a *App) listUsers(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("user list", flag.ContinueOnError)
+	all := fs.Bool("all", false, "include deleted users")
+	if _, err := parseArgs(fs, args, 0, 0); err != nil {
+		return err
+	}
+
+	resp, err := a.User.ListUsers(ctx, &userv1.ListUsersRequest{IncludeClosed: *all})
+	if err != nil {
+		return err
+	}
+	return a.print(resp, func(tw *tabwriter.Writer) {
+		userTable(tw, resp.Users...)
+	})
+}
+
+func (a *App) updateUser(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("user update", flag.ContinueOnError)
+	login := fs.String("login", "", "new login")
+	email := fs.String("email", "", "new email")
+	pos, err := parseArgs(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	req := &userv1.UpdateUserRequest{Id: pos[0]}
+	if *login != "" {
+		req.Login = wrapperspb.String(*login)
+	}
+	if *email != "" {
+		req.Email = wrapperspb.String(*email)
+	}
+	if req.Login == nil && req.Email == nil {
+		return usageError("user update: nothing to update, set --login or --email")
+	}
+
+	resp, err := a.User.UpdateUser(ctx, req)
+	if err != nil {
+		return err
+	}
+	return a.print(resp, func(tw *tabwriter.Writer) {
+		userTable(tw, resp.User)
+	})
+}
+
+func (a *App) deleteUser(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("user delete", flag.ContinueOnError)
+	pos, err := parseArgs(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	resp, err := a.User.DeleteUser(ctx, &userv1.DeleteUserRequest{Id: pos[0]})
+	if err != nil {
+		return err
+	}
+	return a.print(resp, func(tw *tabwriter.Writer) {
+		fmt.Fprintf(tw, "user %s deleted\n", pos[0])
+	})
+}
+
+func userTable(tw *tabwriter.Writer, users ...*userv1.UserInfo) {
+	fmt.Fprintln(tw, "ID\tLOGIN\tEMAIL\tSTATUS")
+	for _, u := range users {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", u.Id, u.Login, u.Email, enumName(u.Status.String(), "USER_STATUS_"))
+	}
+}
@@ -0,0 +1,356 @@
+// Package dataset moves users, accounts and their ledgers in and out of the
+// services as flat records, written as CSV or as newline delimited JSON, so demo
+// data can be exported from one server and seeded into another.
+package dataset
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	accountv2 "github.com/galadeat/bank-sim/api/proto/account/v2"
+	commonv1 "github.com/galadeat/bank-sim/api/proto/common/v1"
+	userv1 "github.com/galadeat/bank-sim/api/proto/user/v1"
+	"github.com/galadeat/bank-sim/pkg/money"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Dataset holds exported users, their accounts and the ledger entries of those
+// accounts, each account's entries oldest first.
+type Dataset struct {
+	Users        []User
+	Accounts     []Account
+	Transactions []Transaction
+}
+
+// User is an exported user. Status is active or closed.
+type User struct {
+	ID        string `json:"id"`
+	Login     string `json:"login"`
+	Email     string `json:"email"`
+	Status    string `json:"status"`
+	CreatedAt string `json:"created_at,omitempty"`
+}
+
+// Account is an exported account. Amounts are decimals in Currency, such as
+// "12.50" or "-3.25".
+type Account struct {
+	ID             string `json:"id"`
+	UserID         string `json:"user_id"`
+	Type           string `json:"type"`
+	Status         string `json:"status"`
+	Currency       string `json:"currency"`
+	Balance        string `json:"balance"`
+	OverdraftLimit string `json:"overdraft_limit,omitempty"`
+	OpenedAt       string `json:"opened_at,omitempty"`
+	MaturesAt      string `json:"matures_at,omitempty"`
+}
+
+// Transaction is an exported ledger entry. Amount is always positive; Type tells
+// whether it was credited or debited.
+type Transaction struct {
+	ID           string `json:"id"`
+	AccountID    string `json:"account_id"`
+	Type         string `json:"type"`
+	Amount       string `json:"amount"`
+	Currency     string `json:"currency"`
+	BalanceAfter string `json:"balance_after,omitempty"`
+	RequestID    string `json:"request_id,omitempty"`
+	CreatedAt    string `json:"created_at,omitempty"`
+	ReversesID   string `json:"reverses_id,omitempty"`
+}
+
+// credits are the entry types that add to the balance.
+var credits = map[accountv2.TransactionType]bool{
+	accountv2.TransactionType_TRANSACTION_TYPE_DEPOSIT:     true,
+	accountv2.TransactionType_TRANSACTION_TYPE_INTEREST:    true,
+	accountv2.TransactionType_TRANSACTION_TYPE_TRANSFER_IN: true,
+	accountv2.TransactionType_TRANSACTION_TYPE_REFUND:      true,
+}
+
+// Validate checks the records on their own, without the services: ids are set
+// and unique, references point at records of the dataset, names, amounts and
+// times parse, and every account's entries add up to its balance. It returns
+// every problem found, joined.
+func (d *Dataset) Validate() error {
+	var errs []error
+	fail := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	users := make(map[string]User, len(d.Users))
+	logins := make(map[string]string)
+	for i, u := range d.Users {
+		switch {
+		case u.ID == "":
+			fail("user %d: id is required", i+1)
+			continue
+		case users[u.ID].ID != "":
+			fail("user %s: duplicate id", u.ID)
+		}
+		users[u.ID] = u
+		if u.Login == "" || u.Email == "" {
+			fail("user %s: login and email are required", u.ID)
+		}
+		if _, err := userStatus(u.Status); err != nil {
+			fail("user %s: %v", u.ID, err)
+		}
+		if _, err := parseTime(u.CreatedAt); err != nil {
+			fail("user %s: %v", u.ID, err)
+		}
+		// closed users free their login, so only active ones must be unique
+		if u.Status == "active" {
+			login := strings.ToLower(u.Login)
+			if other, ok := logins[login]; ok {
+				fail("user %s: login %q is also used by user %s", u.ID, u.Login, other)
+			}
+			logins[login] = u.ID
+		}
+	}
+
+	accounts := make(map[string]Account, len(d.Accounts))
+	for i, a := range d.Accounts {
+		switch {
+		case a.ID == "":
+			fail("account %d: id is required", i+1)
+			continue
+		case accounts[a.ID].ID != "":
+			fail("account %s: duplicate id", a.ID)
+		}
+		accounts[a.ID] = a
+		if _, ok := users[a.UserID]; !ok {
+			fail("account %s: unknown user %q", a.ID, a.UserID)
+		}
+		if _, err := a.info(); err != nil {
+			fail("account %s: %v", a.ID, err)
+		}
+	}
+
+	balances := make(map[string]int64, len(d.Accounts))
+	transactions := make(map[string]bool, len(d.Transactions))
+	for i, t := range d.Transactions {
+		if t.ID == "" {
+			fail("transaction %d: id is required", i+1)
+			continue
+		}
+		if transactions[t.ID] {
+			fail("transaction %s: duplicate id", t.ID)
+		}
+		transactions[t.ID] = true
+		a, ok := accounts[t.AccountID]
+		if !ok {
+			fail("transaction %s: unknown account %q", t.ID, t.AccountID)
+			continue
+		}
+		if t.ReversesID != "" && !transactions[t.ReversesID] {
+			fail("transaction %s: reverses %q, which is not an earlier transaction", t.ID, t.ReversesID)
+		}
+		tx, err := t.info()
+		if err != nil {
+			fail("transaction %s: %v", t.ID, err)
+			continue
+		}
+		if t.Currency != a.Currency {
+			fail("transaction %s: currency %s differs from the account's %s", t.ID, t.Currency, a.Currency)
+			continue
+		}
+
+		if credits[tx.Type] {
+			balances[t.AccountID] += money.Nanos(tx.Amount)
+		} else {
+			balances[t.AccountID] -= money.Nanos(tx.Amount)
+		}
+		if tx.BalanceAfter != nil && money.Nanos(tx.BalanceAfter) != balances[t.AccountID] {
+			fail("transaction %s: balance after is %s, the account's entries add up to %s",
+				t.ID, t.BalanceAfter, formatNanos(balances[t.AccountID], a.Currency))
+		}
+	}
+	for _, a := range d.Accounts {
+		info, err := a.info()
+		if err != nil {
+			continue
+		}
+		if money.Nanos(info.Balance) != balances[a.ID] {
+			fail("account %s: balance is %s, its entries add up to %s", a.ID, a.Balance, formatNanos(balances[a.ID], a.Currency))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// info returns the account as the account service takes it, without its owner.
+func (a Account) info() (*accountv2.AccountInfo, error) {
+	typ, err := enumValue(accountv2.AccountType_value, "ACCOUNT_TYPE_", a.Type)
+	if err != nil {
+		return nil, fmt.Errorf("type: %w", err)
+	}
+	st, err := enumValue(accountv2.AccountStatus_value, "ACCOUNT_STATUS_", a.Status)
+	if err != nil {
+		return nil, fmt.Errorf("status: %w", err)
+	}
+	// accounts opened without a balance have no currency until their first deposit
+	var balance *commonv1.Money
+	switch _, ok := money.Lookup(a.Currency); {
+	case a.Currency == "" && isZeroAmount(a.Balance):
+	case !ok:
+		return nil, fmt.Errorf("unknown currency %q", a.Currency)
+	default:
+		if balance, err = parseAmount(a.Balance, a.Currency, true); err != nil {
+			return nil, fmt.Errorf("balance: %w", err)
+		}
+	}
+	info := &accountv2.AccountInfo{
+		Id:      a.ID,
+		OwnerId: a.UserID,
+		Balance: balance,
+		Status:  accountv2.AccountStatus(st),
+		Type:    accountv2.AccountType(typ),
+	}
+	if a.OverdraftLimit != "" {
+		if info.OverdraftLimit, err = parseAmount(a.OverdraftLimit, a.Currency, false); err != nil {
+			return nil, fmt.Errorf("overdraft limit: %w", err)
+		}
+	}
+	if info.OpenedAt, err = parseTime(a.OpenedAt); err != nil {
+		return nil, fmt.Errorf("opened at: %w", err)
+	}
+	if info.MaturesAt, err = parseTime(a.MaturesAt); err != nil {
+		return nil, fmt.Errorf("matures at: %w", err)
+	}
+	return info, nil
+}
+
+// info returns the entry as the account service takes it.
+func (t Transaction) info() (*accountv2.Transaction, error) {
+	typ, err := enumValue(accountv2.TransactionType_value, "TRANSACTION_TYPE_", t.Type)
+	if err != nil {
+		return nil, fmt.Errorf("type: %w", err)
+	}
+	if _, ok := money.Lookup(t.Currency); !ok {
+		return nil, fmt.Errorf("unknown currency %q", t.Currency)
+	}
+	amount, err := parseAmount(t.Amount, t.Currency, false)
+	if err != nil {
+		return nil, fmt.Errorf("amount: %w", err)
+	}
+	if money.Nanos(amount) == 0 {
+		return nil, errors.New("amount must be greater than zero")
+	}
+	tx := &accountv2.Transaction{
+		Id:                    t.ID,
+		AccountId:             t.AccountID,
+		Type:                  accountv2.TransactionType(typ),
+		Amount:                amount,
+		RequestId:             t.RequestID,
+		ReversesTransactionId: t.ReversesID,
+	}
+	if t.BalanceAfter != "" {
+		if tx.BalanceAfter, err = parseAmount(t.BalanceAfter, t.Currency, true); err != nil {
+			return nil, fmt.Errorf("balance after: %w", err)
+		}
+	}
+	if tx.CreatedAt, err = parseTime(t.CreatedAt); err != nil {
+		return nil, fmt.Errorf("created at: %w", err)
+	}
+	return tx, nil
+}
+
+func userStatus(s string) (userv1.UserStatus, error) {
+	v, err := enumValue(userv1.UserStatus_value, "USER_STATUS_", s)
+	return userv1.UserStatus(v), err
+}
+
+// enumValue reads an enum value written the way enumName writes it.
+func enumValue(values map[string]int32, prefix, name string) (int32, error) {
+	v, ok := values[prefix+strings.ToUpper(name)]
+	if !ok || v == 0 {
+		return 0, fmt.Errorf("unknown value %q", name)
+	}
+	return v, nil
+}
+
+// enumName returns an enum value without its prefix, in lower case:
+// ACCOUNT_STATUS_ACTIVE becomes active.
+func enumName(name, prefix string) string {
+	return strings.ToLower(strings.TrimPrefix(name, prefix))
+}
+
+// formatAmount writes m as a plain decimal with at least the currency's minor
+// unit digits: "12.50", "-3.25", "0.004109589".
+func formatAmount(m *commonv1.Money) string {
+	if m == nil {
+		return ""
+	}
+	return formatNanos(money.Nanos(m), m.Currency)
+}
+
+// formatNanos writes n billionths of a unit like formatAmount.
+func formatNanos(n int64, currency string) string {
+	digits := 2
+	if c, ok := money.Lookup(currency); ok {
+		digits = c.Digits
+	}
+	sign := ""
+	if n < 0 {
+		sign, n = "-", -n
+	}
+	frac := strings.TrimRight(fmt.Sprintf("%09d", n%1_000_000_000), "0")
+	for len(frac) < digits {
+		frac += "0"
+	}
+	out := sign + fmt.Sprint(n/1_000_000_000)
+	if frac != "" {
+		out += "." + frac
+	}
+	return out
+}
+
+// parseAmount reads an amount written by formatAmount. Amounts may have up to
+// nine decimal places, as accrued interest does.
+func parseAmount(s, currency string, signed bool) (*commonv1.Money, error) {
+	digits := strings.TrimPrefix(s, "-")
+	if digits != s && !signed {
+		return nil, fmt.Errorf("%s must not be negative", s)
+	}
+	whole, frac, _ := strings.Cut(digits, ".")
+	if whole == "" || len(frac) > 9 || strings.ContainsFunc(whole+frac, func(r rune) bool { return r < '0' || r > '9' }) {
+		return nil, fmt.Errorf("invalid amount %q", s)
+	}
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return nil, fmt.Errorf("invalid amount %q", s)
+	}
+	r.Mul(r, big.NewRat(1_000_000_000, 1))
+	if !r.Num().IsInt64() {
+		return nil, fmt.Errorf("amount %s is too large", s)
+	}
+	return money.FromNanos(r.Num().Int64(), currency), nil
+}
+
+func isZeroAmount(s string) bool {
+	if s == "" {
+		return true
+	}
+	m, err := parseAmount(s, "", true)
+	return err == nil && money.Nanos(m) == 0
+}
+
+func formatTime(ts *timestamppb.Timestamp) string {
+	if ts == nil {
+		return ""
+	}
+	return ts.AsTime().UTC().Format(time.RFC3339Nano)
+}
+
+// parseTime reads a time written by formatTime; empty is no time.
+func parseTime(s string) (*timestamppb.Timestamp, error) {
+	if s == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return nil, fmt.Errorf("time %q must look like %s", s, time.RFC3339)
+	}
+	return timestamppb.New(t), nil
+}
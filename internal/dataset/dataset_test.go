@@ -0,0 +1,245 @@
+package dataset
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	accountv2 "github.com/galadeat/bank-sim/api/proto/account/v2"
+	commonv1 "github.com/galadeat/bank-sim/api/proto/common/v1"
+	userv1 "github.com/galadeat/bank-sim/api/proto/user/v1"
+	"github.com/galadeat/bank-sim/internal/account"
+	"github.com/galadeat/bank-sim/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func sample() *Dataset {
+	return &Dataset{
+		Users: []User{
+			{ID: "u1", Login: "alice", Email: "alice@example.com", Status: "active"},
+			{ID: "u2", Login: "bob", Email: "bob@example.com", Status: "closed"},
+		},
+		Accounts: []Account{
+			{ID: "a1", UserID: "u1", Type: "checking", Status: "active", Currency: "USD", Balance: "80.004109589", OpenedAt: "2026-01-02T10:00:00Z"},
+			{ID: "a2", UserID: "u2", Type: "savings", Status: "closed", Currency: "USD", Balance: "0.00"},
+			{ID: "a3", UserID: "u1", Type: "checking", Status: "pending"},
+		},
+		Transactions: []Transaction{
+			{ID: "t1", AccountID: "a1", Type: "deposit", Amount: "100.00", Currency: "USD", BalanceAfter: "100.00", RequestID: "r1", CreatedAt: "2026-01-02T10:00:00Z"},
+			{ID: "t2", AccountID: "a1", Type: "withdrawal", Amount: "30.00", Currency: "USD", BalanceAfter: "70.00"},
+			{ID: "t3", AccountID: "a1", Type: "refund", Amount: "10.00", Currency: "USD", ReversesID: "t2"},
+			{ID: "t4", AccountID: "a1", Type: "interest", Amount: "0.004109589", Currency: "USD"},
+			{ID: "t5", AccountID: "a2", Type: "deposit", Amount: "5", Currency: "USD"},
+			{ID: "t6", AccountID: "a2", Type: "transfer_out", Amount: "5", Currency: "USD", BalanceAfter: "0"},
+		},
+	}
+}
+
+func TestFormats(t *testing.T) {
+	d := sample()
+	require.NoError(t, d.Validate())
+
+	t.Run("csv", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, d.WriteCSV(dir))
+		got, err := ReadCSV(dir)
+		require.NoError(t, err)
+		assert.Equal(t, d, got)
+	})
+
+	t.Run("ndjson", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, d.WriteNDJSON(&buf))
+		assert.Contains(t, buf.String(), `{"user":{"id":"u1","login":"alice"`)
+		got, err := ReadNDJSON(&buf)
+		require.NoError(t, err)
+		assert.Equal(t, d, got)
+	})
+
+	t.Run("bad ndjson", func(t *testing.T) {
+		_, err := ReadNDJSON(bytes.NewBufferString("\n{\"user\":{\"id\":\"u1\"},\"account\":{\"id\":\"a1\"}}\n"))
+		assert.ErrorContains(t, err, "line 2:")
+		_, err = ReadNDJSON(bytes.NewBufferString(`{"user":{"id":"u1","nickname":"al"}}`))
+		assert.ErrorContains(t, err, "nickname")
+	})
+}
+
+func TestValidate(t *testing.T) {
+	for name, tc := range map[string]struct {
+		change func(d *Dataset)
+		want   string
+	}{
+		"duplicate user":     {func(d *Dataset) { d.Users[1].ID = "u1" }, "user u1: duplicate id"},
+		"taken login":        {func(d *Dataset) { d.Users[1].Status, d.Users[1].Login = "active", "ALICE" }, `login "ALICE" is also used by user u1`},
+		"unknown status":     {func(d *Dataset) { d.Users[0].Status = "frozen" }, `user u1: unknown value "frozen"`},
+		"unknown user":       {func(d *Dataset) { d.Accounts[0].UserID = "u9" }, `account a1: unknown user "u9"`},
+		"unknown type":       {func(d *Dataset) { d.Accounts[0].Type = "gold" }, `account a1: type: unknown value "gold"`},
+		"bad amount":         {func(d *Dataset) { d.Transactions[1].Amount = "3O" }, `transaction t2: amount: invalid amount "3O"`},
+		"negative amount":    {func(d *Dataset) { d.Transactions[1].Amount = "-30" }, "transaction t2: amount: -30 must not be negative"},
+		"wrong balance":      {func(d *Dataset) { d.Accounts[0].Balance = "80" }, "account a1: balance is 80, its entries add up to 80.004109589"},
+		"wrong entry":        {func(d *Dataset) { d.Transactions[1].BalanceAfter = "60.00" }, "transaction t2: balance after is 60.00, the account's entries add up to 70.00"},
+		"other currency":     {func(d *Dataset) { d.Transactions[0].Currency = "EUR" }, "transaction t1: currency EUR differs from the account's USD"},
+		"later reversal":     {func(d *Dataset) { d.Transactions[2].ReversesID = "t4" }, `transaction t3: reverses "t4"`},
+		"unknown account":    {func(d *Dataset) { d.Transactions[0].AccountID = "a9" }, `transaction t1: unknown account "a9"`},
+		"bad time":           {func(d *Dataset) { d.Accounts[0].OpenedAt = "yesterday" }, `account a1: opened at: time "yesterday"`},
+		"pending with money": {func(d *Dataset) { d.Accounts[2].Balance = "1" }, `account a3: unknown currency ""`},
+	} {
+		t.Run(name, func(t *testing.T) {
+			d := sample()
+			tc.change(d)
+			assert.ErrorContains(t, d.Validate(), tc.want)
+		})
+	}
+}
+
+// fakeUsers is a user service that keeps users in memory.
+type fakeUsers struct {
+	userv1.UserClient
+	users   []*userv1.UserInfo
+	created int
+}
+
+func (f *fakeUsers) ListUsers(context.Context, *userv1.ListUsersRequest, ...grpc.CallOption) (*userv1.ListUsersResponse, error) {
+	return &userv1.ListUsersResponse{Users: f.users}, nil
+}
+
+func (f *fakeUsers) GetUser(_ context.Context, req *userv1.GetUserRequest, _ ...grpc.CallOption) (*userv1.GetUserResponse, error) {
+	for _, u := range f.users {
+		if u.Id == req.Id && (req.IncludeClosed || u.Status == userv1.UserStatus_USER_STATUS_ACTIVE) {
+			return &userv1.GetUserResponse{User: u}, nil
+		}
+	}
+	return nil, status.Error(codes.NotFound, "user does not exist")
+}
+
+func (f *fakeUsers) CreateUser(_ context.Context, req *userv1.CreateUserRequest, _ ...grpc.CallOption) (*userv1.CreateUserResponse, error) {
+	f.created++
+	u := &userv1.UserInfo{Id: "new-" + req.Login, Login: req.Login, Email: req.Email, Status: userv1.UserStatus_USER_STATUS_ACTIVE}
+	f.users = append(f.users, u)
+	return &userv1.CreateUserResponse{Id: u.Id}, nil
+}
+
+func (f *fakeUsers) DeleteUser(_ context.Context, req *userv1.DeleteUserRequest, _ ...grpc.CallOption) (*userv1.DeleteUserResponse, error) {
+	for _, u := range f.users {
+		if u.Id == req.Id && u.Status == userv1.UserStatus_USER_STATUS_ACTIVE {
+			u.Status = userv1.UserStatus_USER_STATUS_CLOSED
+			return &userv1.DeleteUserResponse{Success: true}, nil
+		}
+	}
+	return nil, status.Error(codes.NotFound, "user doesn't exist")
+}
+
+// accountClient calls the account service directly.
+type accountClient struct {
+	accountv2.AccountClient
+	svc *account.Service
+}
+
+func (c accountClient) RestoreAccount(ctx context.Context, req *accountv2.RestoreAccountRequest, _ ...grpc.CallOption) (*accountv2.RestoreAccountResponse, error) {
+	return c.svc.RestoreAccount(ctx, req)
+}
+
+func (c accountClient) ListAccounts(ctx context.Context, req *accountv2.ListAccountsRequest, _ ...grpc.CallOption) (*accountv2.ListAccountsResponse, error) {
+	return c.svc.ListAccounts(ctx, req)
+}
+
+func (c accountClient) ListTransactions(ctx context.Context, req *accountv2.ListTransactionsRequest, _ ...grpc.CallOption) (*accountv2.ListTransactionsResponse, error) {
+	return c.svc.ListTransactions(ctx, req)
+}
+
+func TestImport(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("imports and exports the same records", func(t *testing.T) {
+		users := &fakeUsers{}
+		accounts := accountClient{svc: account.New(users)}
+
+		report, err := Import(ctx, users, accounts, sample(), false)
+		require.NoError(t, err)
+		assert.Zero(t, report.Failed())
+		assert.Equal(t, 6, report.Transactions)
+		assert.Equal(t, []string{ActionCreated, ActionCreated, ActionRestored, ActionRestored, ActionRestored}, actions(report))
+		assert.Equal(t, userv1.UserStatus_USER_STATUS_CLOSED, users.users[1].Status, "closed users are closed after their accounts")
+
+		out, err := Export(ctx, users, accounts)
+		require.NoError(t, err)
+		require.NoError(t, out.Validate())
+		require.Len(t, out.Accounts, 3)
+		assert.Equal(t, "80.004109589", out.Accounts[0].Balance)
+		assert.Equal(t, "closed", out.Accounts[2].Status)
+		require.Len(t, out.Transactions, 6)
+		assert.Equal(t, out.Transactions[1].ID, out.Transactions[2].ReversesID)
+		assert.Equal(t, "2026-01-02T10:00:00Z", out.Transactions[0].CreatedAt)
+
+		t.Run("again", func(t *testing.T) {
+			report, err := Import(ctx, users, accounts, sample(), false)
+			require.NoError(t, err)
+			assert.Zero(t, report.Failed())
+			assert.Equal(t, []string{ActionExists, ActionExists, ActionExists, ActionExists, ActionExists}, actions(report))
+			assert.Equal(t, 2, users.created)
+
+			again, err := Export(ctx, users, accounts)
+			require.NoError(t, err)
+			assert.Equal(t, out, again)
+		})
+	})
+
+	t.Run("dry run changes nothing", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		accounts := mocks.NewMockAccountClient(ctrl)
+		users := &fakeUsers{users: []*userv1.UserInfo{{Id: "x1", Login: "Alice", Email: "alice@example.com", Status: userv1.UserStatus_USER_STATUS_ACTIVE}}}
+		accounts.EXPECT().RestoreAccount(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, req *accountv2.RestoreAccountRequest, _ ...grpc.CallOption) (*accountv2.RestoreAccountResponse, error) {
+				assert.True(t, req.DryRun)
+				assert.Equal(t, "x1", req.Account.OwnerId)
+				return &accountv2.RestoreAccountResponse{Account: &accountv2.AccountInfo{}, Transactions: req.Transactions}, nil
+			}).Times(2)
+
+		report, err := Import(ctx, users, accounts, sample(), true)
+		require.NoError(t, err)
+		assert.Equal(t, []string{ActionExists, ActionWouldCreate, ActionWouldRestore, ActionWouldRestore, ActionWouldRestore}, actions(report))
+		assert.Zero(t, users.created)
+	})
+
+	t.Run("login with another email", func(t *testing.T) {
+		users := &fakeUsers{users: []*userv1.UserInfo{{Id: "x1", Login: "alice", Email: "other@example.com", Status: userv1.UserStatus_USER_STATUS_ACTIVE}}}
+		accounts := accountClient{svc: account.New(users)}
+
+		report, err := Import(ctx, users, accounts, sample(), false)
+		require.NoError(t, err)
+		assert.Equal(t, 3, report.Failed(), "the user and its two accounts")
+		assert.Contains(t, report.Results[0].Error, `login "alice" belongs to user x1`)
+		assert.Equal(t, "user u1 was not imported", report.Results[2].Error)
+	})
+
+	t.Run("invalid dataset", func(t *testing.T) {
+		d := sample()
+		d.Accounts[0].Balance = "1.00"
+		_, err := Import(ctx, &fakeUsers{}, accountClient{}, d, false)
+		assert.ErrorContains(t, err, "invalid dataset")
+	})
+}
+
+func TestAmounts(t *testing.T) {
+	for _, s := range []string{"0.00", "12.50", "-3.25", "0.004109589"} {
+		m, err := parseAmount(s, "USD", true)
+		require.NoError(t, err, s)
+		assert.Equal(t, s, formatAmount(m), "%s written back", s)
+	}
+	assert.Equal(t, "1200", formatAmount(&commonv1.Money{Currency: "JPY", Units: 1200}))
+	_, err := parseAmount("1.0000000001", "USD", false)
+	assert.Error(t, err)
+}
+
+func actions(r *Report) []string {
+	var out []string
+	for _, res := range r.Results {
+		out = append(out, res.Action)
+	}
+	return out
+}
@@ -0,0 +1,74 @@
+package dataset
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	accountv2 "github.com/galadeat/bank-sim/api/proto/account/v2"
+	userv1 "github.com/galadeat/bank-sim/api/proto/user/v1"
+)
+
+// Export reads every user, closed ones included, with their accounts and
+// ledgers. Users come oldest first, accounts in the order they were opened.
+func Export(ctx context.Context, users userv1.UserClient, accounts accountv2.AccountClient) (*Dataset, error) {
+	resp, err := users.ListUsers(ctx, &userv1.ListUsersRequest{IncludeClosed: true})
+	if err != nil {
+		return nil, fmt.Errorf("list users: %w", err)
+	}
+	list := resp.Users
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].CreatedAt.AsTime().Before(list[j].CreatedAt.AsTime())
+	})
+
+	d := &Dataset{}
+	for _, u := range list {
+		d.Users = append(d.Users, User{
+			ID:        u.Id,
+			Login:     u.Login,
+			Email:     u.Email,
+			Status:    enumName(u.Status.String(), "USER_STATUS_"),
+			CreatedAt: formatTime(u.CreatedAt),
+		})
+
+		accs, err := accounts.ListAccounts(ctx, &accountv2.ListAccountsRequest{UserId: u.Id, IncludeClosed: true})
+		if err != nil {
+			return nil, fmt.Errorf("list accounts of user %s: %w", u.Id, err)
+		}
+		sort.SliceStable(accs.Accounts, func(i, j int) bool {
+			return accs.Accounts[i].OpenedAt.AsTime().Before(accs.Accounts[j].OpenedAt.AsTime())
+		})
+		for _, a := range accs.Accounts {
+			d.Accounts = append(d.Accounts, Account{
+				ID:             a.Id,
+				UserID:         u.Id,
+				Type:           enumName(a.Type.String(), "ACCOUNT_TYPE_"),
+				Status:         enumName(a.Status.String(), "ACCOUNT_STATUS_"),
+				Currency:       a.Balance.GetCurrency(),
+				Balance:        formatAmount(a.Balance),
+				OverdraftLimit: formatAmount(a.OverdraftLimit),
+				OpenedAt:       formatTime(a.OpenedAt),
+				MaturesAt:      formatTime(a.MaturesAt),
+			})
+
+			txs, err := accounts.ListTransactions(ctx, &accountv2.ListTransactionsRequest{AccountId: a.Id})
+			if err != nil {
+				return nil, fmt.Errorf("list transactions of account %s: %w", a.Id, err)
+			}
+			for _, t := range txs.Transactions {
+				d.Transactions = append(d.Transactions, Transaction{
+					ID:           t.Id,
+					AccountID:    a.Id,
+					Type:         enumName(t.Type.String(), "TRANSACTION_TYPE_"),
+					Amount:       formatAmount(t.Amount),
+					Currency:     t.Amount.GetCurrency(),
+					BalanceAfter: formatAmount(t.BalanceAfter),
+					RequestID:    t.RequestId,
+					CreatedAt:    formatTime(t.CreatedAt),
+					ReversesID:   t.ReversesTransactionId,
+				})
+			}
+		}
+	}
+	return d, nil
+}
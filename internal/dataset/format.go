@@ -0,0 +1,215 @@
+package dataset
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// File names of the CSV form of a dataset, one file per record type.
+const (
+	UsersFile        = "users.csv"
+	AccountsFile     = "accounts.csv"
+	TransactionsFile = "transactions.csv"
+)
+
+var (
+	userColumns        = []string{"id", "login", "email", "status", "created_at"}
+	accountColumns     = []string{"id", "user_id", "type", "status", "currency", "balance", "overdraft_limit", "opened_at", "matures_at"}
+	transactionColumns = []string{"id", "account_id", "type", "amount", "currency", "balance_after", "request_id", "created_at", "reverses_id"}
+)
+
+func (u *User) fields() map[string]*string {
+	return map[string]*string{"id": &u.ID, "login": &u.Login, "email": &u.Email, "status": &u.Status, "created_at": &u.CreatedAt}
+}
+
+func (a *Account) fields() map[string]*string {
+	return map[string]*string{
+		"id": &a.ID, "user_id": &a.UserID, "type": &a.Type, "status": &a.Status, "currency": &a.Currency,
+		"balance": &a.Balance, "overdraft_limit": &a.OverdraftLimit, "opened_at": &a.OpenedAt, "matures_at": &a.MaturesAt,
+	}
+}
+
+func (t *Transaction) fields() map[string]*string {
+	return map[string]*string{
+		"id": &t.ID, "account_id": &t.AccountID, "type": &t.Type, "amount": &t.Amount, "currency": &t.Currency,
+		"balance_after": &t.BalanceAfter, "request_id": &t.RequestID, "created_at": &t.CreatedAt, "reverses_id": &t.ReversesID,
+	}
+}
+
+// WriteCSV writes the dataset to dir, which is created if needed, as
+// UsersFile, AccountsFile and TransactionsFile with a header row each.
+func (d *Dataset) WriteCSV(dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	if err := writeCSV(filepath.Join(dir, UsersFile), userColumns, d.Users, (*User).fields); err != nil {
+		return err
+	}
+	if err := writeCSV(filepath.Join(dir, AccountsFile), accountColumns, d.Accounts, (*Account).fields); err != nil {
+		return err
+	}
+	return writeCSV(filepath.Join(dir, TransactionsFile), transactionColumns, d.Transactions, (*Transaction).fields)
+}
+
+// ReadCSV reads a dataset written by WriteCSV. Columns are matched by the
+// header, so they may come in any order and optional ones may be left out; a
+// missing file is an empty record type.
+func ReadCSV(dir string) (*Dataset, error) {
+	d := &Dataset{}
+	var err error
+	if d.Users, err = readCSV(filepath.Join(dir, UsersFile), (*User).fields); err != nil {
+		return nil, err
+	}
+	if d.Accounts, err = readCSV(filepath.Join(dir, AccountsFile), (*Account).fields); err != nil {
+		return nil, err
+	}
+	if d.Transactions, err = readCSV(filepath.Join(dir, TransactionsFile), (*Transaction).fields); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func writeCSV[T any](path string, columns []string, rows []T, fields func(*T) map[string]*string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	if err := w.Write(columns); err != nil {
+		return err
+	}
+	record := make([]string, len(columns))
+	for i := range rows {
+		values := fields(&rows[i])
+		for j, c := range columns {
+			record[j] = *values[c]
+		}
+		if err := w.Write(record); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+func readCSV[T any](path string, fields func(*T) map[string]*string) ([]T, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	header, err := r.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	var zero T
+	known := fields(&zero)
+	for _, c := range header {
+		if _, ok := known[c]; !ok {
+			return nil, fmt.Errorf("%s: unknown column %q", path, c)
+		}
+	}
+
+	var out []T
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			return out, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		var row T
+		values := fields(&row)
+		for i, c := range header {
+			*values[c] = record[i]
+		}
+		out = append(out, row)
+	}
+}
+
+// line is one line of the NDJSON form: exactly one of its fields is set.
+type line struct {
+	User        *User        `json:"user,omitempty"`
+	Account     *Account     `json:"account,omitempty"`
+	Transaction *Transaction `json:"transaction,omitempty"`
+}
+
+// WriteNDJSON writes one JSON object per line: users first, then accounts, then
+// transactions, each wrapped in an object naming its type, e.g.
+//
+//	{"user":{"id":"...","login":"alice",...}}
+func (d *Dataset) WriteNDJSON(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	for i := range d.Users {
+		if err := enc.Encode(line{User: &d.Users[i]}); err != nil {
+			return err
+		}
+	}
+	for i := range d.Accounts {
+		if err := enc.Encode(line{Account: &d.Accounts[i]}); err != nil {
+			return err
+		}
+	}
+	for i := range d.Transactions {
+		if err := enc.Encode(line{Transaction: &d.Transactions[i]}); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// ReadNDJSON reads a dataset written by WriteNDJSON. Lines may come in any
+// order; blank lines are skipped.
+func ReadNDJSON(r io.Reader) (*Dataset, error) {
+	d := &Dataset{}
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	for n := 1; sc.Scan(); n++ {
+		data := bytes.TrimSpace(sc.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+		var l line
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&l); err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+		switch {
+		case l.User != nil && l.Account == nil && l.Transaction == nil:
+			d.Users = append(d.Users, *l.User)
+		case l.Account != nil && l.User == nil && l.Transaction == nil:
+			d.Accounts = append(d.Accounts, *l.Account)
+		case l.Transaction != nil && l.User == nil && l.Account == nil:
+			d.Transactions = append(d.Transactions, *l.Transaction)
+		default:
+			return nil, fmt.Errorf("line %d: expected one of user, account or transaction", n)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
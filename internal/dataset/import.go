@@ -0,0 +1,212 @@
+package dataset
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	accountv2 "github.com/galadeat/bank-sim/api/proto/account/v2"
+	userv1 "github.com/galadeat/bank-sim/api/proto/user/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Actions reported by Import.
+const (
+	ActionCreated      = "created"
+	ActionExists       = "exists"
+	ActionRestored     = "restored"
+	ActionFailed       = "failed"
+	ActionWouldCreate  = "would create"
+	ActionWouldRestore = "would restore"
+)
+
+// Result is what Import did with one user or account of the dataset.
+type Result struct {
+	Kind   string `json:"kind"`
+	ID     string `json:"id"`
+	NewID  string `json:"new_id,omitempty"`
+	Action string `json:"action"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Report is the outcome of Import, one result per user and account in dataset
+// order.
+type Report struct {
+	DryRun       bool     `json:"dry_run"`
+	Results      []Result `json:"results"`
+	Transactions int      `json:"transactions"`
+}
+
+// Failed returns the number of records that could not be imported.
+func (r *Report) Failed() int {
+	n := 0
+	for _, res := range r.Results {
+		if res.Action == ActionFailed {
+			n++
+		}
+	}
+	return n
+}
+
+// Import loads a validated dataset into the services. Records get new ids;
+// the report maps the dataset's ids to them.
+//
+// Running an import again is safe. Users are matched by login: an active user
+// with the login is reused, and a login taken by a user with another email is
+// a failure. Accounts are restored with their ledgers through RestoreAccount
+// with their dataset id as source id, so the account service creates each of
+// them once and returns the account restored before on later runs, or the
+// account itself when the dataset is imported into the server it came from.
+// Closed users are created, get their accounts and are then
+// deleted.
+//
+// With dryRun nothing is changed: users are only looked up, and accounts of
+// users that exist already are checked by the account service.
+func Import(ctx context.Context, users userv1.UserClient, accounts accountv2.AccountClient, d *Dataset, dryRun bool) (*Report, error) {
+	if err := d.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid dataset:\n%w", err)
+	}
+
+	existing, err := users.ListUsers(ctx, &userv1.ListUsersRequest{IncludeClosed: true})
+	if err != nil {
+		return nil, fmt.Errorf("list users: %w", err)
+	}
+	active := make(map[string]*userv1.UserInfo)
+	closed := make(map[string]*userv1.UserInfo)
+	for _, u := range existing.Users {
+		if u.Status == userv1.UserStatus_USER_STATUS_CLOSED {
+			closed[userKey(u.Login, u.Email)] = u
+		} else {
+			active[strings.ToLower(u.Login)] = u
+		}
+	}
+
+	report := &Report{DryRun: dryRun}
+	userIDs := make(map[string]string, len(d.Users))
+	var toClose []int
+	for _, u := range d.Users {
+		res := Result{Kind: "user", ID: u.ID}
+		match, ok := active[strings.ToLower(u.Login)]
+		switch {
+		case ok && !strings.EqualFold(match.Email, u.Email):
+			res.Action, res.Error = ActionFailed, fmt.Sprintf("login %q belongs to user %s with another email", u.Login, match.Id)
+		case ok:
+			res.Action, res.NewID = ActionExists, match.Id
+		case u.Status == "closed" && closed[userKey(u.Login, u.Email)] != nil:
+			res.Action, res.NewID = ActionExists, closed[userKey(u.Login, u.Email)].Id
+		case dryRun:
+			res.Action = ActionWouldCreate
+		default:
+			resp, err := users.CreateUser(ctx, &userv1.CreateUserRequest{Login: u.Login, Email: u.Email})
+			if err != nil {
+				res.Action, res.Error = ActionFailed, describe(err)
+				break
+			}
+			res.Action, res.NewID = ActionCreated, resp.Id
+		}
+
+		if res.NewID != "" {
+			userIDs[u.ID] = res.NewID
+			// closed users are closed once their accounts are restored
+			if u.Status == "closed" && (ok || res.Action == ActionCreated) {
+				toClose = append(toClose, len(report.Results))
+			}
+		}
+		report.Results = append(report.Results, res)
+	}
+
+	entries := make(map[string][]Transaction, len(d.Accounts))
+	for _, t := range d.Transactions {
+		entries[t.AccountID] = append(entries[t.AccountID], t)
+	}
+	txIDs := make(map[string]string, len(d.Transactions))
+	for _, a := range d.Accounts {
+		res := Result{Kind: "account", ID: a.ID}
+		n, err := restore(ctx, accounts, a, entries[a.ID], userIDs, txIDs, dryRun, &res)
+		if err != nil {
+			res.Action, res.Error = ActionFailed, describe(err)
+		}
+		report.Transactions += n
+		report.Results = append(report.Results, res)
+	}
+
+	for _, i := range toClose {
+		res := &report.Results[i]
+		_, err := users.DeleteUser(ctx, &userv1.DeleteUserRequest{Id: res.NewID})
+		if err != nil && status.Code(err) != codes.NotFound {
+			res.Action, res.Error = ActionFailed, "close user: "+describe(err)
+		}
+	}
+	return report, nil
+}
+
+// restore restores one account with its entries and returns how many entries
+// it restored. Entries that reverse entries of accounts restored before are
+// pointed at their new ids, and the new ids of this account's entries are added
+// to txIDs.
+func restore(ctx context.Context, accounts accountv2.AccountClient, a Account, entries []Transaction,
+	userIDs, txIDs map[string]string, dryRun bool, res *Result) (int, error) {
+	owner, ok := userIDs[a.UserID]
+	if !ok && !dryRun {
+		return 0, fmt.Errorf("user %s was not imported", a.UserID)
+	}
+
+	info, err := a.info()
+	if err != nil {
+		return 0, err
+	}
+	info.Id = ""
+	info.OwnerId = owner
+	info.SourceId = a.ID
+	req := &accountv2.RestoreAccountRequest{Account: info, RequestId: "import:account:" + a.ID, DryRun: dryRun}
+	for _, t := range entries {
+		tx, err := t.info()
+		if err != nil {
+			return 0, err
+		}
+		if id, ok := txIDs[tx.ReversesTransactionId]; ok {
+			tx.ReversesTransactionId = id
+		}
+		req.Transactions = append(req.Transactions, tx)
+	}
+
+	// the owner of a dry run may not exist yet, so only the dataset checks apply
+	if owner == "" {
+		res.Action = ActionWouldRestore
+		return len(entries), nil
+	}
+
+	resp, err := accounts.RestoreAccount(ctx, req)
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case resp.Existing:
+		res.Action, res.NewID = ActionExists, resp.Account.Id
+	case dryRun:
+		res.Action = ActionWouldRestore
+		return len(resp.Transactions), nil
+	default:
+		res.Action, res.NewID = ActionRestored, resp.Account.Id
+	}
+	for i, tx := range resp.Transactions {
+		if i < len(entries) {
+			txIDs[entries[i].ID] = tx.Id
+		}
+	}
+	return len(resp.Transactions), nil
+}
+
+func userKey(login, email string) string {
+	return strings.ToLower(login) + "\x00" + strings.ToLower(email)
+}
+
+// describe returns the message of errors returned by the services and err
+// itself otherwise.
+func describe(err error) string {
+	if st, ok := status.FromError(err); ok {
+		return st.Code().String() + ": " + st.Message()
+	}
+	return err.Error()
+}
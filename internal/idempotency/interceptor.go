@@ -0,0 +1,61 @@
+package idempotency
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// requestIDer is implemented by every request message with a request_id field.
+type requestIDer interface {
+	GetRequestId() string
+}
+
+// Key scopes a request id to the rpc method it was sent to.
+func Key(fullMethod, requestID string) string {
+	return fullMethod + ":" + requestID
+}
+
+// UnaryServerInterceptor makes the given methods idempotent. A request carrying a
+// request id is handled once, and retries get the saved response. Requests
+// without a request id are passed through. With no methods listed every method
+// whose request has a request id is covered.
+func UnaryServerInterceptor(store *Store, methods ...string) grpc.UnaryServerInterceptor {
+	covered := make(map[string]bool, len(methods))
+	for _, m := range methods {
+		covered[m] = true
+	}
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if len(covered) > 0 && !covered[info.FullMethod] {
+			return handler(ctx, req)
+		}
+		r, ok := req.(requestIDer)
+		if !ok || r.GetRequestId() == "" {
+			return handler(ctx, req)
+		}
+		msg, ok := req.(proto.Message)
+		if !ok {
+			return handler(ctx, req)
+		}
+
+		fingerprint, err := Fingerprint(msg)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to fingerprint request: %v", err)
+		}
+		return store.Do(Key(info.FullMethod, r.GetRequestId()), fingerprint, func() (proto.Message, error) {
+			resp, err := handler(ctx, req)
+			if err != nil {
+				return nil, err
+			}
+			out, ok := resp.(proto.Message)
+			if !ok {
+				return nil, status.Errorf(codes.Internal, "unexpected response type %T", resp)
+			}
+			return out, nil
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package idempotency
+
+import (
+	"context"
+	"testing"
+
+	userv1 "github.com/galadeat/bank-sim/api/proto/user/v1"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	create := &grpc.UnaryServerInfo{FullMethod: userv1.User_CreateUser_FullMethodName}
+	var calls int
+	handler := func(ctx context.Context, req any) (any, error) {
+		calls++
+		return &userv1.CreateUserResponse{Id: req.(*userv1.CreateUserRequest).Login}, nil
+	}
+
+	t.Run("handles a request id once", func(t *testing.T) {
+		calls = 0
+		intercept := UnaryServerInterceptor(New(), userv1.User_CreateUser_FullMethodName)
+		req := &userv1.CreateUserRequest{Login: "alice", Email: "alice@example.com", RequestId: "r-1"}
+
+		first, err := intercept(context.Background(), req, create, handler)
+		assert.NoError(t, err)
+		second, err := intercept(context.Background(), req, create, handler)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, first, second)
+
+		_, err = intercept(context.Background(), &userv1.CreateUserRequest{Login: "bob", Email: "bob@example.com", RequestId: "r-1"}, create, handler)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("passes through requests without an id", func(t *testing.T) {
+		calls = 0
+		intercept := UnaryServerInterceptor(New())
+		req := &userv1.CreateUserRequest{Login: "alice", Email: "alice@example.com"}
+
+		_, _ = intercept(context.Background(), req, create, handler)
+		_, _ = intercept(context.Background(), req, create, handler)
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("skips methods that are not listed", func(t *testing.T) {
+		calls = 0
+		intercept := UnaryServerInterceptor(New(), userv1.User_DeleteUser_FullMethodName)
+		req := &userv1.CreateUserRequest{Login: "alice", Email: "alice@example.com", RequestId: "r-1"}
+
+		_, _ = intercept(context.Background(), req, create, handler)
+		_, _ = intercept(context.Background(), req, create, handler)
+		assert.Equal(t, 2, calls)
+	})
+}
@@ -0,0 +1,445 @@
+package idempotency
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/galadeat/bank-sim/pkg/clock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// DefaultTTL is how long a response is kept for retries of the same request.
+const DefaultTTL = 24 * time.Hour
+
+const pruneInterval = time.Minute
+
+// entry is a saved response. Response is what goes to the journal; resp is the
+// message itself, which is returned as is while the process lives.
+type entry struct {
+	Key         string    `json:"key"`
+	Fingerprint string    `json:"fingerprint"`
+	Response    []byte    `json:"response"`
+	ExpiresAt   time.Time `json:"expires_at"`
+
+	resp proto.Message
+}
+
+// Store remembers the responses of completed requests by key, together with a
+// fingerprint of the request that produced them. A key reused for a different
+// request is rejected instead of returning the old response.
+type Store struct {
+	mu       sync.Mutex
+	entries  map[string]*entry
+	inflight map[string]chan struct{}
+
+	ttl   time.Duration
+	clock clock.Clock
+
+	// journal gets a line per saved response when the store was opened on a
+	// file; lines counts the lines written to it since it was last compacted.
+	path    string
+	journal *os.File
+	lines   int
+
+	// nextPrune is when expired entries are dropped next
+	nextPrune time.Time
+}
+
+// Option configures a Store.
+type Option func(*Store)
+
+// WithTTL sets how long responses are kept.
+func WithTTL(ttl time.Duration) Option {
+	return func(s *Store) {
+		s.ttl = ttl
+	}
+}
+
+// WithClock sets the clock used for expiry.
+func WithClock(c clock.Clock) Option {
+	return func(s *Store) {
+		s.clock = c
+	}
+}
+
+// New returns an in-memory store.
+func New(opts ...Option) *Store {
+	s := &Store{
+		entries:  make(map[string]*entry),
+		inflight: make(map[string]chan struct{}),
+		ttl:      DefaultTTL,
+		clock:    clock.Real(),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Open returns a store that keeps its responses across restarts in a journal at
+// path, one JSON object per line. The unexpired responses of the journal are
+// loaded, later lines replacing earlier ones for the same key, and the journal
+// is compacted to them before new responses are appended. It is compacted again
+// once most of its lines have expired.
+func Open(path string, opts ...Option) (*Store, error) {
+	s := New(opts...)
+	s.path = path
+	if err := s.load(); err != nil {
+		return nil, fmt.Errorf("load %s: %w", path, err)
+	}
+	if err := s.compact(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// load reads the unexpired entries of the journal. A last line without a line
+// break is the remainder of a write cut short by a crash and is skipped.
+func (s *Store) load() error {
+	f, err := os.Open(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	now := s.clock.Now()
+	r := bufio.NewReader(f)
+	for n := 1; ; n++ {
+		line, err := r.ReadBytes('\n')
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var e entry
+		if err := json.Unmarshal(line, &e); err != nil {
+			return fmt.Errorf("line %d: %w", n, err)
+		}
+		if now.Before(e.ExpiresAt) {
+			s.entries[e.Key] = &e
+		} else {
+			delete(s.entries, e.Key)
+		}
+	}
+}
+
+// Close closes the journal, if any.
+func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.journal == nil {
+		return nil
+	}
+	err := s.journal.Close()
+	s.journal, s.path = nil, ""
+	return err
+}
+
+// Fingerprint returns a digest of req that is stable across processes.
+func Fingerprint(req proto.Message) (string, error) {
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Lookup returns the response saved for key. It fails with InvalidArgument when
+// key was saved for a request with another fingerprint.
+func (s *Store) Lookup(key, fingerprint string) (proto.Message, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lookup(key, fingerprint)
+}
+
+// lookup is Lookup for callers that hold s.mu.
+func (s *Store) lookup(key, fingerprint string) (proto.Message, bool, error) {
+	e, ok := s.entries[key]
+	if !ok || !s.clock.Now().Before(e.ExpiresAt) {
+		return nil, false, nil
+	}
+	if e.Fingerprint != fingerprint {
+		return nil, false, status.Error(codes.InvalidArgument, "request id was already used with different parameters")
+	}
+	if e.resp != nil {
+		return e.resp, true, nil
+	}
+
+	var saved anypb.Any
+	if err := proto.Unmarshal(e.Response, &saved); err != nil {
+		return nil, false, status.Errorf(codes.Internal, "failed to read saved response: %v", err)
+	}
+	resp, err := saved.UnmarshalNew()
+	if err != nil {
+		return nil, false, status.Errorf(codes.Internal, "failed to read saved response: %v", err)
+	}
+	e.resp = resp
+	return resp, true, nil
+}
+
+// Save remembers resp for key until the TTL passes. A response saved again under
+// the same key replaces the previous one.
+func (s *Store) Save(key, fingerprint string, resp proto.Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.save(key, fingerprint, resp)
+}
+
+// save is Save for callers that hold s.mu.
+func (s *Store) save(key, fingerprint string, resp proto.Message) error {
+	e, err := s.put(key, fingerprint, resp)
+	if err != nil {
+		return err
+	}
+	return s.persist(e)
+}
+
+// put adds an entry without writing it to the journal. Expired entries are
+// dropped on the way, at most once a minute. Callers must hold s.mu.
+func (s *Store) put(key, fingerprint string, resp proto.Message) (*entry, error) {
+	saved, err := anypb.New(resp)
+	if err != nil {
+		return nil, err
+	}
+	data, err := proto.Marshal(saved)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.clock.Now()
+	if !now.Before(s.nextPrune) {
+		for k, e := range s.entries {
+			if !now.Before(e.ExpiresAt) {
+				delete(s.entries, k)
+			}
+		}
+		s.nextPrune = now.Add(pruneInterval)
+		if s.lines > 2*len(s.entries) {
+			if err := s.compact(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	e := &entry{Key: key, Fingerprint: fingerprint, Response: data, ExpiresAt: now.Add(s.ttl), resp: resp}
+	s.entries[key] = e
+	return e, nil
+}
+
+// Replace swaps the response saved for key and keeps the fingerprint of the
+// request that produced it. It does nothing when key is not saved.
+func (s *Store) Replace(key string, resp proto.Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[key]
+	if !ok {
+		return nil
+	}
+	return s.save(key, e.Fingerprint, resp)
+}
+
+// Do runs fn once for key. Retries get the saved response, and a retry that
+// arrives while fn is still running waits for it. Failed calls are not saved, so
+// they can be retried with the same key.
+func (s *Store) Do(key, fingerprint string, fn func() (proto.Message, error)) (proto.Message, error) {
+	s.mu.Lock()
+	for {
+		resp, ok, err := s.lookup(key, fingerprint)
+		if err != nil || ok {
+			s.mu.Unlock()
+			return resp, err
+		}
+		done, running := s.inflight[key]
+		if !running {
+			break
+		}
+		s.mu.Unlock()
+		<-done
+		s.mu.Lock()
+	}
+	done := make(chan struct{})
+	s.inflight[key] = done
+	s.mu.Unlock()
+
+	resp, err := fn()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.inflight, key)
+	close(done)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.save(key, fingerprint, resp); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to save response: %v", err)
+	}
+	return resp, nil
+}
+
+// DoAll is Do for a batch of keys, which must be distinct. The keys whose
+// response is saved get it back; the others are claimed together, so retries of
+// any of them wait, and passed to fn by index. fn returns their responses in the
+// same order. If fn fails only the responses it returned anyway are saved, for
+// requests it applied before failing. A key used with a different fingerprint
+// fails the batch before fn runs.
+func (s *Store) DoAll(keys, fingerprints []string, fn func(pending []int) ([]proto.Message, error)) ([]proto.Message, error) {
+	resps := make([]proto.Message, len(keys))
+	var pending []int
+
+	s.mu.Lock()
+	for {
+		pending = pending[:0]
+		var wait chan struct{}
+		for i, key := range keys {
+			resp, ok, err := s.lookup(key, fingerprints[i])
+			if err != nil {
+				s.mu.Unlock()
+				return nil, err
+			}
+			if ok {
+				resps[i] = resp
+				continue
+			}
+			if done, running := s.inflight[key]; running {
+				wait = done
+				break
+			}
+			pending = append(pending, i)
+		}
+		if wait == nil {
+			break
+		}
+		s.mu.Unlock()
+		<-wait
+		s.mu.Lock()
+	}
+	done := make(chan struct{})
+	for _, i := range pending {
+		s.inflight[keys[i]] = done
+	}
+	s.mu.Unlock()
+
+	var out []proto.Message
+	var err error
+	if len(pending) > 0 {
+		out, err = fn(pending)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, i := range pending {
+		delete(s.inflight, keys[i])
+	}
+	close(done)
+	if err == nil && len(out) != len(pending) {
+		err = status.Errorf(codes.Internal, "got %d responses for %d requests", len(out), len(pending))
+	}
+
+	var saved []*entry
+	for j, i := range pending {
+		if j >= len(out) || out[j] == nil {
+			continue
+		}
+		resps[i] = out[j]
+		e, err := s.put(keys[i], fingerprints[i], out[j])
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to save response: %v", err)
+		}
+		saved = append(saved, e)
+	}
+	if err := s.persist(saved...); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to save response: %v", err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return resps, nil
+}
+
+// persist appends entries to the journal in a single write. Callers must hold
+// s.mu.
+func (s *Store) persist(entries ...*entry) error {
+	if s.journal == nil || len(entries) == 0 {
+		return nil
+	}
+	data, err := encode(entries)
+	if err != nil {
+		return err
+	}
+	if _, err := s.journal.Write(data); err != nil {
+		return err
+	}
+	s.lines += len(entries)
+	return nil
+}
+
+// compact rewrites the journal with the live entries only, through a temporary
+// file, so a crash never leaves a truncated one behind, and keeps the new file
+// open for appending. Callers must hold s.mu.
+func (s *Store) compact() error {
+	if s.path == "" {
+		return nil
+	}
+	entries := make([]*entry, 0, len(s.entries))
+	for _, e := range s.entries {
+		entries = append(entries, e)
+	}
+	data, err := encode(entries)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if s.journal != nil {
+		s.journal.Close()
+	}
+	s.journal = tmp
+	s.lines = len(entries)
+	return nil
+}
+
+// encode returns entries as JSON lines.
+func encode(entries []*entry) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, e := range entries {
+		if err := enc.Encode(e); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,334 @@
+package idempotency
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	userv1 "github.com/galadeat/bank-sim/api/proto/user/v1"
+	"github.com/galadeat/bank-sim/pkg/clock"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+var start = time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+
+func fingerprint(t *testing.T, req proto.Message) string {
+	t.Helper()
+	fp, err := Fingerprint(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return fp
+}
+
+// journal returns the keys of the lines of the journal at path.
+func journal(t *testing.T, path string) []string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var keys []string
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		if line == "" {
+			continue
+		}
+		var e entry
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			t.Fatalf("bad journal line %q: %v", line, err)
+		}
+		keys = append(keys, e.Key)
+	}
+	return keys
+}
+
+func TestStore(t *testing.T) {
+	req := &userv1.CreateUserRequest{Login: "alice", Email: "alice@example.com", RequestId: "r-1"}
+	resp := &userv1.CreateUserResponse{Id: "user-1"}
+
+	t.Run("returns the saved response", func(t *testing.T) {
+		s := New()
+		assert.NoError(t, s.Save("k", fingerprint(t, req), resp))
+
+		got, ok, err := s.Lookup("k", fingerprint(t, req))
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.True(t, proto.Equal(resp, got))
+	})
+
+	t.Run("rejects a reused key with another payload", func(t *testing.T) {
+		s := New()
+		assert.NoError(t, s.Save("k", fingerprint(t, req), resp))
+
+		other := &userv1.CreateUserRequest{Login: "bob", Email: "bob@example.com", RequestId: "r-1"}
+		_, ok, err := s.Lookup("k", fingerprint(t, other))
+		assert.False(t, ok)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("forgets responses after the ttl", func(t *testing.T) {
+		c := clock.NewManual(start)
+		s := New(WithTTL(time.Hour), WithClock(c))
+		assert.NoError(t, s.Save("k", fingerprint(t, req), resp))
+
+		c.Advance(time.Hour)
+		_, ok, err := s.Lookup("k", fingerprint(t, req))
+		assert.NoError(t, err)
+		assert.False(t, ok)
+
+		assert.NoError(t, s.Save("other", fingerprint(t, req), resp))
+		assert.Len(t, s.entries, 1)
+	})
+
+	t.Run("survives a restart", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "idempotency.json")
+		s, err := Open(path)
+		assert.NoError(t, err)
+		assert.NoError(t, s.Save("k", fingerprint(t, req), resp))
+		assert.NoError(t, s.Replace("k", &userv1.CreateUserResponse{Id: "user-2"}))
+		assert.Equal(t, []string{"k", "k"}, journal(t, path))
+		assert.NoError(t, s.Close())
+
+		reopened, err := Open(path)
+		assert.NoError(t, err)
+		defer reopened.Close()
+		got, ok, err := reopened.Lookup("k", fingerprint(t, req))
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, "user-2", got.(*userv1.CreateUserResponse).Id)
+		assert.Equal(t, []string{"k"}, journal(t, path), "compacted on open")
+
+		other := &userv1.CreateUserRequest{Login: "bob", Email: "bob@example.com", RequestId: "r-1"}
+		_, _, err = reopened.Lookup("k", fingerprint(t, other))
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("drops expired and cut short lines on open", func(t *testing.T) {
+		c := clock.NewManual(start)
+		path := filepath.Join(t.TempDir(), "idempotency.json")
+		s, err := Open(path, WithTTL(time.Hour), WithClock(c))
+		assert.NoError(t, err)
+		assert.NoError(t, s.Save("old", fingerprint(t, req), resp))
+		c.Advance(30 * time.Minute)
+		assert.NoError(t, s.Save("new", fingerprint(t, req), resp))
+		assert.NoError(t, s.Close())
+
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+		assert.NoError(t, err)
+		_, err = f.WriteString(`{"key":"torn","finger`)
+		assert.NoError(t, err)
+		assert.NoError(t, f.Close())
+
+		c.Advance(45 * time.Minute)
+		reopened, err := Open(path, WithTTL(time.Hour), WithClock(c))
+		assert.NoError(t, err)
+		defer reopened.Close()
+		assert.Equal(t, []string{"new"}, journal(t, path))
+		_, ok, _ := reopened.Lookup("old", fingerprint(t, req))
+		assert.False(t, ok)
+	})
+
+	t.Run("rejects a corrupt journal", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "idempotency.json")
+		assert.NoError(t, os.WriteFile(path, []byte("not json\n"), 0o600))
+		_, err := Open(path)
+		assert.ErrorContains(t, err, "line 1")
+	})
+
+	t.Run("compacts the journal", func(t *testing.T) {
+		c := clock.NewManual(start)
+		path := filepath.Join(t.TempDir(), "idempotency.json")
+		s, err := Open(path, WithTTL(time.Hour), WithClock(c))
+		assert.NoError(t, err)
+		defer s.Close()
+		for _, key := range []string{"a", "b", "c"} {
+			assert.NoError(t, s.Save(key, fingerprint(t, req), resp))
+		}
+
+		c.Advance(time.Hour)
+		assert.NoError(t, s.Save("d", fingerprint(t, req), resp))
+		assert.Equal(t, []string{"d"}, journal(t, path))
+		assert.NoError(t, s.Save("e", fingerprint(t, req), resp))
+		assert.Equal(t, []string{"d", "e"}, journal(t, path))
+	})
+
+	t.Run("replace keeps the fingerprint", func(t *testing.T) {
+		s := New()
+		assert.NoError(t, s.Save("k", fingerprint(t, req), resp))
+		assert.NoError(t, s.Replace("k", &userv1.CreateUserResponse{Id: "user-2"}))
+		assert.NoError(t, s.Replace("missing", resp))
+
+		got, ok, err := s.Lookup("k", fingerprint(t, req))
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, "user-2", got.(*userv1.CreateUserResponse).Id)
+		_, ok, _ = s.Lookup("missing", fingerprint(t, req))
+		assert.False(t, ok)
+	})
+}
+
+func TestDo(t *testing.T) {
+	req := &userv1.CreateUserRequest{Login: "alice", Email: "alice@example.com", RequestId: "r-1"}
+
+	t.Run("runs concurrent retries once", func(t *testing.T) {
+		s := New()
+		var calls atomic.Int32
+		release := make(chan struct{})
+
+		var wg sync.WaitGroup
+		results := make([]proto.Message, 5)
+		for i := range results {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				results[i], _ = s.Do("k", fingerprint(t, req), func() (proto.Message, error) {
+					calls.Add(1)
+					<-release
+					return &userv1.CreateUserResponse{Id: "user-1"}, nil
+				})
+			}()
+		}
+		time.Sleep(10 * time.Millisecond)
+		close(release)
+		wg.Wait()
+
+		assert.Equal(t, int32(1), calls.Load())
+		for _, r := range results {
+			assert.Equal(t, "user-1", r.(*userv1.CreateUserResponse).Id)
+		}
+	})
+
+	t.Run("failures are not saved", func(t *testing.T) {
+		s := New()
+		_, err := s.Do("k", fingerprint(t, req), func() (proto.Message, error) {
+			return nil, errors.New("unavailable")
+		})
+		assert.Error(t, err)
+
+		resp, err := s.Do("k", fingerprint(t, req), func() (proto.Message, error) {
+			return &userv1.CreateUserResponse{Id: "user-1"}, nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "user-1", resp.(*userv1.CreateUserResponse).Id)
+	})
+}
+
+func TestDoAll(t *testing.T) {
+	reqs := []proto.Message{
+		&userv1.CreateUserRequest{Login: "alice", RequestId: "r-1"},
+		&userv1.CreateUserRequest{Login: "bob", RequestId: "r-2"},
+		&userv1.CreateUserRequest{Login: "carol", RequestId: "r-3"},
+	}
+	keys := []string{"k1", "k2", "k3"}
+	fps := make([]string, len(reqs))
+	for i, req := range reqs {
+		fps[i] = fingerprint(t, req)
+	}
+	created := func(pending []int) ([]proto.Message, error) {
+		out := make([]proto.Message, len(pending))
+		for j, i := range pending {
+			out[j] = &userv1.CreateUserResponse{Id: keys[i]}
+		}
+		return out, nil
+	}
+
+	t.Run("runs only keys without a saved response", func(t *testing.T) {
+		s := New()
+		assert.NoError(t, s.Save("k2", fps[1], &userv1.CreateUserResponse{Id: "saved"}))
+
+		var got []int
+		resps, err := s.DoAll(keys, fps, func(pending []int) ([]proto.Message, error) {
+			got = pending
+			return created(pending)
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []int{0, 2}, got)
+		assert.Equal(t, "k1", resps[0].(*userv1.CreateUserResponse).Id)
+		assert.Equal(t, "saved", resps[1].(*userv1.CreateUserResponse).Id)
+		assert.Equal(t, "k3", resps[2].(*userv1.CreateUserResponse).Id)
+
+		saved, ok, _ := s.Lookup("k3", fps[2])
+		assert.True(t, ok)
+		assert.Equal(t, "k3", saved.(*userv1.CreateUserResponse).Id)
+	})
+
+	t.Run("failures save nothing", func(t *testing.T) {
+		s := New()
+		_, err := s.DoAll(keys, fps, func([]int) ([]proto.Message, error) {
+			return nil, status.Error(codes.FailedPrecondition, "insufficient balance")
+		})
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+		_, ok, _ := s.Lookup("k1", fps[0])
+		assert.False(t, ok)
+	})
+
+	t.Run("responses returned with a failure are saved", func(t *testing.T) {
+		s := New()
+		_, err := s.DoAll(keys, fps, func(pending []int) ([]proto.Message, error) {
+			return []proto.Message{&userv1.CreateUserResponse{Id: "k1"}}, status.Error(codes.Internal, "failed halfway")
+		})
+		assert.Equal(t, codes.Internal, status.Code(err))
+
+		_, ok, _ := s.Lookup("k1", fps[0])
+		assert.True(t, ok)
+		_, ok, _ = s.Lookup("k2", fps[1])
+		assert.False(t, ok)
+	})
+
+	t.Run("reused key fails before running", func(t *testing.T) {
+		s := New()
+		assert.NoError(t, s.Save("k3", fps[0], &userv1.CreateUserResponse{Id: "other"}))
+
+		_, err := s.DoAll(keys, fps, func([]int) ([]proto.Message, error) {
+			t.Fatal("fn must not run")
+			return nil, nil
+		})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("waits for a running retry", func(t *testing.T) {
+		s := New()
+		release := make(chan struct{})
+		go s.Do("k2", fps[1], func() (proto.Message, error) {
+			<-release
+			return &userv1.CreateUserResponse{Id: "single"}, nil
+		})
+		time.Sleep(10 * time.Millisecond)
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			close(release)
+		}()
+
+		resps, err := s.DoAll(keys, fps, created)
+		assert.NoError(t, err)
+		assert.Equal(t, "single", resps[1].(*userv1.CreateUserResponse).Id)
+	})
+
+	t.Run("saves to the store file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "idempotency.json")
+		s, err := Open(path)
+		assert.NoError(t, err)
+		_, err = s.DoAll(keys, fps, created)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, keys, journal(t, path))
+		assert.NoError(t, s.Close())
+
+		reopened, err := Open(path)
+		assert.NoError(t, err)
+		defer reopened.Close()
+		for i, key := range keys {
+			_, ok, _ := reopened.Lookup(key, fps[i])
+			assert.True(t, ok)
+		}
+	})
+}
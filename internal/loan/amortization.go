@@ -0,0 +1,105 @@
+package loan
+
+import (
+	"math/big"
+	"time"
+
+	commonv1 "github.com/galadeat/bank-sim/api/proto/common/v1"
+	loanv1 "github.com/galadeat/bank-sim/api/proto/loan/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	nanosPerUnit = 1_000_000_000
+	// installments are rounded to cents; the last one absorbs the rounding remainder
+	nanosPerCent = 10_000_000
+	bpsDivisor   = 10_000
+	monthsInYear = 12
+)
+
+// schedule builds the repayment plan for a loan disbursed at start. Installments
+// fall due monthly on the disbursement day.
+func schedule(principal *commonv1.Money, rateBps int64, months int32, typ loanv1.RepaymentType, start time.Time) []*loanv1.Installment {
+	currency := principal.Currency
+	remaining := toNanos(principal)
+	monthlyRate := new(big.Rat).SetFrac64(rateBps, bpsDivisor*monthsInYear)
+
+	var payment, principalPart int64
+	switch typ {
+	case loanv1.RepaymentType_REPAYMENT_TYPE_LINEAR:
+		principalPart = roundCents(new(big.Rat).SetFrac64(remaining, int64(months)))
+	default:
+		payment = annuityPayment(remaining, monthlyRate, months)
+	}
+
+	installments := make([]*loanv1.Installment, 0, months)
+	for n := int32(1); n <= months; n++ {
+		interest := roundCents(new(big.Rat).Mul(new(big.Rat).SetInt64(remaining), monthlyRate))
+
+		var part int64
+		switch {
+		case n == months:
+			part = remaining
+		case typ == loanv1.RepaymentType_REPAYMENT_TYPE_LINEAR:
+			part = principalPart
+		default:
+			part = payment - interest
+		}
+		if part > remaining {
+			part = remaining
+		}
+		remaining -= part
+
+		installments = append(installments, &loanv1.Installment{
+			Number:             n,
+			DueAt:              timestamppb.New(start.AddDate(0, int(n), 0)),
+			Principal:          fromNanos(part, currency),
+			Interest:           fromNanos(interest, currency),
+			Total:              fromNanos(part+interest, currency),
+			RemainingPrincipal: fromNanos(remaining, currency),
+			Status:             loanv1.InstallmentStatus_INSTALLMENT_STATUS_SCHEDULED,
+		})
+	}
+	return installments
+}
+
+// annuityPayment returns the fixed monthly payment P*r / (1 - (1+r)^-n), rounded to
+// cents. With a zero rate the principal is split evenly.
+func annuityPayment(principal int64, monthlyRate *big.Rat, months int32) int64 {
+	if monthlyRate.Sign() == 0 {
+		return roundCents(new(big.Rat).SetFrac64(principal, int64(months)))
+	}
+
+	// (1+r)^n
+	growth := new(big.Rat).SetInt64(1)
+	base := new(big.Rat).Add(big.NewRat(1, 1), monthlyRate)
+	for i := int32(0); i < months; i++ {
+		growth.Mul(growth, base)
+	}
+
+	// P*r*(1+r)^n / ((1+r)^n - 1) is the same formula without negative powers
+	num := new(big.Rat).Mul(new(big.Rat).SetInt64(principal), monthlyRate)
+	num.Mul(num, growth)
+	den := new(big.Rat).Sub(growth, big.NewRat(1, 1))
+	return roundCents(num.Quo(num, den))
+}
+
+// roundCents rounds an amount of nanos half-up to a whole number of cents.
+func roundCents(nanos *big.Rat) int64 {
+	cents := new(big.Rat).Quo(nanos, big.NewRat(nanosPerCent, 1))
+	cents.Add(cents, big.NewRat(1, 2))
+	whole := new(big.Int).Quo(cents.Num(), cents.Denom())
+	return whole.Int64() * nanosPerCent
+}
+
+func toNanos(m *commonv1.Money) int64 {
+	return m.GetUnits()*nanosPerUnit + int64(m.GetNanos())
+}
+
+func fromNanos(nanos int64, currency string) *commonv1.Money {
+	return &commonv1.Money{
+		Currency: currency,
+		Units:    nanos / nanosPerUnit,
+		Nanos:    int32(nanos % nanosPerUnit),
+	}
+}
@@ -0,0 +1,92 @@
+package loan
+
+import (
+	"testing"
+	"time"
+
+	commonv1 "github.com/galadeat/bank-sim/api/proto/common/v1"
+	loanv1 "github.com/galadeat/bank-sim/api/proto/loan/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+var disbursed = time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+
+func usd(units int64, nanos int32) *commonv1.Money {
+	return &commonv1.Money{Currency: "USD", Units: units, Nanos: nanos}
+}
+
+func assertMoney(t *testing.T, want, got *commonv1.Money) {
+	t.Helper()
+	if !proto.Equal(want, got) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestScheduleAnnuity(t *testing.T) {
+	// 12 000 at 12% a year over 12 months pays 1 066.19 a month
+	plan := schedule(usd(12000, 0), 1200, 12, loanv1.RepaymentType_REPAYMENT_TYPE_ANNUITY, disbursed)
+	if len(plan) != 12 {
+		t.Fatalf("expected 12 installments, got %d", len(plan))
+	}
+
+	first := plan[0]
+	assertMoney(t, usd(1066, 190_000_000), first.Total)
+	assertMoney(t, usd(120, 0), first.Interest)
+	assertMoney(t, usd(946, 190_000_000), first.Principal)
+	if !first.DueAt.AsTime().Equal(time.Date(2025, 2, 15, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected first due date %v", first.DueAt.AsTime())
+	}
+
+	var principal int64
+	for _, inst := range plan[:11] {
+		assertMoney(t, usd(1066, 190_000_000), inst.Total)
+	}
+	for _, inst := range plan {
+		principal += toNanos(inst.Principal)
+	}
+	if principal != 12000*nanosPerUnit {
+		t.Errorf("expected principal parts to add up to 12000, got %d nanos", principal)
+	}
+	assertMoney(t, usd(0, 0), plan[11].RemainingPrincipal)
+}
+
+func TestScheduleLinear(t *testing.T) {
+	plan := schedule(usd(1200, 0), 1200, 12, loanv1.RepaymentType_REPAYMENT_TYPE_LINEAR, disbursed)
+
+	assertMoney(t, usd(100, 0), plan[0].Principal)
+	assertMoney(t, usd(12, 0), plan[0].Interest)
+	assertMoney(t, usd(112, 0), plan[0].Total)
+
+	assertMoney(t, usd(100, 0), plan[11].Principal)
+	assertMoney(t, usd(1, 0), plan[11].Interest)
+	assertMoney(t, usd(0, 0), plan[11].RemainingPrincipal)
+}
+
+func TestScheduleRounding(t *testing.T) {
+	tests := []struct {
+		name string
+		typ  loanv1.RepaymentType
+		rate int64
+	}{
+		{name: "annuity", typ: loanv1.RepaymentType_REPAYMENT_TYPE_ANNUITY, rate: 799},
+		{name: "linear", typ: loanv1.RepaymentType_REPAYMENT_TYPE_LINEAR, rate: 799},
+		{name: "zero rate annuity", typ: loanv1.RepaymentType_REPAYMENT_TYPE_ANNUITY, rate: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := schedule(usd(1000, 0), tt.rate, 7, tt.typ, disbursed)
+
+			var principal int64
+			for _, inst := range plan {
+				principal += toNanos(inst.Principal)
+				if toNanos(inst.Total)%nanosPerCent != 0 {
+					t.Errorf("installment %d is not a whole number of cents: %v", inst.Number, inst.Total)
+				}
+			}
+			if principal != 1000*nanosPerUnit {
+				t.Errorf("expected principal parts to add up to 1000, got %d nanos", principal)
+			}
+		})
+	}
+}
@@ -0,0 +1,365 @@
+package loan
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	accountv2 "github.com/galadeat/bank-sim/api/proto/account/v2"
+	commonv1 "github.com/galadeat/bank-sim/api/proto/common/v1"
+	loanv1 "github.com/galadeat/bank-sim/api/proto/loan/v1"
+	userv1 "github.com/galadeat/bank-sim/api/proto/user/v1"
+	"github.com/galadeat/bank-sim/pkg/clock"
+	"github.com/gofrs/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	maxTermMonths = 600
+	// maxPrincipalUnits keeps schedules within int64 nanos.
+	maxPrincipalUnits = 1_000_000_000
+)
+
+// Service originates loans, disburses them into an account and collects the
+// installments from that account when they fall due.
+type Service struct {
+	loanv1.UnimplementedLoanServer
+	mu           sync.RWMutex
+	loans        map[string]*loanv1.LoanInfo
+	originations map[string]*loanv1.OriginateLoanResponse
+
+	userClient    userv1.UserClient
+	accountClient accountv2.AccountClient
+	clock         clock.Clock
+}
+
+// Option configures a Service.
+type Option func(*Service)
+
+// WithClock sets the clock used for due dates and collection.
+func WithClock(c clock.Clock) Option {
+	return func(s *Service) {
+		s.clock = c
+	}
+}
+
+// New is the constructor
+func New(userClient userv1.UserClient, accountClient accountv2.AccountClient, opts ...Option) *Service {
+	s := &Service{
+		loans:         make(map[string]*loanv1.LoanInfo),
+		originations:  make(map[string]*loanv1.OriginateLoanResponse),
+		userClient:    userClient,
+		accountClient: accountClient,
+		clock:         clock.Real(),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// OriginateLoan is the realization of the rpc method. The principal is deposited
+// into the linked account, which must belong to the borrower.
+func (s *Service) OriginateLoan(ctx context.Context, req *loanv1.OriginateLoanRequest) (*loanv1.OriginateLoanResponse, error) {
+	select {
+	case <-ctx.Done():
+		return nil, status.Error(codes.Canceled, "request canceled by client")
+	default:
+	}
+
+	if err := validateOrigination(req); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	resp, ok := s.originations[req.RequestId]
+	s.mu.RUnlock()
+	if ok {
+		return resp, nil
+	}
+
+	if _, err := s.userClient.GetUser(ctx, &userv1.GetUserRequest{Id: req.UserId}); err != nil {
+		return nil, fromRemote(err, "UserService")
+	}
+
+	accResp, err := s.accountClient.GetAccount(ctx, &accountv2.GetAccountRequest{Id: req.AccountId})
+	if err != nil {
+		return nil, fromRemote(err, "AccountService")
+	}
+	if accResp.GetAccount().GetOwnerId() != req.UserId {
+		return nil, status.Error(codes.PermissionDenied, "account does not belong to the user")
+	}
+
+	// the deposit request id is derived from the origination request id, so a retried
+	// origination never disburses twice
+	_, err = s.accountClient.Deposit(ctx, &accountv2.DepositRequest{
+		AccountId: req.AccountId,
+		Amount:    req.Principal,
+		RequestId: fmt.Sprintf("loan:%s:disburse", req.RequestId),
+	})
+	if err != nil {
+		return nil, fromRemote(err, "AccountService")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if resp, ok := s.originations[req.RequestId]; ok {
+		return resp, nil
+	}
+
+	id, err := uuid.NewV4()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error while generating loan id: %v", err)
+	}
+
+	now := s.clock.Now()
+	typ := req.RepaymentType
+	if typ == loanv1.RepaymentType_REPAYMENT_TYPE_UNSPECIFIED {
+		typ = loanv1.RepaymentType_REPAYMENT_TYPE_ANNUITY
+	}
+
+	loan := &loanv1.LoanInfo{
+		Id:                   id.String(),
+		UserId:               req.UserId,
+		AccountId:            req.AccountId,
+		Principal:            req.Principal,
+		AnnualRateBps:        req.AnnualRateBps,
+		TermMonths:           req.TermMonths,
+		RepaymentType:        typ,
+		Status:               loanv1.LoanStatus_LOAN_STATUS_ACTIVE,
+		Schedule:             schedule(req.Principal, req.AnnualRateBps, req.TermMonths, typ, now),
+		DisbursedAt:          timestamppb.New(now),
+		OutstandingPrincipal: req.Principal,
+	}
+	s.loans[loan.Id] = loan
+
+	log.Printf("loan originated: id=%s, user_id=%s, account_id=%s, principal=%v, request_id=%s", loan.Id, loan.UserId, loan.AccountId, loan.Principal, req.RequestId)
+
+	resp = &loanv1.OriginateLoanResponse{Loan: loan}
+	s.originations[req.RequestId] = resp
+	return resp, nil
+}
+
+// GetLoan is the realization of the rpc method
+func (s *Service) GetLoan(ctx context.Context, req *loanv1.GetLoanRequest) (*loanv1.GetLoanResponse, error) {
+	select {
+	case <-ctx.Done():
+		return nil, status.Error(codes.Canceled, "request canceled by client")
+	default:
+	}
+
+	if req.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "loan id is required")
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	loan, ok := s.loans[req.Id]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "loan not found")
+	}
+	return &loanv1.GetLoanResponse{Loan: proto.Clone(loan).(*loanv1.LoanInfo)}, nil
+}
+
+// ListLoans is the realization of the rpc method
+func (s *Service) ListLoans(ctx context.Context, req *loanv1.ListLoansRequest) (*loanv1.ListLoansResponse, error) {
+	select {
+	case <-ctx.Done():
+		return nil, status.Error(codes.Canceled, "request canceled by client")
+	default:
+	}
+
+	if req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user id is required")
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var loans []*loanv1.LoanInfo
+	for _, loan := range s.loans {
+		if loan.UserId == req.UserId {
+			loans = append(loans, proto.Clone(loan).(*loanv1.LoanInfo))
+		}
+	}
+	sort.Slice(loans, func(i, j int) bool {
+		return loans[i].DisbursedAt.AsTime().Before(loans[j].DisbursedAt.AsTime())
+	})
+	return &loanv1.ListLoansResponse{Loans: loans}, nil
+}
+
+// RunCollector collects due installments once a day until ctx is done.
+func (s *Service) RunCollector(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case t := <-s.clock.After(24 * time.Hour):
+			s.CollectDue(ctx, t)
+		}
+	}
+}
+
+// due is an installment that should be collected, captured outside the lock.
+type due struct {
+	loanID    string
+	accountID string
+	number    int32
+	amount    *commonv1.Money
+}
+
+// CollectDue withdraws every installment that is due at now from the linked
+// account, oldest first. An installment that cannot be paid because the account
+// lacks funds is marked overdue and the loan becomes delinquent; it is retried on
+// the next run with the same request id, and later installments of that loan wait
+// until it is paid. A withdrawal held for review moves no money: the installment
+// keeps its status and is retried the same way, which collects it once the
+// review is approved.
+func (s *Service) CollectDue(ctx context.Context, now time.Time) {
+	stopped := make(map[string]bool)
+	for {
+		pending := s.dueInstallments(now, stopped)
+		if len(pending) == 0 {
+			return
+		}
+		for _, d := range pending {
+			resp, err := s.accountClient.Withdraw(ctx, &accountv2.WithdrawRequest{
+				AccountId: d.accountID,
+				Amount:    d.amount,
+				RequestId: fmt.Sprintf("loan:%s:installment:%d", d.loanID, d.number),
+			})
+			if err != nil || held(resp.GetReview()) {
+				stopped[d.loanID] = true
+			}
+			s.settle(d, now, resp, err)
+		}
+	}
+}
+
+// dueInstallments returns the oldest unpaid due installment of every loan that is
+// not in skip.
+func (s *Service) dueInstallments(now time.Time, skip map[string]bool) []due {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ids := make([]string, 0, len(s.loans))
+	for id := range s.loans {
+		if !skip[id] {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	var out []due
+	for _, id := range ids {
+		loan := s.loans[id]
+		for _, inst := range loan.Schedule {
+			if inst.Status == loanv1.InstallmentStatus_INSTALLMENT_STATUS_PAID {
+				continue
+			}
+			if inst.DueAt.AsTime().After(now) {
+				break
+			}
+			out = append(out, due{loanID: loan.Id, accountID: loan.AccountId, number: inst.Number, amount: proto.Clone(inst.Total).(*commonv1.Money)})
+			break
+		}
+	}
+	return out
+}
+
+// settle records the outcome of a collection attempt.
+func (s *Service) settle(d due, now time.Time, resp *accountv2.WithdrawResponse, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	loan := s.loans[d.loanID]
+	inst := loan.Schedule[d.number-1]
+
+	switch {
+	case err == nil && held(resp.GetReview()):
+		log.Printf("installment held for review: loan_id=%s, number=%d, review_id=%s, review_status=%s",
+			loan.Id, inst.Number, resp.Review.Id, resp.Review.Status)
+		return
+	case err == nil:
+		inst.Status = loanv1.InstallmentStatus_INSTALLMENT_STATUS_PAID
+		inst.PaidAt = timestamppb.New(now)
+		loan.OutstandingPrincipal = inst.RemainingPrincipal
+		log.Printf("installment collected: loan_id=%s, number=%d, amount=%v", loan.Id, inst.Number, inst.Total)
+	case status.Code(err) == codes.FailedPrecondition:
+		inst.Status = loanv1.InstallmentStatus_INSTALLMENT_STATUS_OVERDUE
+		log.Printf("installment overdue: loan_id=%s, number=%d, err=%v", loan.Id, inst.Number, err)
+	default:
+		// the account service is unavailable or similar; try again on the next run
+		log.Printf("installment collection failed: loan_id=%s, number=%d, err=%v", loan.Id, inst.Number, err)
+		return
+	}
+
+	updateDelinquency(loan, now)
+}
+
+// held reports whether a withdrawal was held for review and has not been
+// approved, so no money has moved.
+func held(review *accountv2.Review) bool {
+	return review != nil && review.Status != accountv2.ReviewStatus_REVIEW_STATUS_APPROVED
+}
+
+// updateDelinquency derives the loan status and days past due from its schedule.
+func updateDelinquency(loan *loanv1.LoanInfo, now time.Time) {
+	loan.DaysPastDue = 0
+	loan.Status = loanv1.LoanStatus_LOAN_STATUS_PAID_OFF
+	for _, inst := range loan.Schedule {
+		if inst.Status == loanv1.InstallmentStatus_INSTALLMENT_STATUS_PAID {
+			continue
+		}
+		loan.Status = loanv1.LoanStatus_LOAN_STATUS_ACTIVE
+		if inst.Status == loanv1.InstallmentStatus_INSTALLMENT_STATUS_OVERDUE {
+			loan.Status = loanv1.LoanStatus_LOAN_STATUS_DELINQUENT
+			loan.DaysPastDue = int32(now.Sub(inst.DueAt.AsTime()) / (24 * time.Hour))
+		}
+		return
+	}
+}
+
+func validateOrigination(req *loanv1.OriginateLoanRequest) error {
+	if req.UserId == "" {
+		return status.Error(codes.InvalidArgument, "user id is required")
+	}
+	if req.AccountId == "" {
+		return status.Error(codes.InvalidArgument, "account id is required")
+	}
+	if req.RequestId == "" {
+		return status.Error(codes.InvalidArgument, "request id is required")
+	}
+	if req.Principal == nil || req.Principal.Currency == "" {
+		return status.Error(codes.InvalidArgument, "principal is required")
+	}
+	if req.Principal.Units < 0 || req.Principal.Nanos < 0 || req.Principal.Units == 0 && req.Principal.Nanos == 0 {
+		return status.Error(codes.InvalidArgument, "principal must be greater than zero")
+	}
+	if req.Principal.Units >= maxPrincipalUnits {
+		return status.Errorf(codes.InvalidArgument, "principal must be less than %d", maxPrincipalUnits)
+	}
+	if req.AnnualRateBps < 0 {
+		return status.Error(codes.InvalidArgument, "rate must not be negative")
+	}
+	if req.TermMonths <= 0 || req.TermMonths > maxTermMonths {
+		return status.Errorf(codes.InvalidArgument, "term must be between 1 and %d months", maxTermMonths)
+	}
+	return nil
+}
+
+// fromRemote passes gRPC status errors from a downstream service through unchanged.
+func fromRemote(err error, service string) error {
+	if st, ok := status.FromError(err); ok {
+		return st.Err()
+	}
+	return status.Errorf(codes.Internal, "failed to call %s: %v", service, err)
+}
@@ -0,0 +1,266 @@
+package loan
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	accountv2 "github.com/galadeat/bank-sim/api/proto/account/v2"
+	loanv1 "github.com/galadeat/bank-sim/api/proto/loan/v1"
+	userv1 "github.com/galadeat/bank-sim/api/proto/user/v1"
+	"github.com/galadeat/bank-sim/mocks"
+	"github.com/galadeat/bank-sim/pkg/clock"
+	"github.com/golang/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func originateRequest() *loanv1.OriginateLoanRequest {
+	return &loanv1.OriginateLoanRequest{
+		UserId:        "user-123",
+		AccountId:     "acc-123",
+		Principal:     usd(1200, 0),
+		AnnualRateBps: 1200,
+		TermMonths:    12,
+		RepaymentType: loanv1.RepaymentType_REPAYMENT_TYPE_LINEAR,
+		RequestId:     "req-1",
+	}
+}
+
+func expectOrigination(user *mocks.MockUserClient, account *mocks.MockAccountClient, owner string) {
+	user.EXPECT().
+		GetUser(gomock.Any(), &userv1.GetUserRequest{Id: "user-123"}).
+		Return(&userv1.GetUserResponse{User: &userv1.UserInfo{Id: "user-123"}}, nil)
+	account.EXPECT().
+		GetAccount(gomock.Any(), &accountv2.GetAccountRequest{Id: "acc-123"}).
+		Return(&accountv2.GetAccountResponse{Account: &accountv2.AccountInfo{Id: "acc-123", OwnerId: owner, Owner: &userv1.UserInfo{Id: owner}}}, nil)
+}
+
+func TestOriginateLoan(t *testing.T) {
+	t.Run("principal is disbursed once", func(t *testing.T) {
+		ctx := context.Background()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		user := mocks.NewMockUserClient(ctrl)
+		account := mocks.NewMockAccountClient(ctrl)
+		svc := New(user, account, WithClock(clock.NewManual(disbursed)))
+
+		expectOrigination(user, account, "user-123")
+		account.EXPECT().
+			Deposit(gomock.Any(), &accountv2.DepositRequest{AccountId: "acc-123", Amount: usd(1200, 0), RequestId: "loan:req-1:disburse"}).
+			Return(&accountv2.DepositResponse{}, nil)
+
+		first, err := svc.OriginateLoan(ctx, originateRequest())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(first.Loan.Schedule) != 12 {
+			t.Errorf("expected 12 installments, got %d", len(first.Loan.Schedule))
+		}
+		if first.Loan.Status != loanv1.LoanStatus_LOAN_STATUS_ACTIVE {
+			t.Errorf("expected active loan, got %v", first.Loan.Status)
+		}
+
+		second, err := svc.OriginateLoan(ctx, originateRequest())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if second.Loan.Id != first.Loan.Id {
+			t.Errorf("expected the same loan on retry, got %s and %s", first.Loan.Id, second.Loan.Id)
+		}
+
+		list, _ := svc.ListLoans(ctx, &loanv1.ListLoansRequest{UserId: "user-123"})
+		if len(list.Loans) != 1 {
+			t.Errorf("expected 1 loan, got %d", len(list.Loans))
+		}
+	})
+
+	t.Run("account of another user", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		user := mocks.NewMockUserClient(ctrl)
+		account := mocks.NewMockAccountClient(ctrl)
+		svc := New(user, account)
+
+		expectOrigination(user, account, "user-456")
+
+		_, err := svc.OriginateLoan(context.Background(), originateRequest())
+		if status.Code(err) != codes.PermissionDenied {
+			t.Errorf("expected %v, got %v", codes.PermissionDenied, err)
+		}
+	})
+
+	t.Run("invalid term", func(t *testing.T) {
+		svc := New(nil, nil)
+		req := originateRequest()
+		req.TermMonths = 0
+
+		_, err := svc.OriginateLoan(context.Background(), req)
+		if status.Code(err) != codes.InvalidArgument {
+			t.Errorf("expected %v, got %v", codes.InvalidArgument, err)
+		}
+	})
+}
+
+func TestCollectDue(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	user := mocks.NewMockUserClient(ctrl)
+	account := mocks.NewMockAccountClient(ctrl)
+	clk := clock.NewManual(disbursed)
+	svc := New(user, account, WithClock(clk))
+
+	expectOrigination(user, account, "user-123")
+	account.EXPECT().Deposit(gomock.Any(), gomock.Any()).Return(&accountv2.DepositResponse{}, nil)
+	resp, err := svc.OriginateLoan(ctx, originateRequest())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	loanID := resp.Loan.Id
+
+	getLoan := func() *loanv1.LoanInfo {
+		t.Helper()
+		got, err := svc.GetLoan(ctx, &loanv1.GetLoanRequest{Id: loanID})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return got.Loan
+	}
+	withdraw := func(n int) *gomock.Call {
+		return account.EXPECT().Withdraw(gomock.Any(), &accountv2.WithdrawRequest{
+			AccountId: "acc-123",
+			Amount:    resp.Loan.Schedule[n-1].Total,
+			RequestId: fmt.Sprintf("loan:%s:installment:%d", loanID, n),
+		})
+	}
+
+	// nothing is due before the first month has passed
+	svc.CollectDue(ctx, disbursed.AddDate(0, 1, -1))
+
+	// the first installment is collected
+	withdraw(1).Return(&accountv2.WithdrawResponse{}, nil)
+	svc.CollectDue(ctx, disbursed.AddDate(0, 1, 0))
+	loan := getLoan()
+	if loan.Schedule[0].Status != loanv1.InstallmentStatus_INSTALLMENT_STATUS_PAID {
+		t.Errorf("expected first installment paid, got %v", loan.Schedule[0].Status)
+	}
+	assertMoney(t, usd(1100, 0), loan.OutstandingPrincipal)
+
+	// the second one bounces and the loan becomes delinquent
+	withdraw(2).Return(nil, status.Error(codes.FailedPrecondition, "insufficient balance"))
+	svc.CollectDue(ctx, disbursed.AddDate(0, 2, 3))
+	loan = getLoan()
+	if loan.Status != loanv1.LoanStatus_LOAN_STATUS_DELINQUENT {
+		t.Errorf("expected delinquent loan, got %v", loan.Status)
+	}
+	if loan.DaysPastDue != 3 {
+		t.Errorf("expected 3 days past due, got %d", loan.DaysPastDue)
+	}
+
+	// an unavailable account service leaves the installment as it was
+	withdraw(2).Return(nil, status.Error(codes.Unavailable, "connection refused"))
+	svc.CollectDue(ctx, disbursed.AddDate(0, 2, 4))
+	if got := getLoan().Schedule[1].Status; got != loanv1.InstallmentStatus_INSTALLMENT_STATUS_OVERDUE {
+		t.Errorf("expected overdue installment, got %v", got)
+	}
+
+	// once funded, the overdue installment and the one now due are both collected
+	gomock.InOrder(
+		withdraw(2).Return(&accountv2.WithdrawResponse{}, nil),
+		withdraw(3).Return(&accountv2.WithdrawResponse{}, nil),
+	)
+	svc.CollectDue(ctx, disbursed.AddDate(0, 3, 0))
+	loan = getLoan()
+	if loan.Status != loanv1.LoanStatus_LOAN_STATUS_ACTIVE {
+		t.Errorf("expected active loan, got %v", loan.Status)
+	}
+	if loan.DaysPastDue != 0 {
+		t.Errorf("expected 0 days past due, got %d", loan.DaysPastDue)
+	}
+	assertMoney(t, usd(900, 0), loan.OutstandingPrincipal)
+}
+
+func TestCollectDueHeldForReview(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	user := mocks.NewMockUserClient(ctrl)
+	account := mocks.NewMockAccountClient(ctrl)
+	svc := New(user, account, WithClock(clock.NewManual(disbursed)))
+
+	expectOrigination(user, account, "user-123")
+	account.EXPECT().Deposit(gomock.Any(), gomock.Any()).Return(&accountv2.DepositResponse{}, nil)
+	resp, err := svc.OriginateLoan(ctx, originateRequest())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	loanID := resp.Loan.Id
+
+	withdraw := func(n int) *gomock.Call {
+		return account.EXPECT().Withdraw(gomock.Any(), &accountv2.WithdrawRequest{
+			AccountId: "acc-123",
+			Amount:    resp.Loan.Schedule[n-1].Total,
+			RequestId: fmt.Sprintf("loan:%s:installment:%d", loanID, n),
+		})
+	}
+	review := &accountv2.Review{Id: "review-1", Status: accountv2.ReviewStatus_REVIEW_STATUS_PENDING}
+
+	// a held withdrawal is not a payment, and later installments wait for it
+	withdraw(1).Return(&accountv2.WithdrawResponse{Review: review}, nil)
+	svc.CollectDue(ctx, disbursed.AddDate(0, 2, 0))
+	got, _ := svc.GetLoan(ctx, &loanv1.GetLoanRequest{Id: loanID})
+	if got.Loan.Schedule[0].Status != loanv1.InstallmentStatus_INSTALLMENT_STATUS_SCHEDULED {
+		t.Errorf("expected first installment scheduled, got %v", got.Loan.Schedule[0].Status)
+	}
+	assertMoney(t, usd(1200, 0), got.Loan.OutstandingPrincipal)
+
+	// once approved, the retry under the same request id sees the payment
+	approved := &accountv2.Review{Id: "review-1", Status: accountv2.ReviewStatus_REVIEW_STATUS_APPROVED}
+	gomock.InOrder(
+		withdraw(1).Return(&accountv2.WithdrawResponse{Account: &accountv2.AccountInfo{Id: "acc-123"}, Review: approved}, nil),
+		withdraw(2).Return(&accountv2.WithdrawResponse{}, nil),
+	)
+	svc.CollectDue(ctx, disbursed.AddDate(0, 2, 1))
+	got, _ = svc.GetLoan(ctx, &loanv1.GetLoanRequest{Id: loanID})
+	if got.Loan.Schedule[0].Status != loanv1.InstallmentStatus_INSTALLMENT_STATUS_PAID {
+		t.Errorf("expected first installment paid, got %v", got.Loan.Schedule[0].Status)
+	}
+	assertMoney(t, usd(1000, 0), got.Loan.OutstandingPrincipal)
+}
+
+func TestRunCollector(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	clk := clock.NewManual(disbursed)
+	svc := New(mocks.NewMockUserClient(ctrl), mocks.NewMockAccountClient(ctrl), WithClock(clk))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		svc.RunCollector(ctx)
+		close(done)
+	}()
+
+	deadline := time.Now().Add(time.Second)
+	for clk.Waiters() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("collector did not wait on the clock")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	clk.Advance(24 * time.Hour)
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("collector did not stop")
+	}
+}
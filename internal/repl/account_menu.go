@@ -3,15 +3,15 @@ package repl
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
-	"log"
 
 	accountv2 "github.com/galadeat/bank-sim/api/proto/account/v2"
-	userv1 "github.com/galadeat/bank-sim/api/proto/user/v1"
-	"github.com/gofrs/uuid"
+	"github.com/galadeat/bank-sim/pkg/clients"
+	"github.com/galadeat/bank-sim/pkg/money"
 )
 
-func runAccountMenu(reader *bufio.Reader, accountClient accountv2.AccountClient, userClient userv1.UserClient) {
+func runAccountMenu(reader *bufio.Reader, bank *clients.Bank) {
 	for {
 		fmt.Println("\n\t\t\t\tAccount Menu")
 		fmt.Println("1) Create Account")
@@ -26,17 +26,17 @@ func runAccountMenu(reader *bufio.Reader, accountClient accountv2.AccountClient,
 
 		switch choice {
 		case "1":
-			handleCreateAccount(reader, accountClient, userClient)
+			handleCreateAccount(reader, bank)
 		case "2":
-			handleGetAccount(reader, accountClient, userClient)
+			handleGetAccount(reader, bank)
 		case "3":
-			handleListAccounts(reader, accountClient, userClient)
+			handleListAccounts(reader, bank)
 		case "4":
-			handleDeleteAccount(reader, accountClient, userClient)
+			handleDeleteAccount(reader, bank)
 		case "5":
-			handleDepositMoney(reader, accountClient, userClient)
+			handleDepositMoney(reader, bank)
 		case "6":
-			handleWithdrawMoney(reader, accountClient, userClient)
+			handleWithdrawMoney(reader, bank)
 		case "7":
 			return
 		default:
@@ -47,144 +47,129 @@ func runAccountMenu(reader *bufio.Reader, accountClient accountv2.AccountClient,
 
 }
 
-func handleCreateAccount(reader *bufio.Reader, accountClient accountv2.AccountClient, userClient userv1.UserClient) {
+func handleCreateAccount(reader *bufio.Reader, bank *clients.Bank) {
 
-	userId := runChooseUserMenu(reader, userClient)
+	userId := runChooseUserMenu(reader, bank)
 	if userId == "" {
 		return
 	}
-	balance := runBalanceMenu(reader)
-	request, err := uuid.NewV4()
-	if err != nil {
-		log.Fatal(err)
+	accountType, termMonths, ok := runAccountTypeMenu(reader)
+	if !ok {
 		return
 	}
-	req := &accountv2.CreateAccountRequest{
-		UserId:         userId,
-		InitialBalance: balance,
-		RequestId:      request.String(),
+	balance, ok := runAmountMenu(reader)
+	if !ok {
+		return
 	}
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
 
-	resp, err := accountClient.CreateAccount(ctx, req)
+	ctx := context.Background()
+	var err error
+	var account *accountv2.AccountInfo
+	if accountType == accountv2.AccountType_ACCOUNT_TYPE_TERM_DEPOSIT {
+		account, err = bank.CreateTermDeposit(ctx, userId, termMonths, balance)
+	} else {
+		account, err = bank.CreateAccount(ctx, userId, accountType, balance)
+	}
 	if err != nil {
 		fmt.Printf("CreateAccount error: %v\n", err)
 		return
 	}
-	fmt.Printf("Account created: %v\n", resp.GetAccount().GetId())
+	fmt.Printf("Account created: %v\n", account.GetId())
 
 }
 
-func handleGetAccount(reader *bufio.Reader, accountClient accountv2.AccountClient, userClient userv1.UserClient) {
-	id := runChooseAccountMenu(reader, accountClient, userClient)
+func handleGetAccount(reader *bufio.Reader, bank *clients.Bank) {
+	id := runChooseAccountMenu(reader, bank)
 	if id == "" {
 		return
 	}
 
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-	resp, err := accountClient.GetAccount(ctx, &accountv2.GetAccountRequest{Id: id})
+	account, err := bank.GetAccount(context.Background(), id)
 	if err != nil {
 		fmt.Printf("Error getting account: %v\n", err)
 		return
 	}
-	fmt.Printf("\nAccount id: %s\n", resp.GetAccount().GetId())
-	fmt.Printf("Account owner: %v\n", resp.GetAccount().GetOwner().GetId())
-	fmt.Printf("Account balance: %v\n", resp.GetAccount().GetBalance())
+	fmt.Printf("\nAccount id: %s\n", account.GetId())
+	fmt.Printf("Account owner: %v\n", account.GetOwner().GetId())
+	fmt.Printf("Account balance: %s\n", money.Format(account.GetBalance()))
+	fmt.Printf("Available balance: %s\n", money.Format(account.GetAvailableBalance()))
+	fmt.Printf("Overdraft limit: %s\n", money.Format(account.GetOverdraftLimit()))
+	fmt.Printf("Account status: %v\n", account.GetStatus())
+	fmt.Printf("Account type: %v\n", account.GetType())
+	fmt.Printf("Accrued interest: %s\n", money.Format(account.GetAccruedInterest()))
 }
 
-func handleListAccounts(reader *bufio.Reader, accountClient accountv2.AccountClient, userClient userv1.UserClient) {
+func handleListAccounts(reader *bufio.Reader, bank *clients.Bank) {
 
-	id := runChooseUserMenu(reader, userClient)
+	id := runChooseUserMenu(reader, bank)
 	if id == "" {
 		return
 	}
-	req := &accountv2.ListAccountsRequest{
-		UserId: id,
-	}
 	fmt.Printf("\n\tAccounts:\n")
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-	resp, err := accountClient.ListAccounts(ctx, req)
+	accounts, err := bank.ListAccounts(context.Background(), id)
 	if err != nil {
 		fmt.Printf("Error listing accounts: %v\n", err)
 		return
 	}
-	for i, a := range resp.Accounts {
-		fmt.Printf("Account %d: %v", i+1, a.GetId())
+	for i, a := range accounts {
+		fmt.Printf("Account %d: %v %s\n", i+1, a.GetId(), money.Format(a.GetBalance()))
 	}
 }
 
-func handleDeleteAccount(reader *bufio.Reader, accountClient accountv2.AccountClient, client userv1.UserClient) {
+func handleDeleteAccount(reader *bufio.Reader, bank *clients.Bank) {
 
-	id := runChooseAccountMenu(reader, accountClient, client)
+	id := runChooseAccountMenu(reader, bank)
 	if id == "" {
 		return
 	}
-	req := &accountv2.DeleteAccountRequest{
-		AccountId: id,
-	}
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-	_, err := accountClient.DeleteAccount(ctx, req)
-	if err != nil {
+	if err := bank.DeleteAccount(context.Background(), id); err != nil {
 		fmt.Printf("Error deleting account: %v\n", err)
 		return
 	}
 	fmt.Printf("\nAccount deleted: %v\n", id)
 }
-func handleDepositMoney(reader *bufio.Reader, accountClient accountv2.AccountClient, userClient userv1.UserClient) {
-	id := runChooseAccountMenu(reader, accountClient, userClient)
+func handleDepositMoney(reader *bufio.Reader, bank *clients.Bank) {
+	id := runChooseAccountMenu(reader, bank)
 	if id == "" {
 		return
 	}
-	depositMoney := runBalanceMenu(reader)
-	reqId, err := uuid.NewV4()
-	if err != nil {
-		log.Fatal(err)
+	depositMoney, ok := runAmountMenu(reader)
+	if !ok {
 		return
 	}
-	req := &accountv2.DepositRequest{
-		AccountId: id,
-		Amount:    depositMoney,
-		RequestId: reqId.String(),
-	}
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-	resp, err := accountClient.Deposit(ctx, req)
+	account, err := bank.Deposit(context.Background(), id, depositMoney)
 	if err != nil {
 		fmt.Printf("Error depositing money: %v\n", err)
 		return
 	}
-	fmt.Printf("\nAccount deposited: %v\n", resp.GetAccount().GetId())
-	fmt.Printf("New balance: %v\n", resp.GetAccount().GetBalance())
+	fmt.Printf("\nAccount deposited: %v\n", account.GetId())
+	fmt.Printf("New balance: %s\n", money.Format(account.GetBalance()))
 
 }
 
-func handleWithdrawMoney(reader *bufio.Reader, accountClient accountv2.AccountClient, userClient userv1.UserClient) {
+func handleWithdrawMoney(reader *bufio.Reader, bank *clients.Bank) {
 
-	id := runChooseAccountMenu(reader, accountClient, userClient)
+	id := runChooseAccountMenu(reader, bank)
 	if id == "" {
 		return
 	}
-	withdrawMoney := runBalanceMenu(reader)
-	reqId, err := uuid.NewV4()
-	if err != nil {
-		log.Fatal(err)
+	withdrawMoney, ok := runAmountMenu(reader)
+	if !ok {
 		return
 	}
-	req := &accountv2.WithdrawRequest{
-		AccountId: id,
-		Amount:    withdrawMoney,
-		RequestId: reqId.String(),
-	}
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-	resp, err := accountClient.Withdraw(ctx, req)
-	if err != nil {
+	account, err := bank.Withdraw(context.Background(), id, withdrawMoney)
+	var review *clients.ReviewError
+	switch {
+	case errors.As(err, &review):
+		fmt.Printf("\nWithdrawal held for review: %v (reasons: %v)\n", review.Review.GetId(), review.Review.GetReasons())
+		return
+	case errors.Is(err, clients.ErrInsufficientFunds):
+		fmt.Printf("Not enough money on the account: %v\n", err)
+		return
+	case err != nil:
 		fmt.Printf("Error withdrawing money: %v\n", err)
+		return
 	}
-	fmt.Printf("\nAccount withdrawed: %v\n", resp.GetAccount().GetId())
-	fmt.Printf("New balance: %v\n", resp.GetAccount().GetBalance())
+	fmt.Printf("\nAccount withdrawed: %v\n", account.GetId())
+	fmt.Printf("New balance: %s\n", money.Format(account.GetBalance()))
 }
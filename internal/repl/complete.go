@@ -0,0 +1,122 @@
+package repl
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	accountv2 "github.com/galadeat/bank-sim/api/proto/account/v2"
+	userv1 "github.com/galadeat/bank-sim/api/proto/user/v1"
+	"github.com/galadeat/bank-sim/internal/cli"
+)
+
+var shellCommands = []string{"account", "exit", "help", "run", "user", "vars"}
+
+// completer completes command names, variables and user and account ids.
+type completer struct {
+	shell *Shell
+}
+
+// Do implements readline.AutoCompleter. It returns the suffixes that complete
+// the word under the cursor and the length of that word.
+func (c *completer) Do(line []rune, pos int) ([][]rune, int) {
+	text := string(line[:pos])
+	words := strings.Fields(text)
+	word := ""
+	if len(words) > 0 && !strings.HasSuffix(text, " ") {
+		word = words[len(words)-1]
+		words = words[:len(words)-1]
+	}
+	// name = <command> completes like <command>
+	if len(words) >= 2 && words[1] == "=" {
+		words = words[2:]
+	}
+
+	var candidates []string
+	switch {
+	case strings.HasPrefix(word, "$"):
+		for name := range c.shell.vars {
+			candidates = append(candidates, "$"+name)
+		}
+	case strings.HasPrefix(word, "-"):
+		return nil, 0
+	case len(words) == 0:
+		candidates = shellCommands
+	case len(words) == 1:
+		candidates = cli.Commands()[words[0]]
+	default:
+		candidates = c.shell.ids.list()
+	}
+
+	sort.Strings(candidates)
+	var out [][]rune
+	for _, cand := range candidates {
+		if strings.HasPrefix(cand, word) && cand != word {
+			out = append(out, []rune(cand[len(word):]+" "))
+		}
+	}
+	return out, len([]rune(word))
+}
+
+// idCacheTTL is how long listed ids are reused before the servers are asked again.
+const idCacheTTL = 5 * time.Second
+
+// idCache keeps the ids of users and their accounts for completion.
+type idCache struct {
+	users    userv1.UserClient
+	accounts accountv2.AccountClient
+
+	mu      sync.Mutex
+	ids     map[string]bool
+	fetched time.Time
+}
+
+func (c *idCache) add(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.ids == nil {
+		c.ids = make(map[string]bool)
+	}
+	c.ids[id] = true
+}
+
+// list returns the known ids, refreshing them from the servers when they are
+// older than idCacheTTL. A failed refresh keeps the ids known so far.
+func (c *idCache) list() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if time.Since(c.fetched) > idCacheTTL {
+		c.refresh()
+	}
+	out := make([]string, 0, len(c.ids))
+	for id := range c.ids {
+		out = append(out, id)
+	}
+	return out
+}
+
+// refresh lists every user and their accounts. Callers must hold c.mu.
+func (c *idCache) refresh() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	users, err := c.users.ListUsers(ctx, &userv1.ListUsersRequest{})
+	if err != nil {
+		return
+	}
+	ids := make(map[string]bool)
+	for _, u := range users.Users {
+		ids[u.Id] = true
+		accounts, err := c.accounts.ListAccounts(ctx, &accountv2.ListAccountsRequest{UserId: u.Id})
+		if err != nil {
+			continue
+		}
+		for _, acc := range accounts.Accounts {
+			ids[acc.Id] = true
+		}
+	}
+	c.ids = ids
+	c.fetched = time.Now()
+}
@@ -7,17 +7,19 @@ import (
 
 	accountv2 "github.com/galadeat/bank-sim/api/proto/account/v2"
 	userv1 "github.com/galadeat/bank-sim/api/proto/user/v1"
+	"github.com/galadeat/bank-sim/pkg/clients"
 )
 
 func Run(userClient userv1.UserClient, accountClient accountv2.AccountClient) {
 	fmt.Println("\n\n\t\t\tWelcome to Bank Sim REPL")
 	reader := bufio.NewReader(os.Stdin)
+	bank := clients.NewBank(userClient, accountClient)
 	for {
 		switch showMainMenu(reader) {
 		case "user":
-			runUserMenu(reader, userClient)
+			runUserMenu(reader, bank)
 		case "account":
-			runAccountMenu(reader, accountClient, userClient)
+			runAccountMenu(reader, bank)
 		case "exit":
 			fmt.Println("Bye! Thanks for using this application!")
 			os.Exit(0)
@@ -0,0 +1,279 @@
+package repl
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/chzyer/readline"
+	accountv2 "github.com/galadeat/bank-sim/api/proto/account/v2"
+	userv1 "github.com/galadeat/bank-sim/api/proto/user/v1"
+	"github.com/galadeat/bank-sim/internal/cli"
+)
+
+const shellHelp = `Commands are the ones of the bank command line client:
+
+` + cli.CommandHelp + `
+Shell commands:
+  name = <command>   run a create command and keep the created id in $name
+  name = <value>     set a variable
+  vars               list variables
+  run <file.bank>    run a script
+  help               show this help
+  exit               leave the shell
+
+$name and ${name} are replaced by the variable's value; $_ is the id created last.
+Text after # is a comment. Tab completes commands, variables and ids.
+`
+
+// errExit ends the interactive loop.
+var errExit = errors.New("exit")
+
+// Shell is the command language REPL. Every line is a command of the bank
+// command line client or a shell command; create commands can capture the id
+// they return in a variable.
+type Shell struct {
+	app     *cli.App
+	out     io.Writer
+	timeout time.Duration
+	vars    map[string]string
+	ids     *idCache
+}
+
+// NewShell is the constructor
+func NewShell(userClient userv1.UserClient, accountClient accountv2.AccountClient, out io.Writer) *Shell {
+	return &Shell{
+		app:     &cli.App{User: userClient, Account: accountClient, Out: out},
+		out:     out,
+		timeout: 10 * time.Second,
+		vars:    make(map[string]string),
+		ids:     &idCache{users: userClient, accounts: accountClient},
+	}
+}
+
+// Interactive reads commands with line editing, tab completion and history kept
+// in historyFile until exit or end of input.
+func (s *Shell) Interactive(historyFile string) error {
+	rl, err := readline.NewEx(&readline.Config{
+		Prompt:          "bank> ",
+		HistoryFile:     historyFile,
+		AutoComplete:    &completer{shell: s},
+		InterruptPrompt: "^C",
+		EOFPrompt:       "exit",
+	})
+	if err != nil {
+		return err
+	}
+	defer rl.Close()
+	s.app.Out = rl.Stdout()
+	s.out = rl.Stdout()
+
+	fmt.Fprintln(s.out, "Bank Sim shell. Type help for the commands.")
+	for {
+		line, err := rl.Readline()
+		switch {
+		case errors.Is(err, readline.ErrInterrupt):
+			continue
+		case errors.Is(err, io.EOF):
+			return nil
+		case err != nil:
+			return err
+		}
+		if err := s.Exec(line); err != nil {
+			if errors.Is(err, errExit) {
+				return nil
+			}
+			fmt.Fprintf(s.out, "error: %s\n", cli.Describe(err))
+		}
+	}
+}
+
+// RunScript runs every line of a script and stops at the first failing one.
+func (s *Shell) RunScript(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	sc := bufio.NewScanner(f)
+	for n := 1; sc.Scan(); n++ {
+		line := sc.Text()
+		if strings.TrimSpace(stripComment(line)) == "" {
+			continue
+		}
+		fmt.Fprintf(s.out, "bank> %s\n", line)
+		if err := s.Exec(line); err != nil {
+			if errors.Is(err, errExit) {
+				return nil
+			}
+			return fmt.Errorf("%s:%d: %s", filepath.Base(path), n, cli.Describe(err))
+		}
+	}
+	return sc.Err()
+}
+
+var varName = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+
+// Exec runs one line.
+func (s *Shell) Exec(line string) error {
+	words, err := split(line)
+	if err != nil {
+		return err
+	}
+	if len(words) == 0 {
+		return nil
+	}
+
+	// name = ...
+	if len(words) >= 3 && words[1] == "=" {
+		name := words[0]
+		if !varName.MatchString(name) {
+			return fmt.Errorf("invalid variable name %q", name)
+		}
+		rest, err := s.expand(words[2:])
+		if err != nil {
+			return err
+		}
+		if len(rest) == 1 {
+			s.vars[name] = rest[0]
+			return nil
+		}
+		if err := s.command(rest); err != nil {
+			return err
+		}
+		if s.app.LastID == "" {
+			return fmt.Errorf("%s %s returns no id to keep in $%s", rest[0], rest[1], name)
+		}
+		s.vars[name] = s.app.LastID
+		return nil
+	}
+
+	words, err = s.expand(words)
+	if err != nil {
+		return err
+	}
+	switch words[0] {
+	case "help":
+		fmt.Fprint(s.out, shellHelp)
+		return nil
+	case "exit", "quit":
+		return errExit
+	case "vars":
+		s.printVars()
+		return nil
+	case "run":
+		if len(words) != 2 {
+			return errors.New("usage: run <file.bank>")
+		}
+		return s.RunScript(words[1])
+	}
+	return s.command(words)
+}
+
+// command runs a command of the command line client and remembers the id it
+// created in $_.
+func (s *Shell) command(words []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	if err := s.app.Exec(ctx, words); err != nil {
+		return err
+	}
+	if s.app.LastID != "" {
+		s.vars["_"] = s.app.LastID
+		s.ids.add(s.app.LastID)
+	}
+	return nil
+}
+
+func (s *Shell) printVars() {
+	names := make([]string, 0, len(s.vars))
+	for name := range s.vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	tw := tabwriter.NewWriter(s.out, 0, 0, 2, ' ', 0)
+	for _, name := range names {
+		fmt.Fprintf(tw, "$%s\t%s\n", name, s.vars[name])
+	}
+	tw.Flush()
+}
+
+// expand replaces $name and ${name} in every word.
+func (s *Shell) expand(words []string) ([]string, error) {
+	out := make([]string, len(words))
+	for i, w := range words {
+		var missing string
+		out[i] = os.Expand(w, func(name string) string {
+			v, ok := s.vars[name]
+			if !ok && missing == "" {
+				missing = name
+			}
+			return v
+		})
+		if missing != "" {
+			return nil, fmt.Errorf("variable $%s is not set", missing)
+		}
+	}
+	return out, nil
+}
+
+// split breaks a line into words. Words may be quoted with single or double
+// quotes; everything after an unquoted # is a comment.
+func split(line string) ([]string, error) {
+	var words []string
+	var cur strings.Builder
+	inWord := false
+	var quote rune
+	for _, r := range line {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				cur.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote, inWord = r, true
+		case r == '#':
+			return finish(words, &cur, inWord), nil
+		case r == ' ' || r == '\t':
+			if inWord {
+				words = append(words, cur.String())
+				cur.Reset()
+				inWord = false
+			}
+		default:
+			cur.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		return nil, errors.New("unterminated quote")
+	}
+	return finish(words, &cur, inWord), nil
+}
+
+func finish(words []string, cur *strings.Builder, inWord bool) []string {
+	if inWord {
+		words = append(words, cur.String())
+	}
+	return words
+}
+
+func stripComment(line string) string {
+	words, err := split(line)
+	if err != nil {
+		return line
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,132 @@
+package repl
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	accountv2 "github.com/galadeat/bank-sim/api/proto/account/v2"
+	commonv1 "github.com/galadeat/bank-sim/api/proto/common/v1"
+	userv1 "github.com/galadeat/bank-sim/api/proto/user/v1"
+	"github.com/galadeat/bank-sim/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func newTestShell(t *testing.T) (*Shell, *mocks.MockUserClient, *mocks.MockAccountClient, *bytes.Buffer) {
+	ctrl := gomock.NewController(t)
+	users := mocks.NewMockUserClient(ctrl)
+	accounts := mocks.NewMockAccountClient(ctrl)
+	out := &bytes.Buffer{}
+	return NewShell(users, accounts, out), users, accounts, out
+}
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		line string
+		want []string
+	}{
+		{line: "user list", want: []string{"user", "list"}},
+		{line: "  account   get  x  ", want: []string{"account", "get", "x"}},
+		{line: `account close a --reason "fraud report"`, want: []string{"account", "close", "a", "--reason", "fraud report"}},
+		{line: "user list # everyone", want: []string{"user", "list"}},
+		{line: `x = '#not a comment'`, want: []string{"x", "=", "#not a comment"}},
+		{line: "# only a comment", want: nil},
+	}
+	for _, tt := range tests {
+		got, err := split(tt.line)
+		require.NoError(t, err, tt.line)
+		assert.Equal(t, tt.want, got, tt.line)
+	}
+
+	_, err := split(`user create --login "alice`)
+	assert.EqualError(t, err, "unterminated quote")
+}
+
+func TestShellVariables(t *testing.T) {
+	sh, users, accounts, _ := newTestShell(t)
+	users.EXPECT().CreateUser(gomock.Any(), gomock.Any()).Return(&userv1.CreateUserResponse{Id: "user-1"}, nil)
+	accounts.EXPECT().CreateAccount(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, req *accountv2.CreateAccountRequest, _ ...grpc.CallOption) (*accountv2.CreateAccountResponse, error) {
+			assert.Equal(t, "user-1", req.UserId)
+			return &accountv2.CreateAccountResponse{Account: &accountv2.AccountInfo{Id: "acc-1"}}, nil
+		})
+	accounts.EXPECT().Deposit(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, req *accountv2.DepositRequest, _ ...grpc.CallOption) (*accountv2.DepositResponse, error) {
+			assert.Equal(t, "acc-1", req.AccountId)
+			return &accountv2.DepositResponse{Account: &accountv2.AccountInfo{Id: "acc-1", Balance: req.Amount}}, nil
+		})
+
+	require.NoError(t, sh.Exec("alice = user create --login alice --email alice@example.com"))
+	require.NoError(t, sh.Exec("main = account create $alice"))
+	require.NoError(t, sh.Exec("cur = USD"))
+	require.NoError(t, sh.Exec("account deposit ${main} 10 $cur"))
+
+	assert.Equal(t, map[string]string{"alice": "user-1", "main": "acc-1", "cur": "USD", "_": "acc-1"}, sh.vars)
+
+	err := sh.Exec("account get $missing")
+	assert.EqualError(t, err, "variable $missing is not set")
+	assert.Error(t, sh.Exec("1x = USD"))
+}
+
+func TestShellCaptureNeedsID(t *testing.T) {
+	sh, users, _, _ := newTestShell(t)
+	users.EXPECT().ListUsers(gomock.Any(), gomock.Any()).Return(&userv1.ListUsersResponse{}, nil)
+
+	err := sh.Exec("all = user list")
+	assert.EqualError(t, err, "user list returns no id to keep in $all")
+}
+
+func TestRunScript(t *testing.T) {
+	dir := t.TempDir()
+	script := filepath.Join(dir, "demo.bank")
+	require.NoError(t, os.WriteFile(script, []byte(`# open an account and overdraw it
+bob = user create --login bob --email bob@example.com
+acc = account create $bob 5 USD
+
+account withdraw $acc 50 USD
+user list
+`), 0o600))
+
+	sh, users, accounts, out := newTestShell(t)
+	users.EXPECT().CreateUser(gomock.Any(), gomock.Any()).Return(&userv1.CreateUserResponse{Id: "user-2"}, nil)
+	accounts.EXPECT().CreateAccount(gomock.Any(), gomock.Any()).Return(&accountv2.CreateAccountResponse{Account: &accountv2.AccountInfo{
+		Id: "acc-2", Balance: &commonv1.Money{Currency: "USD", Units: 5},
+	}}, nil)
+	accounts.EXPECT().Withdraw(gomock.Any(), gomock.Any()).Return(nil, status.Error(codes.FailedPrecondition, "insufficient balance"))
+
+	err := sh.RunScript(script)
+	assert.EqualError(t, err, "demo.bank:5: failed precondition: insufficient balance")
+	assert.Contains(t, out.String(), "bank> acc = account create $bob 5 USD\n")
+	assert.NotContains(t, out.String(), "user list")
+}
+
+func TestCompleter(t *testing.T) {
+	sh, users, accounts, _ := newTestShell(t)
+	users.EXPECT().ListUsers(gomock.Any(), gomock.Any()).Return(&userv1.ListUsersResponse{Users: []*userv1.UserInfo{{Id: "u-1"}}}, nil)
+	accounts.EXPECT().ListAccounts(gomock.Any(), gomock.Any()).Return(&accountv2.ListAccountsResponse{Accounts: []*accountv2.AccountInfo{{Id: "a-1"}, {Id: "a-2"}}}, nil)
+	sh.vars["alice"] = "u-1"
+	c := &completer{shell: sh}
+
+	complete := func(line string) []string {
+		got, _ := c.Do([]rune(line), len(line))
+		var out []string
+		for _, r := range got {
+			out = append(out, string(r))
+		}
+		return out
+	}
+
+	assert.Equal(t, []string{"ount "}, complete("acc"))
+	assert.Equal(t, []string{"eposit "}, complete("account d"))
+	assert.Equal(t, []string{"eposit "}, complete("x = account d"))
+	assert.Equal(t, []string{"lice "}, complete("account list $a"))
+	assert.Equal(t, []string{"1 ", "2 "}, complete("account get a-"))
+	assert.Equal(t, []string{"a-1 ", "a-2 ", "u-1 "}, complete("account get "))
+}
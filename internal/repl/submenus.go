@@ -8,72 +8,66 @@ import (
 
 	accountv2 "github.com/galadeat/bank-sim/api/proto/account/v2"
 	commonv1 "github.com/galadeat/bank-sim/api/proto/common/v1"
-	userv1 "github.com/galadeat/bank-sim/api/proto/user/v1"
+	"github.com/galadeat/bank-sim/pkg/clients"
+	"github.com/galadeat/bank-sim/pkg/money"
 )
 
-// runBalanceMenu repl function to initialize balance.
-func runBalanceMenu(reader *bufio.Reader) *commonv1.Money {
-	var currency = "None"
-	var units int64 = 0
-	var nanos int32 = 0
+// runAmountMenu repl function to read an amount such as "12.50 EUR" or "€12.50".
+// It asks again until the amount is valid; an empty line cancels.
+func runAmountMenu(reader *bufio.Reader) (*commonv1.Money, bool) {
 	for {
-		fmt.Println("\n\tBalance:")
-		fmt.Printf("1) Currency: %s", currency)
-		fmt.Printf("\n2) Units: %d", units)
-		fmt.Printf("\n3) Nanos: %d", nanos)
-		fmt.Println("\n4) Accept")
+		input := readInput(reader, "Enter amount (e.g. 12.50 USD, empty to cancel): ")
+		if input == "" {
+			return nil, false
+		}
+		amount, err := money.ParseString(input)
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+		return amount, true
+	}
+}
 
-		choice := readInput(reader, "Choose option: ")
-		switch choice {
-		case "1":
-			currency = readInput(reader, "Enter currency: ")
-		case "2":
-			input, err := strconv.Atoi(readInput(reader, "Enter units: "))
-			if err != nil || input < 0 {
-				fmt.Println("Enter valid number")
-				continue
-			}
-			units = int64(input)
-		case "3":
-			input, err := strconv.Atoi(readInput(reader, "Enter nanos: "))
-			if err != nil || input < 0 {
-				fmt.Println("Enter valid number")
-				continue
-			}
-			nanos = int32(input)
-		case "4":
-			if currency == "None" {
-				fmt.Println("Currency must be chosen")
-				continue
-			}
-			return &commonv1.Money{
-				Currency: currency,
-				Units:    units,
-				Nanos:    nanos,
-			}
-		default:
-			fmt.Println("Enter valid option")
+// runAccountTypeMenu repl function to choose the account product.
+func runAccountTypeMenu(reader *bufio.Reader) (accountv2.AccountType, int32, bool) {
+	fmt.Println("\n\tAccount type:")
+	fmt.Println("1) Checking")
+	fmt.Println("2) Savings")
+	fmt.Println("3) Term deposit")
 
+	switch readInput(reader, "Choose option: ") {
+	case "1":
+		return accountv2.AccountType_ACCOUNT_TYPE_CHECKING, 0, true
+	case "2":
+		return accountv2.AccountType_ACCOUNT_TYPE_SAVINGS, 0, true
+	case "3":
+		months, err := strconv.Atoi(readInput(reader, "Enter term in months: "))
+		if err != nil || months <= 0 {
+			fmt.Println("Enter valid number")
+			return 0, 0, false
 		}
+		return accountv2.AccountType_ACCOUNT_TYPE_TERM_DEPOSIT, int32(months), true
+	default:
+		fmt.Println("Invalid choice")
+		return 0, 0, false
 	}
 }
 
 //
 
-func runChooseUserMenu(reader *bufio.Reader, client userv1.UserClient) string {
+func runChooseUserMenu(reader *bufio.Reader, bank *clients.Bank) string {
 	fmt.Println("\n\tUsers:")
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-	resp, err := client.ListUsers(ctx, &userv1.ListUsersRequest{})
+	users, err := bank.ListUsers(context.Background())
 	if err != nil {
 		fmt.Println("Error listing users: ", err)
 		return ""
 	}
-	if len(resp.Users) == 0 {
+	if len(users) == 0 {
 		fmt.Println("No users found")
 		return ""
 	}
-	for i, user := range resp.Users {
+	for i, user := range users {
 		fmt.Printf("%d) User ID: %s\n", i+1, user.GetId())
 	}
 	choice, err := strconv.Atoi(readInput(reader, "Choose User: "))
@@ -81,34 +75,32 @@ func runChooseUserMenu(reader *bufio.Reader, client userv1.UserClient) string {
 		fmt.Println("Error choosing user: ", err)
 		return ""
 	}
-	if choice-1 < 0 || choice-1 >= len(resp.Users) {
+	if choice-1 < 0 || choice-1 >= len(users) {
 		fmt.Println("Invalid choice")
 		return ""
 	}
 
-	return resp.Users[choice-1].GetId()
+	return users[choice-1].GetId()
 
 }
 
-func runChooseAccountMenu(reader *bufio.Reader, client accountv2.AccountClient, userClient userv1.UserClient) string {
-	id := runChooseUserMenu(reader, userClient)
+func runChooseAccountMenu(reader *bufio.Reader, bank *clients.Bank) string {
+	id := runChooseUserMenu(reader, bank)
 	if id == "" {
 		return ""
 	}
 	fmt.Println("\n\tAccounts:")
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-	resp, err := client.ListAccounts(ctx, &accountv2.ListAccountsRequest{UserId: id})
+	accounts, err := bank.ListAccounts(context.Background(), id)
 	if err != nil {
 		fmt.Println("Error listing accounts: ", err)
 		return ""
 	}
-	if len(resp.Accounts) == 0 {
+	if len(accounts) == 0 {
 		fmt.Println("No accounts found")
 		return ""
 	}
 
-	for i, account := range resp.Accounts {
+	for i, account := range accounts {
 		fmt.Printf("%d) Account ID: %s\n", i+1, account.GetId())
 	}
 	choice, err := strconv.Atoi(readInput(reader, "Choose Account: "))
@@ -116,10 +108,10 @@ func runChooseAccountMenu(reader *bufio.Reader, client accountv2.AccountClient,
 		fmt.Println("Error choosing account: ", err)
 		return ""
 	}
-	if choice-1 < 0 || choice-1 >= len(resp.Accounts) {
+	if choice-1 < 0 || choice-1 >= len(accounts) {
 		fmt.Println("Invalid choice")
 		return ""
 	}
 
-	return resp.Accounts[choice-1].GetId()
+	return accounts[choice-1].GetId()
 }
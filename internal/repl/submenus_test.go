@@ -0,0 +1,27 @@
+package repl
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunAmountMenu(t *testing.T) {
+	t.Run("asks again after an invalid amount", func(t *testing.T) {
+		reader := bufio.NewReader(strings.NewReader("ten USD\n-5 USD\n12.50 EUR\n"))
+		amount, ok := runAmountMenu(reader)
+		require.True(t, ok)
+		assert.Equal(t, "EUR", amount.Currency)
+		assert.Equal(t, int64(12), amount.Units)
+		assert.Equal(t, int32(500_000_000), amount.Nanos)
+	})
+
+	t.Run("empty line cancels", func(t *testing.T) {
+		reader := bufio.NewReader(strings.NewReader("\n"))
+		_, ok := runAmountMenu(reader)
+		assert.False(t, ok)
+	})
+}
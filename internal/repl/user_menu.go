@@ -5,11 +5,10 @@ import (
 	"context"
 	"fmt"
 
-	userv1 "github.com/galadeat/bank-sim/api/proto/user/v1"
-	"google.golang.org/protobuf/types/known/wrapperspb"
+	"github.com/galadeat/bank-sim/pkg/clients"
 )
 
-func runUserMenu(reader *bufio.Reader, userClient userv1.UserClient) {
+func runUserMenu(reader *bufio.Reader, bank *clients.Bank) {
 	for {
 		fmt.Println("\n\t\t\t\tUser Menu")
 		fmt.Println("1) Create User")
@@ -23,15 +22,15 @@ func runUserMenu(reader *bufio.Reader, userClient userv1.UserClient) {
 
 		switch choice {
 		case "1":
-			handleCreateUser(reader, userClient)
+			handleCreateUser(reader, bank)
 		case "2":
-			handleGetUser(reader, userClient)
+			handleGetUser(reader, bank)
 		case "3":
-			handleListUsers(userClient)
+			handleListUsers(bank)
 		case "4":
-			handleUpdateUser(reader, userClient)
+			handleUpdateUser(reader, bank)
 		case "5":
-			handleDeleteUser(reader, userClient)
+			handleDeleteUser(reader, bank)
 		case "6":
 			return
 		default:
@@ -42,100 +41,76 @@ func runUserMenu(reader *bufio.Reader, userClient userv1.UserClient) {
 
 }
 
-func handleCreateUser(reader *bufio.Reader, userClient userv1.UserClient) {
+func handleCreateUser(reader *bufio.Reader, bank *clients.Bank) {
 	login := readInput(reader, "Enter your login: ")
 	email := readInput(reader, "Enter your email: ")
 
-	req := &userv1.CreateUserRequest{
-		Login: login,
-		Email: email,
-	}
-
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-
-	resp, err := userClient.CreateUser(ctx, req)
+	id, err := bank.CreateUser(context.Background(), login, email)
 	if err != nil {
 		fmt.Println("Error creating user: ", err)
 		return
 	}
-	fmt.Printf("User %s created.\n", resp.GetId())
+	fmt.Printf("User %s created.\n", id)
 
 }
 
-func handleGetUser(reader *bufio.Reader, userClient userv1.UserClient) {
-	id := runChooseUserMenu(reader, userClient)
+func handleGetUser(reader *bufio.Reader, bank *clients.Bank) {
+	id := runChooseUserMenu(reader, bank)
 	if id == "" {
 		return
 	}
-	resp, err := userClient.GetUser(context.Background(), &userv1.GetUserRequest{Id: id})
+	user, err := bank.GetUser(context.Background(), id)
 	if err != nil {
 		fmt.Println("Error getting user: ", err)
 		return
 	}
 
-	fmt.Printf("UserdID: %s\n", resp.GetUser().GetId())
-	fmt.Printf("Login: %s\n", resp.GetUser().GetLogin())
-	fmt.Printf("Email: %s\n", resp.GetUser().GetEmail())
+	fmt.Printf("UserdID: %s\n", user.GetId())
+	fmt.Printf("Login: %s\n", user.GetLogin())
+	fmt.Printf("Email: %s\n", user.GetEmail())
 
 }
 
-func handleListUsers(userClient userv1.UserClient) {
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+func handleListUsers(bank *clients.Bank) {
 	fmt.Println("\n\t\t\t\tUsers:")
-	resp, err := userClient.ListUsers(ctx, &userv1.ListUsersRequest{})
+	users, err := bank.ListUsers(context.Background())
 	if err != nil {
 		fmt.Println("Error listing users: ", err)
 		return
 	}
-	if len(resp.Users) == 0 {
+	if len(users) == 0 {
 		fmt.Println("No users found")
 		return
 	}
-	for i, user := range resp.Users {
+	for i, user := range users {
 		fmt.Printf("\nUser %d ID: %s", i+1, user.Id)
 	}
 	fmt.Print("\n")
 }
 
-func handleUpdateUser(reader *bufio.Reader, userClient userv1.UserClient) {
-	id := runChooseUserMenu(reader, userClient)
+func handleUpdateUser(reader *bufio.Reader, bank *clients.Bank) {
+	id := runChooseUserMenu(reader, bank)
 	if id == "" {
 		return
 	}
 	login := readInput(reader, "Enter new login or press Enter to skip: ")
 	email := readInput(reader, "Enter new email or press Enter to skip: ")
 
-	req := &userv1.UpdateUserRequest{
-		Login: wrapperspb.String(login),
-		Email: wrapperspb.String(email),
-		Id:    id,
-	}
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-
-	resp, err := userClient.UpdateUser(ctx, req)
+	user, err := bank.UpdateUser(context.Background(), id, login, email)
 	if err != nil {
 		fmt.Println("Error updating user: ", err)
 		return
 	}
-	fmt.Printf("User %s updated\n", resp.GetUser().GetId())
+	fmt.Printf("User %s updated\n", user.GetId())
 
 }
-func handleDeleteUser(reader *bufio.Reader, userClient userv1.UserClient) {
-	id := runChooseUserMenu(reader, userClient)
+func handleDeleteUser(reader *bufio.Reader, bank *clients.Bank) {
+	id := runChooseUserMenu(reader, bank)
 	if id == "" {
 		return
 	}
 
-	req := &userv1.DeleteUserRequest{Id: id}
-
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-
-	_, err := userClient.DeleteUser(ctx, req)
-	if err != nil {
+	if err := bank.DeleteUser(context.Background(), id); err != nil {
 		fmt.Println("Error deleting user: ", err)
 		return
 	}
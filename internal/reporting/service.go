@@ -0,0 +1,71 @@
+package reporting
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	accountv2 "github.com/galadeat/bank-sim/api/proto/account/v2"
+	reportingv1 "github.com/galadeat/bank-sim/api/proto/reporting/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Service builds account statements from the ledger kept by the account service.
+type Service struct {
+	reportingv1.UnimplementedReportingServer
+	accountClient accountv2.AccountClient
+}
+
+// New is the constructor
+func New(accountClient accountv2.AccountClient) *Service {
+	return &Service{accountClient: accountClient}
+}
+
+// GetStatement is the realization of the rpc method. Reversals and refunds are
+// linked to the entries they undo in both directions.
+func (s *Service) GetStatement(ctx context.Context, req *reportingv1.GetStatementRequest) (*reportingv1.GetStatementResponse, error) {
+	select {
+	case <-ctx.Done():
+		return nil, status.Error(codes.Canceled, "request canceled by client")
+	default:
+	}
+
+	if req.AccountId == "" {
+		return nil, status.Error(codes.InvalidArgument, "account id is required")
+	}
+
+	resp, err := s.accountClient.ListTransactions(ctx, &accountv2.ListTransactionsRequest{AccountId: req.AccountId})
+	if err != nil {
+		if st, ok := status.FromError(err); ok {
+			return nil, st.Err()
+		}
+		return nil, status.Errorf(codes.Internal, "failed to call AccountService: %v", err)
+	}
+
+	records := make([]*reportingv1.TransactionRecord, 0, len(resp.Transactions))
+	byID := make(map[string]*reportingv1.TransactionRecord, len(resp.Transactions))
+	for _, tx := range resp.Transactions {
+		rec := &reportingv1.TransactionRecord{
+			TransactionId:         tx.Id,
+			Type:                  typeName(tx.Type),
+			Amount:                tx.Amount,
+			Timestamp:             tx.CreatedAt.AsTime().Format(time.RFC3339),
+			BalanceAfter:          tx.BalanceAfter,
+			ReversesTransactionId: tx.ReversesTransactionId,
+		}
+		records = append(records, rec)
+		byID[tx.Id] = rec
+	}
+	for _, rec := range records {
+		if orig, ok := byID[rec.ReversesTransactionId]; ok {
+			orig.ReversedBy = append(orig.ReversedBy, rec.TransactionId)
+		}
+	}
+
+	return &reportingv1.GetStatementResponse{Records: records}, nil
+}
+
+func typeName(t accountv2.TransactionType) string {
+	return strings.ToLower(strings.TrimPrefix(t.String(), "TRANSACTION_TYPE_"))
+}
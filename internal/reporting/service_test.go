@@ -0,0 +1,61 @@
+package reporting
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	accountv2 "github.com/galadeat/bank-sim/api/proto/account/v2"
+	commonv1 "github.com/galadeat/bank-sim/api/proto/common/v1"
+	reportingv1 "github.com/galadeat/bank-sim/api/proto/reporting/v1"
+	"github.com/galadeat/bank-sim/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestGetStatement(t *testing.T) {
+	t.Run("links reversals to the original entry", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		account := mocks.NewMockAccountClient(ctrl)
+		at := timestamppb.New(time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC))
+		usd := func(units int64) *commonv1.Money { return &commonv1.Money{Currency: "USD", Units: units} }
+
+		account.EXPECT().
+			ListTransactions(gomock.Any(), gomock.Any()).
+			Return(&accountv2.ListTransactionsResponse{Transactions: []*accountv2.Transaction{
+				{Id: "tx-1", Type: accountv2.TransactionType_TRANSACTION_TYPE_WITHDRAWAL, Amount: usd(60), BalanceAfter: usd(40), CreatedAt: at},
+				{Id: "tx-2", Type: accountv2.TransactionType_TRANSACTION_TYPE_REFUND, Amount: usd(20), BalanceAfter: usd(60), CreatedAt: at, ReversesTransactionId: "tx-1"},
+				{Id: "tx-3", Type: accountv2.TransactionType_TRANSACTION_TYPE_REFUND, Amount: usd(40), BalanceAfter: usd(100), CreatedAt: at, ReversesTransactionId: "tx-1"},
+			}}, nil)
+
+		resp, err := New(account).GetStatement(context.Background(), &reportingv1.GetStatementRequest{AccountId: "acc-1"})
+		assert.NoError(t, err)
+		assert.Len(t, resp.Records, 3)
+		assert.Equal(t, "withdrawal", resp.Records[0].Type)
+		assert.Equal(t, "2025-01-01T09:00:00Z", resp.Records[0].Timestamp)
+		assert.Equal(t, []string{"tx-2", "tx-3"}, resp.Records[0].ReversedBy)
+		assert.Equal(t, "refund", resp.Records[1].Type)
+		assert.Equal(t, "tx-1", resp.Records[1].ReversesTransactionId)
+		assert.Empty(t, resp.Records[1].ReversedBy)
+	})
+
+	t.Run("passes account errors through", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		account := mocks.NewMockAccountClient(ctrl)
+		account.EXPECT().
+			ListTransactions(gomock.Any(), gomock.Any()).
+			Return(nil, status.Error(codes.NotFound, "account not found"))
+
+		_, err := New(account).GetStatement(context.Background(), &reportingv1.GetStatementRequest{AccountId: "missing"})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("account id is required", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		_, err := New(mocks.NewMockAccountClient(ctrl)).GetStatement(context.Background(), &reportingv1.GetStatementRequest{})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
@@ -0,0 +1,133 @@
+// Package resilience protects a service from the services it depends on: calls
+// get a deadline, transient failures are retried a bounded number of times and
+// a circuit breaker stops calling a dependency that keeps failing, so requests
+// fail fast with Unavailable instead of piling up behind it.
+package resilience
+
+import (
+	"sync"
+	"time"
+
+	"github.com/galadeat/bank-sim/pkg/clock"
+)
+
+// State is the state of a circuit breaker.
+type State int
+
+const (
+	// Closed lets every call through.
+	Closed State = iota
+	// Open rejects every call until the cool-down has passed.
+	Open
+	// HalfOpen lets one trial call through; its outcome closes or reopens the
+	// circuit.
+	HalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case Closed:
+		return "closed"
+	case Open:
+		return "open"
+	default:
+		return "half-open"
+	}
+}
+
+// Breaker is a circuit breaker. It opens after a number of consecutive
+// failures and, once the cool-down has passed, lets a single trial call decide
+// whether to close again.
+type Breaker struct {
+	threshold int
+	cooldown  time.Duration
+	clock     clock.Clock
+
+	mu       sync.Mutex
+	state    State
+	failures int
+	openedAt time.Time
+	trial    bool // a half-open trial call is in flight
+}
+
+// NewBreaker returns a closed breaker that opens after threshold consecutive
+// failures and stays open for cooldown.
+func NewBreaker(threshold int, cooldown time.Duration, c clock.Clock) *Breaker {
+	return &Breaker{threshold: max(threshold, 1), cooldown: cooldown, clock: c}
+}
+
+// Allow reports whether a call may go ahead. If it may not, it returns how long
+// until the breaker lets a trial call through. Every allowed call must be
+// followed by Record.
+func (b *Breaker) Allow() (bool, time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case Open:
+		wait := b.openedAt.Add(b.cooldown).Sub(b.clock.Now())
+		if wait > 0 {
+			return false, wait
+		}
+		b.state = HalfOpen
+		b.trial = true
+		return true, 0
+	case HalfOpen:
+		if b.trial {
+			return false, 0
+		}
+		b.trial = true
+		return true, 0
+	}
+	return true, 0
+}
+
+// Abandon gives back an allowed call that ended without telling anything about
+// the dependency, for example because the caller went away.
+func (b *Breaker) Abandon() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == HalfOpen {
+		b.trial = false
+	}
+}
+
+// Record reports the outcome of an allowed call.
+func (b *Breaker) Record(failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == HalfOpen {
+		b.trial = false
+		if failed {
+			b.open()
+		} else {
+			b.state, b.failures = Closed, 0
+		}
+		return
+	}
+	if !failed {
+		b.failures = 0
+		return
+	}
+	b.failures++
+	if b.state == Closed && b.failures >= b.threshold {
+		b.open()
+	}
+}
+
+// open opens the circuit. Callers must hold b.mu.
+func (b *Breaker) open() {
+	b.state = Open
+	b.openedAt = b.clock.Now()
+	b.failures = 0
+}
+
+// State returns the current state. An open breaker whose cool-down has passed
+// reports HalfOpen.
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == Open && !b.clock.Now().Before(b.openedAt.Add(b.cooldown)) {
+		return HalfOpen
+	}
+	return b.state
+}
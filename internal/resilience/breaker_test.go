@@ -0,0 +1,88 @@
+package resilience
+
+import (
+	"testing"
+	"time"
+
+	"github.com/galadeat/bank-sim/pkg/clock"
+	"github.com/stretchr/testify/assert"
+)
+
+var start = time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+
+func TestBreaker(t *testing.T) {
+	t.Run("opens after consecutive failures", func(t *testing.T) {
+		c := clock.NewManual(start)
+		b := NewBreaker(3, 10*time.Second, c)
+
+		for range 2 {
+			ok, _ := b.Allow()
+			assert.True(t, ok)
+			b.Record(true)
+		}
+		assert.Equal(t, Closed, b.State())
+
+		ok, _ := b.Allow()
+		assert.True(t, ok)
+		b.Record(true)
+		assert.Equal(t, Open, b.State())
+
+		c.Advance(4 * time.Second)
+		ok, wait := b.Allow()
+		assert.False(t, ok)
+		assert.Equal(t, 6*time.Second, wait)
+	})
+
+	t.Run("a success resets the count", func(t *testing.T) {
+		b := NewBreaker(2, time.Second, clock.NewManual(start))
+		b.Record(true)
+		b.Record(false)
+		b.Record(true)
+		assert.Equal(t, Closed, b.State())
+	})
+
+	t.Run("half-open lets one trial through", func(t *testing.T) {
+		c := clock.NewManual(start)
+		b := NewBreaker(1, 10*time.Second, c)
+		b.Record(true)
+		c.Advance(10 * time.Second)
+		assert.Equal(t, HalfOpen, b.State())
+
+		ok, _ := b.Allow()
+		assert.True(t, ok)
+		ok, _ = b.Allow()
+		assert.False(t, ok, "second call during the trial")
+
+		b.Record(false)
+		assert.Equal(t, Closed, b.State())
+		ok, _ = b.Allow()
+		assert.True(t, ok)
+	})
+
+	t.Run("failed trial reopens", func(t *testing.T) {
+		c := clock.NewManual(start)
+		b := NewBreaker(1, 10*time.Second, c)
+		b.Record(true)
+		c.Advance(10 * time.Second)
+
+		ok, _ := b.Allow()
+		assert.True(t, ok)
+		b.Record(true)
+		assert.Equal(t, Open, b.State())
+		_, wait := b.Allow()
+		assert.Equal(t, 10*time.Second, wait)
+	})
+
+	t.Run("abandoned trial frees the slot", func(t *testing.T) {
+		c := clock.NewManual(start)
+		b := NewBreaker(1, time.Second, c)
+		b.Record(true)
+		c.Advance(time.Second)
+
+		ok, _ := b.Allow()
+		assert.True(t, ok)
+		b.Abandon()
+		ok, _ = b.Allow()
+		assert.True(t, ok)
+	})
+}
@@ -0,0 +1,249 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"strconv"
+	"time"
+
+	"github.com/galadeat/bank-sim/pkg/clock"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// Domain is the ErrorInfo domain of the errors returned for an unavailable
+// dependency.
+const Domain = "resilience.bank-sim"
+
+// Reasons reported in the ErrorInfo of an Unavailable error.
+const (
+	// ReasonCircuitOpen means the call was not made because the dependency has
+	// been failing; a RetryInfo tells when the next call goes through.
+	ReasonCircuitOpen = "CIRCUIT_OPEN"
+	// ReasonDependencyUnavailable means every attempt failed or timed out.
+	ReasonDependencyUnavailable = "DEPENDENCY_UNAVAILABLE"
+)
+
+const maxBackoff = 2 * time.Second
+
+// Option configures a Guard or a client built on one.
+type Option func(*options)
+
+type options struct {
+	timeout   time.Duration
+	attempts  int
+	backoff   time.Duration
+	threshold int
+	cooldown  time.Duration
+	cacheTTL  time.Duration
+	clock     clock.Clock
+}
+
+func newOptions(opts []Option) options {
+	o := options{
+		timeout:   2 * time.Second,
+		attempts:  3,
+		backoff:   100 * time.Millisecond,
+		threshold: 5,
+		cooldown:  10 * time.Second,
+		clock:     clock.Real(),
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithTimeout sets the deadline of each attempt, 2s by default. A shorter
+// deadline of the caller's context still applies; zero leaves only the
+// caller's.
+func WithTimeout(d time.Duration) Option {
+	return func(o *options) {
+		o.timeout = d
+	}
+}
+
+// WithRetries makes up to attempts calls, 3 by default, while the dependency is
+// unavailable or times out. The wait between attempts starts at backoff and
+// doubles, with jitter, up to 2s.
+func WithRetries(attempts int, backoff time.Duration) Option {
+	return func(o *options) {
+		o.attempts = max(attempts, 1)
+		o.backoff = backoff
+	}
+}
+
+// WithBreaker opens the circuit after threshold consecutive failed attempts,
+// 5 by default, and fails calls straight away for cooldown, 10s by default.
+func WithBreaker(threshold int, cooldown time.Duration) Option {
+	return func(o *options) {
+		o.threshold = threshold
+		o.cooldown = cooldown
+	}
+}
+
+// WithCache keeps successful lookups for ttl, so repeated calls for the same
+// entity are answered without calling the dependency. Off by default.
+func WithCache(ttl time.Duration) Option {
+	return func(o *options) {
+		o.cacheTTL = ttl
+	}
+}
+
+// WithClock sets the clock of the breaker, the cache and the backoff.
+func WithClock(c clock.Clock) Option {
+	return func(o *options) {
+		o.clock = c
+	}
+}
+
+// Guard runs calls to one dependency with a deadline per attempt, bounded
+// retries and a circuit breaker. Only Unavailable and timed out attempts count
+// as failures: any other answer, NotFound included, shows the dependency is up
+// and is returned as is.
+type Guard struct {
+	dependency string
+	opts       options
+	breaker    *Breaker
+}
+
+// NewGuard returns a guard for calls to dependency, which names it in logs and
+// errors.
+func NewGuard(dependency string, opts ...Option) *Guard {
+	return newGuard(dependency, newOptions(opts))
+}
+
+func newGuard(dependency string, o options) *Guard {
+	return &Guard{
+		dependency: dependency,
+		opts:       o,
+		breaker:    NewBreaker(o.threshold, o.cooldown, o.clock),
+	}
+}
+
+// Breaker returns the circuit breaker of the guard.
+func (g *Guard) Breaker() *Breaker {
+	return g.breaker
+}
+
+// Do calls fn until it succeeds, returns an error that is not transient or runs
+// out of attempts. Calls cut short by ctx return its error as a status.
+func (g *Guard) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	var err error
+	for attempt := 1; ; attempt++ {
+		allowed, wait := g.breaker.Allow()
+		if !allowed {
+			return g.circuitOpen(wait)
+		}
+
+		callCtx, cancel := ctx, context.CancelFunc(func() {})
+		if g.opts.timeout > 0 {
+			callCtx, cancel = context.WithTimeout(ctx, g.opts.timeout)
+		}
+		err = fn(callCtx)
+		cancel()
+
+		if err != nil && ctx.Err() != nil {
+			g.breaker.Abandon()
+			return status.FromContextError(ctx.Err()).Err()
+		}
+		failed := transient(err)
+		g.record(failed)
+		if !failed {
+			return err
+		}
+		if attempt >= g.opts.attempts {
+			return g.unavailable(attempt, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case <-g.opts.clock.After(g.backoff(attempt)):
+		}
+	}
+}
+
+// record reports an attempt to the breaker and logs when the circuit opens or
+// closes.
+func (g *Guard) record(failed bool) {
+	before := g.breaker.State()
+	g.breaker.Record(failed)
+	after := g.breaker.State()
+	switch {
+	case after == Open && before != Open:
+		log.Printf("circuit to %s service opened, calls fail fast for %s", g.dependency, g.opts.cooldown)
+	case after == Closed && before != Closed:
+		log.Printf("circuit to %s service closed", g.dependency)
+	}
+}
+
+// backoff returns the wait after the given attempt.
+func (g *Guard) backoff(attempt int) time.Duration {
+	if g.opts.backoff <= 0 {
+		return 0
+	}
+	d := min(g.opts.backoff<<(attempt-1), maxBackoff)
+	return d/2 + rand.N(d/2+1)
+}
+
+func (g *Guard) circuitOpen(wait time.Duration) error {
+	msg := fmt.Sprintf("%s service unavailable: circuit open after repeated failures", g.dependency)
+	details := []protoadapt.MessageV1{&errdetails.ErrorInfo{
+		Reason:   ReasonCircuitOpen,
+		Domain:   Domain,
+		Metadata: map[string]string{"dependency": g.dependency},
+	}}
+	if wait > 0 {
+		msg += fmt.Sprintf(", retry in %s", wait.Round(time.Millisecond))
+		details = append(details, &errdetails.RetryInfo{RetryDelay: durationpb.New(wait)})
+	}
+	return unavailableError(msg, details)
+}
+
+func (g *Guard) unavailable(attempts int, cause error) error {
+	st := status.Convert(cause)
+	code := st.Code()
+	if errors.Is(cause, context.DeadlineExceeded) {
+		code = codes.DeadlineExceeded
+	}
+	msg := fmt.Sprintf("%s service unavailable after %d attempts: %s", g.dependency, attempts, st.Message())
+	return unavailableError(msg, []protoadapt.MessageV1{&errdetails.ErrorInfo{
+		Reason: ReasonDependencyUnavailable,
+		Domain: Domain,
+		Metadata: map[string]string{
+			"dependency": g.dependency,
+			"attempts":   strconv.Itoa(attempts),
+			"cause":      code.String(),
+		},
+	}})
+}
+
+// transient reports whether err means the dependency could not answer.
+func transient(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return true
+	}
+	return false
+}
+
+func unavailableError(msg string, details []protoadapt.MessageV1) error {
+	st, err := status.New(codes.Unavailable, msg).WithDetails(details...)
+	if err != nil {
+		return status.Error(codes.Unavailable, msg)
+	}
+	return st.Err()
+}
@@ -0,0 +1,149 @@
+package resilience
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	userv1 "github.com/galadeat/bank-sim/api/proto/user/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+)
+
+// maxCached is the number of cached users past which expired entries are
+// swept out; if none has expired the cache starts over.
+const maxCached = 1024
+
+// UserClient is a user service client whose GetUser calls are guarded, and
+// optionally cached, so a slow or failing user service makes its callers fail
+// fast with Unavailable instead of hanging. The other methods go straight to
+// the wrapped client.
+type UserClient struct {
+	userv1.UserClient
+	guard *Guard
+	ttl   time.Duration
+
+	mu    sync.Mutex
+	cache map[string]cachedUser
+	// forgotten counts the calls to Forget, so a lookup that was under way
+	// meanwhile does not cache what it got
+	forgotten uint64
+}
+
+type cachedUser struct {
+	resp    *userv1.GetUserResponse
+	expires time.Time
+}
+
+// NewUserClient is the constructor
+func NewUserClient(inner userv1.UserClient, opts ...Option) *UserClient {
+	o := newOptions(opts)
+	return &UserClient{
+		UserClient: inner,
+		guard:      newGuard("user", o),
+		ttl:        o.cacheTTL,
+		cache:      make(map[string]cachedUser),
+	}
+}
+
+// Guard returns the guard of the GetUser calls.
+func (c *UserClient) Guard() *Guard {
+	return c.guard
+}
+
+// GetUser returns the user from the cache or, failing that, from the user
+// service. Lookups that include closed users bypass the cache, which only
+// holds the answers to plain lookups.
+//
+// Changes made through other clients reach the cache only through Forget; the
+// account service calls it when the user service reports a change of the user.
+func (c *UserClient) GetUser(ctx context.Context, in *userv1.GetUserRequest, opts ...grpc.CallOption) (*userv1.GetUserResponse, error) {
+	cacheable := !in.GetIncludeClosed()
+	if resp, ok := c.cached(in.GetId()); ok && cacheable {
+		return resp, nil
+	}
+	generation := c.generation()
+
+	var resp *userv1.GetUserResponse
+	err := c.guard.Do(ctx, func(ctx context.Context) error {
+		var err error
+		resp, err = c.UserClient.GetUser(ctx, in, opts...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if cacheable {
+		c.store(in.GetId(), resp, generation)
+	}
+	return resp, nil
+}
+
+// UpdateUser updates the user and drops it from the cache.
+func (c *UserClient) UpdateUser(ctx context.Context, in *userv1.UpdateUserRequest, opts ...grpc.CallOption) (*userv1.UpdateUserResponse, error) {
+	c.Forget(in.GetId())
+	return c.UserClient.UpdateUser(ctx, in, opts...)
+}
+
+// DeleteUser deletes the user and drops it from the cache.
+func (c *UserClient) DeleteUser(ctx context.Context, in *userv1.DeleteUserRequest, opts ...grpc.CallOption) (*userv1.DeleteUserResponse, error) {
+	c.Forget(in.GetId())
+	return c.UserClient.DeleteUser(ctx, in, opts...)
+}
+
+func (c *UserClient) cached(id string) (*userv1.GetUserResponse, bool) {
+	if c.ttl <= 0 {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.cache[id]
+	if !ok || !c.guard.opts.clock.Now().Before(entry.expires) {
+		return nil, false
+	}
+	return proto.Clone(entry.resp).(*userv1.GetUserResponse), true
+}
+
+// store caches resp unless a user was forgotten since generation.
+func (c *UserClient) store(id string, resp *userv1.GetUserResponse, generation uint64) {
+	if c.ttl <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.forgotten != generation {
+		return
+	}
+	now := c.guard.opts.clock.Now()
+	if len(c.cache) >= maxCached {
+		c.sweep(now)
+	}
+	if len(c.cache) >= maxCached {
+		clear(c.cache)
+	}
+	c.cache[id] = cachedUser{resp: proto.Clone(resp).(*userv1.GetUserResponse), expires: now.Add(c.ttl)}
+}
+
+func (c *UserClient) generation() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.forgotten
+}
+
+// Forget drops the user from the cache, so the next lookup asks the user
+// service.
+func (c *UserClient) Forget(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.cache, id)
+	c.forgotten++
+}
+
+// sweep drops the expired entries. Callers must hold c.mu.
+func (c *UserClient) sweep(now time.Time) {
+	for id, entry := range c.cache {
+		if !now.Before(entry.expires) {
+			delete(c.cache, id)
+		}
+	}
+}
@@ -0,0 +1,232 @@
+package resilience
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	userv1 "github.com/galadeat/bank-sim/api/proto/user/v1"
+	"github.com/galadeat/bank-sim/mocks"
+	"github.com/galadeat/bank-sim/pkg/clock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var alice = &userv1.GetUserResponse{User: &userv1.UserInfo{Id: "user-1", Login: "alice"}}
+
+func errorInfo(t *testing.T, err error) *errdetails.ErrorInfo {
+	t.Helper()
+	for _, d := range status.Convert(err).Details() {
+		if info, ok := d.(*errdetails.ErrorInfo); ok {
+			return info
+		}
+	}
+	t.Fatalf("no ErrorInfo in %v", err)
+	return nil
+}
+
+func TestUserClient_GetUser(t *testing.T) {
+	req := &userv1.GetUserRequest{Id: "user-1"}
+
+	t.Run("retries while unavailable", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		inner := mocks.NewMockUserClient(ctrl)
+		gomock.InOrder(
+			inner.EXPECT().GetUser(gomock.Any(), gomock.Any()).Return(nil, status.Error(codes.Unavailable, "connection refused")),
+			inner.EXPECT().GetUser(gomock.Any(), gomock.Any()).Return(alice, nil),
+		)
+
+		c := NewUserClient(inner, WithRetries(3, 0))
+		resp, err := c.GetUser(context.Background(), req)
+		require.NoError(t, err)
+		assert.Equal(t, "alice", resp.User.Login)
+	})
+
+	t.Run("business errors pass through", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		inner := mocks.NewMockUserClient(ctrl)
+		inner.EXPECT().GetUser(gomock.Any(), gomock.Any()).Return(nil, status.Error(codes.NotFound, "user not found")).Times(1)
+
+		c := NewUserClient(inner, WithRetries(3, 0), WithBreaker(1, time.Minute))
+		_, err := c.GetUser(context.Background(), req)
+		assert.Equal(t, codes.NotFound, status.Code(err))
+		assert.Equal(t, Closed, c.Guard().Breaker().State())
+	})
+
+	t.Run("gives up with details", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		inner := mocks.NewMockUserClient(ctrl)
+		inner.EXPECT().GetUser(gomock.Any(), gomock.Any()).Return(nil, status.Error(codes.Unavailable, "connection refused")).Times(3)
+
+		c := NewUserClient(inner, WithRetries(3, 0))
+		_, err := c.GetUser(context.Background(), req)
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+		assert.Contains(t, err.Error(), "user service unavailable after 3 attempts: connection refused")
+		info := errorInfo(t, err)
+		assert.Equal(t, ReasonDependencyUnavailable, info.Reason)
+		assert.Equal(t, map[string]string{"dependency": "user", "attempts": "3", "cause": "Unavailable"}, info.Metadata)
+	})
+
+	t.Run("times out a hung call", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		inner := mocks.NewMockUserClient(ctrl)
+		inner.EXPECT().GetUser(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, _ *userv1.GetUserRequest, _ ...grpc.CallOption) (*userv1.GetUserResponse, error) {
+				<-ctx.Done()
+				return nil, status.FromContextError(ctx.Err()).Err()
+			}).Times(2)
+
+		c := NewUserClient(inner, WithTimeout(20*time.Millisecond), WithRetries(2, 0))
+		begin := time.Now()
+		_, err := c.GetUser(context.Background(), req)
+		assert.Less(t, time.Since(begin), time.Second)
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+		assert.Equal(t, "DeadlineExceeded", errorInfo(t, err).Metadata["cause"])
+	})
+
+	t.Run("caller's deadline is not a failure", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		inner := mocks.NewMockUserClient(ctrl)
+		inner.EXPECT().GetUser(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, _ *userv1.GetUserRequest, _ ...grpc.CallOption) (*userv1.GetUserResponse, error) {
+				<-ctx.Done()
+				return nil, status.FromContextError(ctx.Err()).Err()
+			})
+
+		c := NewUserClient(inner, WithRetries(3, 0), WithBreaker(1, time.Minute))
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := c.GetUser(ctx, req)
+		assert.Equal(t, codes.Canceled, status.Code(err))
+		assert.Equal(t, Closed, c.Guard().Breaker().State())
+	})
+
+	t.Run("open circuit fails fast", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		inner := mocks.NewMockUserClient(ctrl)
+		inner.EXPECT().GetUser(gomock.Any(), gomock.Any()).Return(nil, status.Error(codes.Unavailable, "connection refused")).Times(2)
+
+		c := NewUserClient(inner, WithClock(clock.NewManual(start)), WithRetries(1, 0), WithBreaker(2, 30*time.Second))
+		for range 2 {
+			_, err := c.GetUser(context.Background(), req)
+			assert.Equal(t, ReasonDependencyUnavailable, errorInfo(t, err).Reason)
+		}
+
+		_, err := c.GetUser(context.Background(), req)
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+		assert.Equal(t, ReasonCircuitOpen, errorInfo(t, err).Reason)
+		var retry *errdetails.RetryInfo
+		for _, d := range status.Convert(err).Details() {
+			if r, ok := d.(*errdetails.RetryInfo); ok {
+				retry = r
+			}
+		}
+		require.NotNil(t, retry)
+		assert.Equal(t, 30*time.Second, retry.RetryDelay.AsDuration())
+	})
+
+	t.Run("circuit closes after a successful trial", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		inner := mocks.NewMockUserClient(ctrl)
+		gomock.InOrder(
+			inner.EXPECT().GetUser(gomock.Any(), gomock.Any()).Return(nil, status.Error(codes.Unavailable, "connection refused")),
+			inner.EXPECT().GetUser(gomock.Any(), gomock.Any()).Return(alice, nil),
+		)
+
+		c := clock.NewManual(start)
+		client := NewUserClient(inner, WithClock(c), WithRetries(1, 0), WithBreaker(1, 10*time.Second))
+		_, err := client.GetUser(context.Background(), req)
+		require.Error(t, err)
+
+		c.Advance(10 * time.Second)
+		_, err = client.GetUser(context.Background(), req)
+		require.NoError(t, err)
+		assert.Equal(t, Closed, client.Guard().Breaker().State())
+	})
+}
+
+func TestUserClient_Cache(t *testing.T) {
+	req := &userv1.GetUserRequest{Id: "user-1"}
+
+	t.Run("answers from the cache until the entry expires", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		inner := mocks.NewMockUserClient(ctrl)
+		inner.EXPECT().GetUser(gomock.Any(), gomock.Any()).Return(alice, nil).Times(2)
+
+		c := clock.NewManual(start)
+		client := NewUserClient(inner, WithClock(c), WithCache(30*time.Second))
+		for range 3 {
+			resp, err := client.GetUser(context.Background(), req)
+			require.NoError(t, err)
+			assert.Equal(t, "alice", resp.User.Login)
+		}
+
+		c.Advance(30 * time.Second)
+		_, err := client.GetUser(context.Background(), req)
+		require.NoError(t, err)
+	})
+
+	t.Run("errors are not cached", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		inner := mocks.NewMockUserClient(ctrl)
+		inner.EXPECT().GetUser(gomock.Any(), gomock.Any()).Return(nil, status.Error(codes.NotFound, "user not found")).Times(2)
+
+		client := NewUserClient(inner, WithCache(time.Minute))
+		for range 2 {
+			_, err := client.GetUser(context.Background(), req)
+			assert.Equal(t, codes.NotFound, status.Code(err))
+		}
+	})
+
+	t.Run("delete drops the entry", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		inner := mocks.NewMockUserClient(ctrl)
+		inner.EXPECT().GetUser(gomock.Any(), gomock.Any()).Return(alice, nil)
+		inner.EXPECT().DeleteUser(gomock.Any(), gomock.Any()).Return(&userv1.DeleteUserResponse{}, nil)
+		inner.EXPECT().GetUser(gomock.Any(), gomock.Any()).Return(nil, status.Error(codes.NotFound, "user not found"))
+
+		client := NewUserClient(inner, WithCache(time.Minute))
+		_, err := client.GetUser(context.Background(), req)
+		require.NoError(t, err)
+		_, err = client.DeleteUser(context.Background(), &userv1.DeleteUserRequest{Id: "user-1"})
+		require.NoError(t, err)
+		_, err = client.GetUser(context.Background(), req)
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("forget drops the entry", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		inner := mocks.NewMockUserClient(ctrl)
+		inner.EXPECT().GetUser(gomock.Any(), gomock.Any()).Return(alice, nil)
+		inner.EXPECT().GetUser(gomock.Any(), gomock.Any()).Return(nil, status.Error(codes.NotFound, "user not found"))
+
+		client := NewUserClient(inner, WithCache(time.Minute))
+		_, err := client.GetUser(context.Background(), req)
+		require.NoError(t, err)
+		client.Forget("user-1")
+		_, err = client.GetUser(context.Background(), req)
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("a lookup under way when the user is forgotten is not cached", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		inner := mocks.NewMockUserClient(ctrl)
+		client := NewUserClient(inner, WithCache(time.Minute))
+		inner.EXPECT().GetUser(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(context.Context, *userv1.GetUserRequest, ...grpc.CallOption) (*userv1.GetUserResponse, error) {
+				client.Forget("user-1")
+				return alice, nil
+			})
+		inner.EXPECT().GetUser(gomock.Any(), gomock.Any()).Return(nil, status.Error(codes.NotFound, "user not found"))
+
+		_, err := client.GetUser(context.Background(), req)
+		require.NoError(t, err)
+		_, err = client.GetUser(context.Background(), req)
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+}
@@ -0,0 +1,117 @@
+package risk
+
+import (
+	"time"
+
+	accountv2 "github.com/galadeat/bank-sim/api/proto/account/v2"
+	commonv1 "github.com/galadeat/bank-sim/api/proto/common/v1"
+)
+
+const nanosPerUnit = 1_000_000_000
+
+// Reasons reported by the heuristic scorer.
+const (
+	ReasonUnusualAmount    = "unusual_amount"
+	ReasonRapidWithdrawals = "rapid_withdrawals"
+	ReasonNewAccountLarge  = "new_account_large_withdrawal"
+)
+
+// HeuristicConfig tunes the built-in scorer. Every signal that fires adds its
+// points; the total is compared against the review and decline thresholds.
+type HeuristicConfig struct {
+	// A payment is unusual when it exceeds AmountMultiple times the average of at
+	// least MinHistory earlier payments.
+	AmountMultiple int64
+	MinHistory     int
+	AmountPoints   int
+
+	// Withdrawals are rapid when RapidCount earlier payments happened within RapidWindow.
+	RapidCount  int
+	RapidWindow time.Duration
+	RapidPoints int
+
+	// A large payment from an account younger than NewAccountAge is suspicious.
+	NewAccountAge    time.Duration
+	NewAccountLarge  int64 // in currency units
+	NewAccountPoints int
+
+	ReviewScore  int
+	DeclineScore int
+}
+
+// DefaultHeuristic holds the thresholds used by the server.
+var DefaultHeuristic = HeuristicConfig{
+	AmountMultiple:   5,
+	MinHistory:       3,
+	AmountPoints:     50,
+	RapidCount:       3,
+	RapidWindow:      10 * time.Minute,
+	RapidPoints:      50,
+	NewAccountAge:    7 * 24 * time.Hour,
+	NewAccountLarge:  1000,
+	NewAccountPoints: 60,
+	ReviewScore:      50,
+	DeclineScore:     100,
+}
+
+// Heuristic is a scorer based on the account's own payment history.
+type Heuristic struct {
+	cfg HeuristicConfig
+}
+
+// NewHeuristic is the constructor
+func NewHeuristic(cfg HeuristicConfig) *Heuristic {
+	return &Heuristic{cfg: cfg}
+}
+
+// Assess scores p against the payments in its history.
+func (h *Heuristic) Assess(p Payment) Assessment {
+	amount := toNanos(p.Amount)
+
+	var a Assessment
+	add := func(points int, reason string) {
+		a.Score += points
+		a.Reasons = append(a.Reasons, reason)
+	}
+
+	var total int64
+	var count, recent int
+	for _, tx := range p.History {
+		if !isDebit(tx.Type) {
+			continue
+		}
+		total += toNanos(tx.Amount)
+		count++
+		if p.At.Sub(tx.CreatedAt.AsTime()) <= h.cfg.RapidWindow {
+			recent++
+		}
+	}
+
+	if count >= h.cfg.MinHistory && count > 0 && amount > h.cfg.AmountMultiple*(total/int64(count)) {
+		add(h.cfg.AmountPoints, ReasonUnusualAmount)
+	}
+	if h.cfg.RapidCount > 0 && recent >= h.cfg.RapidCount {
+		add(h.cfg.RapidPoints, ReasonRapidWithdrawals)
+	}
+	if p.At.Sub(p.OpenedAt) < h.cfg.NewAccountAge && amount >= h.cfg.NewAccountLarge*nanosPerUnit {
+		add(h.cfg.NewAccountPoints, ReasonNewAccountLarge)
+	}
+
+	switch {
+	case a.Score >= h.cfg.DeclineScore:
+		a.Decision = Decline
+	case a.Score >= h.cfg.ReviewScore:
+		a.Decision = Review
+	default:
+		a.Decision = Allow
+	}
+	return a
+}
+
+func isDebit(t accountv2.TransactionType) bool {
+	return t == accountv2.TransactionType_TRANSACTION_TYPE_WITHDRAWAL || t == accountv2.TransactionType_TRANSACTION_TYPE_TRANSFER_OUT
+}
+
+func toNanos(m *commonv1.Money) int64 {
+	return m.GetUnits()*nanosPerUnit + int64(m.GetNanos())
+}
@@ -0,0 +1,100 @@
+package risk
+
+import (
+	"testing"
+	"time"
+
+	accountv2 "github.com/galadeat/bank-sim/api/proto/account/v2"
+	commonv1 "github.com/galadeat/bank-sim/api/proto/common/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var now = time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func usd(units int64) *commonv1.Money {
+	return &commonv1.Money{Currency: "USD", Units: units}
+}
+
+func debit(units int64, ago time.Duration) *accountv2.Transaction {
+	return &accountv2.Transaction{
+		Type:      accountv2.TransactionType_TRANSACTION_TYPE_WITHDRAWAL,
+		Amount:    usd(units),
+		CreatedAt: timestamppb.New(now.Add(-ago)),
+	}
+}
+
+func TestHeuristic(t *testing.T) {
+	old := now.AddDate(-1, 0, 0)
+	day := 24 * time.Hour
+	routine := []*accountv2.Transaction{debit(100, 3*day), debit(100, 2*day), debit(100, day)}
+
+	tests := []struct {
+		name     string
+		payment  Payment
+		want     Decision
+		wantWhy  []string
+		wantNone bool
+	}{
+		{
+			name:     "routine payment",
+			payment:  Payment{Amount: usd(120), OpenedAt: old, At: now, History: routine},
+			want:     Allow,
+			wantNone: true,
+		},
+		{
+			name:    "unusual amount",
+			payment: Payment{Amount: usd(501), OpenedAt: old, At: now, History: routine},
+			want:    Review,
+			wantWhy: []string{ReasonUnusualAmount},
+		},
+		{
+			name:     "too little history to judge the amount",
+			payment:  Payment{Amount: usd(900), OpenedAt: old, At: now, History: routine[:2]},
+			want:     Allow,
+			wantNone: true,
+		},
+		{
+			name: "rapid withdrawals",
+			payment: Payment{Amount: usd(100), OpenedAt: old, At: now, History: []*accountv2.Transaction{
+				debit(100, 5*time.Minute), debit(100, 3*time.Minute), debit(100, time.Minute),
+			}},
+			want:    Review,
+			wantWhy: []string{ReasonRapidWithdrawals},
+		},
+		{
+			name:    "large withdrawal from a new account",
+			payment: Payment{Amount: usd(1000), OpenedAt: now.Add(-day), At: now},
+			want:    Review,
+			wantWhy: []string{ReasonNewAccountLarge},
+		},
+		{
+			name: "new account drained in a hurry",
+			payment: Payment{Amount: usd(2000), OpenedAt: now.Add(-time.Hour), At: now, History: []*accountv2.Transaction{
+				debit(10, 3*time.Minute), debit(10, 2*time.Minute), debit(10, time.Minute),
+			}},
+			want:    Decline,
+			wantWhy: []string{ReasonUnusualAmount, ReasonRapidWithdrawals, ReasonNewAccountLarge},
+		},
+	}
+
+	h := NewHeuristic(DefaultHeuristic)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := h.Assess(tt.payment)
+			if got.Decision != tt.want {
+				t.Errorf("expected %v, got %v (score %d, reasons %v)", tt.want, got.Decision, got.Score, got.Reasons)
+			}
+			if tt.wantNone && len(got.Reasons) != 0 {
+				t.Errorf("expected no reasons, got %v", got.Reasons)
+			}
+			if len(tt.wantWhy) > 0 && len(got.Reasons) != len(tt.wantWhy) {
+				t.Fatalf("expected reasons %v, got %v", tt.wantWhy, got.Reasons)
+			}
+			for i, r := range tt.wantWhy {
+				if got.Reasons[i] != r {
+					t.Errorf("expected reasons %v, got %v", tt.wantWhy, got.Reasons)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package risk
+
+import (
+	"time"
+
+	accountv2 "github.com/galadeat/bank-sim/api/proto/account/v2"
+	commonv1 "github.com/galadeat/bank-sim/api/proto/common/v1"
+)
+
+// Decision is what should happen to a payment.
+type Decision int
+
+const (
+	Allow Decision = iota
+	Review
+	Decline
+)
+
+func (d Decision) String() string {
+	switch d {
+	case Allow:
+		return "allow"
+	case Review:
+		return "review"
+	case Decline:
+		return "decline"
+	default:
+		return "unknown"
+	}
+}
+
+// Payment is an outgoing payment about to be committed.
+type Payment struct {
+	Type      accountv2.TransactionType // WITHDRAWAL or TRANSFER_OUT
+	AccountID string
+	UserID    string
+	Amount    *commonv1.Money
+	OpenedAt  time.Time
+	At        time.Time
+	// History is the ledger of the paying account, oldest first. Scorers must not
+	// modify it.
+	History []*accountv2.Transaction
+}
+
+// Assessment is the outcome of scoring a payment.
+type Assessment struct {
+	Decision Decision
+	Score    int
+	Reasons  []string
+}
@@ -0,0 +1,152 @@
+package rules
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+
+	accountv2 "github.com/galadeat/bank-sim/api/proto/account/v2"
+)
+
+// Config is the rules file. Policies are keyed by account type name (checking,
+// savings, term_deposit); the "default" policy applies to types without one.
+//
+// Amounts are decimal strings in the account's currency, e.g. "2500" or "99.95".
+// An empty amount or a zero count means no limit.
+type Config struct {
+	AccountTypes map[string]Policy `json:"account_types"`
+}
+
+// Policy holds the limits of one account type.
+type Policy struct {
+	Deposit Limits `json:"deposit"`
+	// Withdrawal limits apply to withdrawals and outgoing transfers.
+	Withdrawal Limits `json:"withdrawal"`
+	// Velocity rules count every operation, whatever its kind.
+	Velocity []Velocity `json:"velocity"`
+}
+
+// Limits caps the amount of one kind of operation on an account.
+type Limits struct {
+	MaxAmount     string `json:"max_amount,omitempty"`
+	DailyAmount   string `json:"daily_amount,omitempty"`
+	MonthlyAmount string `json:"monthly_amount,omitempty"`
+}
+
+// Velocity caps the number of operations within a sliding window, either per
+// account or across all accounts of the user.
+type Velocity struct {
+	Scope    Scope    `json:"scope"`
+	MaxCount int      `json:"max_count"`
+	Window   Duration `json:"window"`
+}
+
+// Scope is what a velocity rule counts operations of.
+type Scope string
+
+const (
+	ScopeAccount Scope = "account"
+	ScopeUser    Scope = "user"
+)
+
+// Duration is a time.Duration written as "30s", "1m" or "24h" in the rules file.
+type Duration time.Duration
+
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("duration must be a string: %w", err)
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// Load reads a rules file and builds an engine from it.
+func Load(path string) (*Engine, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var cfg Config
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&cfg); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return New(cfg)
+}
+
+// compiled is a Policy with its amounts parsed to nanos.
+type compiled struct {
+	limits   map[Kind]limits
+	velocity []Velocity
+}
+
+type limits struct {
+	max, daily, monthly int64
+}
+
+func compile(p Policy) (compiled, error) {
+	c := compiled{limits: make(map[Kind]limits), velocity: p.Velocity}
+	for kind, l := range map[Kind]Limits{KindDeposit: p.Deposit, KindWithdrawal: p.Withdrawal} {
+		var parsed limits
+		var err error
+		if parsed.max, err = parseAmount(l.MaxAmount); err != nil {
+			return compiled{}, fmt.Errorf("%s max_amount: %w", kind, err)
+		}
+		if parsed.daily, err = parseAmount(l.DailyAmount); err != nil {
+			return compiled{}, fmt.Errorf("%s daily_amount: %w", kind, err)
+		}
+		if parsed.monthly, err = parseAmount(l.MonthlyAmount); err != nil {
+			return compiled{}, fmt.Errorf("%s monthly_amount: %w", kind, err)
+		}
+		c.limits[kind] = parsed
+	}
+	for _, v := range p.Velocity {
+		if v.Scope != ScopeAccount && v.Scope != ScopeUser {
+			return compiled{}, fmt.Errorf("velocity scope must be %q or %q, got %q", ScopeAccount, ScopeUser, v.Scope)
+		}
+		if v.MaxCount < 0 || v.Window <= 0 {
+			return compiled{}, fmt.Errorf("velocity rule needs a non-negative max_count and a positive window")
+		}
+	}
+	return c, nil
+}
+
+// accountType maps a policy key to the account type it configures.
+func accountType(name string) (accountv2.AccountType, bool) {
+	v, ok := accountv2.AccountType_value["ACCOUNT_TYPE_"+strings.ToUpper(name)]
+	if !ok || v == 0 {
+		return 0, false
+	}
+	return accountv2.AccountType(v), true
+}
+
+// parseAmount converts a decimal string to nanos. An empty string is zero.
+func parseAmount(s string) (int64, error) {
+	if s == "" {
+		return 0, nil
+	}
+	r, ok := new(big.Rat).SetString(s)
+	if !ok || r.Sign() < 0 {
+		return 0, fmt.Errorf("invalid amount %q", s)
+	}
+	r.Mul(r, big.NewRat(nanosPerUnit, 1))
+	if !r.IsInt() || !r.Num().IsInt64() {
+		return 0, fmt.Errorf("invalid amount %q", s)
+	}
+	return r.Num().Int64(), nil
+}
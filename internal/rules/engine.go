@@ -0,0 +1,291 @@
+package rules
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	accountv2 "github.com/galadeat/bank-sim/api/proto/account/v2"
+	commonv1 "github.com/galadeat/bank-sim/api/proto/common/v1"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const nanosPerUnit = 1_000_000_000
+
+// Domain is the ErrorInfo domain of rule violations.
+const Domain = "rules.bank-sim"
+
+// Reasons reported in the ErrorInfo of a violation.
+const (
+	ReasonAmountLimit   = "AMOUNT_LIMIT_EXCEEDED"
+	ReasonDailyLimit    = "DAILY_LIMIT_EXCEEDED"
+	ReasonMonthlyLimit  = "MONTHLY_LIMIT_EXCEEDED"
+	ReasonVelocityLimit = "VELOCITY_LIMIT_EXCEEDED"
+)
+
+// Kind is the direction of an operation.
+type Kind string
+
+const (
+	KindDeposit    Kind = "deposit"
+	KindWithdrawal Kind = "withdrawal"
+)
+
+// Operation is a balance change to be checked against the rules. A transfer is
+// a withdrawal from the source and a deposit to the destination.
+type Operation struct {
+	Kind        Kind
+	AccountID   string
+	UserID      string
+	AccountType accountv2.AccountType
+	Amount      *commonv1.Money
+	At          time.Time
+}
+
+// Engine evaluates operations against the policy of their account type and keeps
+// the history the cumulative and velocity rules need.
+type Engine struct {
+	mu       sync.Mutex
+	policies map[accountv2.AccountType]compiled
+	fallback *compiled
+	// history is keyed by "account:<id>" and "user:<id>", oldest first
+	history map[string][]entry
+	// horizon is how long entries are needed by the longest velocity window
+	horizon time.Duration
+}
+
+type entry struct {
+	kind  Kind
+	at    time.Time
+	nanos int64
+}
+
+// New builds an engine from cfg.
+func New(cfg Config) (*Engine, error) {
+	e := &Engine{
+		policies: make(map[accountv2.AccountType]compiled),
+		history:  make(map[string][]entry),
+	}
+	for name, p := range cfg.AccountTypes {
+		c, err := compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("policy %q: %w", name, err)
+		}
+		for _, v := range p.Velocity {
+			if d := time.Duration(v.Window); d > e.horizon {
+				e.horizon = d
+			}
+		}
+
+		if name == "default" {
+			e.fallback = &c
+			continue
+		}
+		typ, ok := accountType(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown account type %q", name)
+		}
+		e.policies[typ] = c
+	}
+	return e, nil
+}
+
+func (e *Engine) policy(typ accountv2.AccountType) (compiled, bool) {
+	// unspecified accounts are checking accounts, as in the product catalogue
+	if typ == accountv2.AccountType_ACCOUNT_TYPE_UNSPECIFIED {
+		typ = accountv2.AccountType_ACCOUNT_TYPE_CHECKING
+	}
+	if p, ok := e.policies[typ]; ok {
+		return p, true
+	}
+	if e.fallback != nil {
+		return *e.fallback, true
+	}
+	return compiled{}, false
+}
+
+// Check returns a gRPC status error carrying an ErrorInfo if op breaks a rule.
+// A single operation over the maximum fails with FailedPrecondition; exhausted
+// cumulative or velocity limits fail with ResourceExhausted.
+func (e *Engine) Check(op Operation) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.check(op)
+}
+
+// CheckAll checks operations that are to be applied together, in order, each
+// one counting the ones before it as if they had been recorded. Nothing is
+// recorded. On a violation it also returns the index of the operation.
+func (e *Engine) CheckAll(ops ...Operation) (int, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	saved := make(map[string][]entry)
+	defer func() {
+		for key, entries := range saved {
+			e.history[key] = entries
+		}
+	}()
+	for i, op := range ops {
+		if err := e.check(op); err != nil {
+			return i, err
+		}
+		for _, key := range []string{"account:" + op.AccountID, "user:" + op.UserID} {
+			if _, ok := saved[key]; !ok {
+				saved[key] = e.history[key]
+			}
+		}
+		e.record(op)
+	}
+	return 0, nil
+}
+
+// check is Check for callers that hold e.mu.
+func (e *Engine) check(op Operation) error {
+	p, ok := e.policy(op.AccountType)
+	if !ok {
+		return nil
+	}
+	amount := op.Amount.GetUnits()*nanosPerUnit + int64(op.Amount.GetNanos())
+	l := p.limits[op.Kind]
+
+	if l.max > 0 && amount > l.max {
+		return violation(codes.FailedPrecondition, ReasonAmountLimit, op, fmt.Sprintf("%s exceeds the maximum of %s per transaction", op.Kind, formatNanos(l.max)), map[string]string{
+			"limit": formatNanos(l.max),
+		})
+	}
+
+	for _, v := range p.velocity {
+		if v.MaxCount == 0 {
+			continue
+		}
+		key, id := "account:"+op.AccountID, op.AccountID
+		if v.Scope == ScopeUser {
+			key, id = "user:"+op.UserID, op.UserID
+		}
+		since := op.At.Add(-time.Duration(v.Window))
+		if count := e.count(key, since); count >= v.MaxCount {
+			return violation(codes.ResourceExhausted, ReasonVelocityLimit, op, fmt.Sprintf("more than %d operations per %s for %s %s", v.MaxCount, time.Duration(v.Window), v.Scope, id), map[string]string{
+				"scope":  string(v.Scope),
+				"limit":  strconv.Itoa(v.MaxCount),
+				"window": time.Duration(v.Window).String(),
+			})
+		}
+	}
+
+	periods := []struct {
+		limit  int64
+		since  time.Time
+		reason string
+		period string
+	}{
+		{limit: l.daily, since: startOfDay(op.At), reason: ReasonDailyLimit, period: "daily"},
+		{limit: l.monthly, since: startOfMonth(op.At), reason: ReasonMonthlyLimit, period: "monthly"},
+	}
+	for _, pr := range periods {
+		if pr.limit == 0 {
+			continue
+		}
+		used := e.sum("account:"+op.AccountID, op.Kind, pr.since)
+		if used+amount > pr.limit {
+			return violation(codes.ResourceExhausted, pr.reason, op, fmt.Sprintf("%s %s limit of %s exceeded", pr.period, op.Kind, formatNanos(pr.limit)), map[string]string{
+				"limit":     formatNanos(pr.limit),
+				"used":      formatNanos(used),
+				"remaining": formatNanos(pr.limit - used),
+			})
+		}
+	}
+	return nil
+}
+
+// Record adds a completed operation to the history. Operations of account types
+// without a policy are not recorded.
+func (e *Engine) Record(op Operation) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.record(op)
+}
+
+// record is Record for callers that hold e.mu.
+func (e *Engine) record(op Operation) {
+	if _, ok := e.policy(op.AccountType); !ok {
+		return
+	}
+	en := entry{kind: op.Kind, at: op.At, nanos: op.Amount.GetUnits()*nanosPerUnit + int64(op.Amount.GetNanos())}
+	for _, key := range []string{"account:" + op.AccountID, "user:" + op.UserID} {
+		e.history[key] = append(e.prune(key, op.At), en)
+	}
+}
+
+// prune drops entries no rule looks at any more. Callers must hold e.mu.
+func (e *Engine) prune(key string, now time.Time) []entry {
+	cutoff := startOfMonth(now)
+	if t := now.Add(-e.horizon); t.Before(cutoff) {
+		cutoff = t
+	}
+	entries := e.history[key]
+	i := sort.Search(len(entries), func(i int) bool { return !entries[i].at.Before(cutoff) })
+	return entries[i:]
+}
+
+// count returns the number of entries after since. Callers must hold e.mu.
+func (e *Engine) count(key string, since time.Time) int {
+	n := 0
+	for _, en := range e.history[key] {
+		if en.at.After(since) {
+			n++
+		}
+	}
+	return n
+}
+
+// sum adds up the entries of a kind at or after since. Callers must hold e.mu.
+func (e *Engine) sum(key string, kind Kind, since time.Time) int64 {
+	var total int64
+	for _, en := range e.history[key] {
+		if en.kind == kind && !en.at.Before(since) {
+			total += en.nanos
+		}
+	}
+	return total
+}
+
+func violation(code codes.Code, reason string, op Operation, msg string, metadata map[string]string) error {
+	metadata["account_id"] = op.AccountID
+	metadata["operation"] = string(op.Kind)
+	st, err := status.New(code, msg).WithDetails(&errdetails.ErrorInfo{
+		Reason:   reason,
+		Domain:   Domain,
+		Metadata: metadata,
+	})
+	if err != nil {
+		return status.Error(code, msg)
+	}
+	return st.Err()
+}
+
+// formatNanos renders an amount without trailing zeros, e.g. 2500 or 99.95.
+func formatNanos(n int64) string {
+	s := strconv.FormatInt(n/nanosPerUnit, 10)
+	if frac := n % nanosPerUnit; frac != 0 {
+		f := fmt.Sprintf("%09d", frac)
+		for f[len(f)-1] == '0' {
+			f = f[:len(f)-1]
+		}
+		s += "." + f
+	}
+	return s
+}
+
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+func startOfMonth(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+}
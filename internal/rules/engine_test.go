@@ -0,0 +1,193 @@
+package rules
+
+import (
+	"testing"
+	"time"
+
+	accountv2 "github.com/galadeat/bank-sim/api/proto/account/v2"
+	commonv1 "github.com/galadeat/bank-sim/api/proto/common/v1"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var simStart = time.Date(2025, 1, 31, 12, 0, 0, 0, time.UTC)
+
+func newEngine(t *testing.T, p Policy) *Engine {
+	t.Helper()
+	e, err := New(Config{AccountTypes: map[string]Policy{"checking": p}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return e
+}
+
+func withdrawal(account string, units int64, at time.Time) Operation {
+	return Operation{
+		Kind:        KindWithdrawal,
+		AccountID:   account,
+		UserID:      "user-123",
+		AccountType: accountv2.AccountType_ACCOUNT_TYPE_CHECKING,
+		Amount:      &commonv1.Money{Currency: "USD", Units: units},
+		At:          at,
+	}
+}
+
+// apply checks and records op, as the account service does.
+func apply(e *Engine, op Operation) error {
+	if err := e.Check(op); err != nil {
+		return err
+	}
+	e.Record(op)
+	return nil
+}
+
+func assertViolation(t *testing.T, err error, code codes.Code, reason string) {
+	t.Helper()
+	st := status.Convert(err)
+	if st.Code() != code {
+		t.Fatalf("expected %v, got %v", code, err)
+	}
+	for _, d := range st.Details() {
+		if info, ok := d.(*errdetails.ErrorInfo); ok {
+			if info.Reason != reason || info.Domain != Domain {
+				t.Errorf("expected reason %s in %s, got %s in %s", reason, Domain, info.Reason, info.Domain)
+			}
+			return
+		}
+	}
+	t.Errorf("expected ErrorInfo in %v", err)
+}
+
+func TestMaxAmount(t *testing.T) {
+	e := newEngine(t, Policy{Withdrawal: Limits{MaxAmount: "100.50"}})
+
+	if err := apply(e, withdrawal("acc-1", 100, simStart)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err := apply(e, withdrawal("acc-1", 101, simStart))
+	assertViolation(t, err, codes.FailedPrecondition, ReasonAmountLimit)
+
+	// deposits have their own limits
+	dep := withdrawal("acc-1", 1000, simStart)
+	dep.Kind = KindDeposit
+	if err := apply(e, dep); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestCumulativeLimits(t *testing.T) {
+	e := newEngine(t, Policy{Withdrawal: Limits{DailyAmount: "100", MonthlyAmount: "150"}})
+
+	if err := apply(e, withdrawal("acc-1", 60, simStart)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err := apply(e, withdrawal("acc-1", 41, simStart.Add(time.Hour)))
+	assertViolation(t, err, codes.ResourceExhausted, ReasonDailyLimit)
+
+	// other accounts have their own totals
+	if err := apply(e, withdrawal("acc-2", 100, simStart)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// the daily total resets at midnight, the monthly one only when the month changes
+	dayBefore := simStart.Add(-12*time.Hour).AddDate(0, 0, -1)
+	if err := apply(e, withdrawal("acc-3", 100, dayBefore)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err = apply(e, withdrawal("acc-3", 51, dayBefore.AddDate(0, 0, 1)))
+	assertViolation(t, err, codes.ResourceExhausted, ReasonMonthlyLimit)
+
+	// a new month starts from zero
+	if err := apply(e, withdrawal("acc-1", 100, simStart.AddDate(0, 0, 1))); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestVelocity(t *testing.T) {
+	e := newEngine(t, Policy{Velocity: []Velocity{
+		{Scope: ScopeAccount, MaxCount: 2, Window: Duration(time.Minute)},
+		{Scope: ScopeUser, MaxCount: 3, Window: Duration(time.Hour)},
+	}})
+
+	for i := 0; i < 2; i++ {
+		if err := apply(e, withdrawal("acc-1", 1, simStart.Add(time.Duration(i)*time.Second))); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	err := apply(e, withdrawal("acc-1", 1, simStart.Add(2*time.Second)))
+	assertViolation(t, err, codes.ResourceExhausted, ReasonVelocityLimit)
+
+	// the window slides
+	if err := apply(e, withdrawal("acc-1", 1, simStart.Add(time.Minute))); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// the user has used up the hourly count on any account
+	err = apply(e, withdrawal("acc-2", 1, simStart.Add(2*time.Minute)))
+	assertViolation(t, err, codes.ResourceExhausted, ReasonVelocityLimit)
+}
+
+func TestCheckAll(t *testing.T) {
+	e := newEngine(t, Policy{Withdrawal: Limits{DailyAmount: "100"}})
+	if err := apply(e, withdrawal("acc-1", 30, simStart)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// each operation counts the ones before it
+	i, err := e.CheckAll(withdrawal("acc-1", 40, simStart), withdrawal("acc-2", 90, simStart), withdrawal("acc-1", 31, simStart))
+	assertViolation(t, err, codes.ResourceExhausted, ReasonDailyLimit)
+	if i != 2 {
+		t.Errorf("expected operation 2 to fail, got %d", i)
+	}
+
+	// and nothing is recorded
+	if _, err := e.CheckAll(withdrawal("acc-1", 40, simStart), withdrawal("acc-1", 30, simStart)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := apply(e, withdrawal("acc-1", 70, simStart)); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestPolicySelection(t *testing.T) {
+	e, err := New(Config{AccountTypes: map[string]Policy{
+		"savings": {Withdrawal: Limits{MaxAmount: "10"}},
+		"default": {Withdrawal: Limits{MaxAmount: "20"}},
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	op := withdrawal("acc-1", 15, simStart)
+	op.AccountType = accountv2.AccountType_ACCOUNT_TYPE_SAVINGS
+	assertViolation(t, e.Check(op), codes.FailedPrecondition, ReasonAmountLimit)
+
+	op.AccountType = accountv2.AccountType_ACCOUNT_TYPE_UNSPECIFIED
+	if err := e.Check(op); err != nil {
+		t.Errorf("expected the default policy to allow 15, got %v", err)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	if _, err := Load("../../configs/rules.json"); err != nil {
+		t.Fatalf("shipped rules file does not load: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		cfg  Config
+	}{
+		{name: "unknown account type", cfg: Config{AccountTypes: map[string]Policy{"gold": {}}}},
+		{name: "bad amount", cfg: Config{AccountTypes: map[string]Policy{"checking": {Deposit: Limits{MaxAmount: "lots"}}}}},
+		{name: "sub-nano amount", cfg: Config{AccountTypes: map[string]Policy{"checking": {Deposit: Limits{MaxAmount: "0.0000000001"}}}}},
+		{name: "bad scope", cfg: Config{AccountTypes: map[string]Policy{"checking": {Velocity: []Velocity{{Scope: "bank", MaxCount: 1, Window: Duration(time.Second)}}}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(tt.cfg); err == nil {
+				t.Errorf("expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSchedule(t *testing.T) {
+	tests := []struct {
+		spec    string
+		wantErr bool
+	}{
+		{spec: "0 9 1 * *"},
+		{spec: "*/15 8-18 * * 1-5"},
+		{spec: "0 0 1,15 * *"},
+		{spec: "@monthly"},
+		{spec: "0 0 * * 7"},
+		{spec: "0 9 1 *", wantErr: true},
+		{spec: "60 * * * *", wantErr: true},
+		{spec: "0 0 0 * *", wantErr: true},
+		{spec: "0 0 * * 5-1", wantErr: true},
+		{spec: "*/0 * * * *", wantErr: true},
+		{spec: "@fortnightly", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			_, err := parseSchedule(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("expected error to be %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestScheduleNext(t *testing.T) {
+	// a Wednesday
+	from := time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		spec string
+		want time.Time
+	}{
+		{spec: "0 9 1 * *", want: time.Date(2025, 2, 1, 9, 0, 0, 0, time.UTC)},
+		{spec: "@monthly", want: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{spec: "*/15 * * * *", want: time.Date(2025, 1, 15, 10, 45, 0, 0, time.UTC)},
+		{spec: "30 10 * * *", want: time.Date(2025, 1, 16, 10, 30, 0, 0, time.UTC)},
+		{spec: "0 0 * * 0", want: time.Date(2025, 1, 19, 0, 0, 0, 0, time.UTC)},
+		{spec: "0 0 * * 7", want: time.Date(2025, 1, 19, 0, 0, 0, 0, time.UTC)},
+		{spec: "0 12 31 * *", want: time.Date(2025, 1, 31, 12, 0, 0, 0, time.UTC)},
+		{spec: "0 12 30 * *", want: time.Date(2025, 1, 30, 12, 0, 0, 0, time.UTC)},
+		{spec: "0 0 29 2 *", want: time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		// both day fields restricted: either one matches
+		{spec: "0 0 20 * 5", want: time.Date(2025, 1, 17, 0, 0, 0, 0, time.UTC)},
+		{spec: "0 0 30 2 *", want: time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			s, err := parseSchedule(tt.spec)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := s.next(from); !got.Equal(tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
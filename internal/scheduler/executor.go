@@ -0,0 +1,162 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"sort"
+	"time"
+
+	accountv2 "github.com/galadeat/bank-sim/api/proto/account/v2"
+	commonv1 "github.com/galadeat/bank-sim/api/proto/common/v1"
+	schedulerv1 "github.com/galadeat/bank-sim/api/proto/scheduler/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Run executes due standing orders once a minute until ctx is done.
+func (s *Service) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case t := <-s.clock.After(time.Minute):
+			s.RunDue(ctx, t)
+		}
+	}
+}
+
+// run is an occurrence that should be executed, captured outside the lock.
+type run struct {
+	orderID      string
+	from, to     string
+	amount       *commonv1.Money
+	scheduledFor time.Time
+}
+
+// RunDue executes every occurrence that is due at now. Occurrences missed while
+// the scheduler was not running are executed one after another. A transfer held
+// for review moves no money: the occurrence is checked again under the same
+// request id after the retry backoff, without using up an attempt, until the
+// review is resolved.
+func (s *Service) RunDue(ctx context.Context, now time.Time) {
+	stopped := make(map[string]bool)
+	for {
+		pending := s.dueRuns(now, stopped)
+		if len(pending) == 0 {
+			return
+		}
+		for _, r := range pending {
+			resp, err := s.accountClient.Transfer(ctx, &accountv2.TransferRequest{
+				FromAccountId: r.from,
+				ToAccountId:   r.to,
+				Amount:        r.amount,
+				RequestId:     requestID(r.orderID, r.scheduledFor),
+			})
+			if err != nil || resp.GetReview() != nil {
+				stopped[r.orderID] = true
+			}
+			s.settle(r, now, resp.GetReview(), err)
+		}
+	}
+}
+
+// dueRuns returns the current occurrence of every active order that is due and
+// not in skip.
+func (s *Service) dueRuns(now time.Time, skip map[string]bool) []run {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ids := make([]string, 0, len(s.orders))
+	for id, o := range s.orders {
+		if skip[id] || o.info.Status != schedulerv1.StandingOrderStatus_STANDING_ORDER_STATUS_ACTIVE {
+			continue
+		}
+		if o.dueAt().After(now) {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	out := make([]run, 0, len(ids))
+	for _, id := range ids {
+		o := s.orders[id]
+		out = append(out, run{
+			orderID:      id,
+			from:         o.info.FromAccountId,
+			to:           o.info.ToAccountId,
+			amount:       proto.Clone(o.info.Amount).(*commonv1.Money),
+			scheduledFor: o.info.NextRunAt.AsTime(),
+		})
+	}
+	return out
+}
+
+// settle records the outcome of an attempt and moves the order on to its next
+// occurrence, or schedules a retry. review is the review the transfer was held
+// for, if any.
+func (s *Service) settle(r run, now time.Time, review *accountv2.Review, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	o := s.orders[r.orderID]
+	o.attempts++
+	exec := &schedulerv1.Execution{
+		OrderId:      r.orderID,
+		ScheduledFor: timestamppb.New(r.scheduledFor),
+		Attempt:      o.attempts,
+		RequestId:    requestID(r.orderID, r.scheduledFor),
+		ExecutedAt:   timestamppb.New(now),
+	}
+	o.history = append(o.history, exec)
+
+	switch {
+	case err == nil && review.GetStatus() == accountv2.ReviewStatus_REVIEW_STATUS_PENDING:
+		exec.Status = schedulerv1.ExecutionStatus_EXECUTION_STATUS_HELD
+		// waiting for a review is not a failed attempt
+		o.attempts--
+		o.retryAt = now.Add(s.retry.Backoff)
+		log.Printf("standing order held for review: id=%s, review_id=%s, check_at=%s", r.orderID, review.Id, o.retryAt.Format(time.RFC3339))
+		return
+	case err == nil && review.GetStatus() == accountv2.ReviewStatus_REVIEW_STATUS_REJECTED:
+		exec.Status = schedulerv1.ExecutionStatus_EXECUTION_STATUS_FAILED
+		exec.Error = "transfer rejected in review: " + review.Resolution
+		log.Printf("standing order rejected in review: id=%s, scheduled_for=%s, review_id=%s", r.orderID, r.scheduledFor.Format(time.RFC3339), review.Id)
+	case err == nil:
+		exec.Status = schedulerv1.ExecutionStatus_EXECUTION_STATUS_SUCCEEDED
+		log.Printf("standing order executed: id=%s, scheduled_for=%s, attempt=%d", r.orderID, r.scheduledFor.Format(time.RFC3339), o.attempts)
+	case retryable(err) && o.attempts < s.retry.MaxAttempts:
+		exec.Status = schedulerv1.ExecutionStatus_EXECUTION_STATUS_RETRYING
+		exec.Error = err.Error()
+		o.retryAt = now.Add(s.retry.Backoff << (o.attempts - 1))
+		log.Printf("standing order failed, retrying: id=%s, attempt=%d, retry_at=%s, err=%v", r.orderID, o.attempts, o.retryAt.Format(time.RFC3339), err)
+		return
+	default:
+		exec.Status = schedulerv1.ExecutionStatus_EXECUTION_STATUS_FAILED
+		exec.Error = err.Error()
+		log.Printf("standing order failed: id=%s, scheduled_for=%s, attempt=%d, err=%v", r.orderID, r.scheduledFor.Format(time.RFC3339), o.attempts, err)
+	}
+
+	o.attempts = 0
+	o.retryAt = time.Time{}
+	next := o.schedule.next(r.scheduledFor)
+	if next.IsZero() {
+		// the schedule has no more occurrences
+		o.info.Status = schedulerv1.StandingOrderStatus_STANDING_ORDER_STATUS_CANCELED
+		return
+	}
+	o.info.NextRunAt = timestamppb.New(next)
+}
+
+// retryable reports whether a failed transfer may succeed later, for example
+// once the source account has been funded or unfrozen.
+func retryable(err error) bool {
+	switch status.Code(err) {
+	case codes.InvalidArgument, codes.NotFound, codes.PermissionDenied:
+		return false
+	default:
+		return true
+	}
+}
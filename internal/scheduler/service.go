@@ -0,0 +1,314 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	accountv2 "github.com/galadeat/bank-sim/api/proto/account/v2"
+	schedulerv1 "github.com/galadeat/bank-sim/api/proto/scheduler/v1"
+	userv1 "github.com/galadeat/bank-sim/api/proto/user/v1"
+	"github.com/galadeat/bank-sim/pkg/clock"
+	"github.com/gofrs/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// RetryPolicy controls how an occurrence that failed is retried. The delay before
+// attempt n+1 is Backoff * 2^(n-1).
+type RetryPolicy struct {
+	MaxAttempts int32
+	Backoff     time.Duration
+}
+
+// DefaultRetryPolicy retries after 1, 2 and 4 hours.
+var DefaultRetryPolicy = RetryPolicy{MaxAttempts: 4, Backoff: time.Hour}
+
+// Service keeps standing orders and executes them as transfers between accounts
+// when they fall due.
+type Service struct {
+	schedulerv1.UnimplementedStandingOrdersServer
+	mu        sync.RWMutex
+	orders    map[string]*order
+	creations map[string]*schedulerv1.CreateStandingOrderResponse
+
+	userClient    userv1.UserClient
+	accountClient accountv2.AccountClient
+	clock         clock.Clock
+	retry         RetryPolicy
+}
+
+// order is a standing order together with the state of its current occurrence.
+type order struct {
+	info     *schedulerv1.StandingOrder
+	schedule *cronSchedule
+	// attempts is the number of failed attempts of the occurrence at info.NextRunAt
+	attempts int32
+	retryAt  time.Time
+	history  []*schedulerv1.Execution
+}
+
+// dueAt returns when the order should be executed next.
+func (o *order) dueAt() time.Time {
+	if !o.retryAt.IsZero() {
+		return o.retryAt
+	}
+	return o.info.NextRunAt.AsTime()
+}
+
+// Option configures a Service.
+type Option func(*Service)
+
+// WithClock sets the clock used to compute and execute occurrences.
+func WithClock(c clock.Clock) Option {
+	return func(s *Service) {
+		s.clock = c
+	}
+}
+
+// WithRetryPolicy replaces DefaultRetryPolicy.
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(s *Service) {
+		s.retry = p
+	}
+}
+
+// New is the constructor
+func New(userClient userv1.UserClient, accountClient accountv2.AccountClient, opts ...Option) *Service {
+	s := &Service{
+		orders:        make(map[string]*order),
+		creations:     make(map[string]*schedulerv1.CreateStandingOrderResponse),
+		userClient:    userClient,
+		accountClient: accountClient,
+		clock:         clock.Real(),
+		retry:         DefaultRetryPolicy,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// CreateStandingOrder is the realization of the rpc method. The source account
+// must belong to the user; the destination may be any account.
+func (s *Service) CreateStandingOrder(ctx context.Context, req *schedulerv1.CreateStandingOrderRequest) (*schedulerv1.CreateStandingOrderResponse, error) {
+	select {
+	case <-ctx.Done():
+		return nil, status.Error(codes.Canceled, "request canceled by client")
+	default:
+	}
+
+	if err := validateCreation(req); err != nil {
+		return nil, err
+	}
+	sched, err := parseSchedule(req.Schedule)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid schedule: %v", err)
+	}
+
+	s.mu.RLock()
+	resp, ok := s.creations[req.RequestId]
+	s.mu.RUnlock()
+	if ok {
+		return resp, nil
+	}
+
+	if _, err := s.userClient.GetUser(ctx, &userv1.GetUserRequest{Id: req.UserId}); err != nil {
+		return nil, fromRemote(err, "UserService")
+	}
+	from, err := s.accountClient.GetAccount(ctx, &accountv2.GetAccountRequest{Id: req.FromAccountId})
+	if err != nil {
+		return nil, fromRemote(err, "AccountService")
+	}
+	if from.GetAccount().GetOwnerId() != req.UserId {
+		return nil, status.Error(codes.PermissionDenied, "source account does not belong to the user")
+	}
+	if from.GetAccount().GetBalance().GetCurrency() != req.Amount.Currency {
+		return nil, status.Error(codes.InvalidArgument, "currency mismatch")
+	}
+	if _, err := s.accountClient.GetAccount(ctx, &accountv2.GetAccountRequest{Id: req.ToAccountId}); err != nil {
+		return nil, fromRemote(err, "AccountService")
+	}
+
+	now := s.clock.Now()
+	next := sched.next(now)
+	if next.IsZero() {
+		return nil, status.Error(codes.InvalidArgument, "schedule never fires")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if resp, ok := s.creations[req.RequestId]; ok {
+		return resp, nil
+	}
+
+	id, err := uuid.NewV4()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error while generating order id: %v", err)
+	}
+
+	info := &schedulerv1.StandingOrder{
+		Id:            id.String(),
+		UserId:        req.UserId,
+		FromAccountId: req.FromAccountId,
+		ToAccountId:   req.ToAccountId,
+		Amount:        req.Amount,
+		Schedule:      req.Schedule,
+		Status:        schedulerv1.StandingOrderStatus_STANDING_ORDER_STATUS_ACTIVE,
+		CreatedAt:     timestamppb.New(now),
+		NextRunAt:     timestamppb.New(next),
+	}
+	s.orders[info.Id] = &order{info: info, schedule: sched}
+
+	log.Printf("standing order created: id=%s, user_id=%s, from=%s, to=%s, amount=%v, schedule=%q, next_run_at=%s", info.Id, info.UserId, info.FromAccountId, info.ToAccountId, info.Amount, info.Schedule, next.Format(time.RFC3339))
+
+	resp = &schedulerv1.CreateStandingOrderResponse{Order: proto.Clone(info).(*schedulerv1.StandingOrder)}
+	s.creations[req.RequestId] = resp
+	return resp, nil
+}
+
+// GetStandingOrder is the realization of the rpc method
+func (s *Service) GetStandingOrder(ctx context.Context, req *schedulerv1.GetStandingOrderRequest) (*schedulerv1.GetStandingOrderResponse, error) {
+	select {
+	case <-ctx.Done():
+		return nil, status.Error(codes.Canceled, "request canceled by client")
+	default:
+	}
+
+	if req.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "order id is required")
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	o, ok := s.orders[req.Id]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "standing order not found")
+	}
+	return &schedulerv1.GetStandingOrderResponse{Order: proto.Clone(o.info).(*schedulerv1.StandingOrder)}, nil
+}
+
+// ListStandingOrders is the realization of the rpc method
+func (s *Service) ListStandingOrders(ctx context.Context, req *schedulerv1.ListStandingOrdersRequest) (*schedulerv1.ListStandingOrdersResponse, error) {
+	select {
+	case <-ctx.Done():
+		return nil, status.Error(codes.Canceled, "request canceled by client")
+	default:
+	}
+
+	if req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user id is required")
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var orders []*schedulerv1.StandingOrder
+	for _, o := range s.orders {
+		if o.info.UserId == req.UserId {
+			orders = append(orders, proto.Clone(o.info).(*schedulerv1.StandingOrder))
+		}
+	}
+	sort.Slice(orders, func(i, j int) bool {
+		return orders[i].CreatedAt.AsTime().Before(orders[j].CreatedAt.AsTime())
+	})
+	return &schedulerv1.ListStandingOrdersResponse{Orders: orders}, nil
+}
+
+// CancelStandingOrder is the realization of the rpc method. A transfer that is
+// already in flight still completes.
+func (s *Service) CancelStandingOrder(ctx context.Context, req *schedulerv1.CancelStandingOrderRequest) (*schedulerv1.CancelStandingOrderResponse, error) {
+	select {
+	case <-ctx.Done():
+		return nil, status.Error(codes.Canceled, "request canceled by client")
+	default:
+	}
+
+	if req.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "order id is required")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	o, ok := s.orders[req.Id]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "standing order not found")
+	}
+	if o.info.Status == schedulerv1.StandingOrderStatus_STANDING_ORDER_STATUS_CANCELED {
+		return nil, status.Error(codes.FailedPrecondition, "standing order is already canceled")
+	}
+	o.info.Status = schedulerv1.StandingOrderStatus_STANDING_ORDER_STATUS_CANCELED
+
+	log.Printf("standing order canceled: id=%s", o.info.Id)
+	return &schedulerv1.CancelStandingOrderResponse{Order: proto.Clone(o.info).(*schedulerv1.StandingOrder)}, nil
+}
+
+// ListExecutions is the realization of the rpc method
+func (s *Service) ListExecutions(ctx context.Context, req *schedulerv1.ListExecutionsRequest) (*schedulerv1.ListExecutionsResponse, error) {
+	select {
+	case <-ctx.Done():
+		return nil, status.Error(codes.Canceled, "request canceled by client")
+	default:
+	}
+
+	if req.OrderId == "" {
+		return nil, status.Error(codes.InvalidArgument, "order id is required")
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	o, ok := s.orders[req.OrderId]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "standing order not found")
+	}
+	executions := make([]*schedulerv1.Execution, len(o.history))
+	for i, e := range o.history {
+		executions[i] = proto.Clone(e).(*schedulerv1.Execution)
+	}
+	return &schedulerv1.ListExecutionsResponse{Executions: executions}, nil
+}
+
+func validateCreation(req *schedulerv1.CreateStandingOrderRequest) error {
+	if req.UserId == "" {
+		return status.Error(codes.InvalidArgument, "user id is required")
+	}
+	if req.FromAccountId == "" || req.ToAccountId == "" {
+		return status.Error(codes.InvalidArgument, "source and destination account ids are required")
+	}
+	if req.FromAccountId == req.ToAccountId {
+		return status.Error(codes.InvalidArgument, "source and destination accounts must differ")
+	}
+	if req.RequestId == "" {
+		return status.Error(codes.InvalidArgument, "request id is required")
+	}
+	if req.Amount == nil || req.Amount.Currency == "" {
+		return status.Error(codes.InvalidArgument, "amount is required")
+	}
+	if req.Amount.Units < 0 || req.Amount.Nanos < 0 || req.Amount.Units == 0 && req.Amount.Nanos == 0 {
+		return status.Error(codes.InvalidArgument, "amount must be greater than zero")
+	}
+	return nil
+}
+
+// fromRemote passes gRPC status errors from a downstream service through unchanged.
+func fromRemote(err error, service string) error {
+	if st, ok := status.FromError(err); ok {
+		return st.Err()
+	}
+	return status.Errorf(codes.Internal, "failed to call %s: %v", service, err)
+}
+
+// requestID is the transfer request id of an occurrence. Retries reuse it, so an
+// attempt whose response was lost is never executed twice.
+func requestID(orderID string, scheduledFor time.Time) string {
+	return fmt.Sprintf("standing-order:%s:%d", orderID, scheduledFor.Unix())
+}
@@ -0,0 +1,372 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	accountv2 "github.com/galadeat/bank-sim/api/proto/account/v2"
+	commonv1 "github.com/galadeat/bank-sim/api/proto/common/v1"
+	schedulerv1 "github.com/galadeat/bank-sim/api/proto/scheduler/v1"
+	userv1 "github.com/galadeat/bank-sim/api/proto/user/v1"
+	"github.com/galadeat/bank-sim/mocks"
+	"github.com/galadeat/bank-sim/pkg/clock"
+	"github.com/golang/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+var simStart = time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+
+func usd(units int64) *commonv1.Money {
+	return &commonv1.Money{Currency: "USD", Units: units}
+}
+
+func createRequest(schedule string) *schedulerv1.CreateStandingOrderRequest {
+	return &schedulerv1.CreateStandingOrderRequest{
+		UserId:        "user-123",
+		FromAccountId: "acc-from",
+		ToAccountId:   "acc-to",
+		Amount:        usd(50),
+		Schedule:      schedule,
+		RequestId:     "req-1",
+	}
+}
+
+func expectLookups(user *mocks.MockUserClient, account *mocks.MockAccountClient, owner string) {
+	user.EXPECT().
+		GetUser(gomock.Any(), &userv1.GetUserRequest{Id: "user-123"}).
+		Return(&userv1.GetUserResponse{User: &userv1.UserInfo{Id: "user-123"}}, nil).
+		AnyTimes()
+	account.EXPECT().
+		GetAccount(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, req *accountv2.GetAccountRequest, _ ...interface{}) (*accountv2.GetAccountResponse, error) {
+			acc := &accountv2.AccountInfo{Id: req.Id, OwnerId: "user-789", Owner: &userv1.UserInfo{Id: "user-789"}, Balance: usd(0)}
+			if req.Id == "acc-from" {
+				acc.OwnerId, acc.Owner.Id = owner, owner
+			}
+			return &accountv2.GetAccountResponse{Account: acc}, nil
+		}).
+		AnyTimes()
+}
+
+// newOrder creates a standing order with the given schedule and returns its id.
+func newOrder(t *testing.T, svc *Service, schedule string) string {
+	t.Helper()
+	resp, err := svc.CreateStandingOrder(context.Background(), createRequest(schedule))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return resp.Order.Id
+}
+
+func expectTransfer(account *mocks.MockAccountClient, orderID string, scheduledFor time.Time) *gomock.Call {
+	return account.EXPECT().Transfer(gomock.Any(), protoEq(&accountv2.TransferRequest{
+		FromAccountId: "acc-from",
+		ToAccountId:   "acc-to",
+		Amount:        usd(50),
+		RequestId:     fmt.Sprintf("standing-order:%s:%d", orderID, scheduledFor.Unix()),
+	}))
+}
+
+// protoEq matches a proto message by value; gomock.Eq also compares internal
+// state that differs between a literal and a clone.
+func protoEq(want proto.Message) gomock.Matcher {
+	return protoMatcher{want: want}
+}
+
+type protoMatcher struct{ want proto.Message }
+
+func (m protoMatcher) Matches(x interface{}) bool {
+	got, ok := x.(proto.Message)
+	return ok && proto.Equal(m.want, got)
+}
+
+func (m protoMatcher) String() string {
+	return fmt.Sprintf("is equal to %v", m.want)
+}
+
+func executions(t *testing.T, svc *Service, orderID string) []*schedulerv1.Execution {
+	t.Helper()
+	resp, err := svc.ListExecutions(context.Background(), &schedulerv1.ListExecutionsRequest{OrderId: orderID})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return resp.Executions
+}
+
+func TestCreateStandingOrder(t *testing.T) {
+	tests := []struct {
+		name        string
+		req         *schedulerv1.CreateStandingOrderRequest
+		owner       string
+		wantErrCode codes.Code
+	}{
+		{name: "success", req: createRequest("0 9 1 * *"), owner: "user-123", wantErrCode: codes.OK},
+		{name: "foreign source account", req: createRequest("0 9 1 * *"), owner: "user-456", wantErrCode: codes.PermissionDenied},
+		{name: "invalid schedule", req: createRequest("every month"), owner: "user-123", wantErrCode: codes.InvalidArgument},
+		{name: "schedule never fires", req: createRequest("0 0 30 2 *"), owner: "user-123", wantErrCode: codes.InvalidArgument},
+		{
+			name: "same account",
+			req: func() *schedulerv1.CreateStandingOrderRequest {
+				r := createRequest("@daily")
+				r.ToAccountId = r.FromAccountId
+				return r
+			}(),
+			wantErrCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			user := mocks.NewMockUserClient(ctrl)
+			account := mocks.NewMockAccountClient(ctrl)
+			expectLookups(user, account, tt.owner)
+			svc := New(user, account, WithClock(clock.NewManual(simStart)))
+
+			resp, err := svc.CreateStandingOrder(context.Background(), tt.req)
+			if status.Code(err) != tt.wantErrCode {
+				t.Fatalf("expected %v, got %v", tt.wantErrCode, err)
+			}
+			if err != nil {
+				return
+			}
+			want := time.Date(2025, 2, 1, 9, 0, 0, 0, time.UTC)
+			if !resp.Order.NextRunAt.AsTime().Equal(want) {
+				t.Errorf("expected next run at %v, got %v", want, resp.Order.NextRunAt.AsTime())
+			}
+
+			again, _ := svc.CreateStandingOrder(context.Background(), tt.req)
+			if again.Order.Id != resp.Order.Id {
+				t.Errorf("expected the retried request to return the same order")
+			}
+		})
+	}
+}
+
+func TestRunDue(t *testing.T) {
+	first := time.Date(2025, 2, 1, 9, 0, 0, 0, time.UTC)
+	second := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	t.Run("executes monthly and catches up", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		user := mocks.NewMockUserClient(ctrl)
+		account := mocks.NewMockAccountClient(ctrl)
+		expectLookups(user, account, "user-123")
+		svc := New(user, account, WithClock(clock.NewManual(simStart)))
+		id := newOrder(t, svc, "0 9 1 * *")
+
+		svc.RunDue(context.Background(), first.Add(-time.Minute))
+
+		gomock.InOrder(
+			expectTransfer(account, id, first).Return(&accountv2.TransferResponse{}, nil),
+			expectTransfer(account, id, second).Return(&accountv2.TransferResponse{}, nil),
+		)
+		svc.RunDue(context.Background(), second.Add(time.Hour))
+
+		history := executions(t, svc, id)
+		if len(history) != 2 {
+			t.Fatalf("expected 2 executions, got %d", len(history))
+		}
+		for _, e := range history {
+			if e.Status != schedulerv1.ExecutionStatus_EXECUTION_STATUS_SUCCEEDED {
+				t.Errorf("expected succeeded execution, got %v", e.Status)
+			}
+		}
+		got, _ := svc.GetStandingOrder(context.Background(), &schedulerv1.GetStandingOrderRequest{Id: id})
+		if want := time.Date(2025, 4, 1, 9, 0, 0, 0, time.UTC); !got.Order.NextRunAt.AsTime().Equal(want) {
+			t.Errorf("expected next run at %v, got %v", want, got.Order.NextRunAt.AsTime())
+		}
+	})
+
+	t.Run("retries insufficient funds with the same request id", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		user := mocks.NewMockUserClient(ctrl)
+		account := mocks.NewMockAccountClient(ctrl)
+		expectLookups(user, account, "user-123")
+		svc := New(user, account, WithClock(clock.NewManual(simStart)), WithRetryPolicy(RetryPolicy{MaxAttempts: 3, Backoff: time.Hour}))
+		id := newOrder(t, svc, "0 9 1 * *")
+
+		insufficient := status.Error(codes.FailedPrecondition, "insufficient balance")
+		expectTransfer(account, id, first).Return(nil, insufficient)
+		svc.RunDue(context.Background(), first)
+
+		// not due again until the backoff has passed
+		svc.RunDue(context.Background(), first.Add(59*time.Minute))
+
+		expectTransfer(account, id, first).Return(&accountv2.TransferResponse{}, nil)
+		svc.RunDue(context.Background(), first.Add(time.Hour))
+
+		history := executions(t, svc, id)
+		if len(history) != 2 {
+			t.Fatalf("expected 2 executions, got %d", len(history))
+		}
+		if history[0].Status != schedulerv1.ExecutionStatus_EXECUTION_STATUS_RETRYING || history[0].Error == "" {
+			t.Errorf("expected a retrying execution with an error, got %v", history[0])
+		}
+		if history[1].Status != schedulerv1.ExecutionStatus_EXECUTION_STATUS_SUCCEEDED || history[1].Attempt != 2 {
+			t.Errorf("expected the second attempt to succeed, got %v", history[1])
+		}
+	})
+
+	t.Run("gives up after max attempts", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		user := mocks.NewMockUserClient(ctrl)
+		account := mocks.NewMockAccountClient(ctrl)
+		expectLookups(user, account, "user-123")
+		svc := New(user, account, WithClock(clock.NewManual(simStart)), WithRetryPolicy(RetryPolicy{MaxAttempts: 2, Backoff: time.Hour}))
+		id := newOrder(t, svc, "0 9 1 * *")
+
+		insufficient := status.Error(codes.FailedPrecondition, "insufficient balance")
+		expectTransfer(account, id, first).Return(nil, insufficient).Times(2)
+		svc.RunDue(context.Background(), first)
+		svc.RunDue(context.Background(), first.Add(time.Hour))
+
+		history := executions(t, svc, id)
+		if last := history[len(history)-1]; last.Status != schedulerv1.ExecutionStatus_EXECUTION_STATUS_FAILED {
+			t.Errorf("expected failed execution, got %v", last.Status)
+		}
+		got, _ := svc.GetStandingOrder(context.Background(), &schedulerv1.GetStandingOrderRequest{Id: id})
+		if !got.Order.NextRunAt.AsTime().Equal(second) {
+			t.Errorf("expected next run at %v, got %v", second, got.Order.NextRunAt.AsTime())
+		}
+	})
+
+	t.Run("held transfer waits for its review", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		user := mocks.NewMockUserClient(ctrl)
+		account := mocks.NewMockAccountClient(ctrl)
+		expectLookups(user, account, "user-123")
+		svc := New(user, account, WithClock(clock.NewManual(simStart)), WithRetryPolicy(RetryPolicy{MaxAttempts: 2, Backoff: time.Hour}))
+		id := newOrder(t, svc, "0 9 1 * *")
+
+		// a pending review does not use up attempts
+		pending := &accountv2.Review{Id: "review-1", Status: accountv2.ReviewStatus_REVIEW_STATUS_PENDING}
+		expectTransfer(account, id, first).Return(&accountv2.TransferResponse{Review: pending}, nil).Times(3)
+		svc.RunDue(context.Background(), first)
+		svc.RunDue(context.Background(), first.Add(time.Hour))
+		svc.RunDue(context.Background(), first.Add(2*time.Hour))
+
+		approved := &accountv2.Review{Id: "review-1", Status: accountv2.ReviewStatus_REVIEW_STATUS_APPROVED}
+		expectTransfer(account, id, first).Return(&accountv2.TransferResponse{Debit: &accountv2.Transaction{Id: "tx-1"}, Review: approved}, nil)
+		svc.RunDue(context.Background(), first.Add(3*time.Hour))
+
+		history := executions(t, svc, id)
+		if len(history) != 4 {
+			t.Fatalf("expected 4 executions, got %d", len(history))
+		}
+		for _, e := range history[:3] {
+			if e.Status != schedulerv1.ExecutionStatus_EXECUTION_STATUS_HELD || e.Attempt != 1 {
+				t.Errorf("expected a held first attempt, got %v", e)
+			}
+		}
+		if last := history[3]; last.Status != schedulerv1.ExecutionStatus_EXECUTION_STATUS_SUCCEEDED || last.Attempt != 1 {
+			t.Errorf("expected the approved transfer to succeed, got %v", last)
+		}
+		got, _ := svc.GetStandingOrder(context.Background(), &schedulerv1.GetStandingOrderRequest{Id: id})
+		if !got.Order.NextRunAt.AsTime().Equal(second) {
+			t.Errorf("expected next run at %v, got %v", second, got.Order.NextRunAt.AsTime())
+		}
+	})
+
+	t.Run("rejected transfer skips the occurrence", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		user := mocks.NewMockUserClient(ctrl)
+		account := mocks.NewMockAccountClient(ctrl)
+		expectLookups(user, account, "user-123")
+		svc := New(user, account, WithClock(clock.NewManual(simStart)))
+		id := newOrder(t, svc, "0 9 1 * *")
+
+		rejected := &accountv2.Review{Id: "review-1", Status: accountv2.ReviewStatus_REVIEW_STATUS_REJECTED, Resolution: "fraud"}
+		expectTransfer(account, id, first).Return(&accountv2.TransferResponse{Review: rejected}, nil)
+		svc.RunDue(context.Background(), first)
+
+		history := executions(t, svc, id)
+		if len(history) != 1 || history[0].Status != schedulerv1.ExecutionStatus_EXECUTION_STATUS_FAILED || history[0].Error == "" {
+			t.Fatalf("expected one failed execution, got %v", history)
+		}
+		got, _ := svc.GetStandingOrder(context.Background(), &schedulerv1.GetStandingOrderRequest{Id: id})
+		if !got.Order.NextRunAt.AsTime().Equal(second) {
+			t.Errorf("expected next run at %v, got %v", second, got.Order.NextRunAt.AsTime())
+		}
+	})
+
+	t.Run("canceled orders are not executed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		user := mocks.NewMockUserClient(ctrl)
+		account := mocks.NewMockAccountClient(ctrl)
+		expectLookups(user, account, "user-123")
+		svc := New(user, account, WithClock(clock.NewManual(simStart)))
+		id := newOrder(t, svc, "0 9 1 * *")
+
+		if _, err := svc.CancelStandingOrder(context.Background(), &schedulerv1.CancelStandingOrderRequest{Id: id}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		_, err := svc.CancelStandingOrder(context.Background(), &schedulerv1.CancelStandingOrderRequest{Id: id})
+		if status.Code(err) != codes.FailedPrecondition {
+			t.Errorf("expected %v, got %v", codes.FailedPrecondition, err)
+		}
+
+		svc.RunDue(context.Background(), second)
+		if len(executions(t, svc, id)) != 0 {
+			t.Errorf("expected no executions")
+		}
+	})
+}
+
+func TestRun(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	user := mocks.NewMockUserClient(ctrl)
+	account := mocks.NewMockAccountClient(ctrl)
+	expectLookups(user, account, "user-123")
+	clk := clock.NewManual(time.Date(2025, 1, 31, 23, 58, 0, 0, time.UTC))
+	svc := New(user, account, WithClock(clk))
+	id := newOrder(t, svc, "@monthly")
+
+	executed := make(chan struct{})
+	expectTransfer(account, id, time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)).
+		DoAndReturn(func(context.Context, *accountv2.TransferRequest, ...interface{}) (*accountv2.TransferResponse, error) {
+			close(executed)
+			return &accountv2.TransferResponse{}, nil
+		})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go svc.Run(ctx)
+
+	for i := 0; i < 2; i++ {
+		deadline := time.Now().Add(time.Second)
+		for clk.Waiters() == 0 {
+			if time.Now().After(deadline) {
+				t.Fatalf("scheduler did not wait on the clock")
+			}
+			time.Sleep(time.Millisecond)
+		}
+		clk.Advance(time.Minute)
+	}
+
+	select {
+	case <-executed:
+	case <-time.After(time.Second):
+		t.Fatalf("standing order was not executed")
+	}
+}
@@ -0,0 +1,54 @@
+package sim
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	accountv2 "github.com/galadeat/bank-sim/api/proto/account/v2"
+	commonv1 "github.com/galadeat/bank-sim/api/proto/common/v1"
+)
+
+// WriteLedger writes every ledger entry of every account, accounts in the order
+// they were opened, one tab separated line per entry:
+//
+//	time  account  entry id  type  amount  balance after  request id
+func (s *Simulation) WriteLedger(ctx context.Context, w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	for _, name := range s.opened {
+		resp, err := s.accounts.ListTransactions(ctx, &accountv2.ListTransactionsRequest{AccountId: s.accountIDs[name]})
+		if err != nil {
+			return fmt.Errorf("list transactions of %s: %w", name, err)
+		}
+		for _, tx := range resp.Transactions {
+			fmt.Fprintf(bw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+				tx.CreatedAt.AsTime().Format(time.RFC3339), name, tx.Id, tx.Type,
+				formatMoney(tx.Amount), formatMoney(tx.BalanceAfter), tx.RequestId)
+		}
+	}
+	return bw.Flush()
+}
+
+// Balances returns the balance of every account by name.
+func (s *Simulation) Balances(ctx context.Context) (map[string]*commonv1.Money, error) {
+	out := make(map[string]*commonv1.Money, len(s.opened))
+	for _, name := range s.opened {
+		resp, err := s.accounts.GetAccount(ctx, &accountv2.GetAccountRequest{Id: s.accountIDs[name]})
+		if err != nil {
+			return nil, fmt.Errorf("get account %s: %w", name, err)
+		}
+		out[name] = resp.Account.Balance
+	}
+	return out, nil
+}
+
+func formatMoney(m *commonv1.Money) string {
+	n := m.GetUnits()*nanosPerUnit + int64(m.GetNanos())
+	sign := ""
+	if n < 0 {
+		sign, n = "-", -n
+	}
+	return fmt.Sprintf("%s%d.%09d %s", sign, n/nanosPerUnit, n%nanosPerUnit, m.GetCurrency())
+}
@@ -0,0 +1,80 @@
+package sim
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	commonv1 "github.com/galadeat/bank-sim/api/proto/common/v1"
+)
+
+// profile is the habits of a generated customer.
+type profile struct {
+	login    string
+	salary   int64 // paid monthly on payday, in currency units
+	payday   int
+	spending int64 // largest card payment
+	savings  bool  // moves a tenth of the salary to savings the day after payday
+	initial  int64
+}
+
+const (
+	checking = "checking"
+	savings  = "savings"
+)
+
+func generateProfiles(r *rand.Rand, n int) []profile {
+	profiles := make([]profile, n)
+	for i := range profiles {
+		profiles[i] = profile{
+			login:    fmt.Sprintf("customer%03d", i+1),
+			salary:   1500 + 100*r.Int64N(36),
+			payday:   1 + r.IntN(28),
+			spending: 20 + r.Int64N(180),
+			savings:  r.IntN(10) < 4,
+			initial:  r.Int64N(2000),
+		}
+	}
+	return profiles
+}
+
+func (p profile) customer() Customer {
+	c := Customer{Login: p.login, Accounts: []Account{{Name: checking, Initial: fmt.Sprint(p.initial)}}}
+	if p.savings {
+		c.Accounts = append(c.Accounts, Account{Name: savings, Type: savings})
+	}
+	return c
+}
+
+// generateActions draws one day of behavior for every customer: the salary on
+// payday, a few card payments, a savings transfer after payday and now and then
+// a payment to another customer.
+func (s *Simulation) generateActions(midnight time.Time) []action {
+	var out []action
+	for i, p := range s.profiles {
+		acc := p.login + "/" + checking
+		if midnight.Day() == p.payday {
+			out = append(out, action{at: midnight.Add(9 * time.Hour), op: OpDeposit, from: acc, amount: s.units(p.salary, 0)})
+		}
+		if p.savings && midnight.Day() == p.payday%28+1 {
+			out = append(out, action{at: midnight.Add(10 * time.Hour), op: OpTransfer, from: acc, to: p.login + "/" + savings, amount: s.units(p.salary/10, 0)})
+		}
+		for n := s.rand.IntN(4); n > 0; n-- {
+			out = append(out, action{at: s.daytime(midnight), op: OpWithdraw, from: acc, amount: s.units(1+s.rand.Int64N(p.spending), s.rand.Int32N(100))})
+		}
+		if len(s.profiles) > 1 && s.rand.IntN(20) == 0 {
+			other := s.profiles[(i+1+s.rand.IntN(len(s.profiles)-1))%len(s.profiles)]
+			out = append(out, action{at: s.daytime(midnight), op: OpTransfer, from: acc, to: other.login + "/" + checking, amount: s.units(20+s.rand.Int64N(280), 0)})
+		}
+	}
+	return out
+}
+
+// daytime returns a random minute between 08:00 and 22:00.
+func (s *Simulation) daytime(midnight time.Time) time.Time {
+	return midnight.Add(8*time.Hour + time.Duration(s.rand.IntN(14*60))*time.Minute)
+}
+
+func (s *Simulation) units(units int64, cents int32) *commonv1.Money {
+	return &commonv1.Money{Currency: defaultCurrency, Units: units, Nanos: cents * 10_000_000}
+}
@@ -0,0 +1,181 @@
+package sim
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"time"
+
+	accountv2 "github.com/galadeat/bank-sim/api/proto/account/v2"
+	commonv1 "github.com/galadeat/bank-sim/api/proto/common/v1"
+)
+
+const (
+	defaultCurrency = "USD"
+	nanosPerUnit    = 1_000_000_000
+)
+
+// Op is a customer action.
+type Op string
+
+const (
+	OpDeposit  Op = "deposit"
+	OpWithdraw Op = "withdraw"
+	OpTransfer Op = "transfer"
+)
+
+// Script is a scenario file: the customers and their accounts, opened before the
+// first day, and the requests they send on later days.
+//
+// Amounts are decimal strings, e.g. "2500" or "99.95". Accounts are referred to
+// as "login/name".
+type Script struct {
+	Start     time.Time  `json:"start,omitempty"`
+	Currency  string     `json:"currency,omitempty"`
+	Customers []Customer `json:"customers"`
+	Events    []Event    `json:"events"`
+}
+
+// Customer is a user of the bank.
+type Customer struct {
+	Login    string    `json:"login"`
+	Email    string    `json:"email,omitempty"`
+	Accounts []Account `json:"accounts"`
+}
+
+// Account is an account opened for a customer.
+type Account struct {
+	Name string `json:"name"`
+	// Type is checking, savings or term_deposit; empty means checking.
+	Type       string `json:"type,omitempty"`
+	Initial    string `json:"initial,omitempty"`
+	TermMonths int32  `json:"term_months,omitempty"`
+}
+
+// Event is one request. Day counts from 0, the start day; Time is the time of
+// day as "15:04" and defaults to noon.
+type Event struct {
+	Day    int    `json:"day"`
+	Time   string `json:"time,omitempty"`
+	Op     Op     `json:"op"`
+	From   string `json:"from"`
+	To     string `json:"to,omitempty"`
+	Amount string `json:"amount"`
+
+	at     time.Duration
+	amount *commonv1.Money
+}
+
+// LoadScript reads and checks a scenario file.
+func LoadScript(path string) (*Script, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var sc Script
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&sc); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	if err := sc.compile(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &sc, nil
+}
+
+// compile checks the events and parses their times and amounts.
+func (sc *Script) compile() error {
+	for i := range sc.Events {
+		e := &sc.Events[i]
+		switch e.Op {
+		case OpDeposit, OpWithdraw:
+		case OpTransfer:
+			if e.To == "" {
+				return fmt.Errorf("event %d: transfer needs a to account", i)
+			}
+		default:
+			return fmt.Errorf("event %d: unknown op %q, want deposit, withdraw or transfer", i, e.Op)
+		}
+		if e.Day < 0 {
+			return fmt.Errorf("event %d: day must not be negative", i)
+		}
+
+		e.at = 12 * time.Hour
+		if e.Time != "" {
+			t, err := time.Parse("15:04", e.Time)
+			if err != nil {
+				return fmt.Errorf("event %d: time must look like 15:04", i)
+			}
+			e.at = time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+		}
+
+		amount, err := parseMoney(e.Amount, sc.currency())
+		if err != nil {
+			return fmt.Errorf("event %d: %w", i, err)
+		}
+		if amount.Units == 0 && amount.Nanos == 0 {
+			return fmt.Errorf("event %d: amount must be positive", i)
+		}
+		e.amount = amount
+	}
+	return nil
+}
+
+func (sc *Script) customers() []Customer {
+	if sc == nil {
+		return nil
+	}
+	return sc.Customers
+}
+
+func (sc *Script) currency() string {
+	if sc == nil || sc.Currency == "" {
+		return defaultCurrency
+	}
+	return sc.Currency
+}
+
+// actions returns the events of day in file order.
+func (sc *Script) actions(day int, midnight time.Time) []action {
+	var out []action
+	for _, e := range sc.Events {
+		if e.Day != day {
+			continue
+		}
+		out = append(out, action{at: midnight.Add(e.at), op: e.Op, from: e.From, to: e.To, amount: e.amount})
+	}
+	return out
+}
+
+func parseAccountType(s string) (accountv2.AccountType, error) {
+	switch s {
+	case "", "checking":
+		return accountv2.AccountType_ACCOUNT_TYPE_CHECKING, nil
+	case "savings":
+		return accountv2.AccountType_ACCOUNT_TYPE_SAVINGS, nil
+	case "term_deposit":
+		return accountv2.AccountType_ACCOUNT_TYPE_TERM_DEPOSIT, nil
+	}
+	return 0, fmt.Errorf("unknown account type %q, want checking, savings or term_deposit", s)
+}
+
+// parseMoney reads a non-negative decimal amount; empty means zero.
+func parseMoney(s, currency string) (*commonv1.Money, error) {
+	if s == "" {
+		return &commonv1.Money{Currency: currency}, nil
+	}
+	r, ok := new(big.Rat).SetString(s)
+	if !ok || r.Sign() < 0 {
+		return nil, fmt.Errorf("invalid amount %q", s)
+	}
+	r.Mul(r, big.NewRat(nanosPerUnit, 1))
+	if !r.IsInt() || !r.Num().IsInt64() {
+		return nil, fmt.Errorf("invalid amount %q", s)
+	}
+	n := r.Num().Int64()
+	return &commonv1.Money{Currency: currency, Units: n / nanosPerUnit, Nanos: int32(n % nanosPerUnit)}, nil
+}
@@ -0,0 +1,313 @@
+// Package sim runs the user and account services in-process on a virtual clock
+// and drives them with scripted or randomly generated customer behavior over
+// simulated days.
+//
+// Every id comes from a generator seeded with the run's seed and requests are
+// sent one at a time, so the same seed and script produce byte-identical ledgers.
+package sim
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"sort"
+	"time"
+
+	accountv2 "github.com/galadeat/bank-sim/api/proto/account/v2"
+	commonv1 "github.com/galadeat/bank-sim/api/proto/common/v1"
+	userv1 "github.com/galadeat/bank-sim/api/proto/user/v1"
+	"github.com/galadeat/bank-sim/internal/account"
+	"github.com/galadeat/bank-sim/internal/user"
+	"github.com/galadeat/bank-sim/pkg/clock"
+	"github.com/galadeat/bank-sim/pkg/idgen"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// DefaultStart is the first simulated day when neither the config nor the script
+// sets one.
+var DefaultStart = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+const bufSize = 1 << 20
+
+// Config describes a run.
+type Config struct {
+	Seed uint64
+	// Start is the first simulated day; only its date is used.
+	Start time.Time
+	Days  int
+	// Customers is the number of customers generated when there is no script.
+	Customers int
+	// Script replaces the randomly generated behavior when set.
+	Script *Script
+	// AccountOptions are passed to the account service after the simulation's
+	// own clock and id generator, e.g. rules or a risk scorer.
+	AccountOptions []account.Option
+}
+
+// Stats counts the requests a run sent.
+type Stats struct {
+	Requests int
+	// Failed counts rejected requests by status code. Rejections, such as a
+	// withdrawal without funds, are part of the simulation and do not stop it.
+	Failed map[codes.Code]int
+	// Held counts withdrawals and transfers held for review, which move no
+	// money.
+	Held int
+}
+
+// Simulation is a bank and the customers acting on it.
+type Simulation struct {
+	cfg     Config
+	clock   *clock.Manual
+	ids     *idgen.Seeded
+	rand    *rand.Rand
+	service *account.Service
+
+	users    userv1.UserClient
+	accounts accountv2.AccountClient
+	close    func()
+
+	userIDs    map[string]string // login -> user id
+	accountIDs map[string]string // "login/name" -> account id
+	opened     []string          // account names in opening order
+	profiles   []profile         // generated customers, when there is no script
+	stats      Stats
+}
+
+// New starts the services of a simulation.
+func New(cfg Config) (*Simulation, error) {
+	if cfg.Days <= 0 {
+		return nil, fmt.Errorf("days must be positive")
+	}
+	if cfg.Script == nil && cfg.Customers <= 0 {
+		return nil, fmt.Errorf("customers must be positive without a script")
+	}
+	start := cfg.Start
+	if cfg.Script != nil && !cfg.Script.Start.IsZero() {
+		start = cfg.Script.Start
+	}
+	if start.IsZero() {
+		start = DefaultStart
+	}
+	y, m, d := start.UTC().Date()
+	cfg.Start = time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+
+	s := &Simulation{
+		cfg:        cfg,
+		clock:      clock.NewManual(cfg.Start),
+		ids:        idgen.NewSeeded(cfg.Seed),
+		rand:       rand.New(rand.NewPCG(cfg.Seed, cfg.Seed)),
+		userIDs:    make(map[string]string),
+		accountIDs: make(map[string]string),
+		stats:      Stats{Failed: make(map[codes.Code]int)},
+	}
+
+	userLis := bufconn.Listen(bufSize)
+	userSrv := grpc.NewServer()
+	userv1.RegisterUserServer(userSrv, user.New(user.WithClock(s.clock), user.WithIDGenerator(s.ids)))
+	go userSrv.Serve(userLis)
+	userConn, err := dial(userLis)
+	if err != nil {
+		userSrv.Stop()
+		return nil, err
+	}
+	s.users = userv1.NewUserClient(userConn)
+
+	opts := append([]account.Option{account.WithClock(s.clock), account.WithIDGenerator(s.ids)}, cfg.AccountOptions...)
+	s.service = account.New(s.users, opts...)
+	accLis := bufconn.Listen(bufSize)
+	accSrv := grpc.NewServer()
+	accountv2.RegisterAccountServer(accSrv, s.service)
+	go accSrv.Serve(accLis)
+	accConn, err := dial(accLis)
+	if err != nil {
+		userConn.Close()
+		userSrv.Stop()
+		accSrv.Stop()
+		return nil, err
+	}
+	s.accounts = accountv2.NewAccountClient(accConn)
+
+	s.close = func() {
+		accConn.Close()
+		userConn.Close()
+		accSrv.Stop()
+		userSrv.Stop()
+	}
+	return s, nil
+}
+
+func dial(lis *bufconn.Listener) (*grpc.ClientConn, error) {
+	return grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+}
+
+// Close stops the services.
+func (s *Simulation) Close() {
+	s.close()
+}
+
+// Stats returns the requests sent so far.
+func (s *Simulation) Stats() Stats {
+	return s.stats
+}
+
+// Run simulates the configured days. Each day's actions are sent in time order
+// with the clock set to their time; at midnight interest is accrued and expired
+// holds are released, as the server's schedulers would.
+func (s *Simulation) Run(ctx context.Context) error {
+	if err := s.setup(ctx); err != nil {
+		return err
+	}
+	for day := 0; day < s.cfg.Days; day++ {
+		midnight := s.cfg.Start.AddDate(0, 0, day)
+		actions := s.actions(day, midnight)
+		sort.SliceStable(actions, func(i, j int) bool { return actions[i].at.Before(actions[j].at) })
+
+		for _, a := range actions {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			s.clock.Set(a.at)
+			if err := s.apply(ctx, a); err != nil {
+				return err
+			}
+		}
+
+		next := midnight.AddDate(0, 0, 1)
+		s.clock.Set(next)
+		s.service.AccrueInterest(next)
+		s.service.ExpireHolds(next)
+	}
+	return nil
+}
+
+// setup opens the script's customers and accounts, or generates customers.
+func (s *Simulation) setup(ctx context.Context) error {
+	customers := s.cfg.Script.customers()
+	if s.cfg.Script == nil {
+		s.profiles = generateProfiles(s.rand, s.cfg.Customers)
+		for _, p := range s.profiles {
+			customers = append(customers, p.customer())
+		}
+	}
+	for _, c := range customers {
+		if err := s.createUser(ctx, c); err != nil {
+			return err
+		}
+		for _, a := range c.Accounts {
+			if err := s.open(ctx, c.Login, a); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Simulation) actions(day int, midnight time.Time) []action {
+	if s.cfg.Script != nil {
+		return s.cfg.Script.actions(day, midnight)
+	}
+	return s.generateActions(midnight)
+}
+
+func (s *Simulation) createUser(ctx context.Context, c Customer) error {
+	email := c.Email
+	if email == "" {
+		email = c.Login + "@sim.test"
+	}
+	resp, err := s.users.CreateUser(ctx, &userv1.CreateUserRequest{Login: c.Login, Email: email, RequestId: s.ids.NewID()})
+	s.stats.Requests++
+	if err != nil {
+		return fmt.Errorf("create customer %s: %w", c.Login, err)
+	}
+	s.userIDs[c.Login] = resp.Id
+	return nil
+}
+
+func (s *Simulation) open(ctx context.Context, login string, a Account) error {
+	name := login + "/" + a.Name
+	if _, ok := s.accountIDs[name]; ok {
+		return fmt.Errorf("account %s is opened twice", name)
+	}
+	typ, err := parseAccountType(a.Type)
+	if err != nil {
+		return fmt.Errorf("account %s: %w", name, err)
+	}
+	initial, err := parseMoney(a.Initial, s.cfg.Script.currency())
+	if err != nil {
+		return fmt.Errorf("account %s: %w", name, err)
+	}
+	resp, err := s.accounts.CreateAccount(ctx, &accountv2.CreateAccountRequest{
+		UserId:         s.userIDs[login],
+		InitialBalance: initial,
+		Type:           typ,
+		TermMonths:     a.TermMonths,
+		RequestId:      s.ids.NewID(),
+	})
+	s.stats.Requests++
+	if err != nil {
+		return fmt.Errorf("open account %s: %w", name, err)
+	}
+	s.accountIDs[name] = resp.Account.Id
+	s.opened = append(s.opened, name)
+	return nil
+}
+
+// action is one customer request at a point in simulated time.
+type action struct {
+	at     time.Time
+	op     Op
+	from   string // account name
+	to     string // account name, transfers only
+	amount *commonv1.Money
+}
+
+// apply sends a. Requests the bank rejects or holds for review are counted; only
+// a reference to an unknown account, which is a mistake in the script, stops the
+// run.
+func (s *Simulation) apply(ctx context.Context, a action) error {
+	from, ok := s.accountIDs[a.from]
+	if !ok {
+		return fmt.Errorf("%s at %s: unknown account %q", a.op, a.at.Format(time.RFC3339), a.from)
+	}
+
+	var (
+		review *accountv2.Review
+		err    error
+	)
+	switch a.op {
+	case OpDeposit:
+		_, err = s.accounts.Deposit(ctx, &accountv2.DepositRequest{AccountId: from, Amount: a.amount, RequestId: s.ids.NewID()})
+	case OpWithdraw:
+		var resp *accountv2.WithdrawResponse
+		resp, err = s.accounts.Withdraw(ctx, &accountv2.WithdrawRequest{AccountId: from, Amount: a.amount, RequestId: s.ids.NewID()})
+		review = resp.GetReview()
+	case OpTransfer:
+		to, ok := s.accountIDs[a.to]
+		if !ok {
+			return fmt.Errorf("%s at %s: unknown account %q", a.op, a.at.Format(time.RFC3339), a.to)
+		}
+		var resp *accountv2.TransferResponse
+		resp, err = s.accounts.Transfer(ctx, &accountv2.TransferRequest{FromAccountId: from, ToAccountId: to, Amount: a.amount, RequestId: s.ids.NewID()})
+		review = resp.GetReview()
+	default:
+		return fmt.Errorf("unknown operation %q", a.op)
+	}
+	s.stats.Requests++
+	switch {
+	case err != nil:
+		s.stats.Failed[status.Code(err)]++
+	case review != nil:
+		s.stats.Held++
+	}
+	return nil
+}
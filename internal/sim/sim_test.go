@@ -0,0 +1,163 @@
+package sim
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/galadeat/bank-sim/internal/account"
+	"github.com/galadeat/bank-sim/internal/risk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	log.SetOutput(io.Discard)
+	os.Exit(m.Run())
+}
+
+func runLedger(t *testing.T, cfg Config) []byte {
+	t.Helper()
+	s, err := New(cfg)
+	require.NoError(t, err)
+	defer s.Close()
+
+	ctx := context.Background()
+	require.NoError(t, s.Run(ctx))
+	var buf bytes.Buffer
+	require.NoError(t, s.WriteLedger(ctx, &buf))
+	return buf.Bytes()
+}
+
+func TestRandomRun(t *testing.T) {
+	cfg := Config{
+		Seed:           7,
+		Days:           62,
+		Customers:      5,
+		AccountOptions: []account.Option{account.WithRiskScorer(risk.NewHeuristic(risk.DefaultHeuristic))},
+	}
+
+	t.Run("same seed gives identical ledgers", func(t *testing.T) {
+		first := runLedger(t, cfg)
+		second := runLedger(t, cfg)
+		require.NotEmpty(t, first)
+		assert.True(t, bytes.Equal(first, second), "ledgers of the same seed differ")
+		// salaries and savings balances accrue interest over two month ends
+		assert.Contains(t, string(first), "INTEREST")
+	})
+
+	t.Run("different seeds give different ledgers", func(t *testing.T) {
+		other := cfg
+		other.Seed = 8
+		assert.NotEqual(t, runLedger(t, cfg), runLedger(t, other))
+	})
+}
+
+func TestScriptedRun(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "script.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{
+		"start": "2025-03-01T00:00:00Z",
+		"customers": [
+			{"login": "alice", "accounts": [{"name": "main", "initial": "100"}]},
+			{"login": "bob", "accounts": [{"name": "main"}]}
+		],
+		"events": [
+			{"day": 0, "time": "09:00", "op": "deposit", "from": "alice/main", "amount": "50.25"},
+			{"day": 1, "op": "transfer", "from": "alice/main", "to": "bob/main", "amount": "30"},
+			{"day": 1, "time": "08:00", "op": "withdraw", "from": "bob/main", "amount": "10"},
+			{"day": 2, "op": "withdraw", "from": "bob/main", "amount": "10"}
+		]
+	}`), 0o600))
+
+	script, err := LoadScript(path)
+	require.NoError(t, err)
+
+	s, err := New(Config{Seed: 1, Days: 3, Script: script})
+	require.NoError(t, err)
+	defer s.Close()
+	ctx := context.Background()
+	require.NoError(t, s.Run(ctx))
+
+	balances, err := s.Balances(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "120.250000000 USD", formatMoney(balances["alice/main"]))
+	// the 08:00 withdrawal runs before the noon transfer and is declined
+	assert.Equal(t, "20.000000000 USD", formatMoney(balances["bob/main"]))
+	assert.Equal(t, 8, s.Stats().Requests)
+	var failed int
+	for _, n := range s.Stats().Failed {
+		failed += n
+	}
+	assert.Equal(t, 1, failed)
+
+	var buf bytes.Buffer
+	require.NoError(t, s.WriteLedger(ctx, &buf))
+	assert.True(t, strings.HasPrefix(buf.String(), "2025-03-01T00:00:00Z\talice/main\t"))
+}
+
+// holdAll holds every payment for review.
+type holdAll struct{}
+
+func (holdAll) Assess(risk.Payment) risk.Assessment {
+	return risk.Assessment{Decision: risk.Review, Score: 50}
+}
+
+func TestHeldPayments(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "script.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{
+		"start": "2025-03-01T00:00:00Z",
+		"customers": [
+			{"login": "alice", "accounts": [{"name": "main", "initial": "100"}]},
+			{"login": "bob", "accounts": [{"name": "main", "initial": "5"}]}
+		],
+		"events": [
+			{"day": 0, "op": "withdraw", "from": "alice/main", "amount": "10"},
+			{"day": 0, "op": "transfer", "from": "alice/main", "to": "bob/main", "amount": "20"}
+		]
+	}`), 0o600))
+
+	script, err := LoadScript(path)
+	require.NoError(t, err)
+
+	s, err := New(Config{Seed: 1, Days: 1, Script: script, AccountOptions: []account.Option{account.WithRiskScorer(holdAll{})}})
+	require.NoError(t, err)
+	defer s.Close()
+	ctx := context.Background()
+	require.NoError(t, s.Run(ctx))
+
+	// held payments move no money and are not failures
+	balances, err := s.Balances(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "100.000000000 USD", formatMoney(balances["alice/main"]))
+	assert.Equal(t, "5.000000000 USD", formatMoney(balances["bob/main"]))
+	assert.Equal(t, 2, s.Stats().Held)
+	assert.Empty(t, s.Stats().Failed)
+}
+
+func TestLoadScriptErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		errMsg string
+	}{
+		{name: "unknown op", script: `{"events": [{"op": "steal", "from": "a/b", "amount": "1"}]}`, errMsg: "unknown op"},
+		{name: "transfer without target", script: `{"events": [{"op": "transfer", "from": "a/b", "amount": "1"}]}`, errMsg: "needs a to account"},
+		{name: "bad time", script: `{"events": [{"op": "deposit", "from": "a/b", "time": "noon", "amount": "1"}]}`, errMsg: "time must look like"},
+		{name: "zero amount", script: `{"events": [{"op": "deposit", "from": "a/b", "amount": "0"}]}`, errMsg: "amount must be positive"},
+		{name: "unknown field", script: `{"customers": [], "clients": []}`, errMsg: "unknown field"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "script.json")
+			require.NoError(t, os.WriteFile(path, []byte(tt.script), 0o600))
+			_, err := LoadScript(path)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errMsg)
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package tui
+
+import (
+	"context"
+
+	tea "github.com/charmbracelet/bubbletea"
+	accountv2 "github.com/galadeat/bank-sim/api/proto/account/v2"
+	userv1 "github.com/galadeat/bank-sim/api/proto/user/v1"
+)
+
+// snapshotMsg carries every user and their open accounts.
+type snapshotMsg struct {
+	users    []*userv1.UserInfo
+	accounts map[string][]*accountv2.AccountInfo
+	err      error
+}
+
+// transactionsMsg carries the transactions of one account.
+type transactionsMsg struct {
+	accountID string
+	txs       []*accountv2.Transaction
+	err       error
+}
+
+// resultMsg reports the outcome of a form.
+type resultMsg struct {
+	text string
+	err  error
+}
+
+// fetchSnapshot lists the users and the accounts of each of them. Commands run
+// on their own goroutine, so they only use values copied from the model.
+func (m *Model) fetchSnapshot() tea.Cmd {
+	users, accounts, timeout := m.users, m.accounts, m.timeout
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		resp, err := users.ListUsers(ctx, &userv1.ListUsersRequest{})
+		if err != nil {
+			return snapshotMsg{err: err}
+		}
+		msg := snapshotMsg{users: resp.Users, accounts: make(map[string][]*accountv2.AccountInfo)}
+		for _, u := range resp.Users {
+			list, err := accounts.ListAccounts(ctx, &accountv2.ListAccountsRequest{UserId: u.Id})
+			if err != nil {
+				return snapshotMsg{err: err}
+			}
+			msg.accounts[u.Id] = list.Accounts
+		}
+		return msg
+	}
+}
+
+// fetchTransactions loads the transactions of the selected account.
+func (m *Model) fetchTransactions() tea.Cmd {
+	if m.selAccount == "" {
+		return nil
+	}
+	accounts, timeout, id := m.accounts, m.timeout, m.selAccount
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		resp, err := accounts.ListTransactions(ctx, &accountv2.ListTransactionsRequest{AccountId: id})
+		if err != nil {
+			return transactionsMsg{accountID: id, err: err}
+		}
+		return transactionsMsg{accountID: id, txs: resp.Transactions}
+	}
+}
@@ -0,0 +1,183 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	accountv2 "github.com/galadeat/bank-sim/api/proto/account/v2"
+	commonv1 "github.com/galadeat/bank-sim/api/proto/common/v1"
+	"github.com/galadeat/bank-sim/pkg/money"
+	"github.com/gofrs/uuid"
+)
+
+type formKind int
+
+const (
+	formDeposit formKind = iota
+	formWithdraw
+	formTransfer
+)
+
+func (k formKind) String() string {
+	switch k {
+	case formDeposit:
+		return "Deposit"
+	case formWithdraw:
+		return "Withdraw"
+	default:
+		return "Transfer"
+	}
+}
+
+// form asks for the amount of a deposit or withdrawal, and for transfers also
+// the account to transfer to.
+type form struct {
+	kind    formKind
+	account *accountv2.AccountInfo
+	inputs  []textinput.Model
+	focus   int
+	err     string
+}
+
+func newForm(kind formKind, account *accountv2.AccountInfo) *form {
+	f := &form{kind: kind, account: account}
+	if kind == formTransfer {
+		to := textinput.New()
+		to.Prompt = "To account: "
+		to.Placeholder = "id or the first characters of it"
+		f.inputs = append(f.inputs, to)
+	}
+	amount := textinput.New()
+	amount.Prompt = "Amount:     "
+	amount.Placeholder = "12.50 " + account.Balance.GetCurrency()
+	f.inputs = append(f.inputs, amount)
+	f.inputs[0].Focus()
+	return f
+}
+
+func (f *form) amount() string {
+	return f.inputs[len(f.inputs)-1].Value()
+}
+
+func (f *form) setFocus(i int) tea.Cmd {
+	f.inputs[f.focus].Blur()
+	f.focus = (i + len(f.inputs)) % len(f.inputs)
+	return f.inputs[f.focus].Focus()
+}
+
+// openForm starts a form on the selected account.
+func (m *Model) openForm(kind formKind) tea.Cmd {
+	acc := m.selectedAccount()
+	if acc == nil {
+		m.status = "select an account first"
+		return nil
+	}
+	m.form = newForm(kind, acc)
+	m.status = ""
+	return textinput.Blink
+}
+
+func (m *Model) updateForm(msg tea.KeyMsg) tea.Cmd {
+	f := m.form
+	switch msg.String() {
+	case "esc":
+		m.form = nil
+		return nil
+	case "tab", "down":
+		return f.setFocus(f.focus + 1)
+	case "shift+tab", "up":
+		return f.setFocus(f.focus - 1)
+	case "enter":
+		if f.focus < len(f.inputs)-1 {
+			return f.setFocus(f.focus + 1)
+		}
+		return m.submit()
+	}
+	var cmd tea.Cmd
+	f.inputs[f.focus], cmd = f.inputs[f.focus].Update(msg)
+	return cmd
+}
+
+// submit checks the form and sends it. Invalid input keeps the form open with
+// the reason; otherwise the form closes and the outcome arrives as a resultMsg.
+func (m *Model) submit() tea.Cmd {
+	f := m.form
+	amount, err := money.ParseString(f.amount())
+	if err != nil {
+		f.err = err.Error()
+		return nil
+	}
+	if cur := f.account.Balance.GetCurrency(); cur != "" && amount.Currency != cur {
+		f.err = fmt.Sprintf("the account is in %s", cur)
+		return nil
+	}
+	var to *accountv2.AccountInfo
+	if f.kind == formTransfer {
+		id := strings.TrimSpace(f.inputs[0].Value())
+		var ok bool
+		if to, ok = m.findAccount(id); id == "" || !ok {
+			f.err = fmt.Sprintf("no single account matches %q", id)
+			return nil
+		}
+		if to.Id == f.account.Id {
+			f.err = "choose another account to transfer to"
+			return nil
+		}
+	}
+
+	m.form = nil
+	m.status = fmt.Sprintf("%s of %s sent", strings.ToLower(f.kind.String()), money.Format(amount))
+	return m.send(f.kind, f.account.Id, to.GetId(), amount)
+}
+
+func (m *Model) send(kind formKind, from, to string, amount *commonv1.Money) tea.Cmd {
+	accounts, timeout := m.accounts, m.timeout
+	requestID := uuid.Must(uuid.NewV4()).String()
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		switch kind {
+		case formDeposit:
+			resp, err := accounts.Deposit(ctx, &accountv2.DepositRequest{AccountId: from, Amount: amount, RequestId: requestID})
+			if err != nil {
+				return resultMsg{err: err}
+			}
+			return resultMsg{text: fmt.Sprintf("deposited %s, balance %s", money.Format(amount), money.Format(resp.Account.GetBalance()))}
+		case formWithdraw:
+			resp, err := accounts.Withdraw(ctx, &accountv2.WithdrawRequest{AccountId: from, Amount: amount, RequestId: requestID})
+			if err != nil {
+				return resultMsg{err: err}
+			}
+			if r := resp.Review; r != nil {
+				return resultMsg{text: fmt.Sprintf("withdrawal held for review %s (score %d)", r.Id, r.Score)}
+			}
+			return resultMsg{text: fmt.Sprintf("withdrew %s, balance %s", money.Format(amount), money.Format(resp.Account.GetBalance()))}
+		default:
+			resp, err := accounts.Transfer(ctx, &accountv2.TransferRequest{FromAccountId: from, ToAccountId: to, Amount: amount, RequestId: requestID})
+			if err != nil {
+				return resultMsg{err: err}
+			}
+			if r := resp.Review; r != nil {
+				return resultMsg{text: fmt.Sprintf("transfer held for review %s (score %d)", r.Id, r.Score)}
+			}
+			return resultMsg{text: fmt.Sprintf("transferred %s to %s", money.Format(amount), shortID(to))}
+		}
+	}
+}
+
+func (f *form) view() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s · account %s · balance %s\n\n", f.kind, shortID(f.account.Id), money.Format(f.account.Balance))
+	for _, in := range f.inputs {
+		b.WriteString(in.View() + "\n")
+	}
+	if f.err != "" {
+		b.WriteString("\n" + errorStyle.Render(f.err) + "\n")
+	}
+	b.WriteString("\n" + helpStyle.Render("enter submit · tab next field · esc cancel"))
+	return b.String()
+}
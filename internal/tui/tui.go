@@ -0,0 +1,385 @@
+// Package tui is the full-screen dashboard of the client: users and their
+// accounts in tables, the selected account with its recent transactions, a
+// feed of balance changes and forms to move money. It polls the services, so
+// changes made by other clients show up within one refresh interval.
+package tui
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	accountv2 "github.com/galadeat/bank-sim/api/proto/account/v2"
+	userv1 "github.com/galadeat/bank-sim/api/proto/user/v1"
+	"github.com/galadeat/bank-sim/internal/cli"
+	"github.com/galadeat/bank-sim/pkg/money"
+)
+
+const (
+	// maxActivity is how many balance changes the activity feed keeps.
+	maxActivity = 8
+	// maxTransactions is how many of the latest transactions are shown.
+	maxTransactions = 10
+)
+
+type pane int
+
+const (
+	paneUsers pane = iota
+	paneAccounts
+)
+
+// Model is the dashboard state.
+type Model struct {
+	users    userv1.UserClient
+	accounts accountv2.AccountClient
+	refresh  time.Duration
+	timeout  time.Duration
+	now      func() time.Time
+
+	focus        pane
+	userTable    table.Model
+	accountTable table.Model
+	txTable      table.Model
+
+	userList   []*userv1.UserInfo
+	byUser     map[string][]*accountv2.AccountInfo
+	selUser    string
+	selAccount string
+	txs        []*accountv2.Transaction
+
+	// balances holds the balance of every account seen in the last snapshot,
+	// in nanos, to tell what changed in the next one. Nil until the first.
+	balances map[string]int64
+	activity []string
+
+	form    *form
+	status  string
+	err     error
+	updated time.Time
+}
+
+// Option configures New.
+type Option func(*Model)
+
+// WithRefreshInterval sets how often users, accounts and transactions are
+// reloaded.
+func WithRefreshInterval(d time.Duration) Option {
+	return func(m *Model) {
+		m.refresh = d
+	}
+}
+
+// WithTimeout sets the deadline of every call to the services.
+func WithTimeout(d time.Duration) Option {
+	return func(m *Model) {
+		m.timeout = d
+	}
+}
+
+// New is the constructor
+func New(users userv1.UserClient, accounts accountv2.AccountClient, opts ...Option) *Model {
+	m := &Model{
+		users:    users,
+		accounts: accounts,
+		refresh:  2 * time.Second,
+		timeout:  5 * time.Second,
+		now:      time.Now,
+		byUser:   make(map[string][]*accountv2.AccountInfo),
+		userTable: table.New(table.WithColumns([]table.Column{
+			{Title: "Login", Width: 14}, {Title: "Email", Width: 22}, {Title: "ID", Width: 8},
+		}), table.WithHeight(8), table.WithFocused(true)),
+		accountTable: table.New(table.WithColumns([]table.Column{
+			{Title: "ID", Width: 8}, {Title: "Type", Width: 12}, {Title: "Status", Width: 8}, {Title: "Balance", Width: 14},
+		}), table.WithHeight(8)),
+		txTable: table.New(table.WithColumns([]table.Column{
+			{Title: "Time", Width: 19}, {Title: "Type", Width: 12}, {Title: "Amount", Width: 14}, {Title: "Balance", Width: 14},
+		}), table.WithHeight(maxTransactions)),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// Run shows the dashboard until the user quits.
+func Run(users userv1.UserClient, accounts accountv2.AccountClient, opts ...Option) error {
+	_, err := tea.NewProgram(New(users, accounts, opts...), tea.WithAltScreen()).Run()
+	return err
+}
+
+type tickMsg time.Time
+
+func (m *Model) tick() tea.Cmd {
+	return tea.Tick(m.refresh, func(t time.Time) tea.Msg { return tickMsg(t) })
+}
+
+// Init implements tea.Model.
+func (m *Model) Init() tea.Cmd {
+	return tea.Batch(m.fetchSnapshot(), m.tick())
+}
+
+// Update implements tea.Model.
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tickMsg:
+		return m, tea.Batch(m.fetchSnapshot(), m.tick())
+	case snapshotMsg:
+		return m, m.applySnapshot(msg)
+	case transactionsMsg:
+		m.applyTransactions(msg)
+		return m, nil
+	case resultMsg:
+		if msg.err != nil {
+			m.status = "error: " + cli.Describe(msg.err)
+		} else {
+			m.status = msg.text
+		}
+		return m, m.fetchSnapshot()
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+		if m.form != nil {
+			return m, m.updateForm(msg)
+		}
+		return m, m.handleKey(msg)
+	}
+	return m, nil
+}
+
+func (m *Model) handleKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "q", "esc":
+		return tea.Quit
+	case "tab":
+		m.switchFocus()
+		return nil
+	case "r":
+		m.status = "refreshing"
+		return m.fetchSnapshot()
+	case "d":
+		return m.openForm(formDeposit)
+	case "w":
+		return m.openForm(formWithdraw)
+	case "t":
+		return m.openForm(formTransfer)
+	}
+
+	if m.focus == paneUsers {
+		m.userTable, _ = m.userTable.Update(msg)
+		if c := m.userTable.Cursor(); c >= 0 && c < len(m.userList) && m.userList[c].Id != m.selUser {
+			m.selUser = m.userList[c].Id
+			m.selAccount = ""
+			m.setAccountRows()
+			return m.fetchTransactions()
+		}
+		return nil
+	}
+	m.accountTable, _ = m.accountTable.Update(msg)
+	accounts := m.byUser[m.selUser]
+	if c := m.accountTable.Cursor(); c >= 0 && c < len(accounts) && accounts[c].Id != m.selAccount {
+		m.selAccount = accounts[c].Id
+		m.txs = nil
+		m.txTable.SetRows(nil)
+		return m.fetchTransactions()
+	}
+	return nil
+}
+
+func (m *Model) switchFocus() {
+	if m.focus == paneUsers {
+		m.focus = paneAccounts
+		m.userTable.Blur()
+		m.accountTable.Focus()
+		return
+	}
+	m.focus = paneUsers
+	m.accountTable.Blur()
+	m.userTable.Focus()
+}
+
+// applySnapshot shows the users and accounts of msg, records what changed since
+// the last snapshot and reloads the transactions of the selected account.
+func (m *Model) applySnapshot(msg snapshotMsg) tea.Cmd {
+	if msg.err != nil {
+		m.err = msg.err
+		return nil
+	}
+	m.err = nil
+	m.updated = m.now()
+	m.recordActivity(msg.accounts)
+	m.userList = msg.users
+	m.byUser = msg.accounts
+
+	rows := make([]table.Row, len(m.userList))
+	cursor := 0
+	for i, u := range m.userList {
+		rows[i] = table.Row{u.Login, u.Email, shortID(u.Id)}
+		if u.Id == m.selUser {
+			cursor = i
+		}
+	}
+	m.userTable.SetRows(rows)
+	if len(rows) == 0 {
+		m.selUser = ""
+	} else {
+		m.userTable.SetCursor(cursor)
+		m.selUser = m.userList[cursor].Id
+	}
+	m.setAccountRows()
+	return m.fetchTransactions()
+}
+
+// setAccountRows shows the accounts of the selected user and keeps the selected
+// account if the user still has it.
+func (m *Model) setAccountRows() {
+	accounts := m.byUser[m.selUser]
+	rows := make([]table.Row, len(accounts))
+	cursor := 0
+	for i, acc := range accounts {
+		rows[i] = table.Row{shortID(acc.Id), enumName(acc.Type.String(), "ACCOUNT_TYPE_"),
+			enumName(acc.Status.String(), "ACCOUNT_STATUS_"), money.Format(acc.Balance)}
+		if acc.Id == m.selAccount {
+			cursor = i
+		}
+	}
+	m.accountTable.SetRows(rows)
+	if len(rows) == 0 {
+		m.selAccount = ""
+		m.txs = nil
+		m.txTable.SetRows(nil)
+		return
+	}
+	m.accountTable.SetCursor(cursor)
+	m.selAccount = accounts[cursor].Id
+}
+
+func (m *Model) applyTransactions(msg transactionsMsg) {
+	if msg.accountID != m.selAccount {
+		return
+	}
+	if msg.err != nil {
+		m.err = msg.err
+		return
+	}
+	txs := msg.txs
+	if len(txs) > maxTransactions {
+		txs = txs[len(txs)-maxTransactions:]
+	}
+	m.txs = txs
+	rows := make([]table.Row, 0, len(txs))
+	for i := len(txs) - 1; i >= 0; i-- {
+		tx := txs[i]
+		rows = append(rows, table.Row{tx.CreatedAt.AsTime().Local().Format(time.DateTime),
+			enumName(tx.Type.String(), "TRANSACTION_TYPE_"), money.Format(tx.Amount), money.Format(tx.BalanceAfter)})
+	}
+	m.txTable.SetRows(rows)
+}
+
+// recordActivity adds a line to the activity feed for every account whose
+// balance changed, and for accounts that were opened or closed, since the last
+// snapshot.
+func (m *Model) recordActivity(byUser map[string][]*accountv2.AccountInfo) {
+	balances := make(map[string]int64)
+	at := m.now().Format(time.TimeOnly)
+	var lines []string
+	for _, u := range m.sortedUsers(byUser) {
+		for _, acc := range byUser[u] {
+			n := money.Nanos(acc.Balance)
+			balances[acc.Id] = n
+			if m.balances == nil {
+				continue
+			}
+			old, ok := m.balances[acc.Id]
+			switch {
+			case !ok:
+				lines = append(lines, at+"  "+shortID(acc.Id)+" opened with "+money.Format(acc.Balance))
+			case old != n:
+				delta := money.FromNanos(n-old, acc.Balance.GetCurrency())
+				sign := "+"
+				if n < old {
+					sign = ""
+				}
+				lines = append(lines, at+"  "+shortID(acc.Id)+" "+sign+money.Format(delta)+" → "+money.Format(acc.Balance))
+			}
+		}
+	}
+	if m.balances != nil {
+		for id := range m.balances {
+			if _, ok := balances[id]; !ok {
+				lines = append(lines, at+"  "+shortID(id)+" closed")
+			}
+		}
+	}
+	m.balances = balances
+	m.activity = append(lines, m.activity...)
+	if len(m.activity) > maxActivity {
+		m.activity = m.activity[:maxActivity]
+	}
+}
+
+// sortedUsers returns the ids of byUser in the order of the users table, so the
+// feed lists changes in the order they are shown.
+func (m *Model) sortedUsers(byUser map[string][]*accountv2.AccountInfo) []string {
+	ids := make([]string, 0, len(byUser))
+	seen := make(map[string]bool)
+	for _, u := range m.userList {
+		if _, ok := byUser[u.Id]; ok {
+			ids = append(ids, u.Id)
+			seen[u.Id] = true
+		}
+	}
+	var rest []string
+	for id := range byUser {
+		if !seen[id] {
+			rest = append(rest, id)
+		}
+	}
+	sort.Strings(rest)
+	return append(ids, rest...)
+}
+
+// selectedAccount returns the account selected in the accounts table.
+func (m *Model) selectedAccount() *accountv2.AccountInfo {
+	for _, acc := range m.byUser[m.selUser] {
+		if acc.Id == m.selAccount {
+			return acc
+		}
+	}
+	return nil
+}
+
+// findAccount returns the account with the given id, or the only account whose
+// id starts with it, as shown in the tables.
+func (m *Model) findAccount(id string) (*accountv2.AccountInfo, bool) {
+	var found *accountv2.AccountInfo
+	for _, accounts := range m.byUser {
+		for _, acc := range accounts {
+			if acc.Id == id {
+				return acc, true
+			}
+			if strings.HasPrefix(acc.Id, id) {
+				if found != nil {
+					return nil, false
+				}
+				found = acc
+			}
+		}
+	}
+	return found, found != nil
+}
+
+func shortID(id string) string {
+	if len(id) > 8 {
+		return id[:8]
+	}
+	return id
+}
+
+// enumName turns ACCOUNT_TYPE_TERM_DEPOSIT into term_deposit.
+func enumName(name, prefix string) string {
+	return strings.ToLower(strings.TrimPrefix(name, prefix))
+}
@@ -0,0 +1,208 @@
+package tui
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	accountv2 "github.com/galadeat/bank-sim/api/proto/account/v2"
+	commonv1 "github.com/galadeat/bank-sim/api/proto/common/v1"
+	userv1 "github.com/galadeat/bank-sim/api/proto/user/v1"
+	"github.com/galadeat/bank-sim/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func newTestModel(t *testing.T) (*Model, *mocks.MockUserClient, *mocks.MockAccountClient) {
+	ctrl := gomock.NewController(t)
+	users := mocks.NewMockUserClient(ctrl)
+	accounts := mocks.NewMockAccountClient(ctrl)
+	m := New(users, accounts)
+	m.now = func() time.Time { return time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC) }
+	return m, users, accounts
+}
+
+func usd(units int64) *commonv1.Money {
+	return &commonv1.Money{Currency: "USD", Units: units}
+}
+
+func account(id string, balance int64) *accountv2.AccountInfo {
+	return &accountv2.AccountInfo{
+		Id: id, Balance: usd(balance), AvailableBalance: usd(balance),
+		Type: accountv2.AccountType_ACCOUNT_TYPE_CHECKING, Status: accountv2.AccountStatus_ACCOUNT_STATUS_ACTIVE,
+	}
+}
+
+func snapshot(accounts ...*accountv2.AccountInfo) snapshotMsg {
+	return snapshotMsg{
+		users:    []*userv1.UserInfo{{Id: "user-1", Login: "alice"}},
+		accounts: map[string][]*accountv2.AccountInfo{"user-1": accounts},
+	}
+}
+
+func key(s string) tea.KeyMsg {
+	switch s {
+	case "enter":
+		return tea.KeyMsg{Type: tea.KeyEnter}
+	case "esc":
+		return tea.KeyMsg{Type: tea.KeyEsc}
+	case "down":
+		return tea.KeyMsg{Type: tea.KeyDown}
+	case "tab":
+		return tea.KeyMsg{Type: tea.KeyTab}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func typeText(m *Model, s string) {
+	for _, r := range s {
+		m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+}
+
+func TestFetchSnapshot(t *testing.T) {
+	m, users, accounts := newTestModel(t)
+	users.EXPECT().ListUsers(gomock.Any(), gomock.Any()).Return(&userv1.ListUsersResponse{
+		Users: []*userv1.UserInfo{{Id: "user-1", Login: "alice"}, {Id: "user-2", Login: "bob"}},
+	}, nil)
+	accounts.EXPECT().ListAccounts(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, req *accountv2.ListAccountsRequest, _ ...grpc.CallOption) (*accountv2.ListAccountsResponse, error) {
+			return &accountv2.ListAccountsResponse{Accounts: []*accountv2.AccountInfo{account("acc-"+req.UserId, 10)}}, nil
+		}).Times(2)
+
+	msg := m.fetchSnapshot()().(snapshotMsg)
+	require.NoError(t, msg.err)
+	assert.Len(t, msg.users, 2)
+	assert.Equal(t, "acc-user-2", msg.accounts["user-2"][0].Id)
+}
+
+func TestApplySnapshot(t *testing.T) {
+	m, _, accounts := newTestModel(t)
+	accounts.EXPECT().ListTransactions(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, req *accountv2.ListTransactionsRequest, _ ...grpc.CallOption) (*accountv2.ListTransactionsResponse, error) {
+			assert.Equal(t, "acc-1", req.AccountId)
+			return &accountv2.ListTransactionsResponse{Transactions: []*accountv2.Transaction{
+				{Id: "tx-1", Type: accountv2.TransactionType_TRANSACTION_TYPE_DEPOSIT, Amount: usd(10), BalanceAfter: usd(10), CreatedAt: timestamppb.Now()},
+			}}, nil
+		})
+
+	_, cmd := m.Update(snapshot(account("acc-1", 10), account("acc-2", 5)))
+	require.NotNil(t, cmd)
+	m.Update(cmd())
+
+	assert.Equal(t, "user-1", m.selUser)
+	assert.Equal(t, "acc-1", m.selAccount)
+	assert.Len(t, m.accountTable.Rows(), 2)
+	assert.Equal(t, "$10.00", m.accountTable.Rows()[0][3])
+	require.Len(t, m.txTable.Rows(), 1)
+	assert.Equal(t, "deposit", m.txTable.Rows()[0][1])
+	assert.Empty(t, m.activity, "the first snapshot is not activity")
+	assert.Contains(t, m.View(), "alice")
+}
+
+func TestActivity(t *testing.T) {
+	m, _, _ := newTestModel(t)
+	m.recordActivity(snapshot(account("acc-1", 10), account("acc-2", 5)).accounts)
+	m.recordActivity(snapshot(account("acc-1", 12), account("acc-3", 1)).accounts)
+
+	require.Len(t, m.activity, 3)
+	assert.Equal(t, "12:00:00  acc-1 +$2.00 → $12.00", m.activity[0])
+	assert.Equal(t, "12:00:00  acc-3 opened with $1.00", m.activity[1])
+	assert.Equal(t, "12:00:00  acc-2 closed", m.activity[2])
+}
+
+func TestNavigation(t *testing.T) {
+	m, _, accounts := newTestModel(t)
+	accounts.EXPECT().ListTransactions(gomock.Any(), gomock.Any()).Return(&accountv2.ListTransactionsResponse{}, nil).AnyTimes()
+	m.Update(snapshot(account("acc-1", 10), account("acc-2", 5)))
+
+	m.Update(key("tab"))
+	assert.Equal(t, paneAccounts, m.focus)
+	_, cmd := m.Update(key("down"))
+	assert.Equal(t, "acc-2", m.selAccount)
+	require.NotNil(t, cmd, "moving to another account loads its transactions")
+
+	// a refresh keeps the selection
+	m.Update(snapshot(account("acc-1", 10), account("acc-2", 7)))
+	assert.Equal(t, "acc-2", m.selAccount)
+	assert.Equal(t, 1, m.accountTable.Cursor())
+}
+
+func TestDepositForm(t *testing.T) {
+	m, _, accounts := newTestModel(t)
+	accounts.EXPECT().ListTransactions(gomock.Any(), gomock.Any()).Return(&accountv2.ListTransactionsResponse{}, nil).AnyTimes()
+	m.Update(snapshot(account("acc-1", 10)))
+
+	m.Update(key("d"))
+	require.NotNil(t, m.form)
+
+	typeText(m, "ten USD")
+	m.Update(key("enter"))
+	require.NotNil(t, m.form, "an invalid amount keeps the form open")
+	assert.Contains(t, m.form.err, "invalid amount")
+
+	m.form.inputs[0].SetValue("2.50 EUR")
+	m.Update(key("enter"))
+	assert.Equal(t, "the account is in USD", m.form.err)
+
+	m.form.inputs[0].SetValue("2.50 USD")
+	_, cmd := m.Update(key("enter"))
+	assert.Nil(t, m.form)
+	require.NotNil(t, cmd)
+
+	accounts.EXPECT().Deposit(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, req *accountv2.DepositRequest, _ ...grpc.CallOption) (*accountv2.DepositResponse, error) {
+			assert.Equal(t, "acc-1", req.AccountId)
+			assert.Equal(t, int64(2), req.Amount.Units)
+			assert.Equal(t, int32(500_000_000), req.Amount.Nanos)
+			assert.NotEmpty(t, req.RequestId)
+			return &accountv2.DepositResponse{Account: account("acc-1", 12)}, nil
+		})
+	result := cmd().(resultMsg)
+	require.NoError(t, result.err)
+	assert.Equal(t, "deposited $2.50, balance $12.00", result.text)
+
+	_, cmd = m.Update(result)
+	assert.Equal(t, "deposited $2.50, balance $12.00", m.status)
+	assert.NotNil(t, cmd, "a result reloads the accounts")
+}
+
+func TestTransferForm(t *testing.T) {
+	m, _, accounts := newTestModel(t)
+	accounts.EXPECT().ListTransactions(gomock.Any(), gomock.Any()).Return(&accountv2.ListTransactionsResponse{}, nil).AnyTimes()
+	m.Update(snapshot(account("acc-1", 10), account("bcc-2", 5)))
+
+	m.Update(key("t"))
+	require.NotNil(t, m.form)
+	typeText(m, "acc")
+	m.Update(key("enter"))
+	typeText(m, "$3")
+	m.Update(key("enter"))
+	assert.Equal(t, "choose another account to transfer to", m.form.err)
+
+	m.form.inputs[0].SetValue("bcc")
+	_, cmd := m.Update(key("enter"))
+	require.Nil(t, m.form)
+
+	accounts.EXPECT().Transfer(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, req *accountv2.TransferRequest, _ ...grpc.CallOption) (*accountv2.TransferResponse, error) {
+			assert.Equal(t, "acc-1", req.FromAccountId)
+			assert.Equal(t, "bcc-2", req.ToAccountId)
+			return nil, status.Error(codes.FailedPrecondition, "insufficient funds")
+		})
+	m.Update(cmd())
+	assert.Equal(t, "error: failed precondition: insufficient funds", m.status)
+}
+
+func TestFormNeedsAccount(t *testing.T) {
+	m, _, _ := newTestModel(t)
+	m.Update(key("w"))
+	assert.Nil(t, m.form)
+	assert.Equal(t, "select an account first", m.status)
+}
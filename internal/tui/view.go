@@ -0,0 +1,95 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/galadeat/bank-sim/internal/cli"
+	"github.com/galadeat/bank-sim/pkg/money"
+)
+
+var (
+	titleStyle   = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205"))
+	helpStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	errorStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("203"))
+	boxStyle     = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("240")).Padding(0, 1)
+	focusedStyle = boxStyle.BorderForeground(lipgloss.Color("205"))
+	headingStyle = lipgloss.NewStyle().Bold(true)
+)
+
+const keyHelp = "↑/↓ move · tab switch table · d deposit · w withdraw · t transfer · r refresh · q quit"
+
+// View implements tea.Model.
+func (m *Model) View() string {
+	header := titleStyle.Render("Bank Sim")
+	switch {
+	case m.err != nil:
+		header += "  " + errorStyle.Render("error: "+cli.Describe(m.err))
+	case !m.updated.IsZero():
+		header += "  " + helpStyle.Render("updated "+m.updated.Format(time.TimeOnly))
+	}
+
+	users, accounts := boxStyle, boxStyle
+	if m.focus == paneUsers {
+		users = focusedStyle
+	} else {
+		accounts = focusedStyle
+	}
+	top := lipgloss.JoinHorizontal(lipgloss.Top,
+		users.Render(headingStyle.Render("Users")+"\n"+m.userTable.View()),
+		accounts.Render(headingStyle.Render("Accounts")+"\n"+m.accountTable.View()))
+
+	middle := m.detailsView()
+	if m.form != nil {
+		middle = focusedStyle.Render(m.form.view())
+	}
+	middle = lipgloss.JoinHorizontal(lipgloss.Top, middle, boxStyle.Render(m.activityView()))
+
+	bottom := boxStyle.Render(headingStyle.Render("Recent transactions") + "\n" + m.txTable.View())
+
+	footer := helpStyle.Render(keyHelp)
+	if m.status != "" {
+		footer = m.status + "\n" + footer
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, header, top, middle, bottom, footer)
+}
+
+func (m *Model) detailsView() string {
+	acc := m.selectedAccount()
+	if acc == nil {
+		return boxStyle.Render(headingStyle.Render("Account") + "\n" + helpStyle.Render("no account selected"))
+	}
+	var b strings.Builder
+	b.WriteString(headingStyle.Render("Account "+shortID(acc.Id)) + "\n")
+	row := func(label, value string) {
+		if value != "" {
+			fmt.Fprintf(&b, "%-18s %s\n", label, value)
+		}
+	}
+	row("ID", acc.Id)
+	row("Owner", acc.Owner.GetLogin())
+	row("Type", enumName(acc.Type.String(), "ACCOUNT_TYPE_"))
+	row("Status", enumName(acc.Status.String(), "ACCOUNT_STATUS_"))
+	row("Balance", money.Format(acc.Balance))
+	row("Available", money.Format(acc.AvailableBalance))
+	row("Overdraft limit", money.Format(acc.OverdraftLimit))
+	row("Accrued interest", money.Format(acc.AccruedInterest))
+	if acc.OpenedAt != nil {
+		row("Opened", acc.OpenedAt.AsTime().Local().Format(time.DateOnly))
+	}
+	if acc.MaturesAt != nil {
+		row("Matures", acc.MaturesAt.AsTime().Local().Format(time.DateOnly))
+	}
+	return boxStyle.Render(strings.TrimSuffix(b.String(), "\n"))
+}
+
+func (m *Model) activityView() string {
+	lines := []string{headingStyle.Render("Activity")}
+	if len(m.activity) == 0 {
+		lines = append(lines, helpStyle.Render("balance changes show up here"))
+	}
+	lines = append(lines, m.activity...)
+	return strings.Join(lines, "\n")
+}
@@ -5,50 +5,107 @@ import (
 	"log"
 	"sync"
 
+	accountv2 "github.com/galadeat/bank-sim/api/proto/account/v2"
 	userv1 "github.com/galadeat/bank-sim/api/proto/user/v1"
-	"github.com/gofrs/uuid"
+	"github.com/galadeat/bank-sim/pkg/clock"
+	"github.com/galadeat/bank-sim/pkg/idgen"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type UserService struct {
 	userv1.UnimplementedUserServer
 	mu      sync.RWMutex
 	userMap map[string]*userv1.UserInfo
+
+	// logins and emails are case-insensitive uniqueness indexes (normalized value -> user id).
+	logins map[string]string
+	emails map[string]string
+
+	// closing holds the users whose accounts DeleteUser is closing. GetUser and
+	// ListUsers hide them, so no account is opened for them in the meantime.
+	closing map[string]bool
+
+	accountClient accountv2.AccountClient
+	clock         clock.Clock
+	ids           idgen.IDGenerator
+}
+
+// Option configures a UserService.
+type Option func(*UserService)
+
+// WithAccountClient makes DeleteUser close the user's accounts through the account
+// service and refuse the deletion while any of them holds funds, and UpdateUser
+// and DeleteUser pass the changed user on to the accounts it owns.
+func WithAccountClient(client accountv2.AccountClient) Option {
+	return func(s *UserService) {
+		s.accountClient = client
+	}
+}
+
+// WithClock sets the clock used for creation timestamps.
+func WithClock(c clock.Clock) Option {
+	return func(s *UserService) {
+		s.clock = c
+	}
+}
+
+// WithIDGenerator sets the generator of user ids.
+func WithIDGenerator(g idgen.IDGenerator) Option {
+	return func(s *UserService) {
+		s.ids = g
+	}
 }
 
 // Constructor
-func New() *UserService {
-	return &UserService{userMap: make(map[string]*userv1.UserInfo)}
+func New(opts ...Option) *UserService {
+	s := &UserService{
+		userMap: make(map[string]*userv1.UserInfo),
+		logins:  make(map[string]string),
+		emails:  make(map[string]string),
+		closing: make(map[string]bool),
+		clock:   clock.Real(),
+		ids:     idgen.Random(),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 // realizatiion of CreateUser rpc method
 func (s *UserService) CreateUser(ctx context.Context, req *userv1.CreateUserRequest) (*userv1.CreateUserResponse, error) {
-	if req.Login == "" {
-		return nil, status.Errorf(codes.InvalidArgument, "login must not be empty")
+	if err := validateLogin(req.Login); err != nil {
+		return nil, err
 	}
 
-	if req.Email == "" {
-		return nil, status.Errorf(codes.InvalidArgument, "email must not be empty")
+	if err := validateEmail(req.Email); err != nil {
+		return nil, err
 	}
 
-	id, err := uuid.NewV4()
-	if err != nil {
-		return nil, status.Errorf(codes.Internal, "error while generating user id: %v", err)
-	}
+	id := s.ids.NewID()
 
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	if _, ok := s.userMap[id.String()]; ok {
+	if _, ok := s.userMap[id]; ok {
 		return nil, status.Errorf(codes.AlreadyExists, "user already exists")
 	}
 
-	s.userMap[id.String()] = &userv1.UserInfo{Id: id.String(),
-		Login: req.Login, Email: req.Email}
+	if err := s.checkUnique(id, req.Login, req.Email); err != nil {
+		return nil, err
+	}
+
+	s.userMap[id] = &userv1.UserInfo{Id: id,
+		Login: req.Login, Email: req.Email, Status: userv1.UserStatus_USER_STATUS_ACTIVE,
+		CreatedAt: timestamppb.New(s.clock.Now()), Version: 1}
+	s.logins[normalize(req.Login)] = id
+	s.emails[normalize(req.Email)] = id
 
-	log.Printf("User %v created", id.String())
-	return &userv1.CreateUserResponse{Id: id.String()}, nil
+	log.Printf("User %v created", id)
+	return &userv1.CreateUserResponse{Id: id}, nil
 }
 
 // realization of GetUser rpc method
@@ -56,7 +113,7 @@ func (s *UserService) GetUser(ctx context.Context, req *userv1.GetUserRequest) (
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 	user, exists := s.userMap[req.Id]
-	if !exists {
+	if !exists || (isClosed(user) || s.closing[req.Id]) && !req.IncludeClosed {
 		return nil, status.Errorf(codes.NotFound, "user does not exist %v", req.Id)
 	}
 	return &userv1.GetUserResponse{User: user}, nil
@@ -76,6 +133,9 @@ func (s *UserService) ListUsers(ctx context.Context, req *userv1.ListUsersReques
 
 	users := make([]*userv1.UserInfo, 0, len(s.userMap))
 	for _, u := range s.userMap {
+		if (isClosed(u) || s.closing[u.Id]) && !req.IncludeClosed {
+			continue
+		}
 		users = append(users, u)
 	}
 
@@ -91,28 +151,72 @@ func (s *UserService) UpdateUser(ctx context.Context, req *userv1.UpdateUserRequ
 		return nil, status.Errorf(codes.Canceled, "request canceled: %v", ctx.Err())
 	default:
 	}
+	login := req.GetLogin().GetValue()
+	email := req.GetEmail().GetValue()
+
+	if login != "" {
+		if err := validateLogin(login); err != nil {
+			return nil, err
+		}
+	}
+	if email != "" {
+		if err := validateEmail(email); err != nil {
+			return nil, err
+		}
+	}
+
+	user, changed, err := s.updateUser(req.Id, login, email)
+	if err != nil {
+		return nil, err
+	}
+
+	if changed {
+		s.syncOwner(ctx, user)
+	}
+
+	return &userv1.UpdateUserResponse{User: user}, nil
+}
+
+// updateUser applies the new login and email, skipping empty ones, and returns
+// a copy of the user and whether anything changed.
+func (s *UserService) updateUser(id, login, email string) (*userv1.UserInfo, bool, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	user, ok := s.userMap[req.Id]
-	if !ok {
-		return nil, status.Errorf(codes.NotFound, "user doesn't exist!")
+	user, ok := s.userMap[id]
+	if !ok || isClosed(user) {
+		return nil, false, status.Errorf(codes.NotFound, "user doesn't exist!")
 	}
 
-	if req.Login.Value == "" && req.Email.Value == "" {
-		return &userv1.UpdateUserResponse{User: user}, nil
+	if login == "" && email == "" {
+		return proto.Clone(user).(*userv1.UserInfo), false, nil
 	}
-	if req.Email != nil && req.Email.Value != "" {
-		user.Email = req.Email.Value
+
+	if err := s.checkUnique(user.Id, login, email); err != nil {
+		return nil, false, err
 	}
 
-	if req.Login != nil && req.Login.Value != "" {
-		user.Login = req.Login.Value
+	if email != "" {
+		delete(s.emails, normalize(user.Email))
+		user.Email = email
+		s.emails[normalize(email)] = user.Id
 	}
 
-	return &userv1.UpdateUserResponse{User: user}, nil
+	if login != "" {
+		delete(s.logins, normalize(user.Login))
+		user.Login = login
+		s.logins[normalize(login)] = user.Id
+	}
+	user.Version++
+
+	return proto.Clone(user).(*userv1.UserInfo), true, nil
 }
 
+// DeleteUser soft-deletes the user: the record is kept with a closed status so its
+// history stays available for reporting, while login and email become free again.
+// With an account client configured, the user's accounts are closed first and the
+// deletion is refused if any of them still holds funds. While they are being
+// closed GetUser no longer finds the user, so no new account is opened for it.
 func (s *UserService) DeleteUser(ctx context.Context, req *userv1.DeleteUserRequest) (*userv1.DeleteUserResponse, error) {
 	select {
 	case <-ctx.Done():
@@ -120,18 +224,94 @@ func (s *UserService) DeleteUser(ctx context.Context, req *userv1.DeleteUserRequ
 	default:
 	}
 
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	user, err := s.startClosing(req.Id)
+	if err != nil {
+		return &userv1.DeleteUserResponse{Success: false}, err
+	}
 
-	_, ok := s.userMap[req.Id]
-	if !ok {
-		return &userv1.DeleteUserResponse{Success: false}, status.Errorf(codes.NotFound, "user doesn't exist")
+	// s.mu is not held across the call to the account service; the user is
+	// marked as closing instead
+	if s.accountClient != nil {
+		resp, err := s.accountClient.CloseUserAccounts(ctx, &accountv2.CloseUserAccountsRequest{UserId: req.Id})
+		if err != nil {
+			s.mu.Lock()
+			delete(s.closing, req.Id)
+			s.mu.Unlock()
+			if st, ok := status.FromError(err); ok {
+				return &userv1.DeleteUserResponse{Success: false}, st.Err()
+			}
+			return &userv1.DeleteUserResponse{Success: false}, status.Errorf(codes.Internal, "failed to call AccountService: %v", err)
+		}
+		log.Printf("User %v accounts closed: %v", req.Id, resp.GetClosedAccountIds())
 	}
 
-	delete(s.userMap, req.Id)
+	s.mu.Lock()
+	delete(s.closing, req.Id)
+	user.Status = userv1.UserStatus_USER_STATUS_CLOSED
+	user.Version++
+	delete(s.logins, normalize(user.Login))
+	delete(s.emails, normalize(user.Email))
+	closed := proto.Clone(user).(*userv1.UserInfo)
+	s.mu.Unlock()
 
 	log.Printf("User %v deleted", req.Id)
 
+	// the closed accounts keep a copy of their owner for reporting
+	s.syncOwner(ctx, closed)
+
 	return &userv1.DeleteUserResponse{Success: true}, nil
+}
+
+// syncOwner passes a changed user on to the owner copies of its accounts. s.mu
+// is not held across the call to the account service, so concurrent changes may
+// arrive out of order; the account service keeps the copy with the highest
+// version. A failed sync leaves the change in place; the owner copies are
+// repaired by the next change or by "bank check owners --fix".
+func (s *UserService) syncOwner(ctx context.Context, user *userv1.UserInfo) {
+	if s.accountClient == nil {
+		return
+	}
+	resp, err := s.accountClient.SyncOwner(ctx, &accountv2.SyncOwnerRequest{Owner: user})
+	if err != nil {
+		log.Printf("User %v changed, but its accounts were not synced: %v", user.Id, err)
+	} else if len(resp.GetUpdatedAccountIds()) > 0 {
+		log.Printf("User %v accounts synced: %v", user.Id, resp.GetUpdatedAccountIds())
+	}
+}
+
+// startClosing marks the user as closing and returns it. Only one DeleteUser
+// closes a user at a time.
+func (s *UserService) startClosing(id string) (*userv1.UserInfo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.userMap[id]
+	if !ok || isClosed(user) {
+		return nil, status.Errorf(codes.NotFound, "user doesn't exist")
+	}
+	if s.closing[id] {
+		return nil, status.Errorf(codes.FailedPrecondition, "user %v is already being deleted", id)
+	}
+	s.closing[id] = true
+	return user, nil
+}
+
+// checkUnique reports AlreadyExists if login or email is taken by a user other than id.
+// Empty values are skipped. Callers must hold s.mu.
+func (s *UserService) checkUnique(id, login, email string) error {
+	if login != "" {
+		if owner, ok := s.logins[normalize(login)]; ok && owner != id {
+			return status.Errorf(codes.AlreadyExists, "login %q is already taken", login)
+		}
+	}
+	if email != "" {
+		if owner, ok := s.emails[normalize(email)]; ok && owner != id {
+			return status.Errorf(codes.AlreadyExists, "email %q is already taken", email)
+		}
+	}
+	return nil
+}
 
+func isClosed(user *userv1.UserInfo) bool {
+	return user.Status == userv1.UserStatus_USER_STATUS_CLOSED
 }
@@ -4,7 +4,11 @@ import (
 	"context"
 	"testing"
 
+	accountv2 "github.com/galadeat/bank-sim/api/proto/account/v2"
 	userv1 "github.com/galadeat/bank-sim/api/proto/user/v1"
+	"github.com/galadeat/bank-sim/mocks"
+	"github.com/golang/mock/gomock"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/wrapperspb"
@@ -42,6 +46,24 @@ func TestCreateUser(t *testing.T) {
 			wantErr:     true,
 			wantErrCode: codes.InvalidArgument,
 		},
+		{
+			name: "invalid email",
+			req: &userv1.CreateUserRequest{
+				Email: "abc",
+				Login: "test",
+			},
+			wantErr:     true,
+			wantErrCode: codes.InvalidArgument,
+		},
+		{
+			name: "invalid login",
+			req: &userv1.CreateUserRequest{
+				Email: "test@test.com",
+				Login: "t!",
+			},
+			wantErr:     true,
+			wantErrCode: codes.InvalidArgument,
+		},
 	}
 
 	for _, tt := range tests {
@@ -65,6 +87,65 @@ func TestCreateUser(t *testing.T) {
 	}
 }
 
+func TestCreateUserUniqueness(t *testing.T) {
+	tests := []struct {
+		name        string
+		req         *userv1.CreateUserRequest
+		wantErrCode codes.Code
+	}{
+		{
+			name:        "same login",
+			req:         &userv1.CreateUserRequest{Login: "alice", Email: "other@test.com"},
+			wantErrCode: codes.AlreadyExists,
+		},
+		{
+			name:        "login differs only in case",
+			req:         &userv1.CreateUserRequest{Login: "ALICE", Email: "other@test.com"},
+			wantErrCode: codes.AlreadyExists,
+		},
+		{
+			name:        "email differs only in case",
+			req:         &userv1.CreateUserRequest{Login: "bob", Email: "Alice@Test.com"},
+			wantErrCode: codes.AlreadyExists,
+		},
+		{
+			name:        "distinct login and email",
+			req:         &userv1.CreateUserRequest{Login: "bob", Email: "bob@test.com"},
+			wantErrCode: codes.OK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			server := New()
+
+			if _, err := server.CreateUser(ctx, &userv1.CreateUserRequest{Login: "alice", Email: "alice@test.com"}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			_, err := server.CreateUser(ctx, tt.req)
+			if st, _ := status.FromError(err); st.Code() != tt.wantErrCode {
+				t.Errorf("expected %v, got %v", tt.wantErrCode, st.Code())
+			}
+		})
+	}
+
+	t.Run("login is released after delete", func(t *testing.T) {
+		ctx := context.Background()
+		server := New()
+
+		created, _ := server.CreateUser(ctx, &userv1.CreateUserRequest{Login: "alice", Email: "alice@test.com"})
+		if _, err := server.DeleteUser(ctx, &userv1.DeleteUserRequest{Id: created.Id}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if _, err := server.CreateUser(ctx, &userv1.CreateUserRequest{Login: "alice", Email: "alice@test.com"}); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
+
 func TestGetUser(t *testing.T) {
 	ctx := context.Background()
 	server := New()
@@ -284,6 +365,94 @@ func TestUpdateUser(t *testing.T) {
 		}
 	})
 
+	t.Run("nil values should not overwrite", func(t *testing.T) {
+		ctx := context.Background()
+		server := New()
+
+		created, _ := server.CreateUser(ctx, &userv1.CreateUserRequest{
+			Login: "login",
+			Email: "email@test.com",
+		})
+
+		res, err := server.UpdateUser(ctx, &userv1.UpdateUserRequest{Id: created.Id})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if res.User.Email != "email@test.com" || res.User.Login != "login" {
+			t.Errorf("expected user unchanged, got %v", res.User)
+		}
+	})
+
+	t.Run("invalid email", func(t *testing.T) {
+		ctx := context.Background()
+		server := New()
+
+		created, _ := server.CreateUser(ctx, &userv1.CreateUserRequest{
+			Login: "login",
+			Email: "email@test.com",
+		})
+
+		_, err := server.UpdateUser(ctx, &userv1.UpdateUserRequest{
+			Id:    created.Id,
+			Email: &wrapperspb.StringValue{Value: "not-an-email"},
+		})
+
+		st, _ := status.FromError(err)
+		if st.Code() != codes.InvalidArgument {
+			t.Errorf("expected InvalidArgument, got %v", st.Code())
+		}
+	})
+
+	t.Run("login taken by another user", func(t *testing.T) {
+		ctx := context.Background()
+		server := New()
+
+		server.CreateUser(ctx, &userv1.CreateUserRequest{
+			Login: "taken",
+			Email: "taken@test.com",
+		})
+		created, _ := server.CreateUser(ctx, &userv1.CreateUserRequest{
+			Login: "login",
+			Email: "email@test.com",
+		})
+
+		_, err := server.UpdateUser(ctx, &userv1.UpdateUserRequest{
+			Id:    created.Id,
+			Login: &wrapperspb.StringValue{Value: "Taken"},
+		})
+
+		st, _ := status.FromError(err)
+		if st.Code() != codes.AlreadyExists {
+			t.Errorf("expected AlreadyExists, got %v", st.Code())
+		}
+	})
+
+	t.Run("change case of own login", func(t *testing.T) {
+		ctx := context.Background()
+		server := New()
+
+		created, _ := server.CreateUser(ctx, &userv1.CreateUserRequest{
+			Login: "login",
+			Email: "email@test.com",
+		})
+
+		res, err := server.UpdateUser(ctx, &userv1.UpdateUserRequest{
+			Id:    created.Id,
+			Login: &wrapperspb.StringValue{Value: "Login"},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if res.User.Login != "Login" {
+			t.Errorf("expected login Login, got %v", res.User.Login)
+		}
+
+		if _, err := server.CreateUser(ctx, &userv1.CreateUserRequest{Login: "login", Email: "other@test.com"}); status.Code(err) != codes.AlreadyExists {
+			t.Errorf("expected AlreadyExists, got %v", err)
+		}
+	})
+
 	t.Run("empty values should not overwrite", func(t *testing.T) {
 		ctx := context.Background()
 		server := New()
@@ -379,3 +548,255 @@ func TestDeleteUser(t *testing.T) {
 		}
 	})
 }
+
+func TestDeleteUserClosesAccounts(t *testing.T) {
+	t.Run("zero-balance accounts are closed", func(t *testing.T) {
+		ctx := context.Background()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		account := mocks.NewMockAccountClient(ctrl)
+		server := New(WithAccountClient(account))
+
+		created, _ := server.CreateUser(ctx, &userv1.CreateUserRequest{
+			Login: "login",
+			Email: "email@test.com",
+		})
+
+		account.EXPECT().
+			CloseUserAccounts(gomock.Any(), &accountv2.CloseUserAccountsRequest{UserId: created.Id}).
+			Return(&accountv2.CloseUserAccountsResponse{ClosedAccountIds: []string{"acc-1"}}, nil)
+		account.EXPECT().
+			SyncOwner(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, req *accountv2.SyncOwnerRequest, _ ...grpc.CallOption) (*accountv2.SyncOwnerResponse, error) {
+				if req.Owner.Id != created.Id || req.Owner.Status != userv1.UserStatus_USER_STATUS_CLOSED || req.Owner.Version != 2 {
+					t.Errorf("expected closed owner, got %v", req.Owner)
+				}
+				return &accountv2.SyncOwnerResponse{UpdatedAccountIds: []string{"acc-1"}}, nil
+			})
+
+		res, err := server.DeleteUser(ctx, &userv1.DeleteUserRequest{Id: created.Id})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !res.Success {
+			t.Errorf("expected success=true, got %v", res.Success)
+		}
+
+		got, err := server.GetUser(ctx, &userv1.GetUserRequest{Id: created.Id, IncludeClosed: true})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got.User.Status != userv1.UserStatus_USER_STATUS_CLOSED {
+			t.Errorf("expected closed user, got %v", got.User.Status)
+		}
+
+		list, _ := server.ListUsers(ctx, &userv1.ListUsersRequest{})
+		if len(list.Users) != 0 {
+			t.Errorf("expected 0 users, got %v", len(list.Users))
+		}
+
+		list, _ = server.ListUsers(ctx, &userv1.ListUsersRequest{IncludeClosed: true})
+		if len(list.Users) != 1 {
+			t.Errorf("expected 1 user, got %v", len(list.Users))
+		}
+	})
+
+	t.Run("funded accounts refuse deletion", func(t *testing.T) {
+		ctx := context.Background()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		account := mocks.NewMockAccountClient(ctrl)
+		server := New(WithAccountClient(account))
+
+		created, _ := server.CreateUser(ctx, &userv1.CreateUserRequest{
+			Login: "login",
+			Email: "email@test.com",
+		})
+
+		account.EXPECT().
+			CloseUserAccounts(gomock.Any(), &accountv2.CloseUserAccountsRequest{UserId: created.Id}).
+			Return(nil, status.Error(codes.FailedPrecondition, "user has funded account acc-1"))
+
+		res, err := server.DeleteUser(ctx, &userv1.DeleteUserRequest{Id: created.Id})
+		st, _ := status.FromError(err)
+		if st.Code() != codes.FailedPrecondition {
+			t.Fatalf("expected FailedPrecondition, got %v", st.Code())
+		}
+		if res.Success {
+			t.Errorf("expected success=false, got %v", res.Success)
+		}
+
+		if _, err := server.GetUser(ctx, &userv1.GetUserRequest{Id: created.Id}); err != nil {
+			t.Errorf("expected user to remain active, got %v", err)
+		}
+	})
+
+	t.Run("user is hidden while its accounts are closed", func(t *testing.T) {
+		ctx := context.Background()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		account := mocks.NewMockAccountClient(ctrl)
+		server := New(WithAccountClient(account))
+
+		created, _ := server.CreateUser(ctx, &userv1.CreateUserRequest{
+			Login: "login",
+			Email: "email@test.com",
+		})
+
+		account.EXPECT().
+			CloseUserAccounts(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, req *accountv2.CloseUserAccountsRequest, _ ...grpc.CallOption) (*accountv2.CloseUserAccountsResponse, error) {
+				_, err := server.GetUser(ctx, &userv1.GetUserRequest{Id: req.UserId})
+				if st, _ := status.FromError(err); st.Code() != codes.NotFound {
+					t.Errorf("expected NotFound while closing, got %v", err)
+				}
+				list, _ := server.ListUsers(ctx, &userv1.ListUsersRequest{})
+				if len(list.Users) != 0 {
+					t.Errorf("expected 0 users while closing, got %v", len(list.Users))
+				}
+				list, _ = server.ListUsers(ctx, &userv1.ListUsersRequest{IncludeClosed: true})
+				if len(list.Users) != 1 {
+					t.Errorf("expected 1 user with closed ones, got %v", len(list.Users))
+				}
+				_, err = server.DeleteUser(ctx, &userv1.DeleteUserRequest{Id: req.UserId})
+				if st, _ := status.FromError(err); st.Code() != codes.FailedPrecondition {
+					t.Errorf("expected FailedPrecondition for a second delete, got %v", err)
+				}
+				return nil, status.Error(codes.FailedPrecondition, "user has funded account acc-1")
+			})
+
+		if _, err := server.DeleteUser(ctx, &userv1.DeleteUserRequest{Id: created.Id}); err == nil {
+			t.Fatal("expected an error")
+		}
+
+		// the failed deletion is rolled back
+		got, err := server.GetUser(ctx, &userv1.GetUserRequest{Id: created.Id})
+		if err != nil {
+			t.Fatalf("expected user to remain active, got %v", err)
+		}
+		if got.User.Status != userv1.UserStatus_USER_STATUS_ACTIVE {
+			t.Errorf("expected active user, got %v", got.User.Status)
+		}
+	})
+
+	t.Run("closed user cannot be deleted twice", func(t *testing.T) {
+		ctx := context.Background()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		account := mocks.NewMockAccountClient(ctrl)
+		server := New(WithAccountClient(account))
+
+		created, _ := server.CreateUser(ctx, &userv1.CreateUserRequest{
+			Login: "login",
+			Email: "email@test.com",
+		})
+
+		account.EXPECT().
+			CloseUserAccounts(gomock.Any(), gomock.Any()).
+			Return(&accountv2.CloseUserAccountsResponse{}, nil).
+			Times(1)
+		account.EXPECT().
+			SyncOwner(gomock.Any(), gomock.Any()).
+			Return(&accountv2.SyncOwnerResponse{}, nil).
+			Times(1)
+
+		server.DeleteUser(ctx, &userv1.DeleteUserRequest{Id: created.Id})
+		_, err := server.DeleteUser(ctx, &userv1.DeleteUserRequest{Id: created.Id})
+
+		st, _ := status.FromError(err)
+		if st.Code() != codes.NotFound {
+			t.Errorf("expected NotFound, got %v", st.Code())
+		}
+	})
+}
+
+func TestUpdateUserSyncsAccounts(t *testing.T) {
+	t.Run("changed user is passed to the account service", func(t *testing.T) {
+		ctx := context.Background()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		account := mocks.NewMockAccountClient(ctrl)
+		server := New(WithAccountClient(account))
+
+		created, _ := server.CreateUser(ctx, &userv1.CreateUserRequest{
+			Login: "login",
+			Email: "email@test.com",
+		})
+
+		account.EXPECT().
+			SyncOwner(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, req *accountv2.SyncOwnerRequest, _ ...grpc.CallOption) (*accountv2.SyncOwnerResponse, error) {
+				if req.Owner.Id != created.Id || req.Owner.Login != "renamed" || req.Owner.Version != 2 {
+					t.Errorf("unexpected owner %v", req.Owner)
+				}
+				return &accountv2.SyncOwnerResponse{UpdatedAccountIds: []string{"acc-1"}}, nil
+			})
+
+		res, err := server.UpdateUser(ctx, &userv1.UpdateUserRequest{
+			Id:    created.Id,
+			Login: &wrapperspb.StringValue{Value: "renamed"},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if res.User.Login != "renamed" {
+			t.Errorf("expected login renamed, got %v", res.User.Login)
+		}
+	})
+
+	t.Run("failed sync keeps the update", func(t *testing.T) {
+		ctx := context.Background()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		account := mocks.NewMockAccountClient(ctrl)
+		server := New(WithAccountClient(account))
+
+		created, _ := server.CreateUser(ctx, &userv1.CreateUserRequest{
+			Login: "login",
+			Email: "email@test.com",
+		})
+
+		account.EXPECT().
+			SyncOwner(gomock.Any(), gomock.Any()).
+			Return(nil, status.Error(codes.Unavailable, "connection refused"))
+
+		_, err := server.UpdateUser(ctx, &userv1.UpdateUserRequest{
+			Id:    created.Id,
+			Email: &wrapperspb.StringValue{Value: "new@test.com"},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		got, _ := server.GetUser(ctx, &userv1.GetUserRequest{Id: created.Id})
+		if got.User.Email != "new@test.com" {
+			t.Errorf("expected email new@test.com, got %v", got.User.Email)
+		}
+	})
+
+	t.Run("no change, no sync", func(t *testing.T) {
+		ctx := context.Background()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		account := mocks.NewMockAccountClient(ctrl)
+		server := New(WithAccountClient(account))
+
+		created, _ := server.CreateUser(ctx, &userv1.CreateUserRequest{
+			Login: "login",
+			Email: "email@test.com",
+		})
+
+		account.EXPECT().SyncOwner(gomock.Any(), gomock.Any()).Times(0)
+
+		if _, err := server.UpdateUser(ctx, &userv1.UpdateUserRequest{Id: created.Id}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
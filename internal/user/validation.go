@@ -0,0 +1,68 @@
+package user
+
+import (
+	"net/mail"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	minLoginLen = 3
+	maxLoginLen = 32
+	maxEmailLen = 254
+)
+
+// validateLogin checks that login is 3-32 characters long, starts with a letter
+// and contains only letters, digits, '.', '_' and '-'.
+func validateLogin(login string) error {
+	if login == "" {
+		return status.Error(codes.InvalidArgument, "login must not be empty")
+	}
+	if len(login) < minLoginLen || len(login) > maxLoginLen {
+		return status.Errorf(codes.InvalidArgument, "login must be between %d and %d characters", minLoginLen, maxLoginLen)
+	}
+	if !isLetter(rune(login[0])) {
+		return status.Error(codes.InvalidArgument, "login must start with a letter")
+	}
+	for _, r := range login {
+		if !isLetter(r) && !isDigit(r) && r != '.' && r != '_' && r != '-' {
+			return status.Errorf(codes.InvalidArgument, "login contains invalid character %q", r)
+		}
+	}
+	return nil
+}
+
+// validateEmail checks that email is a bare RFC 5322 address (no display name)
+// with a non-empty local part and domain.
+func validateEmail(email string) error {
+	if email == "" {
+		return status.Error(codes.InvalidArgument, "email must not be empty")
+	}
+	if len(email) > maxEmailLen {
+		return status.Errorf(codes.InvalidArgument, "email must not be longer than %d characters", maxEmailLen)
+	}
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Name != "" || addr.Address != email {
+		return status.Errorf(codes.InvalidArgument, "invalid email %q", email)
+	}
+	at := strings.LastIndexByte(email, '@')
+	if at <= 0 || at == len(email)-1 {
+		return status.Errorf(codes.InvalidArgument, "invalid email %q", email)
+	}
+	return nil
+}
+
+// normalize returns the key used by the uniqueness indexes.
+func normalize(s string) string {
+	return strings.ToLower(s)
+}
+
+func isLetter(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z'
+}
+
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
@@ -0,0 +1,68 @@
+package user
+
+import (
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestValidateLogin(t *testing.T) {
+	tests := []struct {
+		name    string
+		login   string
+		wantErr bool
+	}{
+		{name: "simple", login: "alice", wantErr: false},
+		{name: "with digits and separators", login: "alice.b_c-1", wantErr: false},
+		{name: "empty", login: "", wantErr: true},
+		{name: "too short", login: "ab", wantErr: true},
+		{name: "too long", login: "a123456789012345678901234567890123", wantErr: true},
+		{name: "starts with digit", login: "1alice", wantErr: true},
+		{name: "contains space", login: "ali ce", wantErr: true},
+		{name: "contains at sign", login: "alice@home", wantErr: true},
+		{name: "non ascii", login: "алиса", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateLogin(tt.login)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error to be %v, got %v", tt.wantErr, err)
+			}
+			if err != nil && status.Code(err) != codes.InvalidArgument {
+				t.Errorf("expected %v, got %v", codes.InvalidArgument, status.Code(err))
+			}
+		})
+	}
+}
+
+func TestValidateEmail(t *testing.T) {
+	tests := []struct {
+		name    string
+		email   string
+		wantErr bool
+	}{
+		{name: "simple", email: "test@test.com", wantErr: false},
+		{name: "plus tag", email: "test+bank@mail.example.org", wantErr: false},
+		{name: "empty", email: "", wantErr: true},
+		{name: "no at sign", email: "abc", wantErr: true},
+		{name: "no domain", email: "abc@", wantErr: true},
+		{name: "no local part", email: "@test.com", wantErr: true},
+		{name: "display name", email: "Alice <alice@test.com>", wantErr: true},
+		{name: "surrounding spaces", email: " alice@test.com ", wantErr: true},
+		{name: "two at signs", email: "a@b@test.com", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateEmail(tt.email)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error to be %v, got %v", tt.wantErr, err)
+			}
+			if err != nil && status.Code(err) != codes.InvalidArgument {
+				t.Errorf("expected %v, got %v", codes.InvalidArgument, status.Code(err))
+			}
+		})
+	}
+}
@@ -0,0 +1,981 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: api/proto/account/v2/account_grpc.pb.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	v2 "github.com/galadeat/bank-sim/api/proto/account/v2"
+	gomock "github.com/golang/mock/gomock"
+	grpc "google.golang.org/grpc"
+)
+
+// MockAccountClient is a mock of AccountClient interface.
+type MockAccountClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockAccountClientMockRecorder
+}
+
+// MockAccountClientMockRecorder is the mock recorder for MockAccountClient.
+type MockAccountClientMockRecorder struct {
+	mock *MockAccountClient
+}
+
+// NewMockAccountClient creates a new mock instance.
+func NewMockAccountClient(ctrl *gomock.Controller) *MockAccountClient {
+	mock := &MockAccountClient{ctrl: ctrl}
+	mock.recorder = &MockAccountClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAccountClient) EXPECT() *MockAccountClientMockRecorder {
+	return m.recorder
+}
+
+// AuthorizeHold mocks base method.
+func (m *MockAccountClient) AuthorizeHold(ctx context.Context, in *v2.AuthorizeHoldRequest, opts ...grpc.CallOption) (*v2.AuthorizeHoldResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "AuthorizeHold", varargs...)
+	ret0, _ := ret[0].(*v2.AuthorizeHoldResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AuthorizeHold indicates an expected call of AuthorizeHold.
+func (mr *MockAccountClientMockRecorder) AuthorizeHold(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthorizeHold", reflect.TypeOf((*MockAccountClient)(nil).AuthorizeHold), varargs...)
+}
+
+// BatchCreateAccounts mocks base method.
+func (m *MockAccountClient) BatchCreateAccounts(ctx context.Context, in *v2.BatchCreateAccountsRequest, opts ...grpc.CallOption) (*v2.BatchCreateAccountsResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "BatchCreateAccounts", varargs...)
+	ret0, _ := ret[0].(*v2.BatchCreateAccountsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BatchCreateAccounts indicates an expected call of BatchCreateAccounts.
+func (mr *MockAccountClientMockRecorder) BatchCreateAccounts(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchCreateAccounts", reflect.TypeOf((*MockAccountClient)(nil).BatchCreateAccounts), varargs...)
+}
+
+// BulkPost mocks base method.
+func (m *MockAccountClient) BulkPost(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[v2.BulkPostRequest, v2.BulkPostResponse], error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "BulkPost", varargs...)
+	ret0, _ := ret[0].(grpc.ClientStreamingClient[v2.BulkPostRequest, v2.BulkPostResponse])
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BulkPost indicates an expected call of BulkPost.
+func (mr *MockAccountClientMockRecorder) BulkPost(ctx interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkPost", reflect.TypeOf((*MockAccountClient)(nil).BulkPost), varargs...)
+}
+
+// CaptureHold mocks base method.
+func (m *MockAccountClient) CaptureHold(ctx context.Context, in *v2.CaptureHoldRequest, opts ...grpc.CallOption) (*v2.CaptureHoldResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CaptureHold", varargs...)
+	ret0, _ := ret[0].(*v2.CaptureHoldResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CaptureHold indicates an expected call of CaptureHold.
+func (mr *MockAccountClientMockRecorder) CaptureHold(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CaptureHold", reflect.TypeOf((*MockAccountClient)(nil).CaptureHold), varargs...)
+}
+
+// CloseAccount mocks base method.
+func (m *MockAccountClient) CloseAccount(ctx context.Context, in *v2.CloseAccountRequest, opts ...grpc.CallOption) (*v2.CloseAccountResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CloseAccount", varargs...)
+	ret0, _ := ret[0].(*v2.CloseAccountResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CloseAccount indicates an expected call of CloseAccount.
+func (mr *MockAccountClientMockRecorder) CloseAccount(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseAccount", reflect.TypeOf((*MockAccountClient)(nil).CloseAccount), varargs...)
+}
+
+// CloseUserAccounts mocks base method.
+func (m *MockAccountClient) CloseUserAccounts(ctx context.Context, in *v2.CloseUserAccountsRequest, opts ...grpc.CallOption) (*v2.CloseUserAccountsResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CloseUserAccounts", varargs...)
+	ret0, _ := ret[0].(*v2.CloseUserAccountsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CloseUserAccounts indicates an expected call of CloseUserAccounts.
+func (mr *MockAccountClientMockRecorder) CloseUserAccounts(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseUserAccounts", reflect.TypeOf((*MockAccountClient)(nil).CloseUserAccounts), varargs...)
+}
+
+// CreateAccount mocks base method.
+func (m *MockAccountClient) CreateAccount(ctx context.Context, in *v2.CreateAccountRequest, opts ...grpc.CallOption) (*v2.CreateAccountResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CreateAccount", varargs...)
+	ret0, _ := ret[0].(*v2.CreateAccountResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAccount indicates an expected call of CreateAccount.
+func (mr *MockAccountClientMockRecorder) CreateAccount(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccount", reflect.TypeOf((*MockAccountClient)(nil).CreateAccount), varargs...)
+}
+
+// DeleteAccount mocks base method.
+func (m *MockAccountClient) DeleteAccount(ctx context.Context, in *v2.DeleteAccountRequest, opts ...grpc.CallOption) (*v2.DeleteAccountResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DeleteAccount", varargs...)
+	ret0, _ := ret[0].(*v2.DeleteAccountResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteAccount indicates an expected call of DeleteAccount.
+func (mr *MockAccountClientMockRecorder) DeleteAccount(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccount", reflect.TypeOf((*MockAccountClient)(nil).DeleteAccount), varargs...)
+}
+
+// Deposit mocks base method.
+func (m *MockAccountClient) Deposit(ctx context.Context, in *v2.DepositRequest, opts ...grpc.CallOption) (*v2.DepositResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Deposit", varargs...)
+	ret0, _ := ret[0].(*v2.DepositResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Deposit indicates an expected call of Deposit.
+func (mr *MockAccountClientMockRecorder) Deposit(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Deposit", reflect.TypeOf((*MockAccountClient)(nil).Deposit), varargs...)
+}
+
+// FreezeAccount mocks base method.
+func (m *MockAccountClient) FreezeAccount(ctx context.Context, in *v2.FreezeAccountRequest, opts ...grpc.CallOption) (*v2.FreezeAccountResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "FreezeAccount", varargs...)
+	ret0, _ := ret[0].(*v2.FreezeAccountResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FreezeAccount indicates an expected call of FreezeAccount.
+func (mr *MockAccountClientMockRecorder) FreezeAccount(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FreezeAccount", reflect.TypeOf((*MockAccountClient)(nil).FreezeAccount), varargs...)
+}
+
+// GetAccount mocks base method.
+func (m *MockAccountClient) GetAccount(ctx context.Context, in *v2.GetAccountRequest, opts ...grpc.CallOption) (*v2.GetAccountResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetAccount", varargs...)
+	ret0, _ := ret[0].(*v2.GetAccountResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccount indicates an expected call of GetAccount.
+func (mr *MockAccountClientMockRecorder) GetAccount(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccount", reflect.TypeOf((*MockAccountClient)(nil).GetAccount), varargs...)
+}
+
+// ListAccountEvents mocks base method.
+func (m *MockAccountClient) ListAccountEvents(ctx context.Context, in *v2.ListAccountEventsRequest, opts ...grpc.CallOption) (*v2.ListAccountEventsResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListAccountEvents", varargs...)
+	ret0, _ := ret[0].(*v2.ListAccountEventsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountEvents indicates an expected call of ListAccountEvents.
+func (mr *MockAccountClientMockRecorder) ListAccountEvents(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountEvents", reflect.TypeOf((*MockAccountClient)(nil).ListAccountEvents), varargs...)
+}
+
+// ListAccounts mocks base method.
+func (m *MockAccountClient) ListAccounts(ctx context.Context, in *v2.ListAccountsRequest, opts ...grpc.CallOption) (*v2.ListAccountsResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListAccounts", varargs...)
+	ret0, _ := ret[0].(*v2.ListAccountsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccounts indicates an expected call of ListAccounts.
+func (mr *MockAccountClientMockRecorder) ListAccounts(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccounts", reflect.TypeOf((*MockAccountClient)(nil).ListAccounts), varargs...)
+}
+
+// ListHolds mocks base method.
+func (m *MockAccountClient) ListHolds(ctx context.Context, in *v2.ListHoldsRequest, opts ...grpc.CallOption) (*v2.ListHoldsResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListHolds", varargs...)
+	ret0, _ := ret[0].(*v2.ListHoldsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListHolds indicates an expected call of ListHolds.
+func (mr *MockAccountClientMockRecorder) ListHolds(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListHolds", reflect.TypeOf((*MockAccountClient)(nil).ListHolds), varargs...)
+}
+
+// ListPendingReviews mocks base method.
+func (m *MockAccountClient) ListPendingReviews(ctx context.Context, in *v2.ListPendingReviewsRequest, opts ...grpc.CallOption) (*v2.ListPendingReviewsResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListPendingReviews", varargs...)
+	ret0, _ := ret[0].(*v2.ListPendingReviewsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPendingReviews indicates an expected call of ListPendingReviews.
+func (mr *MockAccountClientMockRecorder) ListPendingReviews(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPendingReviews", reflect.TypeOf((*MockAccountClient)(nil).ListPendingReviews), varargs...)
+}
+
+// ListTransactions mocks base method.
+func (m *MockAccountClient) ListTransactions(ctx context.Context, in *v2.ListTransactionsRequest, opts ...grpc.CallOption) (*v2.ListTransactionsResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListTransactions", varargs...)
+	ret0, _ := ret[0].(*v2.ListTransactionsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTransactions indicates an expected call of ListTransactions.
+func (mr *MockAccountClientMockRecorder) ListTransactions(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransactions", reflect.TypeOf((*MockAccountClient)(nil).ListTransactions), varargs...)
+}
+
+// ResolveReview mocks base method.
+func (m *MockAccountClient) ResolveReview(ctx context.Context, in *v2.ResolveReviewRequest, opts ...grpc.CallOption) (*v2.ResolveReviewResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ResolveReview", varargs...)
+	ret0, _ := ret[0].(*v2.ResolveReviewResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolveReview indicates an expected call of ResolveReview.
+func (mr *MockAccountClientMockRecorder) ResolveReview(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveReview", reflect.TypeOf((*MockAccountClient)(nil).ResolveReview), varargs...)
+}
+
+// RestoreAccount mocks base method.
+func (m *MockAccountClient) RestoreAccount(ctx context.Context, in *v2.RestoreAccountRequest, opts ...grpc.CallOption) (*v2.RestoreAccountResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RestoreAccount", varargs...)
+	ret0, _ := ret[0].(*v2.RestoreAccountResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreAccount indicates an expected call of RestoreAccount.
+func (mr *MockAccountClientMockRecorder) RestoreAccount(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreAccount", reflect.TypeOf((*MockAccountClient)(nil).RestoreAccount), varargs...)
+}
+
+// ReverseTransaction mocks base method.
+func (m *MockAccountClient) ReverseTransaction(ctx context.Context, in *v2.ReverseTransactionRequest, opts ...grpc.CallOption) (*v2.ReverseTransactionResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ReverseTransaction", varargs...)
+	ret0, _ := ret[0].(*v2.ReverseTransactionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReverseTransaction indicates an expected call of ReverseTransaction.
+func (mr *MockAccountClientMockRecorder) ReverseTransaction(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReverseTransaction", reflect.TypeOf((*MockAccountClient)(nil).ReverseTransaction), varargs...)
+}
+
+// SetOverdraftLimit mocks base method.
+func (m *MockAccountClient) SetOverdraftLimit(ctx context.Context, in *v2.SetOverdraftLimitRequest, opts ...grpc.CallOption) (*v2.SetOverdraftLimitResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "SetOverdraftLimit", varargs...)
+	ret0, _ := ret[0].(*v2.SetOverdraftLimitResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetOverdraftLimit indicates an expected call of SetOverdraftLimit.
+func (mr *MockAccountClientMockRecorder) SetOverdraftLimit(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetOverdraftLimit", reflect.TypeOf((*MockAccountClient)(nil).SetOverdraftLimit), varargs...)
+}
+
+// SyncOwner mocks base method.
+func (m *MockAccountClient) SyncOwner(ctx context.Context, in *v2.SyncOwnerRequest, opts ...grpc.CallOption) (*v2.SyncOwnerResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "SyncOwner", varargs...)
+	ret0, _ := ret[0].(*v2.SyncOwnerResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SyncOwner indicates an expected call of SyncOwner.
+func (mr *MockAccountClientMockRecorder) SyncOwner(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncOwner", reflect.TypeOf((*MockAccountClient)(nil).SyncOwner), varargs...)
+}
+
+// Transfer mocks base method.
+func (m *MockAccountClient) Transfer(ctx context.Context, in *v2.TransferRequest, opts ...grpc.CallOption) (*v2.TransferResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Transfer", varargs...)
+	ret0, _ := ret[0].(*v2.TransferResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Transfer indicates an expected call of Transfer.
+func (mr *MockAccountClientMockRecorder) Transfer(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Transfer", reflect.TypeOf((*MockAccountClient)(nil).Transfer), varargs...)
+}
+
+// UnfreezeAccount mocks base method.
+func (m *MockAccountClient) UnfreezeAccount(ctx context.Context, in *v2.UnfreezeAccountRequest, opts ...grpc.CallOption) (*v2.UnfreezeAccountResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UnfreezeAccount", varargs...)
+	ret0, _ := ret[0].(*v2.UnfreezeAccountResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnfreezeAccount indicates an expected call of UnfreezeAccount.
+func (mr *MockAccountClientMockRecorder) UnfreezeAccount(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnfreezeAccount", reflect.TypeOf((*MockAccountClient)(nil).UnfreezeAccount), varargs...)
+}
+
+// VoidHold mocks base method.
+func (m *MockAccountClient) VoidHold(ctx context.Context, in *v2.VoidHoldRequest, opts ...grpc.CallOption) (*v2.VoidHoldResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "VoidHold", varargs...)
+	ret0, _ := ret[0].(*v2.VoidHoldResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VoidHold indicates an expected call of VoidHold.
+func (mr *MockAccountClientMockRecorder) VoidHold(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VoidHold", reflect.TypeOf((*MockAccountClient)(nil).VoidHold), varargs...)
+}
+
+// Withdraw mocks base method.
+func (m *MockAccountClient) Withdraw(ctx context.Context, in *v2.WithdrawRequest, opts ...grpc.CallOption) (*v2.WithdrawResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Withdraw", varargs...)
+	ret0, _ := ret[0].(*v2.WithdrawResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Withdraw indicates an expected call of Withdraw.
+func (mr *MockAccountClientMockRecorder) Withdraw(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Withdraw", reflect.TypeOf((*MockAccountClient)(nil).Withdraw), varargs...)
+}
+
+// MockAccountServer is a mock of AccountServer interface.
+type MockAccountServer struct {
+	ctrl     *gomock.Controller
+	recorder *MockAccountServerMockRecorder
+}
+
+// MockAccountServerMockRecorder is the mock recorder for MockAccountServer.
+type MockAccountServerMockRecorder struct {
+	mock *MockAccountServer
+}
+
+// NewMockAccountServer creates a new mock instance.
+func NewMockAccountServer(ctrl *gomock.Controller) *MockAccountServer {
+	mock := &MockAccountServer{ctrl: ctrl}
+	mock.recorder = &MockAccountServerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAccountServer) EXPECT() *MockAccountServerMockRecorder {
+	return m.recorder
+}
+
+// AuthorizeHold mocks base method.
+func (m *MockAccountServer) AuthorizeHold(arg0 context.Context, arg1 *v2.AuthorizeHoldRequest) (*v2.AuthorizeHoldResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuthorizeHold", arg0, arg1)
+	ret0, _ := ret[0].(*v2.AuthorizeHoldResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AuthorizeHold indicates an expected call of AuthorizeHold.
+func (mr *MockAccountServerMockRecorder) AuthorizeHold(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthorizeHold", reflect.TypeOf((*MockAccountServer)(nil).AuthorizeHold), arg0, arg1)
+}
+
+// BatchCreateAccounts mocks base method.
+func (m *MockAccountServer) BatchCreateAccounts(arg0 context.Context, arg1 *v2.BatchCreateAccountsRequest) (*v2.BatchCreateAccountsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BatchCreateAccounts", arg0, arg1)
+	ret0, _ := ret[0].(*v2.BatchCreateAccountsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BatchCreateAccounts indicates an expected call of BatchCreateAccounts.
+func (mr *MockAccountServerMockRecorder) BatchCreateAccounts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchCreateAccounts", reflect.TypeOf((*MockAccountServer)(nil).BatchCreateAccounts), arg0, arg1)
+}
+
+// BulkPost mocks base method.
+func (m *MockAccountServer) BulkPost(arg0 grpc.ClientStreamingServer[v2.BulkPostRequest, v2.BulkPostResponse]) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BulkPost", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BulkPost indicates an expected call of BulkPost.
+func (mr *MockAccountServerMockRecorder) BulkPost(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkPost", reflect.TypeOf((*MockAccountServer)(nil).BulkPost), arg0)
+}
+
+// CaptureHold mocks base method.
+func (m *MockAccountServer) CaptureHold(arg0 context.Context, arg1 *v2.CaptureHoldRequest) (*v2.CaptureHoldResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CaptureHold", arg0, arg1)
+	ret0, _ := ret[0].(*v2.CaptureHoldResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CaptureHold indicates an expected call of CaptureHold.
+func (mr *MockAccountServerMockRecorder) CaptureHold(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CaptureHold", reflect.TypeOf((*MockAccountServer)(nil).CaptureHold), arg0, arg1)
+}
+
+// CloseAccount mocks base method.
+func (m *MockAccountServer) CloseAccount(arg0 context.Context, arg1 *v2.CloseAccountRequest) (*v2.CloseAccountResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseAccount", arg0, arg1)
+	ret0, _ := ret[0].(*v2.CloseAccountResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CloseAccount indicates an expected call of CloseAccount.
+func (mr *MockAccountServerMockRecorder) CloseAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseAccount", reflect.TypeOf((*MockAccountServer)(nil).CloseAccount), arg0, arg1)
+}
+
+// CloseUserAccounts mocks base method.
+func (m *MockAccountServer) CloseUserAccounts(arg0 context.Context, arg1 *v2.CloseUserAccountsRequest) (*v2.CloseUserAccountsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseUserAccounts", arg0, arg1)
+	ret0, _ := ret[0].(*v2.CloseUserAccountsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CloseUserAccounts indicates an expected call of CloseUserAccounts.
+func (mr *MockAccountServerMockRecorder) CloseUserAccounts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseUserAccounts", reflect.TypeOf((*MockAccountServer)(nil).CloseUserAccounts), arg0, arg1)
+}
+
+// CreateAccount mocks base method.
+func (m *MockAccountServer) CreateAccount(arg0 context.Context, arg1 *v2.CreateAccountRequest) (*v2.CreateAccountResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAccount", arg0, arg1)
+	ret0, _ := ret[0].(*v2.CreateAccountResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAccount indicates an expected call of CreateAccount.
+func (mr *MockAccountServerMockRecorder) CreateAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccount", reflect.TypeOf((*MockAccountServer)(nil).CreateAccount), arg0, arg1)
+}
+
+// DeleteAccount mocks base method.
+func (m *MockAccountServer) DeleteAccount(arg0 context.Context, arg1 *v2.DeleteAccountRequest) (*v2.DeleteAccountResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAccount", arg0, arg1)
+	ret0, _ := ret[0].(*v2.DeleteAccountResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteAccount indicates an expected call of DeleteAccount.
+func (mr *MockAccountServerMockRecorder) DeleteAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccount", reflect.TypeOf((*MockAccountServer)(nil).DeleteAccount), arg0, arg1)
+}
+
+// Deposit mocks base method.
+func (m *MockAccountServer) Deposit(arg0 context.Context, arg1 *v2.DepositRequest) (*v2.DepositResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Deposit", arg0, arg1)
+	ret0, _ := ret[0].(*v2.DepositResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Deposit indicates an expected call of Deposit.
+func (mr *MockAccountServerMockRecorder) Deposit(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Deposit", reflect.TypeOf((*MockAccountServer)(nil).Deposit), arg0, arg1)
+}
+
+// FreezeAccount mocks base method.
+func (m *MockAccountServer) FreezeAccount(arg0 context.Context, arg1 *v2.FreezeAccountRequest) (*v2.FreezeAccountResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FreezeAccount", arg0, arg1)
+	ret0, _ := ret[0].(*v2.FreezeAccountResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FreezeAccount indicates an expected call of FreezeAccount.
+func (mr *MockAccountServerMockRecorder) FreezeAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FreezeAccount", reflect.TypeOf((*MockAccountServer)(nil).FreezeAccount), arg0, arg1)
+}
+
+// GetAccount mocks base method.
+func (m *MockAccountServer) GetAccount(arg0 context.Context, arg1 *v2.GetAccountRequest) (*v2.GetAccountResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccount", arg0, arg1)
+	ret0, _ := ret[0].(*v2.GetAccountResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccount indicates an expected call of GetAccount.
+func (mr *MockAccountServerMockRecorder) GetAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccount", reflect.TypeOf((*MockAccountServer)(nil).GetAccount), arg0, arg1)
+}
+
+// ListAccountEvents mocks base method.
+func (m *MockAccountServer) ListAccountEvents(arg0 context.Context, arg1 *v2.ListAccountEventsRequest) (*v2.ListAccountEventsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccountEvents", arg0, arg1)
+	ret0, _ := ret[0].(*v2.ListAccountEventsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountEvents indicates an expected call of ListAccountEvents.
+func (mr *MockAccountServerMockRecorder) ListAccountEvents(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountEvents", reflect.TypeOf((*MockAccountServer)(nil).ListAccountEvents), arg0, arg1)
+}
+
+// ListAccounts mocks base method.
+func (m *MockAccountServer) ListAccounts(arg0 context.Context, arg1 *v2.ListAccountsRequest) (*v2.ListAccountsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccounts", arg0, arg1)
+	ret0, _ := ret[0].(*v2.ListAccountsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccounts indicates an expected call of ListAccounts.
+func (mr *MockAccountServerMockRecorder) ListAccounts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccounts", reflect.TypeOf((*MockAccountServer)(nil).ListAccounts), arg0, arg1)
+}
+
+// ListHolds mocks base method.
+func (m *MockAccountServer) ListHolds(arg0 context.Context, arg1 *v2.ListHoldsRequest) (*v2.ListHoldsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListHolds", arg0, arg1)
+	ret0, _ := ret[0].(*v2.ListHoldsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListHolds indicates an expected call of ListHolds.
+func (mr *MockAccountServerMockRecorder) ListHolds(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListHolds", reflect.TypeOf((*MockAccountServer)(nil).ListHolds), arg0, arg1)
+}
+
+// ListPendingReviews mocks base method.
+func (m *MockAccountServer) ListPendingReviews(arg0 context.Context, arg1 *v2.ListPendingReviewsRequest) (*v2.ListPendingReviewsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPendingReviews", arg0, arg1)
+	ret0, _ := ret[0].(*v2.ListPendingReviewsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPendingReviews indicates an expected call of ListPendingReviews.
+func (mr *MockAccountServerMockRecorder) ListPendingReviews(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPendingReviews", reflect.TypeOf((*MockAccountServer)(nil).ListPendingReviews), arg0, arg1)
+}
+
+// ListTransactions mocks base method.
+func (m *MockAccountServer) ListTransactions(arg0 context.Context, arg1 *v2.ListTransactionsRequest) (*v2.ListTransactionsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTransactions", arg0, arg1)
+	ret0, _ := ret[0].(*v2.ListTransactionsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTransactions indicates an expected call of ListTransactions.
+func (mr *MockAccountServerMockRecorder) ListTransactions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransactions", reflect.TypeOf((*MockAccountServer)(nil).ListTransactions), arg0, arg1)
+}
+
+// ResolveReview mocks base method.
+func (m *MockAccountServer) ResolveReview(arg0 context.Context, arg1 *v2.ResolveReviewRequest) (*v2.ResolveReviewResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveReview", arg0, arg1)
+	ret0, _ := ret[0].(*v2.ResolveReviewResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolveReview indicates an expected call of ResolveReview.
+func (mr *MockAccountServerMockRecorder) ResolveReview(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveReview", reflect.TypeOf((*MockAccountServer)(nil).ResolveReview), arg0, arg1)
+}
+
+// RestoreAccount mocks base method.
+func (m *MockAccountServer) RestoreAccount(arg0 context.Context, arg1 *v2.RestoreAccountRequest) (*v2.RestoreAccountResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreAccount", arg0, arg1)
+	ret0, _ := ret[0].(*v2.RestoreAccountResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreAccount indicates an expected call of RestoreAccount.
+func (mr *MockAccountServerMockRecorder) RestoreAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreAccount", reflect.TypeOf((*MockAccountServer)(nil).RestoreAccount), arg0, arg1)
+}
+
+// ReverseTransaction mocks base method.
+func (m *MockAccountServer) ReverseTransaction(arg0 context.Context, arg1 *v2.ReverseTransactionRequest) (*v2.ReverseTransactionResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReverseTransaction", arg0, arg1)
+	ret0, _ := ret[0].(*v2.ReverseTransactionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReverseTransaction indicates an expected call of ReverseTransaction.
+func (mr *MockAccountServerMockRecorder) ReverseTransaction(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReverseTransaction", reflect.TypeOf((*MockAccountServer)(nil).ReverseTransaction), arg0, arg1)
+}
+
+// SetOverdraftLimit mocks base method.
+func (m *MockAccountServer) SetOverdraftLimit(arg0 context.Context, arg1 *v2.SetOverdraftLimitRequest) (*v2.SetOverdraftLimitResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetOverdraftLimit", arg0, arg1)
+	ret0, _ := ret[0].(*v2.SetOverdraftLimitResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetOverdraftLimit indicates an expected call of SetOverdraftLimit.
+func (mr *MockAccountServerMockRecorder) SetOverdraftLimit(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetOverdraftLimit", reflect.TypeOf((*MockAccountServer)(nil).SetOverdraftLimit), arg0, arg1)
+}
+
+// SyncOwner mocks base method.
+func (m *MockAccountServer) SyncOwner(arg0 context.Context, arg1 *v2.SyncOwnerRequest) (*v2.SyncOwnerResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SyncOwner", arg0, arg1)
+	ret0, _ := ret[0].(*v2.SyncOwnerResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SyncOwner indicates an expected call of SyncOwner.
+func (mr *MockAccountServerMockRecorder) SyncOwner(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncOwner", reflect.TypeOf((*MockAccountServer)(nil).SyncOwner), arg0, arg1)
+}
+
+// Transfer mocks base method.
+func (m *MockAccountServer) Transfer(arg0 context.Context, arg1 *v2.TransferRequest) (*v2.TransferResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Transfer", arg0, arg1)
+	ret0, _ := ret[0].(*v2.TransferResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Transfer indicates an expected call of Transfer.
+func (mr *MockAccountServerMockRecorder) Transfer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Transfer", reflect.TypeOf((*MockAccountServer)(nil).Transfer), arg0, arg1)
+}
+
+// UnfreezeAccount mocks base method.
+func (m *MockAccountServer) UnfreezeAccount(arg0 context.Context, arg1 *v2.UnfreezeAccountRequest) (*v2.UnfreezeAccountResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnfreezeAccount", arg0, arg1)
+	ret0, _ := ret[0].(*v2.UnfreezeAccountResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnfreezeAccount indicates an expected call of UnfreezeAccount.
+func (mr *MockAccountServerMockRecorder) UnfreezeAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnfreezeAccount", reflect.TypeOf((*MockAccountServer)(nil).UnfreezeAccount), arg0, arg1)
+}
+
+// VoidHold mocks base method.
+func (m *MockAccountServer) VoidHold(arg0 context.Context, arg1 *v2.VoidHoldRequest) (*v2.VoidHoldResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VoidHold", arg0, arg1)
+	ret0, _ := ret[0].(*v2.VoidHoldResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VoidHold indicates an expected call of VoidHold.
+func (mr *MockAccountServerMockRecorder) VoidHold(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VoidHold", reflect.TypeOf((*MockAccountServer)(nil).VoidHold), arg0, arg1)
+}
+
+// Withdraw mocks base method.
+func (m *MockAccountServer) Withdraw(arg0 context.Context, arg1 *v2.WithdrawRequest) (*v2.WithdrawResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Withdraw", arg0, arg1)
+	ret0, _ := ret[0].(*v2.WithdrawResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Withdraw indicates an expected call of Withdraw.
+func (mr *MockAccountServerMockRecorder) Withdraw(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Withdraw", reflect.TypeOf((*MockAccountServer)(nil).Withdraw), arg0, arg1)
+}
+
+// mustEmbedUnimplementedAccountServer mocks base method.
+func (m *MockAccountServer) mustEmbedUnimplementedAccountServer() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "mustEmbedUnimplementedAccountServer")
+}
+
+// mustEmbedUnimplementedAccountServer indicates an expected call of mustEmbedUnimplementedAccountServer.
+func (mr *MockAccountServerMockRecorder) mustEmbedUnimplementedAccountServer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "mustEmbedUnimplementedAccountServer", reflect.TypeOf((*MockAccountServer)(nil).mustEmbedUnimplementedAccountServer))
+}
+
+// MockUnsafeAccountServer is a mock of UnsafeAccountServer interface.
+type MockUnsafeAccountServer struct {
+	ctrl     *gomock.Controller
+	recorder *MockUnsafeAccountServerMockRecorder
+}
+
+// MockUnsafeAccountServerMockRecorder is the mock recorder for MockUnsafeAccountServer.
+type MockUnsafeAccountServerMockRecorder struct {
+	mock *MockUnsafeAccountServer
+}
+
+// NewMockUnsafeAccountServer creates a new mock instance.
+func NewMockUnsafeAccountServer(ctrl *gomock.Controller) *MockUnsafeAccountServer {
+	mock := &MockUnsafeAccountServer{ctrl: ctrl}
+	mock.recorder = &MockUnsafeAccountServerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUnsafeAccountServer) EXPECT() *MockUnsafeAccountServerMockRecorder {
+	return m.recorder
+}
+
+// mustEmbedUnimplementedAccountServer mocks base method.
+func (m *MockUnsafeAccountServer) mustEmbedUnimplementedAccountServer() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "mustEmbedUnimplementedAccountServer")
+}
+
+// mustEmbedUnimplementedAccountServer indicates an expected call of mustEmbedUnimplementedAccountServer.
+func (mr *MockUnsafeAccountServerMockRecorder) mustEmbedUnimplementedAccountServer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "mustEmbedUnimplementedAccountServer", reflect.TypeOf((*MockUnsafeAccountServer)(nil).mustEmbedUnimplementedAccountServer))
+}
@@ -0,0 +1,327 @@
+package clients
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+
+	accountv2 "github.com/galadeat/bank-sim/api/proto/account/v2"
+	commonv1 "github.com/galadeat/bank-sim/api/proto/common/v1"
+	userv1 "github.com/galadeat/bank-sim/api/proto/user/v1"
+	"github.com/galadeat/bank-sim/pkg/idgen"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+const (
+	defaultTimeout  = 10 * time.Second
+	defaultAttempts = 4
+	defaultBackoff  = 100 * time.Millisecond
+	maxBackoff      = 2 * time.Second
+)
+
+// Bank is a typed client of the user and account services. Calls without a
+// deadline get the default one, money movements carry a new request id, and
+// calls that are safe to repeat are retried with backoff while the service is
+// unavailable. Failures are *Error or *ReviewError values.
+type Bank struct {
+	users    userv1.UserClient
+	accounts accountv2.AccountClient
+	ids      idgen.IDGenerator
+	timeout  time.Duration
+	attempts int
+	backoff  time.Duration
+}
+
+// BankOption configures NewBank.
+type BankOption func(*Bank)
+
+// WithTimeout sets the deadline of calls whose context has none. It covers
+// the retries of the call.
+func WithTimeout(d time.Duration) BankOption {
+	return func(b *Bank) {
+		b.timeout = d
+	}
+}
+
+// WithRetry sets how many times a call is attempted in total and the delay
+// before the first retry, which doubles with every further one.
+func WithRetry(attempts int, backoff time.Duration) BankOption {
+	return func(b *Bank) {
+		b.attempts = max(attempts, 1)
+		b.backoff = backoff
+	}
+}
+
+// WithRequestIDs sets the generator of request ids.
+func WithRequestIDs(ids idgen.IDGenerator) BankOption {
+	return func(b *Bank) {
+		b.ids = ids
+	}
+}
+
+// NewBank is the constructor
+func NewBank(users userv1.UserClient, accounts accountv2.AccountClient, opts ...BankOption) *Bank {
+	b := &Bank{
+		users:    users,
+		accounts: accounts,
+		ids:      idgen.Random(),
+		timeout:  defaultTimeout,
+		attempts: defaultAttempts,
+		backoff:  defaultBackoff,
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+// Bank returns a typed client on the connections of c.
+func (c *Clients) Bank(opts ...BankOption) *Bank {
+	return NewBank(c.User, c.Account, opts...)
+}
+
+// call runs fn under the default deadline. If retry is set, fn is repeated
+// while it fails with Unavailable; it must send the same request each time.
+func (b *Bank) call(ctx context.Context, op string, retry bool, fn func(context.Context) error) error {
+	if _, ok := ctx.Deadline(); !ok && b.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, b.timeout)
+		defer cancel()
+	}
+
+	delay := b.backoff
+	for attempt := 1; ; attempt++ {
+		err := fn(ctx)
+		if err == nil {
+			return nil
+		}
+		if !retry || attempt >= b.attempts || status.Code(err) != codes.Unavailable {
+			return wrap(op, err)
+		}
+		t := time.NewTimer(jitter(delay))
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return wrap(op, err)
+		case <-t.C:
+		}
+		delay = min(delay*2, maxBackoff)
+	}
+}
+
+// jitter returns a random delay between d/2 and d, so that clients that failed
+// together do not retry together.
+func jitter(d time.Duration) time.Duration {
+	if d <= 1 {
+		return d
+	}
+	return d/2 + rand.N(d/2)
+}
+
+// CreateUser creates a user and returns its id.
+func (b *Bank) CreateUser(ctx context.Context, login, email string) (string, error) {
+	req := &userv1.CreateUserRequest{Login: login, Email: email, RequestId: b.ids.NewID()}
+	var resp *userv1.CreateUserResponse
+	err := b.call(ctx, "CreateUser", true, func(ctx context.Context) (err error) {
+		resp, err = b.users.CreateUser(ctx, req)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return resp.Id, nil
+}
+
+// GetUser returns the user with the given id.
+func (b *Bank) GetUser(ctx context.Context, id string) (*userv1.UserInfo, error) {
+	var resp *userv1.GetUserResponse
+	err := b.call(ctx, "GetUser", true, func(ctx context.Context) (err error) {
+		resp, err = b.users.GetUser(ctx, &userv1.GetUserRequest{Id: id})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.User, nil
+}
+
+// ListUsers returns every user.
+func (b *Bank) ListUsers(ctx context.Context) ([]*userv1.UserInfo, error) {
+	var resp *userv1.ListUsersResponse
+	err := b.call(ctx, "ListUsers", true, func(ctx context.Context) (err error) {
+		resp, err = b.users.ListUsers(ctx, &userv1.ListUsersRequest{})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Users, nil
+}
+
+// UpdateUser changes the login and email of a user; empty values are left as
+// they are.
+func (b *Bank) UpdateUser(ctx context.Context, id, login, email string) (*userv1.UserInfo, error) {
+	req := &userv1.UpdateUserRequest{Id: id}
+	if login != "" {
+		req.Login = wrapperspb.String(login)
+	}
+	if email != "" {
+		req.Email = wrapperspb.String(email)
+	}
+	var resp *userv1.UpdateUserResponse
+	err := b.call(ctx, "UpdateUser", true, func(ctx context.Context) (err error) {
+		resp, err = b.users.UpdateUser(ctx, req)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.User, nil
+}
+
+// DeleteUser deletes a user. It is not retried: a repeated delete would fail
+// with NotFound after the first one succeeded.
+func (b *Bank) DeleteUser(ctx context.Context, id string) error {
+	return b.call(ctx, "DeleteUser", false, func(ctx context.Context) error {
+		_, err := b.users.DeleteUser(ctx, &userv1.DeleteUserRequest{Id: id})
+		return err
+	})
+}
+
+// CreateAccount opens an account for a user. initial may be nil.
+func (b *Bank) CreateAccount(ctx context.Context, userID string, typ accountv2.AccountType, initial *commonv1.Money) (*accountv2.AccountInfo, error) {
+	return b.createAccount(ctx, &accountv2.CreateAccountRequest{UserId: userID, Type: typ, InitialBalance: initial})
+}
+
+// CreateTermDeposit opens a term deposit that matures after months.
+func (b *Bank) CreateTermDeposit(ctx context.Context, userID string, months int32, initial *commonv1.Money) (*accountv2.AccountInfo, error) {
+	return b.createAccount(ctx, &accountv2.CreateAccountRequest{
+		UserId: userID, Type: accountv2.AccountType_ACCOUNT_TYPE_TERM_DEPOSIT, TermMonths: months, InitialBalance: initial,
+	})
+}
+
+func (b *Bank) createAccount(ctx context.Context, req *accountv2.CreateAccountRequest) (*accountv2.AccountInfo, error) {
+	req.RequestId = b.ids.NewID()
+	var resp *accountv2.CreateAccountResponse
+	err := b.call(ctx, "CreateAccount", true, func(ctx context.Context) (err error) {
+		resp, err = b.accounts.CreateAccount(ctx, req)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Account, nil
+}
+
+// GetAccount returns the account with the given id.
+func (b *Bank) GetAccount(ctx context.Context, id string) (*accountv2.AccountInfo, error) {
+	var resp *accountv2.GetAccountResponse
+	err := b.call(ctx, "GetAccount", true, func(ctx context.Context) (err error) {
+		resp, err = b.accounts.GetAccount(ctx, &accountv2.GetAccountRequest{Id: id})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Account, nil
+}
+
+// ListAccounts returns the open accounts of a user.
+func (b *Bank) ListAccounts(ctx context.Context, userID string) ([]*accountv2.AccountInfo, error) {
+	var resp *accountv2.ListAccountsResponse
+	err := b.call(ctx, "ListAccounts", true, func(ctx context.Context) (err error) {
+		resp, err = b.accounts.ListAccounts(ctx, &accountv2.ListAccountsRequest{UserId: userID})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Accounts, nil
+}
+
+// DeleteAccount deletes an account. Like DeleteUser it is not retried.
+func (b *Bank) DeleteAccount(ctx context.Context, id string) error {
+	return b.call(ctx, "DeleteAccount", false, func(ctx context.Context) error {
+		_, err := b.accounts.DeleteAccount(ctx, &accountv2.DeleteAccountRequest{AccountId: id})
+		return err
+	})
+}
+
+// Deposit adds amount to an account and returns the account afterwards.
+func (b *Bank) Deposit(ctx context.Context, accountID string, amount *commonv1.Money) (*accountv2.AccountInfo, error) {
+	req := &accountv2.DepositRequest{AccountId: accountID, Amount: amount, RequestId: b.ids.NewID()}
+	var resp *accountv2.DepositResponse
+	err := b.call(ctx, "Deposit", true, func(ctx context.Context) (err error) {
+		resp, err = b.accounts.Deposit(ctx, req)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Account, nil
+}
+
+// Withdraw takes amount from an account and returns the account afterwards.
+// A withdrawal held for review fails with a *ReviewError.
+func (b *Bank) Withdraw(ctx context.Context, accountID string, amount *commonv1.Money) (*accountv2.AccountInfo, error) {
+	req := &accountv2.WithdrawRequest{AccountId: accountID, Amount: amount, RequestId: b.ids.NewID()}
+	var resp *accountv2.WithdrawResponse
+	err := b.call(ctx, "Withdraw", true, func(ctx context.Context) (err error) {
+		resp, err = b.accounts.Withdraw(ctx, req)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resp.Review != nil {
+		return nil, &ReviewError{Op: "Withdraw", Review: resp.Review}
+	}
+	return resp.Account, nil
+}
+
+// Transfer moves amount between two accounts and returns the debit and credit
+// entries. A transfer held for review fails with a *ReviewError.
+func (b *Bank) Transfer(ctx context.Context, fromID, toID string, amount *commonv1.Money) (debit, credit *accountv2.Transaction, err error) {
+	req := &accountv2.TransferRequest{FromAccountId: fromID, ToAccountId: toID, Amount: amount, RequestId: b.ids.NewID()}
+	var resp *accountv2.TransferResponse
+	err = b.call(ctx, "Transfer", true, func(ctx context.Context) (err error) {
+		resp, err = b.accounts.Transfer(ctx, req)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if resp.Review != nil {
+		return nil, nil, &ReviewError{Op: "Transfer", Review: resp.Review}
+	}
+	return resp.Debit, resp.Credit, nil
+}
+
+// Transactions returns the entries of an account, oldest first.
+func (b *Bank) Transactions(ctx context.Context, accountID string) ([]*accountv2.Transaction, error) {
+	var resp *accountv2.ListTransactionsResponse
+	err := b.call(ctx, "Transactions", true, func(ctx context.Context) (err error) {
+		resp, err = b.accounts.ListTransactions(ctx, &accountv2.ListTransactionsRequest{AccountId: accountID})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Transactions, nil
+}
+
+// CloseAccount closes an account, recording reason in its history. It is not
+// retried, as closing a closed account fails.
+func (b *Bank) CloseAccount(ctx context.Context, accountID, reason string) (*accountv2.AccountInfo, error) {
+	var resp *accountv2.CloseAccountResponse
+	err := b.call(ctx, "CloseAccount", false, func(ctx context.Context) (err error) {
+		resp, err = b.accounts.CloseAccount(ctx, &accountv2.CloseAccountRequest{AccountId: accountID, Reason: reason})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Account, nil
+}
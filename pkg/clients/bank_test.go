@@ -0,0 +1,188 @@
+package clients
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	accountv2 "github.com/galadeat/bank-sim/api/proto/account/v2"
+	commonv1 "github.com/galadeat/bank-sim/api/proto/common/v1"
+	userv1 "github.com/galadeat/bank-sim/api/proto/user/v1"
+	"github.com/galadeat/bank-sim/internal/rules"
+	"github.com/galadeat/bank-sim/mocks"
+	"github.com/galadeat/bank-sim/pkg/idgen"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func newTestBank(t *testing.T, opts ...BankOption) (*Bank, *mocks.MockUserClient, *mocks.MockAccountClient) {
+	ctrl := gomock.NewController(t)
+	users := mocks.NewMockUserClient(ctrl)
+	accounts := mocks.NewMockAccountClient(ctrl)
+	opts = append([]BankOption{WithRetry(3, time.Millisecond), WithRequestIDs(idgen.NewSeeded(1))}, opts...)
+	return NewBank(users, accounts, opts...), users, accounts
+}
+
+func usd(units int64) *commonv1.Money {
+	return &commonv1.Money{Currency: "USD", Units: units}
+}
+
+func withReason(code codes.Code, msg, reason string) error {
+	return withInfo(code, msg, "", reason)
+}
+
+func withInfo(code codes.Code, msg, domain, reason string) error {
+	st, err := status.New(code, msg).WithDetails(&errdetails.ErrorInfo{Domain: domain, Reason: reason, Metadata: map[string]string{"limit": "500"}})
+	if err != nil {
+		panic(err)
+	}
+	return st.Err()
+}
+
+func TestDepositRetriesWithTheSameRequestID(t *testing.T) {
+	bank, _, accounts := newTestBank(t)
+	var ids []string
+	accounts.EXPECT().Deposit(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, req *accountv2.DepositRequest, _ ...grpc.CallOption) (*accountv2.DepositResponse, error) {
+			_, ok := ctx.Deadline()
+			assert.True(t, ok, "calls get a default deadline")
+			ids = append(ids, req.RequestId)
+			if len(ids) < 3 {
+				return nil, status.Error(codes.Unavailable, "connection refused")
+			}
+			return &accountv2.DepositResponse{Account: &accountv2.AccountInfo{Id: req.AccountId, Balance: req.Amount}}, nil
+		}).Times(3)
+
+	acc, err := bank.Deposit(context.Background(), "acc-1", usd(10))
+	require.NoError(t, err)
+	assert.Equal(t, "acc-1", acc.Id)
+	require.Len(t, ids, 3)
+	assert.NotEmpty(t, ids[0])
+	assert.Equal(t, ids[0], ids[1])
+	assert.Equal(t, ids[0], ids[2])
+}
+
+func TestRetriesGiveUp(t *testing.T) {
+	bank, users, _ := newTestBank(t)
+	users.EXPECT().ListUsers(gomock.Any(), gomock.Any()).Return(nil, status.Error(codes.Unavailable, "connection refused")).Times(3)
+
+	_, err := bank.ListUsers(context.Background())
+	assert.ErrorIs(t, err, ErrUnavailable)
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	assert.EqualError(t, err, "ListUsers: connection refused")
+}
+
+func TestNoRetry(t *testing.T) {
+	t.Run("not idempotent", func(t *testing.T) {
+		bank, users, _ := newTestBank(t)
+		users.EXPECT().DeleteUser(gomock.Any(), gomock.Any()).Return(nil, status.Error(codes.Unavailable, "connection refused"))
+		assert.ErrorIs(t, bank.DeleteUser(context.Background(), "user-1"), ErrUnavailable)
+	})
+
+	t.Run("other codes", func(t *testing.T) {
+		bank, _, accounts := newTestBank(t)
+		accounts.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Return(nil, status.Error(codes.NotFound, "account not found"))
+		_, err := bank.GetAccount(context.Background(), "acc-1")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("deadline", func(t *testing.T) {
+		bank, _, accounts := newTestBank(t, WithRetry(10, time.Hour))
+		accounts.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Return(nil, status.Error(codes.Unavailable, "connection refused"))
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		_, err := bank.GetAccount(ctx, "acc-1")
+		assert.ErrorIs(t, err, ErrUnavailable)
+	})
+}
+
+func TestTypedErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		kind   error
+		reason string
+	}{
+		{name: "insufficient funds", err: withReason(codes.FailedPrecondition, "insufficient balance", "INSUFFICIENT_FUNDS"), kind: ErrInsufficientFunds, reason: "INSUFFICIENT_FUNDS"},
+		{name: "closed", err: status.Error(codes.FailedPrecondition, "account is closed"), kind: ErrFailedPrecondition},
+		{name: "limit", err: withReason(codes.ResourceExhausted, "daily withdrawal limit exceeded", "DAILY_LIMIT_EXCEEDED"), kind: ErrLimitExceeded, reason: "DAILY_LIMIT_EXCEEDED"},
+		{name: "amount limit", err: withInfo(codes.FailedPrecondition, "withdrawal exceeds the maximum of 500 per transaction", "rules.bank-sim", "AMOUNT_LIMIT_EXCEEDED"), kind: ErrLimitExceeded, reason: "AMOUNT_LIMIT_EXCEEDED"},
+		{name: "declined", err: status.Error(codes.PermissionDenied, "payment declined by risk checks"), kind: ErrDeclined},
+		{name: "invalid", err: status.Error(codes.InvalidArgument, "currency mismatch"), kind: ErrInvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bank, _, accounts := newTestBank(t)
+			accounts.EXPECT().Withdraw(gomock.Any(), gomock.Any()).Return(nil, tt.err)
+
+			_, err := bank.Withdraw(context.Background(), "acc-1", usd(10))
+			assert.ErrorIs(t, err, tt.kind)
+			var e *Error
+			require.ErrorAs(t, err, &e)
+			assert.Equal(t, "Withdraw", e.Op)
+			assert.Equal(t, tt.reason, e.Reason)
+			assert.Equal(t, status.Code(tt.err), e.Code())
+		})
+	}
+
+	t.Run("insufficient funds is a failed precondition", func(t *testing.T) {
+		err := wrap("Withdraw", withReason(codes.FailedPrecondition, "insufficient balance", "INSUFFICIENT_FUNDS"))
+		assert.ErrorIs(t, err, ErrFailedPrecondition)
+		assert.NotErrorIs(t, wrap("Withdraw", status.Error(codes.FailedPrecondition, "account is closed")), ErrInsufficientFunds)
+	})
+
+	t.Run("every limit of the rules is a limit", func(t *testing.T) {
+		// the domain is copied, so that clients do not import the service
+		assert.Equal(t, rules.Domain, domainRules)
+		for _, code := range []codes.Code{codes.FailedPrecondition, codes.ResourceExhausted} {
+			err := wrap("Withdraw", withInfo(code, "limit exceeded", rules.Domain, rules.ReasonMonthlyLimit))
+			assert.ErrorIs(t, err, ErrLimitExceeded)
+			assert.NotErrorIs(t, err, ErrFailedPrecondition)
+		}
+	})
+}
+
+func TestHeldForReview(t *testing.T) {
+	bank, _, accounts := newTestBank(t)
+	accounts.EXPECT().Transfer(gomock.Any(), gomock.Any()).Return(&accountv2.TransferResponse{Review: &accountv2.Review{Id: "rev-1", Score: 60}}, nil)
+
+	_, _, err := bank.Transfer(context.Background(), "acc-1", "acc-2", usd(10))
+	assert.ErrorIs(t, err, ErrHeldForReview)
+	var review *ReviewError
+	require.True(t, errors.As(err, &review))
+	assert.Equal(t, "rev-1", review.Review.Id)
+	assert.EqualError(t, err, "Transfer: held for review rev-1 (score 60)")
+}
+
+func TestCreateUser(t *testing.T) {
+	bank, users, _ := newTestBank(t)
+	users.EXPECT().CreateUser(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, req *userv1.CreateUserRequest, _ ...grpc.CallOption) (*userv1.CreateUserResponse, error) {
+			assert.Equal(t, "alice", req.Login)
+			assert.NotEmpty(t, req.RequestId)
+			return &userv1.CreateUserResponse{Id: "user-1"}, nil
+		})
+
+	id, err := bank.CreateUser(context.Background(), "alice", "alice@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", id)
+}
+
+func TestUpdateUserSkipsEmptyFields(t *testing.T) {
+	bank, users, _ := newTestBank(t)
+	users.EXPECT().UpdateUser(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, req *userv1.UpdateUserRequest, _ ...grpc.CallOption) (*userv1.UpdateUserResponse, error) {
+			assert.Nil(t, req.Login)
+			assert.Equal(t, "new@example.com", req.Email.GetValue())
+			return &userv1.UpdateUserResponse{User: &userv1.UserInfo{Id: req.Id}}, nil
+		})
+
+	_, err := bank.UpdateUser(context.Background(), "user-1", "", "new@example.com")
+	require.NoError(t, err)
+}
@@ -1,46 +1,170 @@
 package clients
 
 import (
+	"strings"
+
 	accountv2 "github.com/galadeat/bank-sim/api/proto/account/v2"
+	loanv1 "github.com/galadeat/bank-sim/api/proto/loan/v1"
+	reportingv1 "github.com/galadeat/bank-sim/api/proto/reporting/v1"
+	schedulerv1 "github.com/galadeat/bank-sim/api/proto/scheduler/v1"
 	userv1 "github.com/galadeat/bank-sim/api/proto/user/v1"
+	"github.com/galadeat/bank-sim/pkg/discovery"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/resolver"
 )
 
 const (
-	accServiceAddr = "localhost:50051"
-	usrServiceAddr = "localhost:50052"
+	accServiceAddr       = "localhost:50051"
+	usrServiceAddr       = "localhost:50052"
+	loanServiceAddr      = "localhost:50053"
+	schedulerServiceAddr = "localhost:50054"
+	reportingServiceAddr = "localhost:50055"
 )
 
 type Clients struct {
-	userConn    *grpc.ClientConn
-	accountConn *grpc.ClientConn
+	userConn      *grpc.ClientConn
+	accountConn   *grpc.ClientConn
+	loanConn      *grpc.ClientConn
+	schedulerConn *grpc.ClientConn
+	reportingConn *grpc.ClientConn
+
+	User           userv1.UserClient
+	Account        accountv2.AccountClient
+	Loan           loanv1.LoanClient
+	StandingOrders schedulerv1.StandingOrdersClient
+	Reporting      reportingv1.ReportingClient
+}
+
+// Service names accepted by WithTarget, also the keys of registry files.
+const (
+	ServiceAccount   = "account"
+	ServiceUser      = "user"
+	ServiceLoan      = "loan"
+	ServiceScheduler = "scheduler"
+	ServiceReporting = "reporting"
+)
+
+// Option configures New.
+type Option func(*options)
+
+type options struct {
+	host      string
+	targets   map[string]string
+	policy    discovery.Policy
+	resolvers []resolver.Builder
+	stateless map[string]bool
+}
+
+// WithHost connects to the services on host instead of localhost, on their
+// usual ports.
+func WithHost(host string) Option {
+	return func(o *options) {
+		o.host = host
+	}
+}
+
+// WithTarget dials service at a gRPC target instead of its usual address, to
+// spread calls over replicas: "static:///10.0.0.1:50051,10.0.0.2:50051",
+// "dns:///accounts.bank.internal:50051" or, with WithRegistry,
+// "registry:///account".
+func WithTarget(service, target string) Option {
+	return func(o *options) {
+		o.targets[service] = target
+	}
+}
+
+// WithBalancing sets how calls are spread over the replicas of a stateless
+// service, see WithStateless. The default is discovery.RoundRobin.
+func WithBalancing(policy discovery.Policy) Option {
+	return func(o *options) {
+		o.policy = policy
+	}
+}
+
+// WithStateless spreads the calls of service over its replicas. Without it every
+// call of a service goes to one of its healthy replicas, the same for every
+// client (see discovery.WithAffinity): the services keep their data in the
+// memory of each replica, so an account opened on one replica is unknown to the
+// others. The other replicas take over only while that one is not serving. Name
+// only services whose replicas share their state.
+func WithStateless(service string) Option {
+	return func(o *options) {
+		o.stateless[service] = true
+	}
+}
 
-	User    userv1.UserClient
-	Account accountv2.AccountClient
+// WithRegistry resolves "registry:///<service>" targets from a registry file,
+// see discovery.FileRegistry.
+func WithRegistry(path string, opts ...discovery.RegistryOption) Option {
+	return func(o *options) {
+		o.resolvers = append(o.resolvers, discovery.NewFileRegistry(path, opts...))
+	}
 }
 
-func New() (*Clients, error) {
+func New(opts ...Option) (*Clients, error) {
+	o := options{host: "localhost", targets: make(map[string]string), policy: discovery.RoundRobin, stateless: make(map[string]bool)}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	// dial connects to service. Replicas whose health service reports
+	// healthService as not serving are left out until they recover; only the
+	// account service has one. Calls of services that are not stateless carry
+	// the service name as their affinity key.
+	dial := func(service, local, healthService string) (*grpc.ClientConn, error) {
+		target, ok := o.targets[service]
+		if !ok {
+			target = strings.Replace(local, "localhost", o.host, 1)
+		}
+		dialOpts := []grpc.DialOption{
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+			grpc.WithResolvers(o.resolvers...),
+			grpc.WithDefaultServiceConfig(discovery.ServiceConfig(o.policy, healthService)),
+		}
+		if !o.stateless[service] {
+			dialOpts = append(dialOpts,
+				grpc.WithChainUnaryInterceptor(discovery.UnaryAffinity(service)),
+				grpc.WithChainStreamInterceptor(discovery.StreamAffinity(service)))
+		}
+		return grpc.NewClient(target, dialOpts...)
+	}
+
+	userConn, err := dial(ServiceUser, usrServiceAddr, "")
+	if err != nil {
+		return nil, err
+	}
+
+	accConn, err := dial(ServiceAccount, accServiceAddr, accountv2.Account_ServiceDesc.ServiceName)
+	if err != nil {
+		return nil, err
+	}
 
-	userConn, err := grpc.NewClient(
-		usrServiceAddr,
-		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	loanConn, err := dial(ServiceLoan, loanServiceAddr, "")
 	if err != nil {
 		return nil, err
 	}
 
-	accConn, err := grpc.NewClient(
-		accServiceAddr,
-		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	schedulerConn, err := dial(ServiceScheduler, schedulerServiceAddr, "")
+	if err != nil {
+		return nil, err
+	}
+
+	reportingConn, err := dial(ServiceReporting, reportingServiceAddr, "")
 	if err != nil {
 		return nil, err
 	}
 
 	return &Clients{
-		userConn:    userConn,
-		accountConn: accConn,
-		User:        userv1.NewUserClient(userConn),
-		Account:     accountv2.NewAccountClient(accConn),
+		userConn:       userConn,
+		accountConn:    accConn,
+		loanConn:       loanConn,
+		schedulerConn:  schedulerConn,
+		reportingConn:  reportingConn,
+		User:           userv1.NewUserClient(userConn),
+		Account:        accountv2.NewAccountClient(accConn),
+		Loan:           loanv1.NewLoanClient(loanConn),
+		StandingOrders: schedulerv1.NewStandingOrdersClient(schedulerConn),
+		Reporting:      reportingv1.NewReportingClient(reportingConn),
 	}, nil
 }
 
@@ -52,4 +176,16 @@ func (c *Clients) Close() {
 	if c.accountConn != nil {
 		c.accountConn.Close()
 	}
+
+	if c.loanConn != nil {
+		c.loanConn.Close()
+	}
+
+	if c.schedulerConn != nil {
+		c.schedulerConn.Close()
+	}
+
+	if c.reportingConn != nil {
+		c.reportingConn.Close()
+	}
 }
@@ -0,0 +1,145 @@
+package clients
+
+import (
+	"errors"
+	"fmt"
+
+	accountv2 "github.com/galadeat/bank-sim/api/proto/account/v2"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Kinds of failure returned by Bank. Test for them with errors.Is:
+//
+//	if errors.Is(err, clients.ErrInsufficientFunds) { ... }
+var (
+	ErrNotFound        = errors.New("not found")
+	ErrAlreadyExists   = errors.New("already exists")
+	ErrInvalidArgument = errors.New("invalid argument")
+	// ErrInsufficientFunds is also an ErrFailedPrecondition.
+	ErrInsufficientFunds  = errors.New("insufficient funds")
+	ErrFailedPrecondition = errors.New("failed precondition")
+	// ErrDeclined is returned for payments the risk checks decline.
+	ErrDeclined = errors.New("declined")
+	// ErrLimitExceeded is returned for payments over a limit of the rules.
+	ErrLimitExceeded = errors.New("limit exceeded")
+	// ErrUnavailable is returned when the service could not be reached before
+	// the deadline, retries included.
+	ErrUnavailable = errors.New("unavailable")
+	ErrCanceled    = errors.New("canceled")
+	// ErrHeldForReview is returned for payments held for manual review.
+	ErrHeldForReview = errors.New("held for review")
+)
+
+const (
+	// reasonInsufficientFunds is the ErrorInfo reason the account service gives
+	// payments the balance does not cover.
+	reasonInsufficientFunds = "INSUFFICIENT_FUNDS"
+	// domainRules is the ErrorInfo domain of the limits of the account
+	// service's rules. Their codes differ: FailedPrecondition for the amount of
+	// a single payment, ResourceExhausted for totals and velocity.
+	domainRules = "rules.bank-sim"
+)
+
+// Error is a failed call. It keeps the gRPC status, so status.Code and
+// status.FromError still work on it.
+type Error struct {
+	// Op is the Bank method that failed, such as "Deposit".
+	Op string
+	// Kind is one of the Err values, or nil for failures without one.
+	Kind error
+	// Domain, Reason and Metadata come from the ErrorInfo of the status, if
+	// any; for limits they tell which limit was hit.
+	Domain   string
+	Reason   string
+	Metadata map[string]string
+
+	status *status.Status
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %s", e.Op, e.status.Message())
+}
+
+// Is reports whether target is the kind of e.
+func (e *Error) Is(target error) bool {
+	if e.Kind == nil {
+		return false
+	}
+	return target == e.Kind || (e.Kind == ErrInsufficientFunds && target == ErrFailedPrecondition)
+}
+
+// Code returns the gRPC status code.
+func (e *Error) Code() codes.Code {
+	return e.status.Code()
+}
+
+// GRPCStatus returns the status the service answered with.
+func (e *Error) GRPCStatus() *status.Status {
+	return e.status
+}
+
+// ReviewError is returned by Withdraw and Transfer when the payment is held for
+// manual review. Nothing has moved yet, so it is an error rather than a
+// result; the review decides later.
+type ReviewError struct {
+	Op     string
+	Review *accountv2.Review
+}
+
+func (e *ReviewError) Error() string {
+	return fmt.Sprintf("%s: held for review %s (score %d)", e.Op, e.Review.GetId(), e.Review.GetScore())
+}
+
+// Is reports whether target is ErrHeldForReview.
+func (e *ReviewError) Is(target error) bool {
+	return target == ErrHeldForReview
+}
+
+// wrap turns the error of a call into an *Error. Errors without a status are
+// only annotated with op.
+func wrap(op string, err error) error {
+	st, ok := status.FromError(err)
+	if !ok {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	e := &Error{Op: op, status: st}
+	for _, d := range st.Details() {
+		if info, ok := d.(*errdetails.ErrorInfo); ok {
+			e.Domain, e.Reason, e.Metadata = info.Domain, info.Reason, info.Metadata
+		}
+	}
+	e.Kind = kindOf(st.Code(), e.Domain, e.Reason)
+	return e
+}
+
+// kindOf returns the kind of a status: by its ErrorInfo where that tells more
+// than the code, otherwise by the code.
+func kindOf(code codes.Code, domain, reason string) error {
+	if domain == domainRules {
+		return ErrLimitExceeded
+	}
+	switch code {
+	case codes.NotFound:
+		return ErrNotFound
+	case codes.AlreadyExists:
+		return ErrAlreadyExists
+	case codes.InvalidArgument, codes.OutOfRange:
+		return ErrInvalidArgument
+	case codes.FailedPrecondition, codes.Aborted:
+		if reason == reasonInsufficientFunds {
+			return ErrInsufficientFunds
+		}
+		return ErrFailedPrecondition
+	case codes.PermissionDenied:
+		return ErrDeclined
+	case codes.ResourceExhausted:
+		return ErrLimitExceeded
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return ErrUnavailable
+	case codes.Canceled:
+		return ErrCanceled
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package clock
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Clock is the source of time used by the services and schedulers, so simulations
+// and tests can fast-forward it.
+type Clock interface {
+	Now() time.Time
+	// After waits for the duration to elapse and then sends the current time.
+	After(d time.Duration) <-chan time.Time
+}
+
+type wall struct{}
+
+// Real returns the wall clock.
+func Real() Clock {
+	return wall{}
+}
+
+func (wall) Now() time.Time {
+	return time.Now()
+}
+
+func (wall) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// Manual is a clock that only moves when told to. Timers created by After fire
+// once Advance or Set moves the clock past their deadline.
+type Manual struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []waiter
+}
+
+type waiter struct {
+	deadline time.Time
+	ch       chan time.Time
+}
+
+// NewManual returns a manual clock set to start.
+func NewManual(start time.Time) *Manual {
+	return &Manual{now: start}
+}
+
+func (m *Manual) Now() time.Time {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.now
+}
+
+func (m *Manual) After(d time.Duration) <-chan time.Time {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ch := make(chan time.Time, 1)
+	deadline := m.now.Add(d)
+	if !deadline.After(m.now) {
+		ch <- m.now
+		return ch
+	}
+	m.waiters = append(m.waiters, waiter{deadline: deadline, ch: ch})
+	return ch
+}
+
+// Advance moves the clock forward by d.
+func (m *Manual) Advance(d time.Duration) {
+	m.Set(m.Now().Add(d))
+}
+
+// Set moves the clock to t and fires every timer whose deadline has passed, in
+// deadline order. Moving the clock backwards is ignored.
+func (m *Manual) Set(t time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if t.Before(m.now) {
+		return
+	}
+	m.now = t
+
+	sort.SliceStable(m.waiters, func(i, j int) bool {
+		return m.waiters[i].deadline.Before(m.waiters[j].deadline)
+	})
+	pending := m.waiters[:0]
+	for _, w := range m.waiters {
+		if w.deadline.After(t) {
+			pending = append(pending, w)
+			continue
+		}
+		w.ch <- t
+	}
+	m.waiters = pending
+}
+
+// Waiters returns the number of timers that have not fired yet. Tests use it to
+// wait until a scheduler goroutine is blocked on the clock.
+func (m *Manual) Waiters() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.waiters)
+}
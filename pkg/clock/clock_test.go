@@ -0,0 +1,61 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestManual(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("now follows advance", func(t *testing.T) {
+		c := NewManual(start)
+		c.Advance(36 * time.Hour)
+		if got := c.Now(); !got.Equal(start.Add(36 * time.Hour)) {
+			t.Errorf("expected %v, got %v", start.Add(36*time.Hour), got)
+		}
+	})
+
+	t.Run("after fires once deadline is reached", func(t *testing.T) {
+		c := NewManual(start)
+		ch := c.After(time.Hour)
+
+		c.Advance(59 * time.Minute)
+		select {
+		case <-ch:
+			t.Fatalf("timer fired early")
+		default:
+		}
+
+		c.Advance(time.Minute)
+		select {
+		case got := <-ch:
+			if !got.Equal(start.Add(time.Hour)) {
+				t.Errorf("expected %v, got %v", start.Add(time.Hour), got)
+			}
+		default:
+			t.Fatalf("timer did not fire")
+		}
+
+		if c.Waiters() != 0 {
+			t.Errorf("expected 0 waiters, got %d", c.Waiters())
+		}
+	})
+
+	t.Run("non-positive duration fires immediately", func(t *testing.T) {
+		c := NewManual(start)
+		select {
+		case <-c.After(0):
+		default:
+			t.Fatalf("timer did not fire")
+		}
+	})
+
+	t.Run("set does not go backwards", func(t *testing.T) {
+		c := NewManual(start)
+		c.Set(start.Add(-time.Hour))
+		if !c.Now().Equal(start) {
+			t.Errorf("expected %v, got %v", start, c.Now())
+		}
+	})
+}
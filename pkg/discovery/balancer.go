@@ -0,0 +1,288 @@
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/balancer"
+	"google.golang.org/grpc/balancer/base"
+	"google.golang.org/grpc/connectivity"
+	_ "google.golang.org/grpc/health" // client side health checking
+	"google.golang.org/grpc/resolver"
+)
+
+// Policy is a load balancing policy. It spreads the calls without an affinity
+// key, see WithAffinity; calls with one go to the replica the key maps to.
+type Policy string
+
+const (
+	// RoundRobin sends calls to the healthy replicas in turn.
+	RoundRobin Policy = "replica_round_robin"
+	// LeastLoaded sends each call to the healthy replica with the fewest calls
+	// in flight from this client.
+	LeastLoaded Policy = "least_loaded"
+)
+
+func init() {
+	balancer.Register(replicaBalancer{policy: RoundRobin})
+	balancer.Register(replicaBalancer{policy: LeastLoaded})
+}
+
+// ServiceConfig returns the gRPC service config that balances with policy
+// and, if healthService is set, takes replicas out of rotation while their
+// health service reports healthService as not serving. Pass it to
+// grpc.WithDefaultServiceConfig.
+func ServiceConfig(policy Policy, healthService string) string {
+	if healthService == "" {
+		return fmt.Sprintf(`{"loadBalancingConfig":[{%q:{}}]}`, policy)
+	}
+	return fmt.Sprintf(`{"loadBalancingConfig":[{%q:{}}],"healthCheckConfig":{"serviceName":%q}}`, policy, healthService)
+}
+
+type affinityKey struct{}
+
+// WithAffinity makes the calls made with ctx go to the healthy replica key maps
+// to instead of being spread. Clients that resolve the same replicas map a key
+// to the same one, and a key only moves when its replica leaves, for example
+// because it is not serving. Services that keep state on each replica need it:
+// any other replica answers without that state.
+func WithAffinity(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, affinityKey{}, key)
+}
+
+// UnaryAffinity returns an interceptor that gives calls without an affinity key
+// the key, so all of them go to one replica.
+func UnaryAffinity(key string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(withDefaultAffinity(ctx, key), method, req, reply, cc, opts...)
+	}
+}
+
+// StreamAffinity is UnaryAffinity for streaming calls.
+func StreamAffinity(key string) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(withDefaultAffinity(ctx, key), desc, cc, method, opts...)
+	}
+}
+
+func withDefaultAffinity(ctx context.Context, key string) context.Context {
+	if _, ok := ctx.Value(affinityKey{}).(string); ok {
+		return ctx
+	}
+	return WithAffinity(ctx, key)
+}
+
+// replicaBalancer builds a balancer with its policy for each ClientConn. Each
+// one counts the calls in flight on its own replicas.
+type replicaBalancer struct {
+	policy Policy
+}
+
+func (b replicaBalancer) Name() string {
+	return string(b.policy)
+}
+
+func (b replicaBalancer) Build(cc balancer.ClientConn, opts balancer.BuildOptions) balancer.Balancer {
+	states := &replicaStates{states: make(map[string]*connState)}
+	pickers := &pickerBuilder{policy: b.policy, inflight: make(map[balancer.SubConn]*atomic.Int64), states: states}
+	return base.NewBalancerBuilder(b.Name(), pickers, base.Config{HealthCheck: true}).Build(trackingConn{cc, states}, opts)
+}
+
+// replicaStates keeps the state of every resolved replica, not only the ready
+// ones pickers are built from, so that affinity keys map onto all of them. A
+// key of a replica that is still connecting waits for it instead of landing on
+// another one that happened to connect first.
+type replicaStates struct {
+	mu     sync.RWMutex
+	states map[string]*connState
+}
+
+// connState is the state of one connection. A replica that is removed and added
+// again gets a new one, so late changes of the old connection are told apart.
+type connState struct {
+	state connectivity.State
+}
+
+// add records a new connection to the replica at addr.
+func (r *replicaStates) add(addr string) *connState {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c := &connState{state: connectivity.Idle}
+	r.states[addr] = c
+	return c
+}
+
+// set records the state of connection c to the replica at addr. Like the base
+// balancer, a replica that failed counts as failed until it is ready again.
+func (r *replicaStates) set(addr string, c *connState, s connectivity.State) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	switch {
+	case r.states[addr] != c:
+	case s == connectivity.Shutdown:
+		delete(r.states, addr)
+	case c.state == connectivity.TransientFailure && (s == connectivity.Idle || s == connectivity.Connecting):
+	default:
+		c.state = s
+	}
+}
+
+// ranked returns the replicas with their states, the one key maps to first.
+func (r *replicaStates) ranked(key string) ([]string, []connectivity.State) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	addrs := make([]string, 0, len(r.states))
+	for addr := range r.states {
+		addrs = append(addrs, addr)
+	}
+	slices.SortFunc(addrs, func(a, b string) int {
+		sa, sb := score(key, a), score(key, b)
+		switch {
+		case sa > sb:
+			return -1
+		case sa < sb:
+			return 1
+		}
+		return 0
+	})
+	states := make([]connectivity.State, len(addrs))
+	for i, addr := range addrs {
+		states[i] = r.states[addr].state
+	}
+	return addrs, states
+}
+
+// score ranks the replica at addr for key (rendezvous hashing). It depends only
+// on the two, so every client agrees on the ranking, and the keys of other
+// replicas stay where they are when one leaves.
+func score(key, addr string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(key))
+	h.Write([]byte{0})
+	h.Write([]byte(addr))
+	return h.Sum64()
+}
+
+// trackingConn records the state changes of the replicas' connections in
+// states before the base balancer sees them. The base balancer publishes its
+// picker after every change, so calls waiting for a replica are picked again.
+type trackingConn struct {
+	balancer.ClientConn
+	states *replicaStates
+}
+
+func (c trackingConn) NewSubConn(addrs []resolver.Address, opts balancer.NewSubConnOptions) (balancer.SubConn, error) {
+	listener := opts.StateListener
+	addr := addrs[0].Addr
+	conn := c.states.add(addr)
+	opts.StateListener = func(s balancer.SubConnState) {
+		c.states.set(addr, conn, s.ConnectivityState)
+		listener(s)
+	}
+	sc, err := c.ClientConn.NewSubConn(addrs, opts)
+	if err != nil {
+		c.states.set(addr, conn, connectivity.Shutdown)
+	}
+	return sc, err
+}
+
+// pickerBuilder builds pickers over the ready replicas of one ClientConn. The
+// in-flight counts outlive pickers, which are rebuilt whenever a replica
+// becomes ready or leaves. The balancer calls Build from one goroutine at a
+// time.
+type pickerBuilder struct {
+	policy   Policy
+	inflight map[balancer.SubConn]*atomic.Int64
+	states   *replicaStates
+}
+
+func (b *pickerBuilder) Build(info base.PickerBuildInfo) balancer.Picker {
+	if len(info.ReadySCs) == 0 {
+		return base.NewErrPicker(balancer.ErrNoSubConnAvailable)
+	}
+	p := &picker{policy: b.policy, states: b.states}
+	for sc, scInfo := range info.ReadySCs {
+		n, ok := b.inflight[sc]
+		if !ok {
+			n = new(atomic.Int64)
+			b.inflight[sc] = n
+		}
+		p.conns = append(p.conns, sc)
+		p.addrs = append(p.addrs, scInfo.Address.Addr)
+		p.inflight = append(p.inflight, n)
+	}
+	// forget replicas that left; calls still running on them hold their own
+	// counter
+	for sc := range b.inflight {
+		if _, ok := info.ReadySCs[sc]; !ok {
+			delete(b.inflight, sc)
+		}
+	}
+	return p
+}
+
+type picker struct {
+	policy   Policy
+	states   *replicaStates
+	conns    []balancer.SubConn
+	addrs    []string
+	inflight []*atomic.Int64
+	// next rotates where round robin and the least loaded search start, so
+	// equally loaded replicas take turns.
+	next atomic.Uint32
+}
+
+func (p *picker) Pick(info balancer.PickInfo) (balancer.PickResult, error) {
+	i, err := p.pick(info.Ctx)
+	if err != nil {
+		return balancer.PickResult{}, err
+	}
+	n := p.inflight[i]
+	n.Add(1)
+	return balancer.PickResult{
+		SubConn: p.conns[i],
+		Done:    func(balancer.DoneInfo) { n.Add(-1) },
+	}, nil
+}
+
+func (p *picker) pick(ctx context.Context) (int, error) {
+	if key, ok := ctx.Value(affinityKey{}).(string); ok {
+		return p.owner(key)
+	}
+	start := int(p.next.Add(1)) % len(p.conns)
+	if p.policy == RoundRobin {
+		return start, nil
+	}
+	best := start
+	for i := 1; i < len(p.conns); i++ {
+		j := (start + i) % len(p.conns)
+		if p.inflight[j].Load() < p.inflight[best].Load() {
+			best = j
+		}
+	}
+	return best, nil
+}
+
+// owner returns the ready replica key maps to: the first in the ranking of
+// key that has not failed. While that one is still connecting, or its picker
+// is not built yet, the call waits for the next picker.
+func (p *picker) owner(key string) (int, error) {
+	addrs, states := p.states.ranked(key)
+	for i, addr := range addrs {
+		switch states[i] {
+		case connectivity.TransientFailure:
+			continue
+		case connectivity.Ready:
+			if j := slices.Index(p.addrs, addr); j >= 0 {
+				return j, nil
+			}
+		}
+		return 0, balancer.ErrNoSubConnAvailable
+	}
+	return 0, balancer.ErrNoSubConnAvailable
+}
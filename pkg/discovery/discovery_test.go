@@ -0,0 +1,278 @@
+package discovery
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	accountv2 "github.com/galadeat/bank-sim/api/proto/account/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// replica is an account service that answers GetAccount with its own name and
+// holds calls for "slow" accounts until release is closed.
+type replica struct {
+	accountv2.UnimplementedAccountServer
+	name    string
+	addr    string
+	health  *health.Server
+	release chan struct{}
+}
+
+func (r *replica) GetAccount(ctx context.Context, req *accountv2.GetAccountRequest) (*accountv2.GetAccountResponse, error) {
+	if req.Id == "slow" {
+		select {
+		case <-r.release:
+		case <-ctx.Done():
+		}
+	}
+	return &accountv2.GetAccountResponse{Account: &accountv2.AccountInfo{Id: r.name}}, nil
+}
+
+func startReplica(t *testing.T, name string) *replica {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	return serveReplica(t, name, lis)
+}
+
+// serveReplica is startReplica on a listener of the caller.
+func serveReplica(t *testing.T, name string, lis net.Listener) *replica {
+	t.Helper()
+	r := &replica{name: name, addr: lis.Addr().String(), health: health.NewServer(), release: make(chan struct{})}
+	srv := grpc.NewServer()
+	accountv2.RegisterAccountServer(srv, r)
+	healthpb.RegisterHealthServer(srv, r.health)
+	r.health.SetServingStatus(accountv2.Account_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	go srv.Serve(lis)
+	t.Cleanup(func() {
+		close(r.release)
+		srv.Stop()
+	})
+	return r
+}
+
+func dial(t *testing.T, target string, policy Policy, opts ...grpc.DialOption) accountv2.AccountClient {
+	t.Helper()
+	opts = append(opts,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultServiceConfig(ServiceConfig(policy, accountv2.Account_ServiceDesc.ServiceName)))
+	conn, err := grpc.NewClient(target, opts...)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return accountv2.NewAccountClient(conn)
+}
+
+// served calls GetAccount n times and counts the answers of each replica.
+func served(t *testing.T, client accountv2.AccountClient, n int) map[string]int {
+	t.Helper()
+	return servedWith(t, context.Background(), client, n)
+}
+
+// servedWith is served with calls made under parent.
+func servedWith(t *testing.T, parent context.Context, client accountv2.AccountClient, n int) map[string]int {
+	t.Helper()
+	counts := make(map[string]int)
+	for i := 0; i < n; i++ {
+		ctx, cancel := context.WithTimeout(parent, 5*time.Second)
+		resp, err := client.GetAccount(ctx, &accountv2.GetAccountRequest{Id: "fast"}, grpc.WaitForReady(true))
+		cancel()
+		require.NoError(t, err)
+		counts[resp.Account.Id]++
+	}
+	return counts
+}
+
+func TestStaticRoundRobin(t *testing.T) {
+	a, b := startReplica(t, "a"), startReplica(t, "b")
+	client := dial(t, "static:///"+a.addr+","+b.addr, RoundRobin)
+
+	// round robin spreads calls once both replicas are connected
+	assert.Eventually(t, func() bool {
+		counts := served(t, client, 10)
+		return counts["a"] == 5 && counts["b"] == 5
+	}, 5*time.Second, 50*time.Millisecond)
+}
+
+func TestUnhealthyReplicaIsEjected(t *testing.T) {
+	for _, policy := range []Policy{RoundRobin, LeastLoaded} {
+		t.Run(string(policy), func(t *testing.T) {
+			a, b := startReplica(t, "a"), startReplica(t, "b")
+			client := dial(t, "static:///"+a.addr+","+b.addr, policy)
+			assert.Eventually(t, func() bool { return len(served(t, client, 10)) == 2 }, 5*time.Second, 50*time.Millisecond)
+
+			a.health.SetServingStatus(accountv2.Account_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_NOT_SERVING)
+			assert.Eventually(t, func() bool { return served(t, client, 10)["b"] == 10 }, 5*time.Second, 50*time.Millisecond)
+
+			a.health.SetServingStatus(accountv2.Account_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+			assert.Eventually(t, func() bool { return served(t, client, 10)["a"] > 0 }, 5*time.Second, 50*time.Millisecond)
+		})
+	}
+}
+
+func TestLeastLoaded(t *testing.T) {
+	a, b := startReplica(t, "a"), startReplica(t, "b")
+	client := dial(t, "static:///"+a.addr+","+b.addr, LeastLoaded)
+	assert.Eventually(t, func() bool { return len(served(t, client, 10)) == 2 }, 5*time.Second, 50*time.Millisecond)
+
+	// a slow call keeps one replica busy, so the other gets every new call
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	slow := make(chan string, 1)
+	go func() {
+		resp, err := client.GetAccount(ctx, &accountv2.GetAccountRequest{Id: "slow"})
+		if err == nil {
+			slow <- resp.Account.Id
+		}
+	}()
+	time.Sleep(100 * time.Millisecond)
+
+	counts := served(t, client, 10)
+	require.Len(t, counts, 1)
+	for name := range counts {
+		busy := map[string]*replica{"a": b, "b": a}[name]
+		busy.release <- struct{}{}
+		assert.Equal(t, busy.name, <-slow)
+	}
+}
+
+func TestLeastLoadedPerConn(t *testing.T) {
+	a, b := startReplica(t, "a"), startReplica(t, "b")
+	client := dial(t, "static:///"+a.addr+","+b.addr, LeastLoaded)
+	assert.Eventually(t, func() bool { return len(served(t, client, 10)) == 2 }, 5*time.Second, 50*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	slow := make(chan string, 1)
+	go func() {
+		resp, err := client.GetAccount(ctx, &accountv2.GetAccountRequest{Id: "slow"})
+		if err == nil {
+			slow <- resp.Account.Id
+		}
+	}()
+	time.Sleep(100 * time.Millisecond)
+	counts := served(t, client, 4)
+	require.Len(t, counts, 1)
+	var idle, busy *replica
+	for name := range counts {
+		idle, busy = map[string]*replica{"a": a, "b": b}[name], map[string]*replica{"a": b, "b": a}[name]
+	}
+
+	// another client of the same replicas keeps its own counts
+	other := dial(t, "static:///"+idle.addr, LeastLoaded)
+	assert.Equal(t, map[string]int{idle.name: 2}, served(t, other, 2))
+
+	// rebuild the first client's picker: the slow call still counts
+	idle.health.SetServingStatus(accountv2.Account_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_NOT_SERVING)
+	assert.Eventually(t, func() bool { return served(t, client, 4)[busy.name] == 4 }, 5*time.Second, 50*time.Millisecond)
+	idle.health.SetServingStatus(accountv2.Account_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	assert.Eventually(t, func() bool { return served(t, client, 1)[idle.name] == 1 }, 5*time.Second, 50*time.Millisecond)
+	assert.Equal(t, map[string]int{idle.name: 10}, served(t, client, 10))
+
+	busy.release <- struct{}{}
+	assert.Equal(t, busy.name, <-slow)
+}
+
+func TestAffinity(t *testing.T) {
+	for _, policy := range []Policy{RoundRobin, LeastLoaded} {
+		t.Run(string(policy), func(t *testing.T) {
+			a, b := startReplica(t, "a"), startReplica(t, "b")
+			client := dial(t, "static:///"+a.addr+","+b.addr, policy)
+			other := dial(t, "static:///"+b.addr+","+a.addr, policy,
+				grpc.WithUnaryInterceptor(UnaryAffinity("account")))
+			assert.Eventually(t, func() bool { return len(served(t, client, 10)) == 2 }, 5*time.Second, 50*time.Millisecond)
+
+			// every call with the key goes to one replica, the same for both clients
+			ctx := WithAffinity(context.Background(), "account")
+			pinned := servedWith(t, ctx, client, 10)
+			require.Len(t, pinned, 1)
+			var owner, standby *replica
+			for name := range pinned {
+				owner, standby = map[string]*replica{"a": a, "b": b}[name], map[string]*replica{"a": b, "b": a}[name]
+			}
+			// the interceptor sets the key; wait until the other client is connected to both
+			assert.Eventually(t, func() bool { return served(t, other, 10)[owner.name] == 10 }, 5*time.Second, 50*time.Millisecond)
+
+			// the key moves while its replica is not serving, and comes back
+			owner.health.SetServingStatus(accountv2.Account_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_NOT_SERVING)
+			assert.Eventually(t, func() bool { return servedWith(t, ctx, client, 4)[standby.name] == 4 }, 5*time.Second, 50*time.Millisecond)
+			owner.health.SetServingStatus(accountv2.Account_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+			assert.Eventually(t, func() bool { return servedWith(t, ctx, client, 4)[owner.name] == 4 }, 5*time.Second, 50*time.Millisecond)
+		})
+	}
+}
+
+func TestAffinityWaitsForConnectingReplica(t *testing.T) {
+	first, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	second, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	owner, other := first, second
+	if score("account", second.Addr().String()) > score("account", first.Addr().String()) {
+		owner, other = second, first
+	}
+
+	// the replica the key maps to accepts connections but answers late, so the
+	// other one is ready first
+	serveReplica(t, "other", other)
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		serveReplica(t, "owner", owner)
+	}()
+
+	client := dial(t, "static:///"+first.Addr().String()+","+second.Addr().String(), RoundRobin)
+	ctx := WithAffinity(context.Background(), "account")
+	assert.Equal(t, map[string]int{"owner": 3}, servedWith(t, ctx, client, 3))
+}
+
+func TestFileRegistry(t *testing.T) {
+	a, b := startReplica(t, "a"), startReplica(t, "b")
+	path := filepath.Join(t.TempDir(), "registry.json")
+	write := func(addrs ...string) {
+		data := `{"account": ["` + strings.Join(addrs, `", "`) + `"]}`
+		require.NoError(t, os.WriteFile(path, []byte(data), 0o644))
+	}
+	write(a.addr)
+
+	registry := NewFileRegistry(path, WithPollInterval(20*time.Millisecond))
+	client := dial(t, "registry:///account", RoundRobin, grpc.WithResolvers(registry))
+	assert.Equal(t, map[string]int{"a": 4}, served(t, client, 4))
+
+	// a replica is added
+	time.Sleep(10 * time.Millisecond) // let the modification time move on
+	write(a.addr, b.addr)
+	assert.Eventually(t, func() bool { return len(served(t, client, 10)) == 2 }, 5*time.Second, 50*time.Millisecond)
+
+	// a broken file keeps the replicas resolved last
+	require.NoError(t, os.WriteFile(path, []byte("{"), 0o644))
+	time.Sleep(100 * time.Millisecond)
+	assert.Len(t, served(t, client, 10), 2)
+
+	// and the first one is taken out
+	write(b.addr)
+	assert.Eventually(t, func() bool { return served(t, client, 10)["b"] == 10 }, 5*time.Second, 50*time.Millisecond)
+}
+
+func TestBadTargets(t *testing.T) {
+	conn, err := grpc.NewClient("static:///", grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	_, err = accountv2.NewAccountClient(conn).GetAccount(ctx, &accountv2.GetAccountRequest{})
+	assert.ErrorContains(t, err, "lists no addresses")
+}
+
+func TestServiceConfig(t *testing.T) {
+	assert.Equal(t, `{"loadBalancingConfig":[{"replica_round_robin":{}}]}`, ServiceConfig(RoundRobin, ""))
+	assert.Equal(t, `{"loadBalancingConfig":[{"least_loaded":{}}],"healthCheckConfig":{"serviceName":"svc"}}`, ServiceConfig(LeastLoaded, "svc"))
+}
@@ -0,0 +1,208 @@
+// Package discovery finds the replicas of a service and spreads calls over
+// them. It plugs into gRPC: resolvers turn a target into addresses and the
+// balancers pick one of the healthy ones for every call.
+//
+// Targets:
+//
+//	static:///10.0.0.1:50051,10.0.0.2:50051   a fixed list
+//	dns:///accounts.bank.internal:50051       every A record, re-resolved by gRPC
+//	registry:///account                       the "account" entry of a registry file
+package discovery
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/resolver"
+)
+
+// StaticScheme is the scheme of targets listing their addresses.
+const StaticScheme = "static"
+
+// RegistryScheme is the scheme of targets looked up in a registry file.
+const RegistryScheme = "registry"
+
+func init() {
+	resolver.Register(staticBuilder{})
+}
+
+type staticBuilder struct{}
+
+func (staticBuilder) Scheme() string { return StaticScheme }
+
+// Build resolves "static:///a:1,b:2" to a and b once; the list never changes.
+func (staticBuilder) Build(target resolver.Target, cc resolver.ClientConn, _ resolver.BuildOptions) (resolver.Resolver, error) {
+	var addrs []string
+	for _, a := range strings.Split(target.Endpoint(), ",") {
+		if a = strings.TrimSpace(a); a != "" {
+			addrs = append(addrs, a)
+		}
+	}
+	if len(addrs) == 0 {
+		return nil, fmt.Errorf("static target %q lists no addresses", target.URL.String())
+	}
+	if err := cc.UpdateState(state(addrs)); err != nil {
+		return nil, err
+	}
+	return nopResolver{}, nil
+}
+
+type nopResolver struct{}
+
+func (nopResolver) ResolveNow(resolver.ResolveNowOptions) {}
+func (nopResolver) Close()                                {}
+
+// state lists addrs as addresses; gRPC derives the endpoints from them.
+func state(addrs []string) resolver.State {
+	s := resolver.State{Addresses: make([]resolver.Address, len(addrs))}
+	for i, a := range addrs {
+		s.Addresses[i] = resolver.Address{Addr: a}
+	}
+	return s
+}
+
+// FileRegistry resolves "registry:///<service>" targets from a JSON file
+// mapping service names to addresses:
+//
+//	{"account": ["localhost:50051", "localhost:50061"], "user": ["localhost:50052"]}
+//
+// The file is checked for changes every poll interval, so replicas can be
+// added and removed while clients run. A file that cannot be read or parsed
+// keeps the addresses resolved last.
+type FileRegistry struct {
+	path string
+	poll time.Duration
+}
+
+// RegistryOption configures NewFileRegistry.
+type RegistryOption func(*FileRegistry)
+
+// WithPollInterval sets how often the registry file is checked for changes.
+func WithPollInterval(d time.Duration) RegistryOption {
+	return func(r *FileRegistry) {
+		r.poll = d
+	}
+}
+
+// NewFileRegistry is the constructor. Pass it to grpc.WithResolvers.
+func NewFileRegistry(path string, opts ...RegistryOption) *FileRegistry {
+	r := &FileRegistry{path: path, poll: 2 * time.Second}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Scheme implements resolver.Builder.
+func (r *FileRegistry) Scheme() string { return RegistryScheme }
+
+// Build implements resolver.Builder.
+func (r *FileRegistry) Build(target resolver.Target, cc resolver.ClientConn, _ resolver.BuildOptions) (resolver.Resolver, error) {
+	w := &registryWatcher{
+		registry: r,
+		service:  target.Endpoint(),
+		cc:       cc,
+		resolve:  make(chan struct{}, 1),
+		done:     make(chan struct{}),
+	}
+	if w.service == "" {
+		return nil, fmt.Errorf("registry target %q names no service", target.URL.String())
+	}
+	w.update()
+	w.wg.Add(1)
+	go w.watch()
+	return w, nil
+}
+
+// registryWatcher resolves one service of a registry file.
+type registryWatcher struct {
+	registry *FileRegistry
+	service  string
+	cc       resolver.ClientConn
+
+	resolve chan struct{}
+	done    chan struct{}
+	wg      sync.WaitGroup
+
+	// modTime and addrs describe the file read last; only watch uses them
+	// after Build.
+	modTime time.Time
+	addrs   []string
+}
+
+func (w *registryWatcher) watch() {
+	defer w.wg.Done()
+	t := time.NewTicker(w.registry.poll)
+	defer t.Stop()
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-t.C:
+		case <-w.resolve:
+			w.modTime = time.Time{}
+		}
+		w.update()
+	}
+}
+
+// update reads the file if it changed and passes the addresses of the service
+// on if they changed.
+func (w *registryWatcher) update() {
+	info, err := os.Stat(w.registry.path)
+	if err != nil {
+		w.cc.ReportError(err)
+		return
+	}
+	if info.ModTime().Equal(w.modTime) {
+		return
+	}
+	addrs, err := readRegistry(w.registry.path, w.service)
+	if err != nil {
+		log.Printf("registry %s: %v", w.registry.path, err)
+		w.cc.ReportError(err)
+		return
+	}
+	w.modTime = info.ModTime()
+	if slices.Equal(addrs, w.addrs) {
+		return
+	}
+	w.addrs = addrs
+	w.cc.UpdateState(state(addrs))
+}
+
+func readRegistry(path, service string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var services map[string][]string
+	if err := json.Unmarshal(data, &services); err != nil {
+		return nil, fmt.Errorf("parse: %w", err)
+	}
+	addrs := services[service]
+	if len(addrs) == 0 {
+		return nil, fmt.Errorf("no addresses for service %q", service)
+	}
+	return addrs, nil
+}
+
+// ResolveNow implements resolver.Resolver. It rereads the file.
+func (w *registryWatcher) ResolveNow(resolver.ResolveNowOptions) {
+	select {
+	case w.resolve <- struct{}{}:
+	default:
+	}
+}
+
+// Close implements resolver.Resolver.
+func (w *registryWatcher) Close() {
+	close(w.done)
+	w.wg.Wait()
+}
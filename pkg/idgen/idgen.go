@@ -0,0 +1,54 @@
+package idgen
+
+import (
+	"math/rand/v2"
+	"sync"
+
+	"github.com/gofrs/uuid"
+)
+
+// IDGenerator hands out the ids of users, accounts, ledger entries and the other
+// records the services create, so simulations can make them reproducible.
+type IDGenerator interface {
+	NewID() string
+}
+
+type random struct{}
+
+// Random returns a generator of random version 4 UUIDs.
+func Random() IDGenerator {
+	return random{}
+}
+
+func (random) NewID() string {
+	return uuid.Must(uuid.NewV4()).String()
+}
+
+// Seeded generates version 4 UUIDs from a seeded source. The same seed and the
+// same sequence of calls yield the same ids.
+type Seeded struct {
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+// NewSeeded returns a generator seeded with seed.
+func NewSeeded(seed uint64) *Seeded {
+	return &Seeded{rand: rand.New(rand.NewPCG(seed, seed^0x9e3779b97f4a7c15))}
+}
+
+func (g *Seeded) NewID() string {
+	g.mu.Lock()
+	var b [16]byte
+	for i := 0; i < len(b); i += 8 {
+		v := g.rand.Uint64()
+		for j := 0; j < 8; j++ {
+			b[i+j] = byte(v >> (8 * j))
+		}
+	}
+	g.mu.Unlock()
+
+	id := uuid.UUID(b)
+	id.SetVersion(uuid.V4)
+	id.SetVariant(uuid.VariantRFC4122)
+	return id.String()
+}
@@ -0,0 +1,43 @@
+package idgen
+
+import (
+	"testing"
+
+	"github.com/gofrs/uuid"
+)
+
+func TestSeeded(t *testing.T) {
+	t.Run("same seed gives same ids", func(t *testing.T) {
+		a, b := NewSeeded(42), NewSeeded(42)
+		for i := 0; i < 100; i++ {
+			if x, y := a.NewID(), b.NewID(); x != y {
+				t.Fatalf("id %d differs: %s != %s", i, x, y)
+			}
+		}
+	})
+
+	t.Run("different seeds give different ids", func(t *testing.T) {
+		if NewSeeded(1).NewID() == NewSeeded(2).NewID() {
+			t.Errorf("expected different ids for different seeds")
+		}
+	})
+
+	t.Run("ids are version 4 uuids", func(t *testing.T) {
+		g := NewSeeded(7)
+		seen := make(map[string]bool)
+		for i := 0; i < 1000; i++ {
+			id := g.NewID()
+			u, err := uuid.FromString(id)
+			if err != nil {
+				t.Fatalf("invalid uuid %q: %v", id, err)
+			}
+			if u.Version() != uuid.V4 || u.Variant() != uuid.VariantRFC4122 {
+				t.Fatalf("expected a version 4 uuid, got %s", id)
+			}
+			if seen[id] {
+				t.Fatalf("duplicate id %s", id)
+			}
+			seen[id] = true
+		}
+	})
+}
@@ -0,0 +1,196 @@
+// Package money converts between amounts written by people, such as "12.50 EUR"
+// or "€12.50", and the Money message used by the APIs.
+package money
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"unicode"
+
+	commonv1 "github.com/galadeat/bank-sim/api/proto/common/v1"
+)
+
+const nanosPerUnit = 1_000_000_000
+
+// Currency is a currency the clients accept.
+type Currency struct {
+	Code   string
+	Symbol string
+	// Digits is the number of minor unit digits: 2 for cents, 0 for yen.
+	Digits int
+}
+
+var currencies = map[string]Currency{
+	"AUD": {Code: "AUD", Symbol: "A$", Digits: 2},
+	"BHD": {Code: "BHD", Symbol: "BD", Digits: 3},
+	"CAD": {Code: "CAD", Symbol: "CA$", Digits: 2},
+	"CHF": {Code: "CHF", Symbol: "CHF ", Digits: 2},
+	"CNY": {Code: "CNY", Symbol: "CN¥", Digits: 2},
+	"CZK": {Code: "CZK", Symbol: "Kč ", Digits: 2},
+	"DKK": {Code: "DKK", Symbol: "kr. ", Digits: 2},
+	"EUR": {Code: "EUR", Symbol: "€", Digits: 2},
+	"GBP": {Code: "GBP", Symbol: "£", Digits: 2},
+	"INR": {Code: "INR", Symbol: "₹", Digits: 2},
+	"JPY": {Code: "JPY", Symbol: "¥", Digits: 0},
+	"KRW": {Code: "KRW", Symbol: "₩", Digits: 0},
+	"KWD": {Code: "KWD", Symbol: "KD", Digits: 3},
+	"NOK": {Code: "NOK", Symbol: "NOK ", Digits: 2},
+	"PLN": {Code: "PLN", Symbol: "zł ", Digits: 2},
+	"SEK": {Code: "SEK", Symbol: "SEK ", Digits: 2},
+	"UAH": {Code: "UAH", Symbol: "₴", Digits: 2},
+	"USD": {Code: "USD", Symbol: "$", Digits: 2},
+}
+
+// symbols are the prefixes accepted in place of a code, such as "€12.50".
+var symbols = map[string]string{"$": "USD", "€": "EUR", "£": "GBP", "¥": "JPY", "₹": "INR", "₩": "KRW", "₴": "UAH"}
+
+// Lookup returns the currency with the given code.
+func Lookup(code string) (Currency, bool) {
+	c, ok := currencies[strings.ToUpper(strings.TrimSpace(code))]
+	return c, ok
+}
+
+// Codes returns the codes of every known currency.
+func Codes() []string {
+	codes := make([]string, 0, len(currencies))
+	for code := range currencies {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// Parse reads a decimal amount in currency. The currency must be known, the
+// amount must not be negative and must not have more decimal places than the
+// currency's minor unit.
+func Parse(amount, currency string) (*commonv1.Money, error) {
+	c, ok := Lookup(currency)
+	if !ok {
+		return nil, fmt.Errorf("unknown currency %q, use one of %s", strings.TrimSpace(currency), strings.Join(Codes(), ", "))
+	}
+
+	amount = strings.TrimSpace(amount)
+	if strings.HasPrefix(amount, "-") {
+		return nil, fmt.Errorf("amount must not be negative, got %s", amount)
+	}
+	if !isDecimal(amount) {
+		return nil, fmt.Errorf("invalid amount %q, write it as digits with an optional decimal point, e.g. 12.50", amount)
+	}
+	if _, frac, ok := strings.Cut(amount, "."); ok && len(frac) > c.Digits {
+		if c.Digits == 0 {
+			return nil, fmt.Errorf("%s amounts are whole numbers, got %s", c.Code, amount)
+		}
+		return nil, fmt.Errorf("%s amounts have at most %d decimal places, got %s", c.Code, c.Digits, amount)
+	}
+
+	r, ok := new(big.Rat).SetString(amount)
+	if !ok {
+		return nil, fmt.Errorf("invalid amount %q", amount)
+	}
+	r.Mul(r, big.NewRat(nanosPerUnit, 1))
+	if !r.Num().IsInt64() {
+		return nil, fmt.Errorf("amount %s is too large", amount)
+	}
+	return FromNanos(r.Num().Int64(), c.Code), nil
+}
+
+// ParseString reads an amount with its currency: "12.50 EUR", "EUR 12.50" or,
+// for currencies with a distinct symbol, "€12.50".
+func ParseString(s string) (*commonv1.Money, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, fmt.Errorf("enter an amount and a currency, e.g. 12.50 USD")
+	}
+	for symbol, code := range symbols {
+		if rest, ok := strings.CutPrefix(s, symbol); ok {
+			return Parse(rest, code)
+		}
+		if rest, ok := strings.CutPrefix(s, "-"+symbol); ok {
+			return Parse("-"+rest, code)
+		}
+	}
+
+	fields := strings.Fields(s)
+	if len(fields) != 2 {
+		return nil, fmt.Errorf("invalid amount %q, write it as an amount and a currency, e.g. 12.50 USD", s)
+	}
+	if isCode(fields[0]) && !isCode(fields[1]) {
+		return Parse(fields[1], fields[0])
+	}
+	return Parse(fields[0], fields[1])
+}
+
+// FromNanos returns the amount of n billionths of a unit.
+func FromNanos(n int64, currency string) *commonv1.Money {
+	return &commonv1.Money{Currency: currency, Units: n / nanosPerUnit, Nanos: int32(n % nanosPerUnit)}
+}
+
+// Nanos returns m in billionths of a unit.
+func Nanos(m *commonv1.Money) int64 {
+	return m.GetUnits()*nanosPerUnit + int64(m.GetNanos())
+}
+
+// Format returns m for people: "$12.50", "¥1200", "-€3.25". Amounts show the
+// currency's minor unit digits, and more only when they hold more, as accrued
+// interest does. Unknown currencies are written as "12.50 XYZ".
+func Format(m *commonv1.Money) string {
+	if m == nil {
+		return ""
+	}
+	c, known := Lookup(m.Currency)
+	digits := 2
+	if known {
+		digits = c.Digits
+	}
+
+	n := Nanos(m)
+	sign := ""
+	if n < 0 {
+		sign, n = "-", -n
+	}
+	frac := strings.TrimRight(fmt.Sprintf("%09d", n%nanosPerUnit), "0")
+	for len(frac) < digits {
+		frac += "0"
+	}
+	number := fmt.Sprint(n / nanosPerUnit)
+	if frac != "" {
+		number += "." + frac
+	}
+
+	switch {
+	case known:
+		return sign + c.Symbol + number
+	case m.Currency != "":
+		return sign + number + " " + m.Currency
+	}
+	return sign + number
+}
+
+func isDecimal(s string) bool {
+	whole, frac, _ := strings.Cut(s, ".")
+	if whole == "" && frac == "" {
+		return false
+	}
+	for _, part := range []string{whole, frac} {
+		for _, r := range part {
+			if r < '0' || r > '9' {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func isCode(s string) bool {
+	if len(s) != 3 {
+		return false
+	}
+	for _, r := range s {
+		if !unicode.IsLetter(r) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,90 @@
+package money
+
+import (
+	"testing"
+
+	commonv1 "github.com/galadeat/bank-sim/api/proto/common/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   string
+		currency string
+		want     *commonv1.Money
+		errMsg   string
+	}{
+		{name: "whole", amount: "10", currency: "USD", want: &commonv1.Money{Currency: "USD", Units: 10}},
+		{name: "cents", amount: "10.50", currency: "usd", want: &commonv1.Money{Currency: "USD", Units: 10, Nanos: 500_000_000}},
+		{name: "point only", amount: ".5", currency: "EUR", want: &commonv1.Money{Currency: "EUR", Nanos: 500_000_000}},
+		{name: "yen", amount: "1200", currency: "JPY", want: &commonv1.Money{Currency: "JPY", Units: 1200}},
+		{name: "fils", amount: "1.125", currency: "KWD", want: &commonv1.Money{Currency: "KWD", Units: 1, Nanos: 125_000_000}},
+		{name: "negative", amount: "-1", currency: "USD", errMsg: "must not be negative"},
+		{name: "too precise", amount: "0.001", currency: "USD", errMsg: "USD amounts have at most 2 decimal places"},
+		{name: "fractional yen", amount: "10.5", currency: "JPY", errMsg: "JPY amounts are whole numbers"},
+		{name: "not a number", amount: "ten", currency: "USD", errMsg: "invalid amount"},
+		{name: "two points", amount: "1.2.3", currency: "USD", errMsg: "invalid amount"},
+		{name: "empty", amount: "", currency: "USD", errMsg: "invalid amount"},
+		{name: "unknown currency", amount: "1", currency: "XYZ", errMsg: `unknown currency "XYZ"`},
+		{name: "bad currency", amount: "1", currency: "dollars", errMsg: "unknown currency"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.amount, tt.currency)
+			if tt.errMsg != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want.String(), got.String())
+		})
+	}
+}
+
+func TestParseString(t *testing.T) {
+	tests := []struct {
+		in     string
+		want   *commonv1.Money
+		errMsg string
+	}{
+		{in: "12.50 EUR", want: &commonv1.Money{Currency: "EUR", Units: 12, Nanos: 500_000_000}},
+		{in: "  eur 12.50 ", want: &commonv1.Money{Currency: "EUR", Units: 12, Nanos: 500_000_000}},
+		{in: "€12.50", want: &commonv1.Money{Currency: "EUR", Units: 12, Nanos: 500_000_000}},
+		{in: "$7", want: &commonv1.Money{Currency: "USD", Units: 7}},
+		{in: "¥1200", want: &commonv1.Money{Currency: "JPY", Units: 1200}},
+		{in: "-$7", errMsg: "must not be negative"},
+		{in: "-5 USD", errMsg: "must not be negative"},
+		{in: "12.50", errMsg: "e.g. 12.50 USD"},
+		{in: "12.50 EUR extra", errMsg: "e.g. 12.50 USD"},
+		{in: "", errMsg: "enter an amount"},
+		{in: "12,50 EUR", errMsg: "invalid amount"},
+		{in: "12.50 ABC", errMsg: "unknown currency"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseString(tt.in)
+			if tt.errMsg != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want.String(), got.String())
+		})
+	}
+}
+
+func TestFormat(t *testing.T) {
+	assert.Equal(t, "$10.00", Format(&commonv1.Money{Currency: "USD", Units: 10}))
+	assert.Equal(t, "$10.50", Format(&commonv1.Money{Currency: "USD", Units: 10, Nanos: 500_000_000}))
+	assert.Equal(t, "€0.125", Format(&commonv1.Money{Currency: "EUR", Nanos: 125_000_000}))
+	assert.Equal(t, "-$3.25", Format(&commonv1.Money{Currency: "USD", Units: -3, Nanos: -250_000_000}))
+	assert.Equal(t, "¥1200", Format(&commonv1.Money{Currency: "JPY", Units: 1200}))
+	assert.Equal(t, "KD1.500", Format(&commonv1.Money{Currency: "KWD", Units: 1, Nanos: 500_000_000}))
+	assert.Equal(t, "CHF 2.00", Format(&commonv1.Money{Currency: "CHF", Units: 2}))
+	assert.Equal(t, "2.50 XYZ", Format(&commonv1.Money{Currency: "XYZ", Units: 2, Nanos: 500_000_000}))
+	assert.Equal(t, "", Format(nil))
+}